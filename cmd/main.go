@@ -28,6 +28,8 @@ func createApp() *cli.App {
 		Flags:    getGlobalFlags(),
 		Commands: getCommands(),
 		Action:   cli.ShowAppHelp,
+		// Repeat slice flags instead of comma separating values, which may contain commas
+		DisableSliceFlagSeparator: true,
 	}
 }
 
@@ -68,5 +70,12 @@ func getCommands() []*cli.Command {
 		commands.GetVersionCommand(),
 		commands.GetPromptCommand(),
 		commands.GetAgentCommand(),
+		commands.GetMCPCommand(),
+		commands.GetToolCommand(),
+		commands.GetSecretCommand(),
+		commands.GetLockCommand(),
+		commands.GetInstallCommand(),
+		commands.GetOutdatedCommand(),
+		commands.GetUpgradeCommand(),
 	}
 }
@@ -0,0 +1,79 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// conversationAgent starts the sessions of an agent with the messages of a
+// conversation prompt. This is a private implementation of types.ConversationAgent.
+type conversationAgent struct {
+	types.Agent
+	system   []string
+	messages []llms.MessageContent
+}
+
+// NewConversationAgent returns agent with the rendered messages of prompt as
+// few-shot prefix of its sessions. Leading system messages of the prompt are
+// appended to the system prompt of the agent. The messages follow the prefix
+// of agents that are conversation agents already.
+func NewConversationAgent(agent types.Agent, prompt *schema.Prompt, values map[string]any) (types.ConversationAgent, error) {
+	messages, err := promptrender.RenderMessages(prompt, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render conversation prompt %s: %w", prompt.Metadata.Name, err)
+	}
+
+	var system []string
+	for len(messages) > 0 && messages[0].Role == llms.ChatMessageTypeSystem {
+		system = append(system, promptrender.MessageText(messages[0]))
+		messages = messages[1:]
+	}
+
+	if conversation, ok := agent.(types.ConversationAgent); ok {
+		messages = append(conversation.GetPromptMessages(), messages...)
+	}
+
+	log.Info("Conversation prompt attached to agent",
+		zap.String("agent", agent.GetName()),
+		zap.String("prompt", prompt.Metadata.Name),
+		zap.Int("messages", len(messages)))
+
+	return &conversationAgent{
+		Agent:    agent,
+		system:   system,
+		messages: messages,
+	}, nil
+}
+
+// GetSystemPrompt returns the agent system prompt with the prompt system messages.
+func (a *conversationAgent) GetSystemPrompt() string {
+	return strings.Join(append([]string{a.Agent.GetSystemPrompt()}, a.system...), "\n\n")
+}
+
+// GetPromptMessages returns the few-shot messages of the conversation prompt.
+func (a *conversationAgent) GetPromptMessages() []llms.MessageContent {
+	messages := make([]llms.MessageContent, len(a.messages))
+	copy(messages, a.messages)
+	return messages
+}
+
+// GetOutputSchema returns the output schema of the wrapped agent.
+func (a *conversationAgent) GetOutputSchema() map[string]any {
+	return types.OutputSchema(a.Agent)
+}
+
+// Clone clones the wrapped agent and keeps the conversation prefix.
+func (a *conversationAgent) Clone(overrides map[string]any) types.Agent {
+	return &conversationAgent{
+		Agent:    a.Agent.Clone(overrides),
+		system:   a.system,
+		messages: a.messages,
+	}
+}
@@ -0,0 +1,102 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// defaultAgentName is the agent preferred by GetDefaultAgent.
+const defaultAgentName = "planner"
+
+// agentProvider provides the agents installed in a directory.
+// This is a private implementation of types.AgentProvider interface.
+type agentProvider struct {
+	agents map[string]types.Agent
+}
+
+// NewAgentProvider creates an agent provider for the agents installed in
+// agentsDir as <name>/component.yaml. Their prompts are found with prompts.
+// Agents that fail to load are logged and skipped.
+func NewAgentProvider(agentsDir string, prompts *promptrender.Resolver) (types.AgentProvider, error) {
+	entries, err := os.ReadDir(agentsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read agents directory: %w", err)
+	}
+
+	provider := &agentProvider{agents: make(map[string]types.Agent)}
+	for _, entry := range entries {
+		// Hidden directories are staged installs
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		agent, err := loadAgent(filepath.Join(agentsDir, entry.Name()), prompts)
+		if err != nil {
+			log.Warn("Skipping installed agent", zap.String("agent", entry.Name()), zap.Error(err))
+			continue
+		}
+		provider.agents[agent.GetName()] = agent
+	}
+
+	log.Info("Agent provider initialized", zap.Int("agents", len(provider.agents)))
+	return provider, nil
+}
+
+// loadAgent builds the agent whose manifest is in dir.
+func loadAgent(dir string, prompts *promptrender.Resolver) (types.Agent, error) {
+	content, err := os.ReadFile(filepath.Join(dir, schema.ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent manifest: %w", err)
+	}
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent manifest: %w", err)
+	}
+	manifest, ok := component.(*schema.Agent)
+	if !ok {
+		return nil, fmt.Errorf("manifest in %s is not an Agent", dir)
+	}
+	return NewAgent(manifest, prompts)
+}
+
+// GetAgents returns all available agents.
+func (p *agentProvider) GetAgents() map[string]types.Agent {
+	agents := make(map[string]types.Agent, len(p.agents))
+	for name, agent := range p.agents {
+		agents[name] = agent
+	}
+	return agents
+}
+
+// GetAgent returns a specific agent by name.
+func (p *agentProvider) GetAgent(name string) (types.Agent, error) {
+	agent, ok := p.agents[name]
+	if !ok {
+		return nil, fmt.Errorf("agent %s is not installed", name)
+	}
+	return agent, nil
+}
+
+// GetDefaultAgent returns the planner agent, or the first installed agent by name.
+func (p *agentProvider) GetDefaultAgent() (types.Agent, error) {
+	if agent, ok := p.agents[defaultAgentName]; ok {
+		return agent, nil
+	}
+	if len(p.agents) == 0 {
+		return nil, fmt.Errorf("no agents installed, pull one with forge agent pull")
+	}
+	names := make([]string, 0, len(p.agents))
+	for name := range p.agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return p.agents[names[0]], nil
+}
@@ -0,0 +1,146 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/session"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// writeComponent writes component below dir as <name>/component.yaml.
+func writeComponent(t *testing.T, dir, name string, component any) {
+	t.Helper()
+	content, err := schema.NewComponentParser().SerializeComponent(component)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name, schema.ManifestFileName), content, 0644))
+}
+
+// newTestPrompt creates a valid prompt of promptType.
+func newTestPrompt(name string, promptType schema.PromptType) *schema.Prompt {
+	prompt := schema.NewPrompt(name, "1.0.0")
+	prompt.Metadata.Description = "Prompt " + name + " for agent tests"
+	prompt.Metadata.Author = "Test"
+	prompt.Metadata.License = "MIT"
+	prompt.Metadata.ForgeVersion = ">=0.1.0"
+	prompt.Spec.Type = promptType
+	prompt.Spec.Format = schema.FormatText
+	return prompt
+}
+
+// newTestAgent creates a valid agent manifest using prompts.
+func newTestAgent(name string, prompts ...schema.AgentPrompt) *schema.Agent {
+	agent := schema.NewAgent(name, "1.0.0")
+	agent.Metadata.Description = "Agent " + name + " for agent tests"
+	agent.Spec.Type = schema.AgentTypeConversational
+	agent.Spec.Model = schema.AgentModel{Provider: "openai", Model: "gpt-4o-mini"}
+	agent.Spec.Interface = schema.AgentInterface{Type: "cli"}
+	agent.Spec.Tools = []schema.AgentTool{
+		{Name: "search", Type: "tool", Source: "builtin", Required: true},
+		{Name: "notes", Type: "tool", Source: "builtin"},
+	}
+	agent.Spec.Prompts = prompts
+	return agent
+}
+
+func TestAgentProvider_ConversationPromptReachesSession(t *testing.T) {
+	promptsDir, agentsDir := t.TempDir(), t.TempDir()
+
+	system := newTestPrompt("translator-system", schema.PromptTypeSystem)
+	system.Spec.Template = "You are a translator."
+	writeComponent(t, promptsDir, system.Metadata.Name, system)
+
+	fewShot := newTestPrompt("translator-examples", schema.PromptTypeConversation)
+	fewShot.Spec.Messages = []schema.PromptMessage{
+		{Role: "system", Content: "Translate to {{language}}."},
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "Hallo"},
+	}
+	fewShot.Spec.Variables = []schema.PromptVariable{{Name: "language", Type: "string", Description: "Target", Required: true}}
+	writeComponent(t, promptsDir, fewShot.Metadata.Name, fewShot)
+
+	writeComponent(t, agentsDir, "translator", newTestAgent("translator",
+		schema.AgentPrompt{Name: "translator-examples", Type: "conversation", Source: "builtin", Priority: 2,
+			Config: map[string]string{"language": "German"}},
+		schema.AgentPrompt{Name: "translator-system", Type: "system", Source: "builtin", Priority: 1},
+	))
+	writeComponent(t, agentsDir, "broken", newTestAgent("broken",
+		schema.AgentPrompt{Name: "missing", Type: "system", Source: "builtin"},
+	))
+
+	provider, err := NewAgentProvider(agentsDir, promptrender.NewResolver(promptsDir))
+	require.NoError(t, err)
+	assert.Len(t, provider.GetAgents(), 1, "agents with missing prompts are skipped")
+
+	agent, err := provider.GetDefaultAgent()
+	require.NoError(t, err)
+	assert.Equal(t, "translator", agent.GetName())
+	assert.Equal(t, []string{"search"}, agent.GetRequiredTools())
+	assert.Equal(t, "openai", agent.GetLLMConfig().GetProvider())
+
+	agentSession, err := session.NewAgentSession(nil, agent, nil, nil, provider, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You are a translator.\n\nTranslate to German."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "Hello"),
+		llms.TextParts(llms.ChatMessageTypeAI, "Hallo"),
+	}, agentSession.GetMessageHistory())
+
+	agentSession.AddMessage(llms.TextParts(llms.ChatMessageTypeHuman, "Good night"))
+	agentSession.ClearMessageHistory()
+	assert.Len(t, agentSession.GetMessageHistory(), 3, "clearing keeps the few-shot prefix")
+
+	_, err = provider.GetAgent("broken")
+	assert.ErrorContains(t, err, "not installed")
+}
+
+func TestNewAgentProvider_SkipsStagedInstalls(t *testing.T) {
+	agentsDir := t.TempDir()
+	writeComponent(t, agentsDir, "reviewer", newTestAgent("reviewer"))
+	writeComponent(t, agentsDir, ".stale-123", newTestAgent("stale"))
+
+	provider, err := NewAgentProvider(agentsDir, promptrender.NewResolver(t.TempDir()))
+	require.NoError(t, err)
+	assert.Len(t, provider.GetAgents(), 1)
+	_, err = provider.GetAgent("stale")
+	assert.Error(t, err)
+}
+
+func TestNewConversationAgent_StacksPrompts(t *testing.T) {
+	first := newTestPrompt("first", schema.PromptTypeConversation)
+	first.Spec.Messages = []schema.PromptMessage{{Role: "user", Content: "one"}, {Role: "assistant", Content: "1"}}
+	second := newTestPrompt("second", schema.PromptTypeConversation)
+	second.Spec.Messages = []schema.PromptMessage{{Role: "user", Content: "two"}, {Role: "assistant", Content: "2"}}
+
+	manifest := newTestAgent("counter")
+	manifest.Spec.OutputSchema = map[string]any{"type": "integer"}
+	agent, err := NewAgent(manifest, promptrender.NewResolver())
+	require.NoError(t, err)
+	assert.Equal(t, "Agent counter for agent tests", agent.GetSystemPrompt(), "agents without system prompts use their description")
+
+	withFirst, err := NewConversationAgent(agent, first, nil)
+	require.NoError(t, err)
+	withBoth, err := NewConversationAgent(withFirst, second, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "one"),
+		llms.TextParts(llms.ChatMessageTypeAI, "1"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "two"),
+		llms.TextParts(llms.ChatMessageTypeAI, "2"),
+	}, withBoth.GetPromptMessages())
+
+	assert.Equal(t, map[string]any{"type": "integer"}, types.OutputSchema(withBoth), "the output schema is kept by conversation agents")
+
+	clone := withBoth.Clone(map[string]any{"model": "gpt-4o"})
+	assert.Equal(t, "gpt-4o", clone.GetLLMConfig().GetModel())
+	assert.Len(t, clone.(*conversationAgent).GetPromptMessages(), 4)
+}
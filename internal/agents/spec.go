@@ -0,0 +1,171 @@
+package agents
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// defaultTemperature is used for agents whose model sets no temperature.
+const defaultTemperature = 0.7
+
+// specAgent is an agent built from an agent manifest.
+// This is a private implementation of types.Agent interface.
+type specAgent struct {
+	manifest      *schema.Agent
+	systemPrompt  string
+	requiredTools []string
+	llmConfig     types.LLMConfig
+}
+
+// NewAgent builds the agent of manifest. The prompts of the manifest are found
+// with prompts and composed with their includes: system and instruction prompts
+// form the system prompt, conversation prompts become the few-shot prefix of
+// the agent sessions. Template prompts are left to workflows.
+func NewAgent(manifest *schema.Agent, prompts *promptrender.Resolver) (types.Agent, error) {
+	model := manifest.Spec.Model
+	temperature := defaultTemperature
+	if model.Temperature != nil {
+		temperature = *model.Temperature
+	}
+	maxTokens := 0
+	if model.MaxTokens != nil {
+		maxTokens = *model.MaxTokens
+	}
+
+	agent := &specAgent{
+		manifest:  manifest,
+		llmConfig: llm.NewLLMConfig(model.Provider, model.Model, temperature, maxTokens, nil),
+	}
+	for _, tool := range manifest.Spec.Tools {
+		if tool.Required {
+			agent.requiredTools = append(agent.requiredTools, tool.Name)
+		}
+	}
+
+	agentPrompts := slices.Clone(manifest.Spec.Prompts)
+	sort.SliceStable(agentPrompts, func(i, j int) bool {
+		return agentPrompts[i].Priority < agentPrompts[j].Priority
+	})
+
+	var system []string
+	var conversations []*schema.Prompt
+	var values []map[string]any
+	for _, agentPrompt := range agentPrompts {
+		if agentPrompt.Type == "template" {
+			continue
+		}
+		prompt, err := findPrompt(prompts, agentPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load prompt %s of agent %s: %w", agentPrompt.Name, manifest.Metadata.Name, err)
+		}
+		config := promptrender.StringValues(agentPrompt.Config)
+
+		if agentPrompt.Type == "conversation" {
+			conversations = append(conversations, prompt)
+			values = append(values, config)
+			continue
+		}
+		messages, err := promptrender.RenderMessages(prompt, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s of agent %s: %w", agentPrompt.Name, manifest.Metadata.Name, err)
+		}
+		for _, message := range messages {
+			system = append(system, promptrender.MessageText(message))
+		}
+	}
+	agent.systemPrompt = strings.Join(system, "\n\n")
+	if agent.systemPrompt == "" {
+		agent.systemPrompt = manifest.Metadata.Description
+	}
+
+	var result types.Agent = agent
+	for i, prompt := range conversations {
+		conversation, err := NewConversationAgent(result, prompt, values[i])
+		if err != nil {
+			return nil, err
+		}
+		result = conversation
+	}
+	return result, nil
+}
+
+// findPrompt returns the composed prompt of an agent prompt.
+func findPrompt(prompts *promptrender.Resolver, agentPrompt schema.AgentPrompt) (*schema.Prompt, error) {
+	_, version := schema.ParseSource(agentPrompt.Source, agentPrompt.Version)
+	prompt, err := prompts.Find(schema.PromptDependency{Name: agentPrompt.Name, Version: version})
+	if err != nil {
+		return nil, err
+	}
+	return prompts.Compose(prompt)
+}
+
+// GetName returns the agent's name.
+func (a *specAgent) GetName() string {
+	return a.manifest.Metadata.Name
+}
+
+// GetDescription returns the agent's description.
+func (a *specAgent) GetDescription() string {
+	return a.manifest.Metadata.Description
+}
+
+// GetSystemPrompt returns the rendered system and instruction prompts.
+func (a *specAgent) GetSystemPrompt() string {
+	return a.systemPrompt
+}
+
+// GetRequiredTools returns the names of the required tools of the manifest.
+func (a *specAgent) GetRequiredTools() []string {
+	return a.requiredTools
+}
+
+// GetLLMConfig returns the model configuration of the manifest.
+func (a *specAgent) GetLLMConfig() types.LLMConfig {
+	return a.llmConfig
+}
+
+// HasRequiredTool checks if the agent requires a specific tool.
+func (a *specAgent) HasRequiredTool(toolName string) bool {
+	return slices.Contains(a.requiredTools, toolName)
+}
+
+// GetOutputSchema returns the output schema of the manifest, nil for free text.
+func (a *specAgent) GetOutputSchema() map[string]any {
+	if len(a.manifest.Spec.OutputSchema) == 0 {
+		return nil
+	}
+	return a.manifest.Spec.OutputSchema
+}
+
+// Clone creates a copy of the agent. The overrides system_prompt, model and
+// temperature replace the values of the manifest.
+func (a *specAgent) Clone(overrides map[string]any) types.Agent {
+	clone := *a
+	clone.requiredTools = slices.Clone(a.requiredTools)
+	if systemPrompt, ok := overrides["system_prompt"].(string); ok {
+		clone.systemPrompt = systemPrompt
+	}
+
+	config := a.llmConfig
+	model, modelOK := overrides["model"].(string)
+	temperature, temperatureOK := overrides["temperature"].(float64)
+	if modelOK || temperatureOK {
+		if !modelOK {
+			model = config.GetModel()
+		}
+		if !temperatureOK {
+			temperature = config.GetTemperature()
+		}
+		clone.llmConfig = llm.NewLLMConfig(config.GetProvider(), model, temperature,
+			config.GetMaxTokens(), maps.Clone(config.GetParameters()))
+	}
+	return &clone
+}
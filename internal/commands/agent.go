@@ -3,18 +3,21 @@ package commands
 
 import (
 	"fmt"
-	"strings"
+	"os"
+	"text/tabwriter"
 
-	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/samber/do"
 	cli "github.com/urfave/cli/v2"
-	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/startup"
 )
 
 // GetAgentCommand returns the agent management command configuration.
 func GetAgentCommand() *cli.Command {
 	return &cli.Command{
-		Name:    "agent",
-		Usage:   "Manage AgentForge agents",
+		Name:  "agent",
+		Usage: "Manage AgentForge agents",
 		Subcommands: []*cli.Command{
 			getAgentListCommand(),
 			getAgentPullCommand(),
@@ -29,13 +32,16 @@ func getAgentListCommand() *cli.Command {
 	return &cli.Command{
 		Name:    "list",
 		Aliases: []string{"ls"},
-		Usage:   "List available agents",
+		Usage:   "List installed agents and agents known from pulled repositories",
 		Action:  HandleAgentList(),
 		Flags: []cli.Flag{
-			&cli.IntFlag{
-				Name:  "stars",
-				Usage: "Minimum GitHub stars filter",
-				Value: 0,
+			&cli.BoolFlag{
+				Name:  "installed",
+				Usage: "Show only installed agents",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
 			},
 		},
 	}
@@ -45,9 +51,19 @@ func getAgentListCommand() *cli.Command {
 func getAgentPullCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "pull",
-		Usage:     "Pull an agent from GitHub",
+		Usage:     "Pull an agent and its tool, prompt and agent dependencies",
 		Action:    HandleAgentPull(),
 		ArgsUsage: "<user/repo[@version]>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "version",
+				Usage: "Tag or branch to pull, a version such as 1.2.0 also matches the tag v1.2.0",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Force overwrite if the agent is already installed",
+			},
+		},
 	}
 }
 
@@ -55,16 +71,26 @@ func getAgentPullCommand() *cli.Command {
 func getAgentPushCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "push",
-		Usage:     "Push an agent to GitHub",
+		Usage:     "Push an agent to a git repository",
 		Action:    HandleAgentPush(),
 		ArgsUsage: "<repo-url>",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     "name",
 				Aliases:  []string{"n"},
-				Usage:    "Agent name",
+				Usage:    "Installed agent name or agent directory",
 				Required: true,
 			},
+			&cli.StringFlag{
+				Name:    "message",
+				Aliases: []string{"m"},
+				Usage:   "Commit message",
+				Value:   "Update agent",
+			},
+			&cli.StringFlag{
+				Name:  "tag",
+				Usage: "Create a git tag for this version",
+			},
 		},
 	}
 }
@@ -76,161 +102,84 @@ func getAgentNewCommand() *cli.Command {
 		Usage:     "Create a new agent",
 		Action:    HandleAgentNew(),
 		ArgsUsage: "<name>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "Directory to create the agent in (default: ./<name>)",
+			},
+			&cli.StringFlag{
+				Name:  "description",
+				Usage: "Agent description",
+			},
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: "Agent type: conversational, task, workflow, reactive or autonomous",
+				Value: "conversational",
+			},
+			&cli.StringFlag{
+				Name:  "provider",
+				Usage: "LLM provider of the agent model",
+				Value: "openai",
+			},
+			&cli.StringFlag{
+				Name:  "model",
+				Usage: "Model of the agent",
+				Value: "gpt-4o",
+			},
+			&cli.StringSliceFlag{
+				Name:    "tool",
+				Aliases: []string{"t"},
+				Usage:   "Tool dependency as 'name=user/repo[@version]' (repeatable)",
+			},
+			&cli.StringSliceFlag{
+				Name:    "prompt",
+				Aliases: []string{"p"},
+				Usage:   "System prompt dependency as 'name=user/repo[@version]' (repeatable)",
+			},
+		},
 	}
 }
 
 // HandleAgentList handles the agent list command.
 func HandleAgentList() cli.ActionFunc {
-	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
-		minStars := ctx.CLI.Int("stars")
-		
-		log.Info("Listing agents", 
-			zap.Int("min_stars", minStars))
-
-		// TODO: Implement agent service when available
-		// For now, return a placeholder message
-		log.Warn("Agent service not yet implemented")
-		
-		// Placeholder implementation
-		components := []interface{}{}
-
-		// Display results
-		if len(components) == 0 {
-			fmt.Printf("No agents found with minimum %d stars\n", minStars)
-			return nil
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		agentService, err := do.Invoke[database.AgentService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get agent service: %w", err)
 		}
 
-		fmt.Printf("Found %d agents:\n\n", len(components))
-		for _, comp := range components {
-			if compMap, ok := comp.(map[string]interface{}); ok {
-				displayComponentPlaceholder(compMap, "AGENT")
-			}
+		opts := database.ListAgentsOptions{}
+		if ctx.CLI.Bool("installed") {
+			installed := true
+			opts.IsInstalled = &installed
 		}
-		
-		return nil
-	})
-}
-
-// HandleAgentPull handles the agent pull command.
-func HandleAgentPull() cli.ActionFunc {
-	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
-		args := ctx.CLI.Args()
-		if args.Len() == 0 {
-			return fmt.Errorf("repository required: forge agent pull <user/repo[@version]>")
+		entries, err := agentService.ListAgentsForCLI(ctx.Context, opts)
+		if err != nil {
+			return err
 		}
-		
-		repo := args.First()
-		
-		// Parse repo and version
-		var version string
-		if strings.Contains(repo, "@") {
-			parts := strings.Split(repo, "@")
-			repo, version = parts[0], parts[1]
-		}
-		
-		// Auto-expand GitHub URLs if needed
-		if !strings.HasPrefix(repo, "github.com/") && !strings.HasPrefix(repo, "http") {
-			repo = "github.com/" + repo
-		}
-		
-		log.Info("Pulling agent", 
-			zap.String("repo", repo),
-			zap.String("version", version))
-
-		// TODO: Implement component service when available
-		// For now, return a placeholder message
-		log.Warn("Agent pull service not yet implemented")
-		
-		// Pull the agent using placeholder logic
-		fmt.Printf("Pulling agent from %s", repo)
-		if version != "" {
-			fmt.Printf("@%s", version)
-		}
-		fmt.Println("...")
-		fmt.Printf("(This is a placeholder - actual implementation pending)\n")
-
-		fmt.Printf("Agent successfully pulled from %s\n", repo)
-		
-		return nil
-	})
-}
 
-// HandleAgentPush handles the agent push command.
-func HandleAgentPush() cli.ActionFunc {
-	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
-		name := ctx.CLI.String("name")
-		
-		args := ctx.CLI.Args()
-		if args.Len() == 0 {
-			return fmt.Errorf("repository required: forge agent push --name %s <repo-url>", name)
-		}
-		
-		repo := args.First()
-		
-		// Parse repo and version
-		var version string
-		if strings.Contains(repo, "@") {
-			parts := strings.Split(repo, "@")
-			repo, version = parts[0], parts[1]
+		if ctx.CLI.Bool("json") {
+			return printJSON(entries)
 		}
-		
-		// Auto-expand GitHub URLs if needed
-		if !strings.HasPrefix(repo, "github.com/") && !strings.HasPrefix(repo, "http") {
-			repo = "github.com/" + repo
-		}
-		
-		log.Info("Pushing agent", 
-			zap.String("name", name),
-			zap.String("repo", repo),
-			zap.String("version", version))
-
-		// TODO: Implement component service when available
-		// For now, return a placeholder message
-		log.Warn("Agent push service not yet implemented")
-		
-		// Push the agent using placeholder logic
-		fmt.Printf("Pushing agent '%s' to %s", name, repo)
-		if version != "" {
-			fmt.Printf("@%s", version)
+		if len(entries) == 0 {
+			fmt.Println("No agents found")
+			fmt.Println("Create one with: forge agent new <name>")
+			return nil
 		}
-		fmt.Println("...")
-		fmt.Printf("(This is a placeholder - actual implementation pending)\n")
 
-		fmt.Printf("Agent '%s' successfully pushed to %s\n", name, repo)
-		
-		return nil
-	})
-}
-
-// HandleAgentNew handles the agent new command.
-func HandleAgentNew() cli.ActionFunc {
-	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
-		args := ctx.CLI.Args()
-		if args.Len() == 0 {
-			return fmt.Errorf("agent name required: forge agent new <name>")
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tVERSION\tTYPE\tMODEL\tDEPENDENCIES\tINSTALLED\tSOURCE")
+		for _, entry := range entries {
+			source := entry.Repository
+			if source == "" {
+				source = "local"
+			}
+			if entry.Error != "" {
+				source = "error: " + entry.Error
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%t\t%s\n",
+				entry.Name, entry.Version, entry.Type, entry.Model, entry.Dependencies, entry.Installed, source)
 		}
-		
-		name := args.First()
-		
-		log.Info("Creating new agent", 
-			zap.String("name", name))
-
-		// TODO: Implement component service when available
-		// For now, return a placeholder message
-		log.Warn("Agent creation service not yet implemented")
-		
-		// Create the agent using placeholder logic
-		fmt.Printf("Creating new agent '%s'...\n", name)
-		fmt.Printf("(This is a placeholder - actual implementation pending)\n")
-
-		fmt.Printf("Agent '%s' successfully created!\n", name)
-		fmt.Printf("Files created in ./%s/\n", name)
-		
-		return nil
+		return writer.Flush()
 	})
 }
-
-// displayComponentPlaceholder displays component information (placeholder implementation)
-func displayComponentPlaceholder(comp map[string]interface{}, componentType string) {
-	fmt.Printf("  %s: %v\n", componentType, comp)
-}
\ No newline at end of file
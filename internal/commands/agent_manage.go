@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/templates"
+)
+
+// HandleAgentPull handles the agent pull command.
+func HandleAgentPull() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		repo := ctx.CLI.Args().First()
+		if repo == "" {
+			return fmt.Errorf("repository required: forge agent pull <user/repo[@version]>")
+		}
+		version := ctx.CLI.String("version")
+		if strings.Contains(repo, "@") && version == "" {
+			repo, version, _ = strings.Cut(repo, "@")
+		}
+		force := ctx.CLI.Bool("force")
+
+		log.Info("Pulling agent",
+			zap.String("repo", repo),
+			zap.String("version", version),
+			zap.Bool("force", force))
+
+		agentService, err := do.Invoke[database.AgentService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get agent service: %w", err)
+		}
+
+		fmt.Printf("Pulling agent from %s", repo)
+		if version != "" {
+			fmt.Printf("@%s", version)
+		}
+		fmt.Println("...")
+
+		manifest, err := agentService.PullAgent(ctx.Context, repo, version, force)
+		if err != nil {
+			return fmt.Errorf("failed to pull agent: %w", err)
+		}
+
+		fmt.Printf("✓ Agent '%s' %s successfully pulled from %s\n", manifest.Metadata.Name, manifest.Metadata.Version, repo)
+		for _, tool := range manifest.Spec.Tools {
+			fmt.Printf("  tool   %s (%s)\n", tool.Name, tool.Source)
+		}
+		for _, prompt := range manifest.Spec.Prompts {
+			fmt.Printf("  prompt %s (%s)\n", prompt.Name, prompt.Source)
+		}
+		return nil
+	})
+}
+
+// HandleAgentPush handles the agent push command.
+func HandleAgentPush() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.String("name")
+		repo := ctx.CLI.Args().First()
+		if repo == "" {
+			return fmt.Errorf("repository required: forge agent push --name %s <repo-url>", name)
+		}
+		tag := ctx.CLI.String("tag")
+
+		dir, err := resolveAgentDir(ctx, name)
+		if err != nil {
+			return err
+		}
+		agentService, err := do.Invoke[database.AgentService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get agent service: %w", err)
+		}
+
+		fmt.Printf("Pushing agent '%s' to %s...\n", name, repo)
+		if err := agentService.PushAgent(ctx.Context, dir, repo, ctx.CLI.String("message"), tag); err != nil {
+			return fmt.Errorf("failed to push agent: %w", err)
+		}
+
+		fmt.Printf("✓ Agent '%s' successfully pushed to %s\n", name, repo)
+		if tag != "" {
+			fmt.Printf("✓ Tagged as %s\n", tag)
+		}
+		return nil
+	})
+}
+
+// HandleAgentNew handles the agent new command.
+func HandleAgentNew() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("agent name required: forge agent new <name>")
+		}
+		dir := ctx.CLI.String("dir")
+		if dir == "" {
+			dir = name
+		}
+
+		data := templates.AgentTemplateData{
+			Name:        name,
+			Description: ctx.CLI.String("description"),
+			Type:        ctx.CLI.String("type"),
+			Provider:    ctx.CLI.String("provider"),
+			Model:       ctx.CLI.String("model"),
+		}
+		for _, spec := range ctx.CLI.StringSlice("tool") {
+			dependency, source, err := parseDependencyFlag(spec)
+			if err != nil {
+				return err
+			}
+			data.Tools = append(data.Tools, schema.AgentTool{Name: dependency, Type: "tool", Source: source, Required: true})
+		}
+		for _, spec := range ctx.CLI.StringSlice("prompt") {
+			dependency, source, err := parseDependencyFlag(spec)
+			if err != nil {
+				return err
+			}
+			data.Prompts = append(data.Prompts, schema.AgentPrompt{Name: dependency, Type: "system", Source: source})
+		}
+
+		log.Info("Creating new agent",
+			zap.String("name", name),
+			zap.String("dir", dir))
+
+		agentService, err := do.Invoke[database.AgentService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get agent service: %w", err)
+		}
+		if err := agentService.CreateAgentFiles(ctx.Context, dir, data); err != nil {
+			return err
+		}
+
+		fmt.Printf("Agent '%s' created in %s\n", name, dir)
+		fmt.Printf("Publish it with: forge agent push --name %s --tag v0.1.0 <user/repo>\n", dir)
+		return nil
+	})
+}
+
+// parseDependencyFlag splits a 'name=source' dependency flag.
+func parseDependencyFlag(spec string) (string, string, error) {
+	name, source, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || source == "" {
+		return "", "", fmt.Errorf("invalid dependency %q, expected name=user/repo[@version]", spec)
+	}
+	return name, source, nil
+}
+
+// resolveAgentDir returns the directory of an agent given as a path to an agent
+// directory or as the name of an installed agent.
+func resolveAgentDir(ctx *startup.Context, nameOrPath string) (string, error) {
+	if _, err := os.Stat(filepath.Join(nameOrPath, schema.ManifestFileName)); err == nil {
+		return nameOrPath, nil
+	}
+
+	cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+	dir := filepath.Join(cfg.GetAgentsDir(), nameOrPath)
+	if _, err := os.Stat(filepath.Join(dir, schema.ManifestFileName)); err != nil {
+		return "", fmt.Errorf("agent %s is neither installed nor an agent directory", nameOrPath)
+	}
+	return dir, nil
+}
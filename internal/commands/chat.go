@@ -1,8 +1,23 @@
-// Package commands provides command-line interface handlers for the MCP Planner application.
+// Package commands provides command-line interface handlers for AgentForge.
 package commands
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/samber/do"
+	"github.com/tmc/langchaingo/llms"
 	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/types"
 )
 
 // GetChatCommand returns the chat command configuration.
@@ -10,241 +25,329 @@ func GetChatCommand() *cli.Command {
 	return &cli.Command{
 		Name:    "chat",
 		Aliases: []string{"c"},
-		Usage:   "Start interactive chat session (not yet implemented)",
-		Action: func(c *cli.Context) error {
-			log.Info("Chat command not yet implemented")
-			return nil
+		Usage:   "Start an interactive chat session with tools, MCP resources and prompts",
+		Action:  HandleChat(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "agent",
+				Aliases: []string{"a"},
+				Usage:   "Agent to chat with (defaults to the default agent)",
+			},
 		},
 	}
 }
-// 	return &cli.Command{
-// 		Name:    "chat",
-// 		Aliases: []string{"c"},
-// 		Usage:   "Start an interactive chat session with the AI agent",
-// 		Action:  HandleChat(),
-// 		Flags: []cli.Flag{
-// 			&cli.BoolFlag{
-// 				Name:    "clear",
-// 				Aliases: []string{"cl"},
-// 				Usage:   "Clear message history before starting",
-// 			},
-// 		},
-// 	}
-// }
-
-// // HandleChat creates a new chat command handler.
-// func HandleChat() cli.ActionFunc {
-// 	return func(c *cli.Context) error {
-// 		ctx := c.Context
-
-// 		sc, err := shared.StartupCommon(c)
-// 		if err != nil {
-// 			return err
-// 		}
-
-// 		defer sc.Cleanup()
-
-// 		h := &chatHandler{}
-// 		// Create session
-// 		session, err := h.createSession(ctx, sc)
-// 		if err != nil {
-// 			return err
-// 		}
-
-// 		// Clear history if requested
-// 		if c.Bool("clear") {
-// 			session.ClearMessageHistory()
-// 			log.Info("Message history cleared by user request")
-// 			log.Info("Message history cleared.")
-// 		}
-
-// 		// Start interactive chat
-// 		return h.runInteractiveChat(ctx, session)
-// 	}
-// }
-
-// // createSession creates and configures an agent session.
-// func (h *chatHandler) createSession(ctx context.Context, startupContext *shared.StartupContext) (types.AgentSession, error) {
-// 	// Get agent provider
-// 	agentProvider := do.MustInvoke[types.AgentProvider](startupContext.Injector)
-// 	cfg := startupContext.Config
-
-// 	// Create a session with the default agent
-// 	agent, err := agentProvider.GetAgent("planner")
-// 	if err != nil {
-// 		if errors.IsNotFound(err) {
-// 			return nil, fmt.Errorf("default agent 'planner' not found: %w", err)
-// 		}
-// 		return nil, fmt.Errorf("failed to get agent: %w", err)
-// 	}
-
-// 	// Get dependencies for session creation
-// 	toolProvider := do.MustInvoke[types.ToolProvider](startupContext.Injector)
-
-// 	// Create session using the session factory
-// 	sessionFactory := do.MustInvoke[types.SessionFactory](startupContext.Injector)
-// 	agentSession, err := sessionFactory.CreateSession(ctx, types.SessionOptions{
-// 		Config:        cfg,
-// 		Agent:         agent,
-// 		ToolProvider:  toolProvider,
-// 		AgentProvider: agentProvider,
-// 		AgentType:     "planner", // hint for potential optimizations
-// 	})
-// 	if err != nil {
-// 		return nil, fmt.Errorf("failed to create agent session: %w", err)
-// 	}
-
-// 	return agentSession, nil
-// }
-
-// // printWelcomeMessage displays the initial chat interface information.
-// func (h *chatHandler) printWelcomeMessage(session types.AgentSession) {
-// 	log.Info("MCP Planner Chat Interface")
-// 	log.Info("Using agent", zap.String("agent_name", session.GetAgent().GetName()))
-// 	log.Info("Type 'exit', 'quit', or 'bye' to end the session.")
-// 	log.Info("Type 'clear' to clear the message history.")
-// 	log.Info("Type 'history' to view the current message history.")
-// 	log.Info("Type 'switch to <agent>' to switch agents.")
-// 	log.Info("Type 'help' for more commands.")
-// 	log.Info("---")
-// }
-
-// // handleSpecialCommands processes special chat commands.
-// // Returns true if the command was handled, false otherwise.
-// func (h *chatHandler) handleSpecialCommands(ctx context.Context, input string, session types.AgentSession) (bool, error) {
-// 	lowerInput := strings.ToLower(input)
-
-// 	switch lowerInput {
-// 	case "exit", "quit", "bye":
-// 		log.Info("Goodbye!")
-// 		os.Exit(0)
-// 		return true, nil
-// 	case "clear":
-// 		session.ClearMessageHistory()
-// 		log.Info("Message history cleared by user command")
-// 		log.Info("Message history cleared.")
-// 		return true, nil
-// 	case "history":
-// 		h.printMessageHistory(session)
-// 		return true, nil
-// 	case "help":
-// 		h.printChatHelp()
-// 		return true, nil
-// 	}
-
-// 	// Handle agent switching commands
-// 	if strings.HasPrefix(lowerInput, "switch to ") {
-// 		agentName := strings.TrimSpace(input[10:])
-// 		return true, h.handleAgentSwitch(ctx, session, agentName)
-// 	}
-
-// 	return false, nil
-// }
-
-// // handleAgentSwitch processes agent switching commands.
-// func (h *chatHandler) handleAgentSwitch(ctx context.Context, session types.AgentSession, agentName string) error {
-// 	if err := session.SwitchAgent(ctx, agentName); err != nil {
-// 		if errors.IsNotFound(err) {
-// 			log.Info("Agent not found", zap.String("agent_name", agentName),
-// 				zap.String("hint", "Available agents can be listed with 'agents' command."))
-// 		} else {
-// 			log.Error("Failed to switch agent", zap.Error(err), zap.String("agent_name", agentName))
-// 			log.Error("Error switching agent", zap.String("agent_name", agentName), zap.Error(err))
-// 		}
-// 		return err
-// 	}
-
-// 	log.Info("Switched to agent", zap.String("agent_name", session.GetAgent().GetName()))
-// 	return nil
-// }
-
-// // runInteractiveChat handles the interactive chat loop.
-// func (h *chatHandler) runInteractiveChat(ctx context.Context, session types.AgentSession) error {
-// 	h.printWelcomeMessage(session)
-
-// 	scanner := bufio.NewScanner(os.Stdin)
-
-// 	for {
-// 		log.Info("You: ", zap.String("prompt", "true"))
-// 		if !scanner.Scan() {
-// 			break
-// 		}
-
-// 		input := strings.TrimSpace(scanner.Text())
-// 		if input == "" {
-// 			continue
-// 		}
-
-// 		// Handle special commands
-// 		if handled, err := h.handleSpecialCommands(ctx, input, session); handled {
-// 			if err != nil {
-// 				log.Error("Error during command handling", zap.Error(err))
-// 			}
-// 			continue
-// 		}
-
-// 		// Process user message
-// 		log.Info("Assistant: ", zap.String("prompt", "true"))
-// 		if err := session.Chat(ctx, input); err != nil {
-// 			h.handleChatError(err, input)
-// 			continue
-// 		}
-// 		log.Info("")
-// 	}
-
-// 	if err := scanner.Err(); err != nil {
-// 		return fmt.Errorf("error reading input: %w", err)
-// 	}
-
-// 	return nil
-
-// }
-
-// // handleChatError handles different types of chat errors appropriately.
-// func (h *chatHandler) handleChatError(err error, userInput string) {
-// 	if errors.IsConfiguration(err) {
-// 		log.Error("Configuration error during chat", zap.Error(err))
-// 		log.Error("Configuration error", zap.Error(err))
-// 	} else if errors.IsProvider(err) {
-// 		log.Error("Provider error during chat", zap.Error(err))
-// 		log.Error("Service error", zap.Error(err))
-// 	} else {
-// 		log.Error("Chat error occurred", zap.Error(err), zap.String("user_input", userInput))
-// 		log.Error("Chat error", zap.Error(err))
-// 	}
-// }
-
-// // printMessageHistory displays the current message history.
-// func (h *chatHandler) printMessageHistory(session types.AgentSession) {
-// 	history := session.GetMessageHistory()
-// 	log.Info("Message History", zap.Int("message_count", len(history)))
-
-// 	for i, msg := range history {
-// 		role := string(msg.Role)
-// 		if len(msg.Parts) > 0 {
-// 			if textPart, ok := msg.Parts[0].(interface{ GetText() string }); ok {
-// 				content := textPart.GetText()
-// 				if len(content) > 100 {
-// 					content = content[:100] + "..."
-// 				}
-// 				log.Info("Message", zap.Int("index", i+1), zap.String("role", role), zap.String("content", content))
-// 			}
-// 		}
-// 	}
-// 	log.Info("---")
-// }
-
-// // printChatHelp displays available chat commands.
-// func (h *chatHandler) printChatHelp() {
-// 	log.Info("Available Commands:")
-// 	log.Info("  exit, quit, bye - End the chat session")
-// 	log.Info("  clear           - Clear message history")
-// 	log.Info("  history         - View current message history")
-// 	log.Info("  help            - Show this help message")
-// 	log.Info("")
-// 	log.Info("Example queries:")
-// 	log.Info("  - What's the weather like in Chicago?")
-// 	log.Info("  - Create a task to review project documentation")
-// 	log.Info("  - Help me plan a team meeting for next week")
-// 	log.Info("---")
-// }
+
+// HandleChat creates a new chat command handler.
+func HandleChat() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		defer serveMCPControl(ctx)()
+
+		h := &chatHandler{
+			injector: ctx.DIContainer,
+			out:      os.Stdout,
+		}
+
+		h.session = h.createSession(ctx.Context, ctx.CLI.String("agent"))
+		return h.runInteractiveChat(ctx.Context, os.Stdin)
+	})
+}
+
+// chatHandler runs the interactive chat loop.
+type chatHandler struct {
+	injector *do.Injector
+	session  types.AgentSession
+	out      io.Writer
+}
+
+// createSession creates an agent session if an agent and LLM backend are available.
+// A missing backend is not fatal: resources and prompts can still be browsed.
+func (h *chatHandler) createSession(ctx context.Context, agentName string) types.AgentSession {
+	agentProvider, err := do.Invoke[types.AgentProvider](h.injector)
+	if err != nil {
+		log.Warn("No agent provider available, chat messages are disabled", zap.Error(err))
+		return nil
+	}
+
+	var agent types.Agent
+	if agentName != "" {
+		agent, err = agentProvider.GetAgent(agentName)
+	} else {
+		agent, err = agentProvider.GetDefaultAgent()
+	}
+	if err != nil {
+		log.Warn("Failed to get agent, chat messages are disabled", zap.Error(err))
+		return nil
+	}
+
+	sessionFactory, err := do.Invoke[types.SessionFactory](h.injector)
+	if err != nil {
+		log.Warn("No session factory available, chat messages are disabled", zap.Error(err))
+		return nil
+	}
+
+	session, err := sessionFactory.CreateSession(ctx, types.SessionOptions{
+		Config:        do.MustInvoke[*config.Config](h.injector),
+		Agent:         agent,
+		ToolProvider:  do.MustInvoke[types.ToolProvider](h.injector),
+		AgentProvider: agentProvider,
+	})
+	if err != nil {
+		log.Warn("Failed to create agent session, chat messages are disabled", zap.Error(err))
+		return nil
+	}
+
+	return session
+}
+
+// runInteractiveChat handles the interactive chat loop.
+func (h *chatHandler) runInteractiveChat(ctx context.Context, in io.Reader) error {
+	h.printWelcomeMessage()
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(h.out, "You: ")
+		if !scanner.Scan() {
+			break
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+
+		if strings.HasPrefix(input, "/") {
+			exit, err := h.handleSpecialCommand(ctx, input)
+			if err != nil {
+				fmt.Fprintf(h.out, "Error: %v\n", err)
+			}
+			if exit {
+				return nil
+			}
+			continue
+		}
+
+		if err := h.handleMessage(ctx, input); err != nil {
+			fmt.Fprintf(h.out, "Error: %v\n", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading input: %w", err)
+	}
+	return nil
+}
+
+// printWelcomeMessage displays the initial chat interface information.
+func (h *chatHandler) printWelcomeMessage() {
+	fmt.Fprintln(h.out, "AgentForge Chat")
+	if h.session != nil {
+		fmt.Fprintf(h.out, "Using agent: %s\n", h.session.GetAgent().GetName())
+	} else {
+		fmt.Fprintln(h.out, "No agent available - only slash commands are supported.")
+	}
+	fmt.Fprintln(h.out, "Type /help for available commands, /exit to quit.")
+	fmt.Fprintln(h.out, "---")
+}
+
+// handleSpecialCommand processes slash commands. It returns true when the chat should end.
+func (h *chatHandler) handleSpecialCommand(ctx context.Context, input string) (bool, error) {
+	fields := strings.Fields(input)
+	command, args := strings.ToLower(fields[0]), fields[1:]
+
+	switch command {
+	case "/exit", "/quit", "/bye":
+		fmt.Fprintln(h.out, "Goodbye!")
+		return true, nil
+	case "/help":
+		h.printChatHelp()
+	case "/clear":
+		if h.session != nil {
+			h.session.ClearMessageHistory()
+		}
+		fmt.Fprintln(h.out, "Message history cleared.")
+	case "/tools":
+		return false, h.listTools()
+	case "/resources":
+		return false, h.listResources(ctx)
+	case "/read":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: /read <uri>")
+		}
+		return false, h.readResource(ctx, args[0])
+	case "/prompts":
+		return false, h.listPrompts()
+	case "/prompt":
+		if len(args) == 0 {
+			return false, fmt.Errorf("usage: /prompt <name> [key=value ...]")
+		}
+		return false, h.usePrompt(ctx, args[0], args[1:])
+	default:
+		return false, fmt.Errorf("unknown command %s, type /help for available commands", command)
+	}
+
+	return false, nil
+}
+
+// handleMessage sends a user message to the agent and prints the reply.
+func (h *chatHandler) handleMessage(ctx context.Context, input string) error {
+	if h.session == nil {
+		return fmt.Errorf("no agent session available, configure an agent and LLM provider to chat")
+	}
+
+	if err := h.session.Chat(ctx, input); err != nil {
+		return fmt.Errorf("chat failed: %w", err)
+	}
+
+	history := h.session.GetMessageHistory()
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role != llms.ChatMessageTypeAI {
+			continue
+		}
+		for _, part := range history[i].Parts {
+			if text, ok := part.(llms.TextContent); ok && text.Text != "" {
+				fmt.Fprintf(h.out, "Assistant: %s\n", text.Text)
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// listTools prints all available tools.
+func (h *chatHandler) listTools() error {
+	toolProvider, err := do.Invoke[types.ToolProvider](h.injector)
+	if err != nil {
+		return fmt.Errorf("failed to get tool provider: %w", err)
+	}
+
+	names := toolProvider.GetToolNames()
+	sort.Strings(names)
+	fmt.Fprintf(h.out, "Found %d tools:\n", len(names))
+	for _, name := range names {
+		fmt.Fprintf(h.out, "  %s\n", name)
+	}
+	return nil
+}
+
+// listResources prints the resources of all running MCP servers.
+func (h *chatHandler) listResources(ctx context.Context) error {
+	resourceProvider, err := do.Invoke[types.ResourceProvider](h.injector)
+	if err != nil {
+		return fmt.Errorf("failed to get resource provider: %w", err)
+	}
+
+	resources, err := resourceProvider.ListResources(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	if len(resources) == 0 {
+		fmt.Fprintln(h.out, "No MCP resources available.")
+		return nil
+	}
+
+	fmt.Fprintf(h.out, "Found %d resources:\n", len(resources))
+	for _, resource := range resources {
+		fmt.Fprintf(h.out, "  [%s] %s (%s)\n", resource.Server, resource.URI, resource.Name)
+		if resource.Description != "" {
+			fmt.Fprintf(h.out, "      %s\n", resource.Description)
+		}
+	}
+	return nil
+}
+
+// readResource prints the contents of an MCP resource.
+func (h *chatHandler) readResource(ctx context.Context, uri string) error {
+	resourceProvider, err := do.Invoke[types.ResourceProvider](h.injector)
+	if err != nil {
+		return fmt.Errorf("failed to get resource provider: %w", err)
+	}
+
+	contents, err := resourceProvider.ReadResource(ctx, uri)
+	if err != nil {
+		return err
+	}
+
+	for _, content := range contents {
+		if content.Text != "" {
+			fmt.Fprintln(h.out, content.Text)
+			continue
+		}
+		fmt.Fprintf(h.out, "<binary content, %s, %d bytes base64>\n", content.MIMEType, len(content.Blob))
+	}
+	return nil
+}
+
+// listPrompts prints all local and MCP prompts.
+func (h *chatHandler) listPrompts() error {
+	promptProvider, err := do.Invoke[types.PromptProvider](h.injector)
+	if err != nil {
+		return fmt.Errorf("failed to get prompt provider: %w", err)
+	}
+
+	prompts := promptProvider.GetPrompts()
+	if len(prompts) == 0 {
+		fmt.Fprintln(h.out, "No prompts available.")
+		return nil
+	}
+
+	names := make([]string, 0, len(prompts))
+	for name := range prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(h.out, "Found %d prompts:\n", len(names))
+	for _, name := range names {
+		fmt.Fprintf(h.out, "  %s - %s\n", name, prompts[name].GetDescription())
+	}
+	return nil
+}
+
+// usePrompt renders a prompt with key=value arguments and sends it as a message.
+func (h *chatHandler) usePrompt(ctx context.Context, name string, args []string) error {
+	promptProvider, err := do.Invoke[types.PromptProvider](h.injector)
+	if err != nil {
+		return fmt.Errorf("failed to get prompt provider: %w", err)
+	}
+
+	prompt, exists := promptProvider.GetPrompts()[name]
+	if !exists {
+		return fmt.Errorf("prompt %s not found", name)
+	}
+
+	variables := make(map[string]any, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid prompt argument %q, expected key=value", arg)
+		}
+		variables[key] = value
+	}
+
+	text, err := prompt.Render(variables)
+	if err != nil {
+		return fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+
+	if h.session == nil {
+		fmt.Fprintln(h.out, text)
+		return nil
+	}
+	return h.handleMessage(ctx, text)
+}
+
+// printChatHelp displays available chat commands.
+func (h *chatHandler) printChatHelp() {
+	fmt.Fprintln(h.out, "Available Commands:")
+	fmt.Fprintln(h.out, "  /exit, /quit, /bye           - End the chat session")
+	fmt.Fprintln(h.out, "  /clear                       - Clear message history")
+	fmt.Fprintln(h.out, "  /tools                       - List available tools")
+	fmt.Fprintln(h.out, "  /resources                   - List MCP resources")
+	fmt.Fprintln(h.out, "  /read <uri>                  - Show the content of an MCP resource")
+	fmt.Fprintln(h.out, "  /prompts                     - List local and MCP prompts")
+	fmt.Fprintln(h.out, "  /prompt <name> [key=value]   - Render a prompt and send it to the agent")
+	fmt.Fprintln(h.out, "  /help                        - Show this help message")
+	fmt.Fprintln(h.out, "---")
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/startup"
+)
+
+// workspaceFlags are the flags naming the workspace manifest and lockfile.
+func workspaceFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "workspace",
+			Aliases: []string{"w"},
+			Usage:   "Workspace manifest listing the required tools, prompts and agents",
+			Value:   resolver.WorkspaceFileName,
+		},
+		&cli.StringFlag{
+			Name:  "lockfile",
+			Usage: "Lockfile pinning the resolved components",
+			Value: resolver.LockfileName,
+		},
+	}
+}
+
+// GetInstallCommand returns the install command configuration.
+func GetInstallCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "install",
+		Usage:  "Install the components locked in forge.lock, locking forge.yaml first if needed",
+		Action: HandleInstall(),
+		Flags: append(workspaceFlags(),
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Reinstall components already installed at their locked commit",
+			},
+		),
+	}
+}
+
+// HandleInstall handles the install command.
+func HandleInstall() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		lockfile, err := workspaceLockfile(ctx, ctx.CLI.String("workspace"), ctx.CLI.String("lockfile"))
+		if err != nil {
+			return err
+		}
+		return installLockfile(ctx, lockfile, ctx.CLI.Bool("force"))
+	})
+}
+
+// workspaceLockfile returns the lockfile at lockPath after checking it against the
+// workspace manifest. Without a lockfile the workspace is resolved and locked.
+func workspaceLockfile(ctx *startup.Context, workspacePath, lockPath string) (*resolver.Lockfile, error) {
+	if _, err := os.Stat(lockPath); err == nil {
+		lockfile, err := resolver.ReadLockfile(lockPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(workspacePath); err == nil {
+			workspace, err := resolver.ReadWorkspace(workspacePath)
+			if err != nil {
+				return nil, err
+			}
+			if err := lockfile.Check(workspace.Requirements()); err != nil {
+				return nil, fmt.Errorf("%s is out of date with %s: %w, run forge lock or forge upgrade", lockPath, workspacePath, err)
+			}
+		}
+		return lockfile, nil
+	}
+
+	workspace, err := resolver.ReadWorkspace(workspacePath)
+	if err != nil {
+		return nil, err
+	}
+	dependencyResolver, err := do.Invoke[resolver.Resolver](ctx.DIContainer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency resolver: %w", err)
+	}
+	lockfile, err := dependencyResolver.Resolve(ctx.Context, workspace.Requirements())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve dependencies:\n%w", err)
+	}
+	if err := lockfile.Write(lockPath); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Locked %d components in %s\n", len(lockfile.Components), lockPath)
+	return lockfile, nil
+}
+
+// installLockfile installs the components of a lockfile and prints the results.
+func installLockfile(ctx *startup.Context, lockfile *resolver.Lockfile, force bool) error {
+	installService, err := do.Invoke[database.InstallService](ctx.DIContainer)
+	if err != nil {
+		return fmt.Errorf("failed to get install service: %w", err)
+	}
+	return installComponents(ctx.Context, os.Stdout, installService, lockfile, force)
+}
+
+// installComponents installs the components of a lockfile with installService and
+// prints the results to out.
+func installComponents(ctx context.Context, out io.Writer, installService database.InstallService, lockfile *resolver.Lockfile, force bool) error {
+	results, err := installService.Install(ctx, lockfile, force)
+	installed := 0
+	if len(results) > 0 {
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "COMPONENT\tVERSION\tCOMMIT\tSTATUS")
+		for _, result := range results {
+			status := "up to date"
+			if result.Installed {
+				status = "installed"
+				installed++
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+				result.Component.Key(), result.Component.Version, shortCommit(result.Component.Commit), status)
+		}
+		writer.Flush()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to install components: %w", err)
+	}
+
+	fmt.Fprintf(out, "✓ %d installed, %d up to date\n", installed, len(results)-installed)
+	return nil
+}
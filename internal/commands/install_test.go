@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// fakeInstallService installs every component of a lockfile but the failing one.
+type fakeInstallService struct {
+	failing string
+	current map[string]bool
+}
+
+func (s *fakeInstallService) Install(ctx context.Context, lockfile *resolver.Lockfile, force bool) ([]database.InstallResult, error) {
+	var results []database.InstallResult
+	for _, component := range lockfile.Components {
+		if component.Name == s.failing {
+			return results, errors.New("failed to install " + component.Key())
+		}
+		installed := force || !s.current[component.Key()]
+		results = append(results, database.InstallResult{Component: component, Installed: installed})
+	}
+	return results, nil
+}
+
+// newTestLockfile locks the tools search and notes at version.
+func newTestLockfile(version string) *resolver.Lockfile {
+	return &resolver.Lockfile{Components: []resolver.LockedComponent{
+		{Kind: schema.KindTool, Name: "notes", Version: version, Repository: "acme/notes", Commit: "0123456789abcdef"},
+		{Kind: schema.KindTool, Name: "search", Version: version, Repository: "acme/search", Commit: "fedcba9876543210"},
+	}}
+}
+
+func TestInstallComponents(t *testing.T) {
+	var out bytes.Buffer
+	service := &fakeInstallService{current: map[string]bool{"tool/notes": true}}
+
+	require.NoError(t, installComponents(context.Background(), &out, service, newTestLockfile("1.0.0"), false))
+	assert.Contains(t, out.String(), "tool/notes   1.0.0    0123456789ab  up to date")
+	assert.Contains(t, out.String(), "tool/search  1.0.0    fedcba987654  installed")
+	assert.Contains(t, out.String(), "✓ 1 installed, 1 up to date")
+
+	out.Reset()
+	service.failing = "search"
+	err := installComponents(context.Background(), &out, service, newTestLockfile("1.0.0"), false)
+	assert.EqualError(t, err, "failed to install components: failed to install tool/search")
+	assert.Contains(t, out.String(), "tool/notes", "the results before the failure are printed")
+	assert.NotContains(t, out.String(), "✓")
+}
+
+func TestUpgradeLockfile_WritesLockfileAfterInstall(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), resolver.LockfileName)
+	require.NoError(t, newTestLockfile("1.0.0").Write(lockPath))
+
+	var out bytes.Buffer
+	err := upgradeLockfile(context.Background(), &out, &fakeInstallService{failing: "search"}, newTestLockfile("1.1.0"), lockPath)
+	assert.ErrorContains(t, err, lockPath+" was left unchanged")
+	lockfile, err := resolver.ReadLockfile(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", lockfile.Components[0].Version, "a failed upgrade keeps the previous lockfile")
+
+	require.NoError(t, upgradeLockfile(context.Background(), &out, &fakeInstallService{}, newTestLockfile("1.1.0"), lockPath))
+	lockfile, err = resolver.ReadLockfile(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", lockfile.Components[0].Version)
+}
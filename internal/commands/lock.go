@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/startup"
+)
+
+// GetLockCommand returns the lock command configuration.
+func GetLockCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "lock",
+		Usage:  "Resolve the workspace dependencies and write them to forge.lock",
+		Action: HandleLock(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "workspace",
+				Aliases: []string{"w"},
+				Usage:   "Workspace manifest listing the required tools, prompts and agents",
+				Value:   resolver.WorkspaceFileName,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Lockfile to write",
+				Value:   resolver.LockfileName,
+			},
+		},
+	}
+}
+
+// HandleLock handles the lock command.
+func HandleLock() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		workspacePath := ctx.CLI.String("workspace")
+		workspace, err := resolver.ReadWorkspace(workspacePath)
+		if err != nil {
+			return err
+		}
+
+		dependencyResolver, err := do.Invoke[resolver.Resolver](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get dependency resolver: %w", err)
+		}
+
+		log.Info("Resolving workspace dependencies",
+			zap.String("workspace", workspacePath),
+			zap.Int("dependencies", len(workspace.Dependencies)))
+
+		lockfile, err := dependencyResolver.Resolve(ctx.Context, workspace.Requirements())
+		if err != nil {
+			return fmt.Errorf("failed to resolve dependencies:\n%w", err)
+		}
+
+		output := ctx.CLI.String("output")
+		if err := lockfile.Write(output); err != nil {
+			return err
+		}
+
+		printLockedComponents(lockfile)
+		fmt.Printf("✓ Locked %d components in %s\n", len(lockfile.Components), output)
+		return nil
+	})
+}
+
+// printLockedComponents prints the components of a lockfile as a table.
+func printLockedComponents(lockfile *resolver.Lockfile) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "COMPONENT\tVERSION\tREF\tCOMMIT\tREQUIREMENTS")
+	for _, component := range lockfile.Components {
+		ref := component.Ref
+		if ref == "" {
+			ref = "default branch"
+		}
+		requirements := strings.Join(component.Requirements, ", ")
+		if requirements == "" {
+			requirements = "*"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			component.Key(), component.Version, ref, shortCommit(component.Commit), requirements)
+	}
+	writer.Flush()
+}
+
+// shortCommit abbreviates a commit hash for display.
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
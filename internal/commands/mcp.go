@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/mcpserver"
+	"github.com/denkhaus/agentforge/internal/prompts"
+	"github.com/denkhaus/agentforge/internal/signals"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// GetMCPCommand returns the MCP command configuration.
+func GetMCPCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "mcp",
+		Usage: "Model Context Protocol integration",
+		Subcommands: []*cli.Command{
+			getMCPServeCommand(),
+			getMCPListCommand(),
+			getMCPStatusCommand(),
+			getMCPRestartCommand(),
+			getMCPLogsCommand(),
+		},
+	}
+}
+
+// getMCPServeCommand returns the mcp serve subcommand.
+func getMCPServeCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "serve",
+		Usage:  "Serve forge tools, prompts and agents as an MCP server",
+		Action: HandleMCPServe(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "transport",
+				Aliases: []string{"t"},
+				Value:   "stdio",
+				Usage:   "Transport to serve on (stdio, http)",
+			},
+			&cli.StringFlag{
+				Name:  "addr",
+				Value: ":8090",
+				Usage: "Listen address for the http transport",
+			},
+			&cli.BoolFlag{
+				Name:  "no-agents",
+				Usage: "Do not expose agents as MCP tools",
+			},
+		},
+	}
+}
+
+// HandleMCPServe handles the mcp serve command.
+func HandleMCPServe() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		transport := ctx.CLI.String("transport")
+		if transport != "stdio" && transport != "http" {
+			return fmt.Errorf("unsupported transport %q, use stdio or http", transport)
+		}
+
+		opts, err := buildMCPServerOptions(ctx)
+		if err != nil {
+			return err
+		}
+		if ctx.CLI.Bool("no-agents") {
+			opts.AgentProvider = nil
+		}
+
+		server, err := mcpserver.NewServer(opts)
+		if err != nil {
+			return fmt.Errorf("failed to create MCP server: %w", err)
+		}
+
+		defer serveMCPControl(ctx)()
+
+		serveCtx, cancel := signals.WithInterruptContext(ctx.Context)
+		defer cancel()
+
+		if transport == "http" {
+			return server.ServeHTTP(serveCtx, ctx.CLI.String("addr"))
+		}
+		return server.ServeStdio(serveCtx)
+	})
+}
+
+// buildMCPServerOptions resolves the served components from the DI container.
+func buildMCPServerOptions(ctx *startup.Context) (mcpserver.Options, error) {
+	toolProvider, err := do.InvokeNamed[types.ToolProvider](ctx.DIContainer, "decoratedToolProvider")
+	if err != nil {
+		return mcpserver.Options{}, fmt.Errorf("failed to get tool provider: %w", err)
+	}
+
+	promptProvider, err := do.Invoke[types.PromptProvider](ctx.DIContainer)
+	if err != nil {
+		return mcpserver.Options{}, fmt.Errorf("failed to get prompt provider: %w", err)
+	}
+
+	opts := mcpserver.Options{
+		Name:           "agentforge",
+		Version:        ctx.CLI.App.Version,
+		ToolProvider:   toolProvider,
+		PromptProvider: promptProvider,
+		Config:         do.MustInvoke[*config.Config](ctx.DIContainer),
+	}
+
+	if promptService, err := do.Invoke[prompts.PromptService](ctx.DIContainer); err == nil {
+		opts.PromptService = promptService
+	}
+
+	if healthMonitor, err := do.Invoke[types.ToolHealthMonitor](ctx.DIContainer); err == nil {
+		opts.HealthMonitor = healthMonitor
+	}
+
+	agentProvider, err := do.Invoke[types.AgentProvider](ctx.DIContainer)
+	if err != nil {
+		log.Info("No agent provider available, agents are not served", zap.Error(err))
+		return opts, nil
+	}
+
+	sessionFactory, err := do.Invoke[types.SessionFactory](ctx.DIContainer)
+	if err != nil {
+		log.Info("No session factory available, agents are not served", zap.Error(err))
+		return opts, nil
+	}
+
+	opts.AgentProvider = agentProvider
+	opts.SessionFactory = sessionFactory
+	return opts, nil
+}
@@ -0,0 +1,325 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/providers"
+	"github.com/denkhaus/agentforge/internal/signals"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// getMCPListCommand returns the mcp list subcommand.
+func getMCPListCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "list",
+		Aliases: []string{"ls"},
+		Usage:   "List the MCP servers of the running forge process, or start them to list them",
+		Action:  HandleMCPList(),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+	}
+}
+
+// getMCPStatusCommand returns the mcp status subcommand.
+func getMCPStatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Usage:     "Health-check an MCP server of the running forge process and show its details",
+		ArgsUsage: "<server>",
+		Action:    HandleMCPStatus(),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+	}
+}
+
+// getMCPRestartCommand returns the mcp restart subcommand.
+func getMCPRestartCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "restart",
+		Usage:     "Restart an MCP server of a running forge chat or forge mcp serve",
+		ArgsUsage: "<server>",
+		Description: "Servers are restarted in the forge process supervising them, reached over its " +
+			"control socket. Without such a process there is nothing to restart.",
+		Action: HandleMCPRestart(),
+	}
+}
+
+// getMCPLogsCommand returns the mcp logs subcommand.
+func getMCPLogsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "logs",
+		Usage:     "Show the captured stderr of an MCP server",
+		ArgsUsage: "<server>",
+		Action:    HandleMCPLogs(),
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "lines",
+				Aliases: []string{"n"},
+				Value:   50,
+				Usage:   "Number of lines to show (0 for all)",
+			},
+			&cli.BoolFlag{
+				Name:    "follow",
+				Aliases: []string{"f"},
+				Usage:   "Keep printing new log lines",
+			},
+		},
+	}
+}
+
+// HandleMCPList handles the mcp list command.
+func HandleMCPList() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		controller, err := getMCPController(ctx, true)
+		if err != nil {
+			return err
+		}
+
+		servers, err := controller.ListServers(ctx.Context)
+		if err != nil {
+			return err
+		}
+		if ctx.CLI.Bool("json") {
+			return printJSON(servers)
+		}
+
+		if len(servers) == 0 {
+			fmt.Println("No MCP servers configured")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tSTATE\tPID\tTRANSPORT\tTOOLS\tSTARTED")
+		for _, server := range servers {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%s\n",
+				server.Name, server.State, formatPID(server.PID), server.Transport,
+				server.ToolCount, formatTime(server.StartedAt))
+		}
+		return writer.Flush()
+	})
+}
+
+// HandleMCPStatus handles the mcp status command.
+func HandleMCPStatus() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("server name is required")
+		}
+
+		controller, err := getMCPController(ctx, true)
+		if err != nil {
+			return err
+		}
+
+		info, err := controller.CheckServer(ctx.Context, name)
+		if err != nil {
+			return err
+		}
+
+		if ctx.CLI.Bool("json") {
+			return printJSON(info)
+		}
+
+		fmt.Printf("Server:     %s\n", info.Name)
+		fmt.Printf("State:      %s\n", info.State)
+		fmt.Printf("Transport:  %s\n", info.Transport)
+		fmt.Printf("PID:        %s\n", formatPID(info.PID))
+		fmt.Printf("Started:    %s\n", formatTime(info.StartedAt))
+		fmt.Printf("Last ping:  %s\n", formatTime(info.LastPing))
+		fmt.Printf("Tools:      %d\n", info.ToolCount)
+		fmt.Printf("Restarts:   %d\n", info.Restarts)
+		if info.LastError != "" {
+			fmt.Printf("Last error: %s\n", info.LastError)
+		}
+		if info.LogFile != "" {
+			fmt.Printf("Log file:   %s\n", info.LogFile)
+		}
+		return nil
+	})
+}
+
+// HandleMCPRestart handles the mcp restart command.
+func HandleMCPRestart() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("server name is required")
+		}
+
+		controller, err := getMCPController(ctx, false)
+		if err != nil {
+			return err
+		}
+
+		info, err := controller.RestartServer(ctx.Context, name)
+		if err != nil {
+			return fmt.Errorf("failed to restart MCP server: %w", err)
+		}
+
+		fmt.Printf("Restarted %s (state: %s, pid: %s, tools: %d)\n",
+			info.Name, info.State, formatPID(info.PID), info.ToolCount)
+		return nil
+	})
+}
+
+// HandleMCPLogs handles the mcp logs command. It reads the log files directly
+// and does not start any server.
+func HandleMCPLogs() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("server name is required")
+		}
+
+		cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+		logFile := filepath.Join(cfg.GetMCPConfig().GetLogDir(), name+".log")
+
+		file, err := os.Open(logFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no logs found for MCP server %s (%s)", name, logFile)
+			}
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer file.Close()
+
+		if err := printLastLines(file, ctx.CLI.Int("lines")); err != nil {
+			return err
+		}
+
+		if !ctx.CLI.Bool("follow") {
+			return nil
+		}
+
+		followCtx, cancel := signals.WithInterruptContext(ctx.Context)
+		defer cancel()
+
+		for {
+			if _, err := io.Copy(os.Stdout, file); err != nil {
+				return fmt.Errorf("failed to read log file: %w", err)
+			}
+			select {
+			case <-followCtx.Done():
+				return nil
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	})
+}
+
+// getMCPController returns the controller of the forge process supervising the MCP
+// servers, reached over its control socket. Without such a process the servers are
+// started in this process if startLocal is set, otherwise it is an error.
+func getMCPController(ctx *startup.Context, startLocal bool) (types.MCPController, error) {
+	cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+	mcpConfig := cfg.GetMCPConfig()
+	if !mcpConfig.Enabled {
+		return nil, fmt.Errorf("MCP integration is disabled, set MCP_ENABLED=true to manage MCP servers")
+	}
+
+	if controller, ok := providers.DialMCPControl(mcpConfig.GetControlSocket()); ok {
+		return controller, nil
+	}
+	if !startLocal {
+		return nil, fmt.Errorf("no running forge chat or forge mcp serve supervises the MCP servers of %s", mcpConfig.ConfigPath)
+	}
+
+	fmt.Fprintln(os.Stderr, "No running forge process supervises these MCP servers, starting them in this process")
+	supervisor, err := do.Invoke[types.MCPSupervisor](ctx.DIContainer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start MCP servers: %w", err)
+	}
+	return providers.NewMCPController(supervisor), nil
+}
+
+// serveMCPControl lets forge mcp list, status and restart of other processes manage
+// the MCP servers of this long running process. It returns a function closing the
+// control socket. Failures are logged, the process runs without the socket.
+func serveMCPControl(ctx *startup.Context) func() {
+	cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+	mcpConfig := cfg.GetMCPConfig()
+	if !mcpConfig.Enabled {
+		return func() {}
+	}
+
+	supervisor, err := do.Invoke[types.MCPSupervisor](ctx.DIContainer)
+	if err != nil {
+		log.Warn("MCP servers are not supervised", zap.Error(err))
+		return func() {}
+	}
+	control, err := providers.ListenMCPControl(log, supervisor, mcpConfig.GetControlSocket())
+	if err != nil {
+		log.Warn("MCP servers of this process cannot be managed by forge mcp commands", zap.Error(err))
+		return func() {}
+	}
+	return func() { control.Close() }
+}
+
+// printLastLines prints the last n lines of the reader, or everything if n is 0.
+// The reader is left positioned at its end.
+func printLastLines(reader io.Reader, n int) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lines := make([]string, 0, n)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if n > 0 && len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+
+	if len(lines) > 0 {
+		fmt.Println(strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// printJSON prints a value as indented JSON.
+func printJSON(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// formatPID formats a process id, using "-" for servers without a local process.
+func formatPID(pid int) string {
+	if pid == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", pid)
+}
+
+// formatTime formats a timestamp, using "-" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -16,6 +16,9 @@ func GetPromptCommand() *cli.Command {
 			GetPromptPushCommand(),
 			GetPromptNewCommand(),
 			GetPromptRunCommand(),
+			GetPromptEvalCommand(),
+			GetPromptCompareCommand(),
+			GetPromptOptimizeCommand(),
 		},
 	}
 }
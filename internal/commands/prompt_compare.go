@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/startup"
+)
+
+// GetPromptCompareCommand returns the prompt compare subcommand.
+func GetPromptCompareCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "compare",
+		Usage:     "Run a prompt across several models and compare the answers",
+		ArgsUsage: "<prompt-name|path>",
+		Description: "Renders the prompt with the variables of an example and sends it to all models " +
+			"concurrently. Outputs, latency, token usage and estimated cost are shown side by side.",
+		Action: HandlePromptCompare(),
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "model",
+				Aliases: []string{"m"},
+				Usage:   "Model to compare as provider:model, repeatable (default: models listed by the prompt)",
+			},
+			&cli.StringFlag{
+				Name:    "example",
+				Aliases: []string{"e"},
+				Usage:   "Example providing the variables (default: first example)",
+			},
+			&cli.Float64Flag{
+				Name:  "temperature",
+				Usage: "Override the temperature recommended by the prompt",
+			},
+			&cli.IntFlag{
+				Name:  "max-tokens",
+				Usage: "Override the max tokens recommended by the prompt",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for each model",
+				Value: 2 * time.Minute,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format (table, json, markdown)",
+				Value: "table",
+			},
+			&cli.StringFlag{
+				Name:  "export",
+				Usage: "Write the results to a file, as JSON for .json files and Markdown otherwise",
+			},
+		},
+	}
+}
+
+// HandlePromptCompare handles the prompt compare command.
+func HandlePromptCompare() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		manifestPath, prompt, err := resolvePromptManifest(ctx.CLI.Args().First())
+		if err != nil {
+			return err
+		}
+		if prompt, err = composePrompt(ctx, manifestPath, prompt); err != nil {
+			return err
+		}
+
+		format := ctx.CLI.String("format")
+		if format != "table" && format != prompteval.FormatJSON && format != prompteval.FormatMarkdown {
+			return fmt.Errorf("unsupported format %q, use table, json or markdown", format)
+		}
+
+		modelRefs := ctx.CLI.StringSlice("model")
+		if len(modelRefs) == 0 {
+			modelRefs = prompt.Spec.Models
+		}
+		if len(modelRefs) == 0 {
+			return fmt.Errorf("models required: forge prompt compare --model <provider:model> --model <provider:model> %s", prompt.Metadata.Name)
+		}
+
+		example, err := selectExample(prompt, ctx.CLI.String("example"))
+		if err != nil {
+			return err
+		}
+		messages, err := prompteval.RenderMessages(prompt, example)
+		if err != nil {
+			return err
+		}
+
+		// Models that fail to initialize report the error in their column
+		targets := make([]prompteval.Target, 0, len(modelRefs))
+		for _, ref := range modelRefs {
+			model, llmConfig, err := initializeModel(ctx, ref)
+			if err != nil {
+				log.Warn("Failed to initialize model for comparison", zap.String("model", ref), zap.Error(err))
+				targets = append(targets, prompteval.FailedTarget(ref, llmConfig, err))
+				continue
+			}
+			targets = append(targets, prompteval.Target{Config: llmConfig, Model: model})
+		}
+
+		opts := prompteval.Options{MaxTokens: ctx.CLI.Int("max-tokens")}
+		if ctx.CLI.IsSet("temperature") {
+			temperature := ctx.CLI.Float64("temperature")
+			opts.Temperature = &temperature
+		}
+
+		log.Info("Comparing models",
+			zap.String("prompt", prompt.Metadata.Name),
+			zap.Strings("models", modelRefs))
+		if format == "table" {
+			fmt.Printf("Comparing prompt '%s' across %d models\n\n", prompt.Metadata.Name, len(targets))
+		}
+
+		comparison := prompteval.Compare(ctx.Context, prompt.Metadata.Name, messages, targets, prompteval.CompareOptions{
+			Timeout:     ctx.CLI.Duration("timeout"),
+			CallOptions: prompteval.CallOptions(prompt, opts),
+		})
+
+		if format == "table" {
+			printComparison(comparison)
+		} else if err := prompteval.WriteComparison(os.Stdout, comparison, format); err != nil {
+			return err
+		}
+
+		if path := ctx.CLI.String("export"); path != "" {
+			if err := exportComparison(path, comparison); err != nil {
+				return err
+			}
+			if format == "table" {
+				fmt.Printf("\nResults exported to %s\n", path)
+			}
+		}
+		return nil
+	})
+}
+
+// selectExample returns the named example, the first example when name is
+// empty, or an empty example for prompts without examples.
+func selectExample(prompt *schema.Prompt, name string) (schema.PromptExample, error) {
+	if name == "" {
+		if len(prompt.Spec.Examples) == 0 {
+			return schema.PromptExample{Name: "defaults"}, nil
+		}
+		return prompt.Spec.Examples[0], nil
+	}
+	for _, example := range prompt.Spec.Examples {
+		if example.Name == name {
+			return example, nil
+		}
+	}
+	return schema.PromptExample{}, fmt.Errorf("prompt %s has no example %s", prompt.Metadata.Name, name)
+}
+
+// printComparison prints the metrics table followed by each answer.
+func printComparison(comparison *prompteval.Comparison) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "MODEL\tSTATUS\tLATENCY\tIN\tOUT\tEST. COST")
+	for _, result := range comparison.Results {
+		status := "ok"
+		if result.Error != "" {
+			status = "failed"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%d\t%s\n", result.Model, status,
+			result.Latency.Round(time.Millisecond), result.Usage.InputTokens,
+			result.Usage.OutputTokens, prompteval.FormatCost(result.Cost))
+	}
+	writer.Flush()
+
+	for _, result := range comparison.Results {
+		fmt.Printf("\n── %s ──\n", result.Model)
+		if result.Error != "" {
+			fmt.Printf("Error: %s\n", result.Error)
+			continue
+		}
+		fmt.Println(strings.TrimSpace(result.Output))
+	}
+}
+
+// exportComparison writes comparison to path in the format of its extension.
+func exportComparison(path string, comparison *prompteval.Comparison) error {
+	format := prompteval.FormatMarkdown
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = prompteval.FormatJSON
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer file.Close()
+	return prompteval.WriteComparison(file, comparison, format)
+}
@@ -0,0 +1,283 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samber/do"
+	"github.com/tmc/langchaingo/llms"
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// GetPromptEvalCommand returns the prompt eval subcommand.
+func GetPromptEvalCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "eval",
+		Usage:     "Evaluate a prompt against its examples with a model",
+		ArgsUsage: "<prompt-name|path>",
+		Description: "Renders each example of the prompt, sends it to the model and scores the output. " +
+			"Examples may list their own scorers, the others use --scorer. Models are given as " +
+			"provider:model, e.g. openai:gpt-4o-mini, or as a model name like claude-3-5-haiku-latest.",
+		Action: HandlePromptEval(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "model",
+				Aliases: []string{"m"},
+				Usage:   "Model to evaluate (default: first model listed by the prompt)",
+			},
+			&cli.StringSliceFlag{
+				Name:    "scorer",
+				Aliases: []string{"s"},
+				Usage:   "Scorer for examples without their own: " + strings.Join(prompteval.ScorerNames(), ", "),
+				Value:   cli.NewStringSlice(prompteval.ScorerContains),
+			},
+			&cli.StringFlag{
+				Name:  "judge-model",
+				Usage: "Model grading outputs for the judge scorer (default: --model)",
+			},
+			&cli.Float64Flag{
+				Name:  "threshold",
+				Usage: "Score a judged output needs to pass",
+				Value: 0.7,
+			},
+			&cli.Float64Flag{
+				Name:  "temperature",
+				Usage: "Override the temperature recommended by the prompt",
+			},
+			&cli.IntFlag{
+				Name:  "max-tokens",
+				Usage: "Override the max tokens recommended by the prompt",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for each model call",
+				Value: 2 * time.Minute,
+			},
+			&cli.BoolFlag{
+				Name:  "write-metrics",
+				Usage: "Write the measured success rate, latency and tokens into the prompt manifest",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the report as JSON",
+			},
+		},
+	}
+}
+
+// HandlePromptEval handles the prompt eval command.
+func HandlePromptEval() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		manifestPath, prompt, err := resolvePromptManifest(ctx.CLI.Args().First())
+		if err != nil {
+			return err
+		}
+
+		modelRef := ctx.CLI.String("model")
+		if modelRef == "" && len(prompt.Spec.Models) > 0 {
+			modelRef = prompt.Spec.Models[0]
+		}
+		if modelRef == "" {
+			return fmt.Errorf("model required: forge prompt eval %s --model <provider:model>", prompt.Metadata.Name)
+		}
+
+		model, llmConfig, err := initializeModel(ctx, modelRef)
+		if err != nil {
+			return err
+		}
+		modelName := llmConfig.GetProvider() + ":" + llmConfig.GetModel()
+		opts := prompteval.Options{
+			Model:     model,
+			ModelName: modelName,
+			Scorers:   ctx.CLI.StringSlice("scorer"),
+			ScorerOptions: prompteval.ScorerOptions{
+				Judge:     model,
+				Threshold: ctx.CLI.Float64("threshold"),
+			},
+			Timeout:   ctx.CLI.Duration("timeout"),
+			MaxTokens: ctx.CLI.Int("max-tokens"),
+		}
+		if ctx.CLI.IsSet("temperature") {
+			temperature := ctx.CLI.Float64("temperature")
+			opts.Temperature = &temperature
+		}
+		if judgeRef := ctx.CLI.String("judge-model"); judgeRef != "" {
+			if opts.ScorerOptions.Judge, _, err = initializeModel(ctx, judgeRef); err != nil {
+				return err
+			}
+		}
+
+		log.Info("Evaluating prompt",
+			zap.String("prompt", prompt.Metadata.Name),
+			zap.String("model", modelName),
+			zap.Int("examples", len(prompt.Spec.Examples)))
+
+		asJSON := ctx.CLI.Bool("json")
+		if !asJSON {
+			fmt.Printf("Evaluating prompt '%s' %s with %s (%d examples)\n\n",
+				prompt.Metadata.Name, prompt.Metadata.Version, modelName, len(prompt.Spec.Examples))
+		}
+
+		composed, err := composePrompt(ctx, manifestPath, prompt)
+		if err != nil {
+			return err
+		}
+		report, err := prompteval.Evaluate(ctx.Context, composed, opts)
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode report: %w", err)
+			}
+			fmt.Println(string(data))
+		} else {
+			printEvalReport(report)
+		}
+
+		if ctx.CLI.Bool("write-metrics") {
+			prompteval.ApplyMetrics(prompt, report)
+			if err := writePromptManifest(manifestPath, prompt); err != nil {
+				return err
+			}
+			if !asJSON {
+				fmt.Printf("Metrics written to %s\n", manifestPath)
+			}
+		}
+
+		if failed := report.Total - report.Passed; failed > 0 {
+			return fmt.Errorf("%d of %d examples failed", failed, report.Total)
+		}
+		return nil
+	})
+}
+
+// initializeModel creates the model for a model reference with the configured API keys.
+func initializeModel(ctx *startup.Context, ref string) (llms.Model, types.LLMConfig, error) {
+	llmConfig, err := llm.ParseModel(ref, 0.7, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	llmService, err := do.Invoke[types.LLMService](ctx.DIContainer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get LLM service: %w", err)
+	}
+	cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+
+	model, err := llmService.InitializeLLM(ctx.Context, cfg, llmConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return model, llmConfig, nil
+}
+
+// printEvalReport prints the evaluation results as a table and a summary.
+func printEvalReport(report *prompteval.Report) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "EXAMPLE\tRESULT\tLATENCY\tTOKENS\tSCORES")
+	for _, result := range report.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		scores := make([]string, 0, len(result.Scores))
+		for _, score := range result.Scores {
+			if score.Skipped {
+				scores = append(scores, score.Scorer+"=skipped")
+				continue
+			}
+			scores = append(scores, fmt.Sprintf("%s=%.2f", score.Scorer, score.Value))
+		}
+		if result.Error != "" {
+			scores = append(scores, "error: "+result.Error)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\n", result.Name, status,
+			result.Latency.Round(time.Millisecond), result.Usage.Total(), strings.Join(scores, " "))
+	}
+	writer.Flush()
+
+	for _, result := range report.Results {
+		for _, score := range result.Scores {
+			if !score.Passed && score.Reason != "" {
+				fmt.Printf("  %s/%s: %s\n", result.Name, score.Scorer, score.Reason)
+			}
+		}
+	}
+
+	fmt.Printf("\nPassed %d/%d (%.1f%%), average latency %s, average tokens %d\n",
+		report.Passed, report.Total, report.SuccessRate*100,
+		report.AverageLatency.Round(time.Millisecond), report.AverageTokens)
+}
+
+// resolvePromptManifest loads the Prompt manifest given as file, as directory or by
+// name from the local prompts directory.
+func resolvePromptManifest(nameOrPath string) (string, *schema.Prompt, error) {
+	if nameOrPath == "" {
+		return "", nil, fmt.Errorf("prompt name or path is required")
+	}
+
+	candidates := []string{
+		nameOrPath,
+		filepath.Join(nameOrPath, schema.ManifestFileName),
+		filepath.Join("prompts", nameOrPath, schema.ManifestFileName),
+	}
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(candidate)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read prompt manifest: %w", err)
+		}
+		component, err := schema.NewComponentParser().ParseComponent(content)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse prompt manifest %s: %w", candidate, err)
+		}
+		prompt, ok := component.(*schema.Prompt)
+		if !ok {
+			return "", nil, fmt.Errorf("manifest %s is not a Prompt", candidate)
+		}
+		return candidate, prompt, nil
+	}
+	return "", nil, fmt.Errorf("prompt %s not found, expected a manifest file, a prompt directory or prompts/%s", nameOrPath, nameOrPath)
+}
+
+// composePrompt resolves the includes and base prompt of the prompt at
+// manifestPath with the prompt service.
+func composePrompt(ctx *startup.Context, manifestPath string, prompt *schema.Prompt) (*schema.Prompt, error) {
+	promptService, err := getPromptServiceFromDI(ctx.DIContainer)
+	if err != nil {
+		log.Warn("Failed to get prompt service from DI, using direct instantiation", zap.Error(err))
+		promptService = getPromptService()
+	}
+	return promptService.ComposePrompt(manifestPath, prompt)
+}
+
+// writePromptManifest serializes prompt back to its manifest file.
+func writePromptManifest(path string, prompt *schema.Prompt) error {
+	content, err := schema.NewComponentParser().SerializeComponent(prompt)
+	if err != nil {
+		return fmt.Errorf("failed to serialize prompt manifest: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write prompt manifest: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 
+	"github.com/denkhaus/agentforge/internal/schema"
 	"github.com/denkhaus/agentforge/internal/startup"
 	"github.com/denkhaus/agentforge/internal/types"
 	"github.com/samber/do"
@@ -39,12 +40,17 @@ func HandlePromptNew() cli.ActionFunc {
 		
 		log.Info("Creating new prompt", zap.String("name", name))
 		
-		// Step 1: Create basic prompt structure (placeholder)
+		// Step 1: Create the prompt manifest
 		fmt.Printf("Creating prompt '%s'...\n", name)
-		log.Info("Creating prompt structure", zap.String("name", name))
-		
-		// TODO: Implement actual file creation when needed
-		fmt.Printf("Basic structure created for '%s'\n", name)
+		promptService, err := getPromptServiceFromDI(ctx.DIContainer)
+		if err != nil {
+			log.Warn("Failed to get prompt service from DI, using direct instantiation", zap.Error(err))
+			promptService = getPromptService()
+		}
+		if err := promptService.CreatePromptStructure(name); err != nil {
+			return fmt.Errorf("failed to create prompt '%s': %w", name, err)
+		}
+		fmt.Printf("Created prompts/%s/%s\n", name, schema.ManifestFileName)
 		
 		// Step 2: Launch TUI Prompt Workbench
 		fmt.Println("Launching TUI Prompt Workbench...")
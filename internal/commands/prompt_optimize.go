@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/startup"
+)
+
+// GetPromptOptimizeCommand returns the prompt optimize subcommand.
+func GetPromptOptimizeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "optimize",
+		Usage:     "Improve a prompt template with model-proposed revisions",
+		ArgsUsage: "<prompt-name|path>",
+		Description: "An optimizer model revises the template over several rounds. Every revision is " +
+			"scored against the prompt examples, or judged against --criteria for prompts without " +
+			"examples. The iteration trail is saved in the optimizations directory next to the manifest.",
+		Action: HandlePromptOptimize(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "model",
+				Aliases: []string{"m"},
+				Usage:   "Model the prompt is evaluated with (default: first model listed by the prompt)",
+			},
+			&cli.StringFlag{
+				Name:  "optimizer-model",
+				Usage: "Model proposing revisions (default: --model)",
+			},
+			&cli.StringFlag{
+				Name:    "criteria",
+				Aliases: []string{"c"},
+				Usage:   "Desired outcome the revisions should achieve",
+			},
+			&cli.IntFlag{
+				Name:  "iterations",
+				Usage: "Number of revision rounds",
+				Value: 3,
+			},
+			&cli.IntFlag{
+				Name:  "candidates",
+				Usage: "Number of revisions proposed per round",
+				Value: 2,
+			},
+			&cli.StringSliceFlag{
+				Name:    "scorer",
+				Aliases: []string{"s"},
+				Usage:   "Scorer for examples without their own: " + strings.Join(prompteval.ScorerNames(), ", "),
+				Value:   cli.NewStringSlice(prompteval.ScorerContains),
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for each model call",
+				Value: 2 * time.Minute,
+			},
+			&cli.BoolFlag{
+				Name:  "accept",
+				Usage: "Write the best revision into the prompt manifest",
+			},
+		},
+	}
+}
+
+// HandlePromptOptimize handles the prompt optimize command.
+func HandlePromptOptimize() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		manifestPath, prompt, err := resolvePromptManifest(ctx.CLI.Args().First())
+		if err != nil {
+			return err
+		}
+
+		modelRef := ctx.CLI.String("model")
+		if modelRef == "" && len(prompt.Spec.Models) > 0 {
+			modelRef = prompt.Spec.Models[0]
+		}
+		if modelRef == "" {
+			return fmt.Errorf("model required: forge prompt optimize --model <provider:model> %s", prompt.Metadata.Name)
+		}
+		model, llmConfig, err := initializeModel(ctx, modelRef)
+		if err != nil {
+			return err
+		}
+
+		opts := prompteval.OptimizeOptions{
+			Optimizer:  model,
+			Criteria:   ctx.CLI.String("criteria"),
+			Iterations: ctx.CLI.Int("iterations"),
+			Candidates: ctx.CLI.Int("candidates"),
+			Eval: prompteval.Options{
+				Model:         model,
+				ModelName:     llmConfig.GetProvider() + ":" + llmConfig.GetModel(),
+				Scorers:       ctx.CLI.StringSlice("scorer"),
+				ScorerOptions: prompteval.ScorerOptions{Judge: model},
+				Timeout:       ctx.CLI.Duration("timeout"),
+			},
+			Progress: printCandidateProgress,
+		}
+		if optimizerRef := ctx.CLI.String("optimizer-model"); optimizerRef != "" {
+			if opts.Optimizer, _, err = initializeModel(ctx, optimizerRef); err != nil {
+				return err
+			}
+		}
+
+		log.Info("Optimizing prompt",
+			zap.String("prompt", prompt.Metadata.Name),
+			zap.String("model", opts.Eval.ModelName),
+			zap.Int("iterations", opts.Iterations))
+		fmt.Printf("Optimizing prompt '%s' with %s\n\n", prompt.Metadata.Name, opts.Eval.ModelName)
+
+		trail, err := prompteval.Optimize(ctx.Context, prompt, opts)
+		if err != nil {
+			return err
+		}
+
+		trailPath, err := prompteval.SaveTrail(filepath.Dir(manifestPath), trail)
+		if err != nil {
+			return err
+		}
+
+		best := trail.Best(3)
+		if len(best) == 0 {
+			fmt.Printf("\nNo revision scored better than the current template (%.2f)\n", trail.Baseline.Score)
+			fmt.Printf("Trail saved to %s\n", trailPath)
+			return nil
+		}
+
+		for i, candidate := range best {
+			fmt.Printf("\n#%d score %.2f (was %.2f)", i+1, candidate.Score, trail.Baseline.Score)
+			if candidate.Rationale != "" {
+				fmt.Printf(": %s", candidate.Rationale)
+			}
+			fmt.Printf("\n%s", prompteval.TemplateDiff(trail.Baseline.Template, candidate.Template))
+		}
+		fmt.Printf("\nTrail saved to %s\n", trailPath)
+
+		if !ctx.CLI.Bool("accept") {
+			fmt.Println("Run again with --accept to write the best revision into the manifest")
+			return nil
+		}
+		if prompt.Spec.Template != "" || prompt.Spec.Content == "" {
+			prompt.Spec.Template = best[0].Template
+		} else {
+			prompt.Spec.Content = best[0].Template
+		}
+		if err := writePromptManifest(manifestPath, prompt); err != nil {
+			return err
+		}
+		fmt.Printf("Best revision written to %s\n", manifestPath)
+		return nil
+	})
+}
+
+// printCandidateProgress prints a line for every evaluated candidate.
+func printCandidateProgress(candidate prompteval.Candidate) {
+	switch {
+	case candidate.Iteration == 0:
+		fmt.Printf("Current template scores %.2f\n", candidate.Score)
+	case candidate.Error != "":
+		fmt.Printf("Round %d: revision failed: %s\n", candidate.Iteration, candidate.Error)
+	default:
+		fmt.Printf("Round %d: revision scores %.2f\n", candidate.Iteration, candidate.Score)
+	}
+}
@@ -2,9 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/denkhaus/agentforge/internal/config"
 	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/samber/do"
 	cli "github.com/urfave/cli/v2"
 	"go.uber.org/zap"
 )
@@ -19,7 +22,7 @@ func GetPromptPullCommand() *cli.Command {
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "version",
-				Usage: "Specific version to pull",
+				Usage: "Tag or branch to pull, a version such as 1.2.0 also matches the tag v1.2.0",
 			},
 			&cli.BoolFlag{
 				Name:  "force",
@@ -31,7 +34,7 @@ func GetPromptPullCommand() *cli.Command {
 
 // HandlePromptPull handles the prompt pull command.
 func HandlePromptPull() cli.ActionFunc {
-	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
 		args := ctx.CLI.Args()
 		if args.Len() == 0 {
 			return fmt.Errorf("repository required: forge prompt pull <user/repo[@version]>")
@@ -43,13 +46,7 @@ func HandlePromptPull() cli.ActionFunc {
 		
 		// Parse repo and version
 		if strings.Contains(repo, "@") && version == "" {
-			parts := strings.Split(repo, "@")
-			repo, version = parts[0], parts[1]
-		}
-		
-		// Auto-expand GitHub URLs if needed
-		if !strings.HasPrefix(repo, "github.com/") && !strings.HasPrefix(repo, "http") {
-			repo = "github.com/" + repo
+			repo, version, _ = strings.Cut(repo, "@")
 		}
 		
 		log.Info("Pulling prompt", 
@@ -59,8 +56,7 @@ func HandlePromptPull() cli.ActionFunc {
 
 		promptService, err := getPromptServiceFromDI(ctx.DIContainer)
 		if err != nil {
-			log.Warn("Failed to get prompt service from DI, using direct instantiation", zap.Error(err))
-			promptService = getPromptService()
+			return fmt.Errorf("failed to get prompt service: %w", err)
 		}
 		
 		// Pull the prompt using the prompt service
@@ -70,13 +66,15 @@ func HandlePromptPull() cli.ActionFunc {
 		}
 		fmt.Println("...")
 
-		err = promptService.PullPrompt(repo, version, force)
+		prompt, err := promptService.PullPrompt(ctx.Context, repo, version, force)
 		if err != nil {
 			return fmt.Errorf("failed to pull prompt: %w", err)
 		}
 
-		fmt.Printf("✓ Prompt successfully pulled from %s\n", repo)
+		cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+		fmt.Printf("✓ Prompt '%s' %s installed to %s\n",
+			prompt.Metadata.Name, prompt.Metadata.Version, filepath.Join(cfg.GetPromptsDir(), prompt.Metadata.Name))
 		
 		return nil
 	})
-}
\ No newline at end of file
+}
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/denkhaus/agentforge/internal/startup"
 	cli "github.com/urfave/cli/v2"
@@ -31,7 +30,7 @@ func GetPromptPushCommand() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "tag",
-				Usage: "Create a git tag for this version",
+				Usage: "Git tag to create, defaults to v<version> of the prompt manifest",
 			},
 		},
 	}
@@ -39,7 +38,7 @@ func GetPromptPushCommand() *cli.Command {
 
 // HandlePromptPush handles the prompt push command.
 func HandlePromptPush() cli.ActionFunc {
-	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
 		name := ctx.CLI.String("name")
 		message := ctx.CLI.String("message")
 		tag := ctx.CLI.String("tag")
@@ -51,48 +50,28 @@ func HandlePromptPush() cli.ActionFunc {
 		
 		repo := args.First()
 		
-		// Parse repo and version
-		var version string
-		if strings.Contains(repo, "@") {
-			parts := strings.Split(repo, "@")
-			repo, version = parts[0], parts[1]
-		}
-		
-		// Auto-expand GitHub URLs if needed
-		if !strings.HasPrefix(repo, "github.com/") && !strings.HasPrefix(repo, "http") {
-			repo = "github.com/" + repo
-		}
-		
 		log.Info("Pushing prompt", 
 			zap.String("name", name),
 			zap.String("repo", repo),
-			zap.String("version", version),
 			zap.String("message", message),
 			zap.String("tag", tag))
 
 		promptService, err := getPromptServiceFromDI(ctx.DIContainer)
 		if err != nil {
-			log.Warn("Failed to get prompt service from DI, using direct instantiation", zap.Error(err))
-			promptService = getPromptService()
+			return fmt.Errorf("failed to get prompt service: %w", err)
 		}
 		
 		// Push the prompt using the prompt service
-		fmt.Printf("Pushing prompt '%s' to %s", name, repo)
-		if version != "" {
-			fmt.Printf("@%s", version)
-		}
-		fmt.Println("...")
+		fmt.Printf("Pushing prompt '%s' to %s...\n", name, repo)
 
-		err = promptService.PushPrompt(name, repo, message, tag)
+		tag, err = promptService.PushPrompt(ctx.Context, name, repo, message, tag)
 		if err != nil {
 			return fmt.Errorf("failed to push prompt: %w", err)
 		}
 
 		fmt.Printf("✓ Prompt '%s' successfully pushed to %s\n", name, repo)
-		if tag != "" {
-			fmt.Printf("✓ Tagged as %s\n", tag)
-		}
+		fmt.Printf("✓ Tagged as %s\n", tag)
 		
 		return nil
 	})
-}
\ No newline at end of file
+}
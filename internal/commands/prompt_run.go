@@ -1,13 +1,47 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/tmc/langchaingo/llms"
 	cli "github.com/urfave/cli/v2"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/structured"
 )
 
+// runOutput is the result of a prompt run encoded as JSON or YAML.
+type runOutput struct {
+	Prompt           string            `json:"prompt" yaml:"prompt"`
+	Variables        map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Messages         []runMessage      `json:"messages" yaml:"messages"`
+	Model            string            `json:"model,omitempty" yaml:"model,omitempty"`
+	Output           string            `json:"output,omitempty" yaml:"output,omitempty"`
+	Result           any               `json:"result,omitempty" yaml:"result,omitempty"`
+	Attempts         int               `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	Usage            *llm.Usage        `json:"usage,omitempty" yaml:"usage,omitempty"`
+	LatencyMS        int64             `json:"latencyMs,omitempty" yaml:"latencyMs,omitempty"`
+	EstimatedCostUSD *float64          `json:"estimatedCostUsd,omitempty" yaml:"estimatedCostUsd,omitempty"`
+}
+
+// runMessage is a rendered prompt message.
+type runMessage struct {
+	Role    string `json:"role" yaml:"role"`
+	Content string `json:"content" yaml:"content"`
+}
+
 // GetPromptRunCommand returns the prompt run subcommand.
 func GetPromptRunCommand() *cli.Command {
 	return &cli.Command{
@@ -15,22 +49,39 @@ func GetPromptRunCommand() *cli.Command {
 		Usage:     "Run a prompt with variables",
 		Action:    HandlePromptRun(),
 		ArgsUsage: "<prompt-name>",
+		Description: "Renders the prompt with the given variables and prints it, or sends it to " +
+			"--model honoring the temperature, max tokens and stop sequences of the prompt. " +
+			"Replies to prompts with an output schema are validated and repaired.",
 		Flags: []cli.Flag{
 			&cli.StringSliceFlag{
 				Name:    "var",
 				Aliases: []string{"v"},
-				Usage:   "Set template variables (e.g., --var task='review this code')",
+				Usage:   "Set a template variable as key=value, or key=@file to read the value from a file",
+			},
+			&cli.StringFlag{
+				Name:  "stdin",
+				Usage: "Read the value of this variable from standard input",
 			},
 			&cli.StringFlag{
-				Name:  "output",
+				Name:    "output",
 				Aliases: []string{"o"},
-				Usage: "Output format (text, json, yaml)",
-				Value: "text",
+				Usage:   "Output format (text, json, yaml)",
+				Value:   "text",
+			},
+			&cli.StringFlag{
+				Name:    "model",
+				Aliases: []string{"m"},
+				Usage:   "Send the rendered prompt to a model (provider:model)",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for the model",
+				Value: 2 * time.Minute,
 			},
 			&cli.BoolFlag{
-				Name:  "interactive",
+				Name:    "interactive",
 				Aliases: []string{"i"},
-				Usage: "Interactive mode to set variables",
+				Usage:   "Interactive mode to set variables",
 			},
 		},
 	}
@@ -43,71 +94,265 @@ func HandlePromptRun() cli.ActionFunc {
 		if args.Len() == 0 {
 			return fmt.Errorf("prompt name required: forge prompt run <prompt-name>")
 		}
-		
+
 		name := args.First()
-		variables := ctx.CLI.StringSlice("var")
 		output := ctx.CLI.String("output")
 		interactive := ctx.CLI.Bool("interactive")
-		
-		log.Info("Running prompt", 
+		if output != "text" && output != "json" && output != "yaml" {
+			return fmt.Errorf("unsupported output format %q, use text, json or yaml", output)
+		}
+
+		variables, err := parseRunVariables(ctx.CLI.StringSlice("var"), ctx.CLI.String("stdin"), os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		log.Info("Running prompt",
 			zap.String("name", name),
-			zap.Strings("variables", variables),
+			zap.Int("variables", len(variables)),
 			zap.String("output", output),
 			zap.Bool("interactive", interactive))
 
-		promptService, err := getPromptServiceFromDI(ctx.DIContainer)
+		if interactive {
+			return runPromptInteractive(ctx, name)
+		}
+
+		messages, prompt, err := renderRunMessages(ctx, name, variables)
 		if err != nil {
-			log.Warn("Failed to get prompt service from DI, using direct instantiation", zap.Error(err))
-			promptService = getPromptService()
+			return err
+		}
+
+		result := runOutput{Prompt: name, Variables: variables}
+		for _, message := range messages {
+			result.Messages = append(result.Messages, runMessage{
+				Role:    string(message.Role),
+				Content: promptrender.MessageText(message),
+			})
+		}
+
+		modelRef := ctx.CLI.String("model")
+		if modelRef == "" {
+			return printRunOutput(os.Stdout, os.Stderr, result, output)
+		}
+		// Spec options apply to manifests, legacy prompts have none
+		var callOptions []llms.CallOption
+		if prompt != nil {
+			callOptions = prompteval.CallOptions(prompt, prompteval.Options{})
+		}
+		if prompt != nil && len(prompt.Spec.OutputSchema) > 0 {
+			err = sendStructuredMessages(ctx, modelRef, messages, prompt.Spec.OutputSchema, callOptions, &result)
+		} else {
+			err = sendRunMessages(ctx, modelRef, messages, callOptions, &result, output)
 		}
-		
-		// Load prompt data
-		promptData, err := promptService.LoadPromptData(name)
 		if err != nil {
-			return fmt.Errorf("failed to load prompt '%s': %w", name, err)
+			return err
 		}
-		
-		fmt.Printf("Running prompt: %s\n", promptData.Name)
-		fmt.Printf("Description: %s\n\n", promptData.Description)
-		
-		if interactive {
-			// Launch interactive variable setter
-			fmt.Println("Interactive mode - launching variable editor...")
-			
-			tuiManager, err := getTUIManagerFromDI(ctx.DIContainer)
-			if err != nil {
-				return fmt.Errorf("failed to get TUI manager: %w", err)
-			}
-			err = tuiManager.RunPromptVariableEditor(name, promptData)
+		return printRunOutput(os.Stdout, os.Stderr, result, output)
+	})
+}
+
+// parseRunVariables parses key=value and key=@file variables and reads the
+// stdin variable from stdin. A value starting with @@ is the literal @ value.
+func parseRunVariables(assignments []string, stdinVariable string, stdin io.Reader) (map[string]string, error) {
+	variables := make(map[string]string, len(assignments)+1)
+	for _, assignment := range assignments {
+		key, value, found := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value or key=@file", assignment)
+		}
+
+		switch {
+		case strings.HasPrefix(value, "@@"):
+			value = value[1:]
+		case strings.HasPrefix(value, "@"):
+			content, err := os.ReadFile(value[1:])
 			if err != nil {
-				return fmt.Errorf("failed to run variable editor: %w", err)
-			}
-		} else {
-			// Parse variables from command line
-			varMap := make(map[string]string)
-			for _, variable := range variables {
-				// Parse key=value format
-				// TODO: Implement variable parsing
-				_ = variable // Suppress unused variable warning
+				return nil, fmt.Errorf("failed to read variable %s: %w", key, err)
 			}
-			
-			// Execute prompt with variables
-			result, err := promptService.ExecutePrompt(name, varMap)
+			value = strings.TrimSuffix(string(content), "\n")
+		}
+		variables[key] = value
+	}
+
+	if stdinVariable != "" {
+		content, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read variable %s from stdin: %w", stdinVariable, err)
+		}
+		variables[stdinVariable] = strings.TrimSuffix(string(content), "\n")
+	}
+	return variables, nil
+}
+
+// renderRunMessages renders the prompt manifest into messages and returns the
+// composed prompt. The legacy template of prompts without a manifest renders
+// into one user message without a prompt.
+func renderRunMessages(ctx *startup.Context, name string, variables map[string]string) ([]llms.MessageContent, *schema.Prompt, error) {
+	if manifestPath, prompt, err := resolvePromptManifest(name); err == nil {
+		composed, err := composePrompt(ctx, manifestPath, prompt)
+		if err != nil {
+			return nil, nil, err
+		}
+		messages, err := promptrender.RenderMessages(composed, promptrender.StringValues(variables))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to render prompt '%s': %w", name, err)
+		}
+		return messages, composed, nil
+	}
+
+	promptService, err := getPromptServiceFromDI(ctx.DIContainer)
+	if err != nil {
+		log.Warn("Failed to get prompt service from DI, using direct instantiation", zap.Error(err))
+		promptService = getPromptService()
+	}
+	text, err := promptService.ExecutePrompt(name, variables)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute prompt: %w", err)
+	}
+	return []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, text)}, nil, nil
+}
+
+// sendRunMessages sends messages to the model and records its answer in result.
+// Text output is streamed while the model answers.
+func sendRunMessages(ctx *startup.Context, modelRef string, messages []llms.MessageContent, callOptions []llms.CallOption, result *runOutput, output string) error {
+	model, llmConfig, err := initializeModel(ctx, modelRef)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Sending prompt to model",
+		zap.String("prompt", result.Prompt),
+		zap.String("model", modelRef),
+		zap.Int("messages", len(messages)))
+
+	var onChunk func(string)
+	if output == "text" {
+		onChunk = func(chunk string) { fmt.Print(chunk) }
+	}
+	target := prompteval.Target{Config: llmConfig, Model: model}
+	answer := prompteval.RunTarget(ctx.Context, messages, target, prompteval.CompareOptions{
+		Timeout:     ctx.CLI.Duration("timeout"),
+		CallOptions: callOptions,
+	}, onChunk)
+	if answer.Error != "" {
+		return fmt.Errorf("failed to run prompt '%s': %s", result.Prompt, answer.Error)
+	}
+
+	result.Model = answer.Model
+	result.Output = answer.Output
+	result.Usage = &answer.Usage
+	result.LatencyMS = answer.LatencyMS
+	result.EstimatedCostUSD = answer.Cost
+	return nil
+}
+
+// sendStructuredMessages asks the model for a reply matching outputSchema in
+// the mode of its provider and records the decoded reply in result.
+func sendStructuredMessages(ctx *startup.Context, modelRef string, messages []llms.MessageContent, outputSchema map[string]any, callOptions []llms.CallOption, result *runOutput) error {
+	model, llmConfig, err := initializeModel(ctx, modelRef)
+	if err != nil {
+		return err
+	}
+
+	mode := structured.ModeFor(llmConfig.GetProvider())
+	log.Info("Sending prompt to model for structured output",
+		zap.String("prompt", result.Prompt),
+		zap.String("model", modelRef),
+		zap.String("mode", string(mode)))
+
+	callCtx, cancel := context.WithTimeout(ctx.Context, ctx.CLI.Duration("timeout"))
+	defer cancel()
+
+	start := time.Now()
+	reply, err := structured.Generate(callCtx, model, messages, structured.Options{
+		Schema:      outputSchema,
+		Mode:        mode,
+		CallOptions: callOptions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run prompt '%s': %w", result.Prompt, err)
+	}
+
+	result.Model = prompteval.Target{Config: llmConfig}.Name()
+	result.Output = reply.Raw
+	result.Result = reply.Value
+	result.Attempts = reply.Attempts
+	result.Usage = &reply.Usage
+	result.LatencyMS = time.Since(start).Milliseconds()
+	if cost, ok := llm.EstimateCost(llmConfig.GetProvider(), llmConfig.GetModel(), reply.Usage); ok {
+		result.EstimatedCostUSD = &cost
+	}
+	return nil
+}
+
+// printRunOutput prints the run result in the output format to out. Streamed text
+// output is followed by the run statistics on errOut.
+func printRunOutput(out, errOut io.Writer, result runOutput, output string) error {
+	switch output {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		fmt.Fprint(out, string(data))
+	default:
+		if result.Result != nil {
+			data, err := json.MarshalIndent(result.Result, "", "  ")
 			if err != nil {
-				return fmt.Errorf("failed to execute prompt: %w", err)
+				return fmt.Errorf("failed to encode result: %w", err)
 			}
-			
-			// Output result
-			switch output {
-			case "json":
-				fmt.Printf("{\n  \"prompt\": \"%s\",\n  \"result\": \"%s\"\n}\n", name, result)
-			case "yaml":
-				fmt.Printf("prompt: %s\nresult: |\n  %s\n", name, result)
-			default:
-				fmt.Printf("Result:\n%s\n", result)
+			fmt.Fprint(out, string(data))
+		}
+		if result.Model != "" {
+			fmt.Fprintln(out)
+			fmt.Fprintf(errOut, "\n%s • %s • %d in / %d out tokens • %s\n",
+				result.Model, time.Duration(result.LatencyMS)*time.Millisecond,
+				result.Usage.InputTokens, result.Usage.OutputTokens, prompteval.FormatCost(result.EstimatedCostUSD))
+			return nil
+		}
+		for i, message := range result.Messages {
+			if len(result.Messages) > 1 {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				fmt.Fprintf(out, "[%s]\n", message.Role)
 			}
+			fmt.Fprintln(out, message.Content)
 		}
-		
-		return nil
-	})
-}
\ No newline at end of file
+	}
+	return nil
+}
+
+// runPromptInteractive launches the variable editor for a prompt.
+func runPromptInteractive(ctx *startup.Context, name string) error {
+	promptService, err := getPromptServiceFromDI(ctx.DIContainer)
+	if err != nil {
+		log.Warn("Failed to get prompt service from DI, using direct instantiation", zap.Error(err))
+		promptService = getPromptService()
+	}
+
+	promptData, err := promptService.LoadPromptData(name)
+	if err != nil {
+		return fmt.Errorf("failed to load prompt '%s': %w", name, err)
+	}
+
+	fmt.Printf("Running prompt: %s\n", promptData.Name)
+	fmt.Printf("Description: %s\n\n", promptData.Description)
+	fmt.Println("Interactive mode - launching variable editor...")
+
+	tuiManager, err := getTUIManagerFromDI(ctx.DIContainer)
+	if err != nil {
+		return fmt.Errorf("failed to get TUI manager: %w", err)
+	}
+	if err := tuiManager.RunPromptVariableEditor(name, promptData); err != nil {
+		return fmt.Errorf("failed to run variable editor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+)
+
+func TestParseRunVariables(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "diff.txt")
+	require.NoError(t, os.WriteFile(file, []byte("+ added line\n"), 0644))
+
+	tests := []struct {
+		name          string
+		assignments   []string
+		stdinVariable string
+		stdin         string
+		want          map[string]string
+		wantErr       string
+	}{
+		{
+			name:        "key=value",
+			assignments: []string{"language=Go", " file =main.go", "empty="},
+			want:        map[string]string{"language": "Go", "file": "main.go", "empty": ""},
+		},
+		{
+			name:        "value containing =",
+			assignments: []string{"query=a=b"},
+			want:        map[string]string{"query": "a=b"},
+		},
+		{
+			name:        "key=@file",
+			assignments: []string{"diff=@" + file},
+			want:        map[string]string{"diff": "+ added line"},
+		},
+		{
+			name:        "@@ escape",
+			assignments: []string{"handle=@@gopher", "at=@@"},
+			want:        map[string]string{"handle": "@gopher", "at": "@"},
+		},
+		{
+			name:          "stdin",
+			assignments:   []string{"language=Go"},
+			stdinVariable: "code",
+			stdin:         "package main\n",
+			want:          map[string]string{"language": "Go", "code": "package main"},
+		},
+		{
+			name:          "stdin overrides var",
+			assignments:   []string{"code=old"},
+			stdinVariable: "code",
+			stdin:         "new",
+			want:          map[string]string{"code": "new"},
+		},
+		{
+			name:        "missing =",
+			assignments: []string{"language"},
+			wantErr:     `invalid variable "language", expected key=value or key=@file`,
+		},
+		{
+			name:        "empty key",
+			assignments: []string{"=Go"},
+			wantErr:     `invalid variable "=Go"`,
+		},
+		{
+			name:        "missing file",
+			assignments: []string{"diff=@" + filepath.Join(dir, "missing.txt")},
+			wantErr:     "failed to read variable diff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variables, err := parseRunVariables(tt.assignments, tt.stdinVariable, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, variables)
+		})
+	}
+}
+
+func TestPrintRunOutput(t *testing.T) {
+	cost := 0.0015
+	rendered := runOutput{
+		Prompt:    "reviewer",
+		Variables: map[string]string{"file": "main.go"},
+		Messages:  []runMessage{{Role: "system", Content: "You review code."}, {Role: "human", Content: "Review main.go."}},
+	}
+	answered := runOutput{
+		Prompt:           "reviewer",
+		Messages:         []runMessage{{Role: "human", Content: "Review main.go."}},
+		Model:            "openai/gpt-4o-mini",
+		Output:           "Looks good.",
+		Usage:            &llm.Usage{InputTokens: 12, OutputTokens: 3},
+		LatencyMS:        1500,
+		EstimatedCostUSD: &cost,
+	}
+	structuredResult := answered
+	structuredResult.Result = map[string]any{"approved": true}
+
+	tests := []struct {
+		name   string
+		result runOutput
+		output string
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "text messages",
+			result: rendered,
+			output: "text",
+			stdout: "[system]\nYou review code.\n\n[human]\nReview main.go.\n",
+		},
+		{
+			name:   "text single message",
+			result: runOutput{Prompt: "reviewer", Messages: []runMessage{{Role: "human", Content: "Review main.go."}}},
+			output: "text",
+			stdout: "Review main.go.\n",
+		},
+		{
+			name:   "text answer",
+			result: answered,
+			output: "text",
+			stdout: "\n",
+			stderr: "\nopenai/gpt-4o-mini • 1.5s • 12 in / 3 out tokens • $0.001500\n",
+		},
+		{
+			name:   "text structured result",
+			result: structuredResult,
+			output: "text",
+			stdout: "{\n  \"approved\": true\n}\n",
+			stderr: "\nopenai/gpt-4o-mini • 1.5s • 12 in / 3 out tokens • $0.001500\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			require.NoError(t, printRunOutput(&stdout, &stderr, tt.result, tt.output))
+			assert.Equal(t, tt.stdout, stdout.String())
+			assert.Equal(t, tt.stderr, stderr.String())
+		})
+	}
+
+	decoders := map[string]func([]byte, any) error{"json": json.Unmarshal, "yaml": yaml.Unmarshal}
+	for output, decode := range decoders {
+		t.Run(output, func(t *testing.T) {
+			for _, result := range []runOutput{rendered, structuredResult} {
+				var stdout, stderr bytes.Buffer
+				require.NoError(t, printRunOutput(&stdout, &stderr, result, output))
+				assert.Empty(t, stderr.String())
+
+				var decoded runOutput
+				require.NoError(t, decode(stdout.Bytes(), &decoded))
+				assert.Equal(t, result, decoded, "the encoded result round trips")
+			}
+		})
+	}
+
+	var stdout bytes.Buffer
+	require.NoError(t, printRunOutput(&stdout, &bytes.Buffer{}, rendered, "json"))
+	assert.NotContains(t, stdout.String(), "usage", "unset fields are omitted")
+	stdout.Reset()
+	require.NoError(t, printRunOutput(&stdout, &bytes.Buffer{}, answered, "json"))
+	assert.Contains(t, stdout.String(), `"latencyMs": 1500`)
+	assert.Contains(t, stdout.String(), `"estimatedCostUsd": 0.0015`)
+	assert.Contains(t, stdout.String(), `"inputTokens": 12`)
+}
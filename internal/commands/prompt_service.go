@@ -12,6 +12,7 @@ func getPromptServiceFromDI(injector *do.Injector) (prompts.PromptService, error
 
 // Deprecated: Use getPromptServiceFromDI instead
 // getPromptService returns a prompt service instance (legacy direct instantiation)
+// that cannot pull or push prompts
 func getPromptService() prompts.PromptService {
-	return prompts.NewPromptService()
+	return prompts.NewPromptService(nil, nil, nil, "", "")
 }
\ No newline at end of file
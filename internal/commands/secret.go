@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// GetSecretCommand returns the secret command configuration.
+func GetSecretCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "secret",
+		Usage: "Manage encrypted secrets for tools and providers",
+		Description: "Secrets are stored AES-GCM encrypted in SECRETS_FILE, keyed by SECRETS_PASSPHRASE " +
+			"or by a random key in SECRETS_KEY_FILE. Tools receive the secrets they declare, " +
+			"provider API keys such as OPENAI_API_KEY are read from secrets of the same name.",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "set",
+				Usage:     "Store a secret, reading the value from stdin when omitted",
+				ArgsUsage: "<name> [value]",
+				Action:    HandleSecretSet(),
+			},
+			{
+				Name:      "get",
+				Usage:     "Print the value of a secret",
+				ArgsUsage: "<name>",
+				Action:    HandleSecretGet(),
+			},
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Usage:   "List the names of all secrets",
+				Action:  HandleSecretList(),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output as JSON",
+					},
+				},
+			},
+			{
+				Name:      "rm",
+				Aliases:   []string{"remove"},
+				Usage:     "Remove a secret",
+				ArgsUsage: "<name>",
+				Action:    HandleSecretRemove(),
+			},
+		},
+	}
+}
+
+// HandleSecretSet handles the secret set command.
+func HandleSecretSet() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("secret name is required")
+		}
+
+		value := ctx.CLI.Args().Get(1)
+		if ctx.CLI.Args().Len() < 2 {
+			var err error
+			if value, err = readSecretValue(name); err != nil {
+				return err
+			}
+		}
+
+		store, err := do.Invoke[types.SecretStore](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to open secret store: %w", err)
+		}
+		if err := store.Set(name, value); err != nil {
+			return err
+		}
+
+		fmt.Printf("Secret %s stored\n", name)
+		return nil
+	})
+}
+
+// HandleSecretGet handles the secret get command.
+func HandleSecretGet() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("secret name is required")
+		}
+
+		store, err := do.Invoke[types.SecretStore](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to open secret store: %w", err)
+		}
+		value, err := store.Get(name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(value)
+		return nil
+	})
+}
+
+// HandleSecretList handles the secret list command. Values are never printed.
+func HandleSecretList() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		store, err := do.Invoke[types.SecretStore](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to open secret store: %w", err)
+		}
+		names, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		if ctx.CLI.Bool("json") {
+			return printJSON(names)
+		}
+		if len(names) == 0 {
+			fmt.Println("No secrets stored")
+			return nil
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	})
+}
+
+// HandleSecretRemove handles the secret rm command.
+func HandleSecretRemove() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("secret name is required")
+		}
+
+		store, err := do.Invoke[types.SecretStore](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to open secret store: %w", err)
+		}
+		if err := store.Delete(name); err != nil {
+			return err
+		}
+
+		fmt.Printf("Secret %s removed\n", name)
+		return nil
+	})
+}
+
+// readSecretValue prompts for a secret without echo on a terminal and reads
+// stdin otherwise, dropping a single trailing newline.
+func readSecretValue(name string) (string, error) {
+	if term.IsTerminal(os.Stdin.Fd()) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		value, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret value: %w", err)
+		}
+		return string(value), nil
+	}
+
+	value, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret value: %w", err)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(value), "\n"), "\r"), nil
+}
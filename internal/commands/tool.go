@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/toolruntime"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// GetToolCommand returns the tool command configuration.
+func GetToolCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tool",
+		Usage: "Manage AgentForge tools",
+		Subcommands: []*cli.Command{
+			getToolNewCommand(),
+			getToolListCommand(),
+			getToolShowCommand(),
+			getToolPullCommand(),
+			getToolPushCommand(),
+			getToolInstallCommand(),
+			getToolUninstallCommand(),
+			getToolTestCommand(),
+			getToolRunCommand(),
+			getToolStatusCommand(),
+			getToolImportCommand(),
+		},
+	}
+}
+
+// getToolListCommand returns the tool list subcommand.
+func getToolListCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "list",
+		Aliases: []string{"ls"},
+		Usage:   "List installed tools and tools known from pulled repositories",
+		Action:  HandleToolList(),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "installed",
+				Usage: "Show only installed tools",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+	}
+}
+
+// getToolShowCommand returns the tool show subcommand.
+func getToolShowCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "show",
+		Usage:     "Show the manifest of a tool",
+		ArgsUsage: "<name|path>",
+		Action:    HandleToolShow(),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "yaml",
+				Usage: "Print the raw manifest",
+			},
+		},
+	}
+}
+
+// getToolStatusCommand returns the tool status subcommand.
+func getToolStatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Usage:     "Load the installed tools and run their health checks",
+		ArgsUsage: "[tool]",
+		Action:    HandleToolStatus(),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+	}
+}
+
+// HandleToolList handles the tool list command.
+func HandleToolList() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		toolService, err := do.Invoke[database.ToolService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get tool service: %w", err)
+		}
+
+		opts := database.ListToolsOptions{}
+		if ctx.CLI.Bool("installed") {
+			installed := true
+			opts.IsInstalled = &installed
+		}
+		entries, err := toolService.ListToolsForCLI(ctx.Context, opts)
+		if err != nil {
+			return err
+		}
+
+		if ctx.CLI.Bool("json") {
+			return printJSON(entries)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No tools found")
+			fmt.Println("Create one with: forge tool new <name>")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tVERSION\tTYPE\tFUNCTIONS\tINSTALLED\tSOURCE")
+		for _, entry := range entries {
+			source := entry.Repository
+			if source == "" {
+				source = "local"
+			}
+			if entry.Error != "" {
+				source = "error: " + entry.Error
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%t\t%s\n",
+				entry.Name, entry.Version, entry.Type, entry.Functions, entry.Installed, source)
+		}
+		return writer.Flush()
+	})
+}
+
+// HandleToolShow handles the tool show command.
+func HandleToolShow() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		dir, err := resolveToolDir(ctx, ctx.CLI.Args().First())
+		if err != nil {
+			return err
+		}
+		manifest, err := toolruntime.ReadManifest(dir)
+		if err != nil {
+			return err
+		}
+
+		if ctx.CLI.Bool("yaml") {
+			content, err := os.ReadFile(filepath.Join(dir, schema.ManifestFileName))
+			if err != nil {
+				return fmt.Errorf("failed to read tool manifest: %w", err)
+			}
+			_, err = os.Stdout.Write(content)
+			return err
+		}
+
+		fmt.Printf("Name:        %s\n", manifest.Metadata.Name)
+		fmt.Printf("Version:     %s\n", manifest.Metadata.Version)
+		fmt.Printf("Description: %s\n", manifest.Metadata.Description)
+		fmt.Printf("Author:      %s\n", manifest.Metadata.Author)
+		fmt.Printf("Type:        %s (%s)\n", manifest.Spec.Type, manifest.Spec.Runtime)
+		fmt.Printf("Entry point: %s\n", manifest.Spec.EntryPoint)
+		fmt.Printf("Path:        %s\n", dir)
+		if len(manifest.Spec.Configuration.Secrets) > 0 {
+			fmt.Printf("Secrets:     %s\n", strings.Join(manifest.Spec.Configuration.Secrets, ", "))
+		}
+
+		fmt.Printf("\nFunctions:\n")
+		for _, function := range manifest.Spec.Functions {
+			fmt.Printf("  %s_%s - %s\n", manifest.Metadata.Name, function.Name, function.Description)
+			for _, param := range function.Parameters {
+				required := ""
+				if param.Required {
+					required = ", required"
+				}
+				fmt.Printf("    %s (%s%s): %s\n", param.Name, param.Type, required, param.Description)
+			}
+		}
+		return nil
+	})
+}
+
+// resolveToolDir returns the directory of a tool given as a path to a tool directory
+// or as the name of an installed tool.
+func resolveToolDir(ctx *startup.Context, nameOrPath string) (string, error) {
+	if nameOrPath == "" {
+		return "", fmt.Errorf("tool name or path is required")
+	}
+	if _, err := os.Stat(filepath.Join(nameOrPath, schema.ManifestFileName)); err == nil {
+		return nameOrPath, nil
+	}
+
+	cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+	dir := filepath.Join(cfg.GetToolsDir(), nameOrPath)
+	if _, err := os.Stat(filepath.Join(dir, schema.ManifestFileName)); err != nil {
+		return "", fmt.Errorf("tool %s is neither installed nor a tool directory: %w", nameOrPath, errors.ErrToolNotFound)
+	}
+	return dir, nil
+}
+
+// HandleToolStatus handles the tool status command.
+func HandleToolStatus() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		// The installed tools are started on their first use
+		installed, err := do.InvokeNamed[types.ToolProvider](ctx.DIContainer, "installedProvider")
+		if err != nil {
+			return fmt.Errorf("failed to load installed tools: %w", err)
+		}
+		installed.GetTools()
+		monitor, err := do.Invoke[types.ToolHealthMonitor](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to create tool health monitor: %w", err)
+		}
+
+		names := ctx.CLI.Args().Slice()
+		if len(names) == 0 {
+			for _, health := range monitor.GetHealth() {
+				names = append(names, health.Tool)
+			}
+		}
+
+		results := make([]types.ToolHealth, 0, len(names))
+		for _, name := range names {
+			health, err := monitor.CheckTool(ctx.Context, name)
+			if err != nil {
+				return err
+			}
+			results = append(results, health)
+		}
+
+		if ctx.CLI.Bool("json") {
+			return printJSON(results)
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No tools installed")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "TOOL\tSTATE\tFUNCTIONS\tFAILURES\tLAST CHECK\tLAST ERROR")
+		for _, health := range results {
+			lastError := health.LastError
+			if lastError == "" {
+				lastError = "-"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%d/%d\t%s\t%s\n",
+				health.Tool, health.State, strings.Join(health.Functions, ","),
+				health.ConsecutiveFailures, health.FailureThreshold,
+				formatTime(health.LastCheck), lastError)
+		}
+		return writer.Flush()
+	})
+}
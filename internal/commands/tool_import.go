@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/openapi"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/startup"
+)
+
+// getToolImportCommand returns the tool import subcommand.
+func getToolImportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Generate tool manifests from API descriptions",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "openapi",
+				Usage:     "Generate an http tool with one function per operation of an OpenAPI 3 spec",
+				ArgsUsage: "<spec file or url>",
+				Action:    HandleToolImportOpenAPI(),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "Tool name, derived from the spec title by default",
+					},
+					&cli.StringFlag{
+						Name:  "base-url",
+						Usage: "Base URL of the API, overriding the first server of the spec",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Manifest file to write, '-' for stdout (default: install into the tools directory)",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Overwrite an existing manifest",
+					},
+				},
+			},
+		},
+	}
+}
+
+// HandleToolImportOpenAPI handles the tool import openapi command.
+func HandleToolImportOpenAPI() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		location := ctx.CLI.Args().First()
+		if location == "" {
+			return fmt.Errorf("OpenAPI spec file or url is required")
+		}
+
+		content, err := openapi.LoadSpec(location)
+		if err != nil {
+			return err
+		}
+		result, err := openapi.GenerateTool(content, openapi.Options{
+			Name:    ctx.CLI.String("name"),
+			BaseURL: ctx.CLI.String("base-url"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate tool from %s: %w", location, err)
+		}
+
+		data, err := schema.NewComponentParser().SerializeComponent(result.Tool)
+		if err != nil {
+			return fmt.Errorf("failed to serialize tool manifest: %w", err)
+		}
+
+		output := ctx.CLI.String("output")
+		if output == "-" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if output == "" {
+			cfg := do.MustInvoke[*config.Config](ctx.DIContainer)
+			output = filepath.Join(cfg.GetToolsDir(), result.Tool.Metadata.Name, schema.ManifestFileName)
+		}
+		if _, err := os.Stat(output); err == nil && !ctx.CLI.Bool("force") {
+			return fmt.Errorf("manifest %s already exists, use --force to overwrite it", output)
+		}
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return fmt.Errorf("failed to create tool directory: %w", err)
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return fmt.Errorf("failed to write tool manifest: %w", err)
+		}
+
+		fmt.Printf("Tool %s with %d functions written to %s\n",
+			result.Tool.Metadata.Name, len(result.Tool.Spec.Functions), output)
+		for _, warning := range result.Warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+		if len(result.Secrets) > 0 {
+			fmt.Println("Store the API credentials before using the tool:")
+			for _, secret := range result.Secrets {
+				fmt.Printf("  forge secret set %s\n", secret)
+			}
+		}
+		return nil
+	})
+}
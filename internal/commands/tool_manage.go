@@ -0,0 +1,335 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/templates"
+	"github.com/denkhaus/agentforge/internal/toolruntime"
+)
+
+// getToolNewCommand returns the tool new subcommand.
+func getToolNewCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "new",
+		Usage:     "Create a new Go MCP server tool project",
+		ArgsUsage: "<name>",
+		Description: "Generates a compiling MCP server skeleton with a handler stub per function, " +
+			"a matching component.yaml, example-based tests and a Makefile.",
+		Action: HandleToolNew(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "Directory to create the tool in (default: ./<name>)",
+			},
+			&cli.StringFlag{
+				Name:  "module",
+				Usage: "Go module path of the generated project (default: <name>)",
+			},
+			&cli.StringFlag{
+				Name:  "description",
+				Usage: "Tool description",
+			},
+			&cli.StringSliceFlag{
+				Name:    "function",
+				Aliases: []string{"f"},
+				Usage:   "Function to generate, as 'name(param:type!, other:type):description' (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "Generate the functions declared in an existing component.yaml or tool directory",
+			},
+		},
+	}
+}
+
+// getToolPullCommand returns the tool pull subcommand.
+func getToolPullCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "pull",
+		Usage:     "Pull a tool from a git repository and install it",
+		ArgsUsage: "<user/repo[@version]>",
+		Action:    HandleToolPull(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "version",
+				Usage: "Tag or branch to pull",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Force overwrite if the tool is already installed",
+			},
+		},
+	}
+}
+
+// getToolPushCommand returns the tool push subcommand.
+func getToolPushCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "push",
+		Usage:     "Push a tool to a git repository",
+		ArgsUsage: "<repo-url>",
+		Action:    HandleToolPush(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Aliases:  []string{"n"},
+				Usage:    "Installed tool name or tool directory",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "message",
+				Aliases: []string{"m"},
+				Usage:   "Commit message",
+				Value:   "Update tool",
+			},
+			&cli.StringFlag{
+				Name:  "tag",
+				Usage: "Create a git tag for this version",
+			},
+		},
+	}
+}
+
+// getToolInstallCommand returns the tool install subcommand.
+func getToolInstallCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "install",
+		Usage:     "Install a tool from a local directory or a git repository",
+		ArgsUsage: "<path|user/repo[@version]>",
+		Action:    HandleToolInstall(),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Force overwrite if the tool is already installed",
+			},
+		},
+	}
+}
+
+// getToolUninstallCommand returns the tool uninstall subcommand.
+func getToolUninstallCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "uninstall",
+		Aliases:   []string{"rm"},
+		Usage:     "Remove an installed tool",
+		ArgsUsage: "<name>",
+		Action:    HandleToolUninstall(),
+	}
+}
+
+// HandleToolNew handles the tool new command.
+func HandleToolNew() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("tool name required: forge tool new <name>")
+		}
+		dir := ctx.CLI.String("dir")
+		if dir == "" {
+			dir = name
+		}
+
+		data, err := toolTemplateData(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		log.Info("Creating new tool",
+			zap.String("name", name),
+			zap.String("dir", dir),
+			zap.Int("functions", len(data.Functions)))
+
+		toolService, err := do.Invoke[database.ToolService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get tool service: %w", err)
+		}
+		if err := toolService.CreateToolFiles(ctx.Context, dir, data); err != nil {
+			return err
+		}
+
+		fmt.Printf("Tool '%s' created in %s\n", name, dir)
+		fmt.Printf("Build and test it with: make -C %s build test\n", dir)
+		fmt.Printf("Install it with: forge tool install %s\n", dir)
+		return nil
+	})
+}
+
+// toolTemplateData collects the project data for tool new from its flags.
+// Functions given with --function are added after those read with --from.
+func toolTemplateData(ctx *startup.Context, name string) (templates.ToolTemplateData, error) {
+	data := templates.ToolTemplateData{
+		Name:        name,
+		Module:      ctx.CLI.String("module"),
+		Description: ctx.CLI.String("description"),
+	}
+
+	if from := ctx.CLI.String("from"); from != "" {
+		if filepath.Base(from) == schema.ManifestFileName {
+			from = filepath.Dir(from)
+		}
+		manifest, err := toolruntime.ReadManifest(from)
+		if err != nil {
+			return data, err
+		}
+		data.Functions = append(data.Functions, manifest.Spec.Functions...)
+		if data.Description == "" {
+			data.Description = manifest.Metadata.Description
+		}
+	}
+
+	for _, spec := range ctx.CLI.StringSlice("function") {
+		function, err := templates.ParseFunctionSpec(spec)
+		if err != nil {
+			return data, err
+		}
+		data.Functions = append(data.Functions, function)
+	}
+	return data, nil
+}
+
+// HandleToolPull handles the tool pull command.
+func HandleToolPull() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		repo := ctx.CLI.Args().First()
+		if repo == "" {
+			return fmt.Errorf("repository required: forge tool pull <user/repo[@version]>")
+		}
+		version := ctx.CLI.String("version")
+		if strings.Contains(repo, "@") && version == "" {
+			repo, version, _ = strings.Cut(repo, "@")
+		}
+
+		return pullTool(ctx, repo, version, ctx.CLI.Bool("force"))
+	})
+}
+
+// HandleToolPush handles the tool push command.
+func HandleToolPush() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.String("name")
+		repo := ctx.CLI.Args().First()
+		if repo == "" {
+			return fmt.Errorf("repository required: forge tool push --name %s <repo-url>", name)
+		}
+		tag := ctx.CLI.String("tag")
+
+		dir, err := resolveToolDir(ctx, name)
+		if err != nil {
+			return err
+		}
+		toolService, err := do.Invoke[database.ToolService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get tool service: %w", err)
+		}
+
+		fmt.Printf("Pushing tool '%s' to %s...\n", name, repo)
+		if err := toolService.PushTool(ctx.Context, dir, repo, ctx.CLI.String("message"), tag); err != nil {
+			return fmt.Errorf("failed to push tool: %w", err)
+		}
+
+		fmt.Printf("✓ Tool '%s' successfully pushed to %s\n", name, repo)
+		if tag != "" {
+			fmt.Printf("✓ Tagged as %s\n", tag)
+		}
+		return nil
+	})
+}
+
+// HandleToolInstall handles the tool install command.
+func HandleToolInstall() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		source := ctx.CLI.Args().First()
+		if source == "" {
+			return fmt.Errorf("tool source required: forge tool install <path|user/repo[@version]>")
+		}
+		force := ctx.CLI.Bool("force")
+
+		if _, err := os.Stat(filepath.Join(source, schema.ManifestFileName)); err != nil {
+			repo, version, _ := strings.Cut(source, "@")
+			return pullTool(ctx, repo, version, force)
+		}
+
+		toolService, err := do.Invoke[database.ToolService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get tool service: %w", err)
+		}
+		manifest, err := toolService.InstallToolFromDir(ctx.Context, source, force)
+		if err != nil {
+			return fmt.Errorf("failed to install tool: %w", err)
+		}
+
+		fmt.Printf("✓ Tool '%s' %s installed from %s\n", manifest.Metadata.Name, manifest.Metadata.Version, source)
+		printRequiredSecrets(manifest)
+		return nil
+	})
+}
+
+// HandleToolUninstall handles the tool uninstall command.
+func HandleToolUninstall() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("tool name required: forge tool uninstall <name>")
+		}
+
+		toolService, err := do.Invoke[database.ToolService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get tool service: %w", err)
+		}
+		if err := toolService.RemoveInstalledTool(ctx.Context, name); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Tool '%s' uninstalled\n", name)
+		return nil
+	})
+}
+
+// pullTool pulls and installs a tool from a repository.
+func pullTool(ctx *startup.Context, repo, version string, force bool) error {
+	log.Info("Pulling tool",
+		zap.String("repo", repo),
+		zap.String("version", version),
+		zap.Bool("force", force))
+
+	toolService, err := do.Invoke[database.ToolService](ctx.DIContainer)
+	if err != nil {
+		return fmt.Errorf("failed to get tool service: %w", err)
+	}
+
+	fmt.Printf("Pulling tool from %s", repo)
+	if version != "" {
+		fmt.Printf("@%s", version)
+	}
+	fmt.Println("...")
+
+	manifest, err := toolService.PullTool(ctx.Context, repo, version, force)
+	if err != nil {
+		return fmt.Errorf("failed to pull tool: %w", err)
+	}
+
+	fmt.Printf("✓ Tool '%s' %s successfully pulled from %s\n", manifest.Metadata.Name, manifest.Metadata.Version, repo)
+	printRequiredSecrets(manifest)
+	return nil
+}
+
+// printRequiredSecrets lists the secrets a tool reads credentials from.
+func printRequiredSecrets(manifest *schema.Tool) {
+	if len(manifest.Spec.Configuration.Secrets) == 0 {
+		return
+	}
+	fmt.Println("The tool reads these secrets, set them with forge secret set:")
+	for _, secret := range manifest.Spec.Configuration.Secrets {
+		fmt.Printf("  %s\n", secret)
+	}
+}
@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/startup"
+	"github.com/denkhaus/agentforge/internal/toolruntime"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// getToolTestCommand returns the tool test subcommand.
+func getToolTestCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "test",
+		Usage:     "Start a tool and run the examples of its functions",
+		ArgsUsage: "<name|path>",
+		Description: "Checks that every declared function is served, calls each function with the input " +
+			"of its examples and compares the result with the expected output. Examples may set " +
+			"match to exact, subset or regex, the others use --match.",
+		Action: HandleToolTest(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "match",
+				Usage: "Default output matching for examples: exact, subset or regex",
+				Value: toolruntime.MatchSubset,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for each function call",
+				Value: 30 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:  "junit",
+				Usage: "Write a JUnit XML report to this file",
+			},
+		},
+	}
+}
+
+// getToolRunCommand returns the tool run subcommand.
+func getToolRunCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "run",
+		Usage:     "Invoke a single tool function with JSON arguments, without an LLM",
+		ArgsUsage: "<function> [json-args|-]",
+		Description: "Functions of installed tools are named <tool>_<function>. Arguments are read " +
+			"from stdin when given as '-' or omitted with piped input.",
+		Action: HandleToolRun(),
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for the function",
+				Value: time.Minute,
+			},
+		},
+	}
+}
+
+// HandleToolTest handles the tool test command.
+func HandleToolTest() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		match := ctx.CLI.String("match")
+		switch match {
+		case toolruntime.MatchExact, toolruntime.MatchSubset, toolruntime.MatchRegex:
+		default:
+			return fmt.Errorf("unknown match mode %q, use exact, subset or regex", match)
+		}
+		dir, err := resolveToolDir(ctx, ctx.CLI.Args().First())
+		if err != nil {
+			return err
+		}
+		manifest, err := toolruntime.ReadManifest(dir)
+		if err != nil {
+			return err
+		}
+		name := manifest.Metadata.Name
+
+		runtime, err := do.Invoke[types.ToolRuntime](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to create tool runtime: %w", err)
+		}
+
+		started := time.Now()
+		fmt.Printf("Testing tool '%s' %s (%s)\n", name, manifest.Metadata.Version, manifest.Spec.Type)
+		loaded, err := runtime.LoadTool(ctx.Context, dir)
+		if err != nil {
+			fmt.Printf("  ✗ start: %v\n", err)
+			results := []toolruntime.ExampleResult{{Example: "start", Err: err}}
+			if reportErr := writeJUnitReport(ctx.CLI.String("junit"), name, results, started); reportErr != nil {
+				return reportErr
+			}
+			return fmt.Errorf("tool %s failed to start", name)
+		}
+		defer func() {
+			if err := runtime.UnloadTool(name); err != nil {
+				log.Warn("Failed to stop tool", zap.String("tool", name), zap.Error(err))
+			}
+		}()
+
+		served := make(map[string]bool, len(loaded))
+		for _, tool := range loaded {
+			served[tool.Name()] = true
+		}
+
+		results := make([]toolruntime.ExampleResult, 0)
+		for _, function := range manifest.Spec.Functions {
+			result := toolruntime.ExampleResult{Function: function.Name, Example: "served"}
+			if !served[name+"_"+function.Name] {
+				result.Err = fmt.Errorf("declared but not served")
+			}
+			results = append(results, result)
+			printToolCheck(result)
+		}
+
+		for _, result := range toolruntime.RunExamples(ctx.Context, manifest, loaded, match, ctx.CLI.Duration("timeout")) {
+			results = append(results, result)
+			printToolCheck(result)
+		}
+
+		monitor, err := do.Invoke[types.ToolHealthMonitor](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to create tool health monitor: %w", err)
+		}
+		health, err := monitor.CheckTool(ctx.Context, name)
+		if err != nil {
+			return err
+		}
+		result := toolruntime.ExampleResult{Example: "health", Output: string(health.State)}
+		if health.State == types.ToolHealthUnhealthy {
+			result.Err = fmt.Errorf("%s", health.LastError)
+		}
+		results = append(results, result)
+		printToolCheck(result)
+
+		if err := writeJUnitReport(ctx.CLI.String("junit"), name, results, started); err != nil {
+			return err
+		}
+
+		failures := 0
+		for _, result := range results {
+			if !result.Passed() {
+				failures++
+			}
+		}
+		if failures > 0 {
+			return fmt.Errorf("%d of %d checks failed", failures, len(results))
+		}
+		fmt.Printf("All %d checks passed\n", len(results))
+		return nil
+	})
+}
+
+// printToolCheck prints one line of the tool test report.
+func printToolCheck(result toolruntime.ExampleResult) {
+	label := result.Example
+	if result.Function != "" {
+		label = result.Function + "/" + result.Example
+	}
+
+	detail := ""
+	switch {
+	case result.Match != "":
+		detail = fmt.Sprintf(" (%s, %s)", result.Match, result.Duration.Round(time.Microsecond))
+	case result.Output != "":
+		detail = ": " + result.Output
+	}
+
+	if result.Passed() {
+		fmt.Printf("  ✓ %s%s\n", label, detail)
+		return
+	}
+	fmt.Printf("  ✗ %s%s: %v\n", label, detail, result.Err)
+}
+
+// writeJUnitReport writes the tool test results to path, if set.
+func writeJUnitReport(path, tool string, results []toolruntime.ExampleResult, started time.Time) error {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report: %w", err)
+	}
+	defer file.Close()
+
+	if err := toolruntime.WriteJUnitReport(file, tool, results, started); err != nil {
+		return err
+	}
+	fmt.Printf("JUnit report written to %s\n", path)
+	return nil
+}
+
+// HandleToolRun handles the tool run command.
+func HandleToolRun() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		name := ctx.CLI.Args().First()
+		if name == "" {
+			return fmt.Errorf("function name required: forge tool run <function> [json-args]")
+		}
+		input, err := readToolInput(ctx.CLI.Args().Get(1), ctx.CLI.Args().Len() > 1)
+		if err != nil {
+			return err
+		}
+
+		// The aggregated provider serves internal, MCP server and installed tools
+		provider, err := do.Invoke[types.ToolProvider](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to create tool provider: %w", err)
+		}
+		if !provider.HasTool(name) {
+			return fmt.Errorf("tool function %s not found%s", name, suggestTools(provider, name))
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx.Context, ctx.CLI.Duration("timeout"))
+		defer cancel()
+
+		log.Info("Running tool function", zap.String("name", name))
+		result, err := provider.ExecuteTool(runCtx, name, input)
+		if err != nil {
+			return fmt.Errorf("failed to run %s: %w", name, err)
+		}
+
+		fmt.Println(result)
+		return nil
+	})
+}
+
+// readToolInput returns the JSON arguments given on the command line, or reads them
+// from stdin for "-" or when nothing was given and stdin is not a terminal.
+func readToolInput(arg string, given bool) (string, error) {
+	if given && arg != "-" {
+		return arg, nil
+	}
+	if !given && term.IsTerminal(os.Stdin.Fd()) {
+		return "{}", nil
+	}
+
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read arguments from stdin: %w", err)
+	}
+	input := strings.TrimSpace(string(content))
+	if input == "" {
+		input = "{}"
+	}
+	return input, nil
+}
+
+// suggestTools lists available tool functions whose name contains name.
+func suggestTools(provider types.ToolProvider, name string) string {
+	matches := make([]string, 0)
+	for _, tool := range provider.GetTools() {
+		if strings.Contains(tool.Name(), name) {
+			matches = append(matches, tool.Name())
+		}
+	}
+	if len(matches) == 0 {
+		return ", list installed tools with: forge tool list"
+	}
+	sort.Strings(matches)
+	return ", did you mean: " + strings.Join(matches, ", ")
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/do"
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/startup"
+)
+
+// GetOutdatedCommand returns the outdated command configuration.
+func GetOutdatedCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "outdated",
+		Usage:  "List locked components with newer compatible or breaking tags upstream",
+		Action: HandleOutdated(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "lockfile",
+				Usage: "Lockfile pinning the resolved components",
+				Value: resolver.LockfileName,
+			},
+		},
+	}
+}
+
+// GetUpgradeCommand returns the upgrade command configuration.
+func GetUpgradeCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "upgrade",
+		Usage:  "Upgrade components to the newest versions within the workspace ranges",
+		Action: HandleUpgrade(),
+		Flags: append(workspaceFlags(),
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show the changes without applying them",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Apply the changes without asking",
+			},
+		),
+	}
+}
+
+// HandleOutdated handles the outdated command.
+func HandleOutdated() cli.ActionFunc {
+	return startup.WithStartup(startup.Minimal()...)(func(ctx *startup.Context) error {
+		lockPath := ctx.CLI.String("lockfile")
+		lockfile, err := resolver.ReadLockfile(lockPath)
+		if err != nil {
+			return fmt.Errorf("%w, run forge lock first", err)
+		}
+		source, err := do.Invoke[resolver.Source](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get component source: %w", err)
+		}
+
+		updates, err := resolver.Outdated(ctx.Context, source, lockfile)
+		if err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			fmt.Println("All components are up to date")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "COMPONENT\tCURRENT\tCOMPATIBLE\tLATEST\tREQUIREMENTS")
+		for _, update := range updates {
+			compatible := update.Compatible
+			if !update.HasCompatible() {
+				compatible = "-"
+			}
+			latest := update.Latest
+			if update.HasBreaking() {
+				latest += " (breaking)"
+			}
+			requirements := strings.Join(update.Component.Requirements, ", ")
+			if requirements == "" {
+				requirements = "*"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+				update.Component.Key(), update.Component.Ref, compatible, latest, requirements)
+		}
+		writer.Flush()
+		fmt.Println("Run forge upgrade to apply compatible updates, breaking updates need a wider range in the workspace")
+		return nil
+	})
+}
+
+// HandleUpgrade handles the upgrade command.
+func HandleUpgrade() cli.ActionFunc {
+	return startup.WithStartup(startup.Database()...)(func(ctx *startup.Context) error {
+		workspacePath := ctx.CLI.String("workspace")
+		lockPath := ctx.CLI.String("lockfile")
+		workspace, err := resolver.ReadWorkspace(workspacePath)
+		if err != nil {
+			return err
+		}
+		var current *resolver.Lockfile
+		if _, err := os.Stat(lockPath); err == nil {
+			if current, err = resolver.ReadLockfile(lockPath); err != nil {
+				return err
+			}
+		}
+
+		dependencyResolver, err := do.Invoke[resolver.Resolver](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get dependency resolver: %w", err)
+		}
+		source, err := do.Invoke[resolver.Source](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get component source: %w", err)
+		}
+
+		upgraded, err := dependencyResolver.Resolve(ctx.Context, workspace.Requirements())
+		if err != nil {
+			return fmt.Errorf("failed to resolve dependencies:\n%w", err)
+		}
+		changes := resolver.Changes(current, upgraded)
+		if len(changes) == 0 {
+			fmt.Println("All components are up to date")
+			return nil
+		}
+		if err := printChanges(ctx, source, changes); err != nil {
+			return err
+		}
+
+		if ctx.CLI.Bool("dry-run") {
+			return nil
+		}
+		if !ctx.CLI.Bool("yes") && !confirm("Apply these changes?") {
+			fmt.Println("Upgrade cancelled")
+			return nil
+		}
+
+		installService, err := do.Invoke[database.InstallService](ctx.DIContainer)
+		if err != nil {
+			return fmt.Errorf("failed to get install service: %w", err)
+		}
+		return upgradeLockfile(ctx.Context, os.Stdout, installService, upgraded, lockPath)
+	})
+}
+
+// upgradeLockfile installs the components of the upgraded lockfile and writes it to
+// lockPath once all of them are installed. A failed install keeps the previous
+// lockfile, so forge install restores the locked versions.
+func upgradeLockfile(ctx context.Context, out io.Writer, installService database.InstallService, upgraded *resolver.Lockfile, lockPath string) error {
+	if err := installComponents(ctx, out, installService, upgraded, false); err != nil {
+		return fmt.Errorf("%w, %s was left unchanged", err, lockPath)
+	}
+	return upgraded.Write(lockPath)
+}
+
+// printChanges prints the changes of an upgrade and the spec diff of changed manifests.
+func printChanges(ctx *startup.Context, source resolver.Source, changes []resolver.Change) error {
+	for _, change := range changes {
+		switch {
+		case change.From == nil:
+			fmt.Printf("+ %s %s (%s)\n", change.Key, change.To.Version, lockedRef(change.To))
+		case change.To == nil:
+			fmt.Printf("- %s %s, no longer required and left installed\n", change.Key, change.From.Version)
+		default:
+			fmt.Printf("~ %s %s (%s) -> %s (%s)\n", change.Key,
+				change.From.Version, lockedRef(change.From), change.To.Version, lockedRef(change.To))
+		}
+		if !change.SpecChanged() {
+			continue
+		}
+
+		from, err := source.Fetch(ctx.Context, change.From.Repository, change.From.Ref)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s %s: %w", change.Key, change.From.Version, err)
+		}
+		to, err := source.Fetch(ctx.Context, change.To.Repository, change.To.Ref)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s %s: %w", change.Key, change.To.Version, err)
+		}
+		fmt.Println(resolver.SpecDiff(from, to))
+	}
+	return nil
+}
+
+// lockedRef describes the ref of a locked component.
+func lockedRef(component *resolver.LockedComponent) string {
+	if component.Ref == "" {
+		return "default branch@" + shortCommit(component.Commit)
+	}
+	return component.Ref
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -2,6 +2,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -45,6 +47,29 @@ type Config struct {
 	MCPServerTimeout int    `envconfig:"MCP_SERVER_TIMEOUT" default:"30"`
 	MCPToolPrefix    string `envconfig:"MCP_TOOL_PREFIX" default:""`
 	MCPHotReload     bool   `envconfig:"MCP_HOT_RELOAD" default:"false"`
+	MCPLogDir        string `envconfig:"MCP_LOG_DIR" default:""`
+	MCPHealthInterval int   `envconfig:"MCP_HEALTH_INTERVAL" default:"30"`
+	MCPMaxRestarts   int    `envconfig:"MCP_MAX_RESTARTS" default:"5"`
+
+	// ToolsDir is the directory installed tool manifests are loaded from
+	ToolsDir string `envconfig:"TOOLS_DIR" default:""`
+
+	// PromptsDir is the directory installed prompts are resolved from
+	PromptsDir string `envconfig:"PROMPTS_DIR" default:""`
+
+	// AgentsDir is the directory pulled agents are installed into
+	AgentsDir string `envconfig:"AGENTS_DIR" default:""`
+
+	// HistoryDir is the directory prompt test runs are recorded in
+	HistoryDir string `envconfig:"HISTORY_DIR" default:""`
+
+	// ToolCgroupRoot is a delegated cgroup v2 directory used to limit tool processes
+	ToolCgroupRoot string `envconfig:"TOOL_CGROUP_ROOT" default:""`
+
+	// Secrets store configuration
+	SecretsFile       string `envconfig:"SECRETS_FILE" default:""`
+	SecretsKeyFile    string `envconfig:"SECRETS_KEY_FILE" default:""`
+	SecretsPassphrase string `envconfig:"SECRETS_PASSPHRASE" default:""`
 }
 
 // Load reads configuration from environment variables and returns a Config struct.
@@ -66,6 +91,9 @@ func Load(c *cli.Context) (*Config, error) {
 		cfg.Port = c.Int("port")
 	}
 
+	// Fill API keys missing from the environment from the secret store
+	cfg.applyStoredSecrets()
+
 	// Initialize API keys map
 	cfg.initializeAPIKeys()
 
@@ -152,6 +180,47 @@ func (c *Config) GetAPIKeys() map[string]string {
 	return result
 }
 
+// GetToolsDir returns the directory of installed tools, defaulting to ~/.agentforge/tools.
+func (c *Config) GetToolsDir() string {
+	if c.ToolsDir != "" {
+		return c.ToolsDir
+	}
+	return dataPath("tools")
+}
+
+// GetPromptsDir returns the directory of installed prompts, defaulting to ~/.agentforge/prompts.
+func (c *Config) GetPromptsDir() string {
+	if c.PromptsDir != "" {
+		return c.PromptsDir
+	}
+	return dataPath("prompts")
+}
+
+// GetAgentsDir returns the directory of installed agents, defaulting to ~/.agentforge/agents.
+func (c *Config) GetAgentsDir() string {
+	if c.AgentsDir != "" {
+		return c.AgentsDir
+	}
+	return dataPath("agents")
+}
+
+// GetHistoryDir returns the directory of recorded test runs, defaulting to ~/.agentforge/history.
+func (c *Config) GetHistoryDir() string {
+	if c.HistoryDir != "" {
+		return c.HistoryDir
+	}
+	return dataPath("history")
+}
+
+// dataPath returns a path below ~/.agentforge, or below the temp dir without a home directory.
+func dataPath(name string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "agentforge", name)
+	}
+	return filepath.Join(homeDir, ".agentforge", name)
+}
+
 // GetTimeout returns a default timeout for operations.
 func (c *Config) GetTimeout() time.Duration {
 	return 30 * time.Second
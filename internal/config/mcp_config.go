@@ -1,6 +1,13 @@
 // Package config provides MCP-specific configuration management.
 package config
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
 // MCPConfig holds configuration for MCP server integration.
 type MCPConfig struct {
 	// Enabled controls whether MCP integration is active
@@ -17,6 +24,46 @@ type MCPConfig struct {
 	
 	// EnableHotReload enables hot-reloading of MCP server configuration
 	EnableHotReload bool `envconfig:"MCP_HOT_RELOAD" default:"false"`
+
+	// LogDir is the directory receiving the captured stderr of MCP servers
+	LogDir string `envconfig:"MCP_LOG_DIR" default:""`
+
+	// HealthInterval is the interval between health pings in seconds
+	HealthInterval int `envconfig:"MCP_HEALTH_INTERVAL" default:"30"`
+
+	// MaxRestarts is the number of consecutive restarts before a server is given up
+	MaxRestarts int `envconfig:"MCP_MAX_RESTARTS" default:"5"`
+}
+
+// GetLogDir returns the MCP server log directory, defaulting to ~/.agentforge/logs/mcp.
+func (c *MCPConfig) GetLogDir() string {
+	if c.LogDir != "" {
+		return c.LogDir
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "agentforge", "logs", "mcp")
+	}
+	return filepath.Join(homeDir, ".agentforge", "logs", "mcp")
+}
+
+// GetControlSocket returns the control socket of the forge process supervising the
+// servers of ConfigPath, ~/.agentforge/run/mcp-<hash>.sock. The name hashes the
+// absolute configuration path, so projects with different server files do not share it.
+func (c *MCPConfig) GetControlSocket() string {
+	configPath, err := filepath.Abs(c.ConfigPath)
+	if err != nil {
+		configPath = c.ConfigPath
+	}
+	sum := sha256.Sum256([]byte(configPath))
+	name := "mcp-" + hex.EncodeToString(sum[:6]) + ".sock"
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "agentforge", "run", name)
+	}
+	return filepath.Join(homeDir, ".agentforge", "run", name)
 }
 
 // GetMCPConfig returns the MCP configuration from the main config.
@@ -31,12 +78,25 @@ func (c *Config) GetMCPConfig() *MCPConfig {
 		serverTimeout = 30
 	}
 	
+	healthInterval := c.MCPHealthInterval
+	if healthInterval == 0 {
+		healthInterval = 30
+	}
+
+	maxRestarts := c.MCPMaxRestarts
+	if maxRestarts == 0 {
+		maxRestarts = 5
+	}
+
 	return &MCPConfig{
 		Enabled:         c.MCPEnabled,
 		ConfigPath:      configPath,
 		ServerTimeout:   serverTimeout,
 		ToolPrefix:      c.MCPToolPrefix,
 		EnableHotReload: c.MCPHotReload,
+		LogDir:          c.MCPLogDir,
+		HealthInterval:  healthInterval,
+		MaxRestarts:     maxRestarts,
 	}
 }
 
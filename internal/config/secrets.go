@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/secrets"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// GetSecretsFile returns the encrypted secrets file, defaulting to ~/.agentforge/secrets.json.
+func (c *Config) GetSecretsFile() string {
+	if c.SecretsFile != "" {
+		return c.SecretsFile
+	}
+	return dataPath("secrets.json")
+}
+
+// GetSecretsKeyFile returns the key file used when no passphrase is set,
+// defaulting to ~/.agentforge/secrets.key.
+func (c *Config) GetSecretsKeyFile() string {
+	if c.SecretsKeyFile != "" {
+		return c.SecretsKeyFile
+	}
+	return dataPath("secrets.key")
+}
+
+// NewSecretStore opens the configured encrypted secret store.
+func (c *Config) NewSecretStore() (types.SecretStore, error) {
+	return secrets.NewFileStore(secrets.Options{
+		Path:       c.GetSecretsFile(),
+		KeyFile:    c.GetSecretsKeyFile(),
+		Passphrase: c.SecretsPassphrase,
+	})
+}
+
+// apiKeySecrets maps the secret names of provider API keys to their config fields.
+// Secrets are named after the environment variables they replace.
+func (c *Config) apiKeySecrets() map[string]*string {
+	return map[string]*string{
+		"GOOGLE_API_KEY":    &c.GoogleAPIKey,
+		"OPENAI_API_KEY":    &c.OpenAIAPIKey,
+		"ANTHROPIC_API_KEY": &c.AnthropicAPIKey,
+		"AZURE_API_KEY":     &c.AzureAPIKey,
+		"GITHUB_API_KEY":    &c.GitHubAPIKey,
+		"GITHUB_TOKEN":      &c.GitHubToken,
+	}
+}
+
+// ApplySecrets fills the API keys that are not set in the environment from lookup.
+func (c *Config) ApplySecrets(lookup func(name string) (string, bool)) {
+	for name, field := range c.apiKeySecrets() {
+		if *field != "" {
+			continue
+		}
+		if value, ok := lookup(name); ok {
+			*field = value
+		}
+	}
+	c.apiKeys = nil
+}
+
+// applyStoredSecrets applies the secret store if one exists. Failures to open it are
+// logged so that environment-only setups keep working.
+func (c *Config) applyStoredSecrets() {
+	if _, err := os.Stat(c.GetSecretsFile()); err != nil {
+		return
+	}
+
+	store, err := c.NewSecretStore()
+	if err == nil {
+		_, err = store.List()
+	}
+	if err != nil {
+		log.Warn("Failed to open secret store", zap.Error(err))
+		return
+	}
+	c.ApplySecrets(store.Lookup)
+}
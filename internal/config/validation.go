@@ -45,6 +45,11 @@ func (c *Config) ValidateAPIKeysIfNeeded() error {
 			return nil
 		}
 		
+		// Secret management must work before any API key is configured
+		if commandName == "secret" {
+			return nil
+		}
+		
 		// Version command doesn't need any API keys
 		if commandName == "version" || commandName == "v" {
 			return nil
@@ -2,17 +2,30 @@
 package container
 
 import (
+	"context"
+	"path/filepath"
+
 	"github.com/samber/do"
+	"github.com/tmc/langchaingo/tools"
 	"go.uber.org/zap"
 
+	"github.com/denkhaus/agentforge/internal/agents"
 	"github.com/denkhaus/agentforge/internal/config"
 	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/decorators"
 	"github.com/denkhaus/agentforge/internal/git"
 	"github.com/denkhaus/agentforge/internal/github"
+	"github.com/denkhaus/agentforge/internal/llm"
 	"github.com/denkhaus/agentforge/internal/logger"
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/promptrender"
 	"github.com/denkhaus/agentforge/internal/prompts"
 	"github.com/denkhaus/agentforge/internal/providers"
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/secrets"
 	"github.com/denkhaus/agentforge/internal/session"
+	"github.com/denkhaus/agentforge/internal/toolruntime"
+	agenttools "github.com/denkhaus/agentforge/internal/tools"
 	"github.com/denkhaus/agentforge/internal/tui"
 	"github.com/denkhaus/agentforge/internal/types"
 )
@@ -38,6 +51,11 @@ func Setup(cfg *config.Config) *do.Injector {
 		return logger.Create(cfg.LogLevel)
 	})
 
+	// Register LLM service creating models for the configured providers
+	do.Provide(newInjector, func(i *do.Injector) (types.LLMService, error) {
+		return llm.NewLLMService(), nil
+	})
+
 	// Register session factory (only factory we keep - adds real value)
 	do.Provide(newInjector, func(i *do.Injector) (types.SessionFactory, error) {
 		// Correctly invoke LLMService
@@ -45,6 +63,40 @@ func Setup(cfg *config.Config) *do.Injector {
 		return session.NewFactory(llmService), nil
 	})
 
+	// Register agent provider building the installed agents from their manifests
+	do.Provide(newInjector, func(i *do.Injector) (types.AgentProvider, error) {
+		cfg := do.MustInvoke[*config.Config](i)
+		return agents.NewAgentProvider(cfg.GetAgentsDir(), promptrender.NewResolver("prompts", cfg.GetPromptsDir()))
+	})
+
+	// Register built-in tools consumed by the internal tool provider
+	do.Provide(newInjector, func(i *do.Injector) ([]tools.Tool, error) {
+		return agenttools.GetTools(), nil
+	})
+
+	// Register built-in prompts consumed by the local prompt provider
+	do.Provide(newInjector, func(i *do.Injector) (map[string]types.Prompt, error) {
+		return make(map[string]types.Prompt), nil
+	})
+
+	// Register MCP server registry shared by the MCP tool, resource and prompt providers
+	do.ProvideNamed(newInjector, "mcpRegistry", func(i *do.Injector) (types.MCPServerRegistry, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		config := do.MustInvoke[*config.Config](i)
+		return providers.NewMCPServerRegistry(log, config.GetMCPConfig())
+	})
+
+	// Register MCP supervisor tracking, health-checking and restarting the MCP servers
+	do.Provide(newInjector, func(i *do.Injector) (types.MCPSupervisor, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		config := do.MustInvoke[*config.Config](i)
+		registry, err := do.InvokeNamed[types.MCPServerRegistry](i, "mcpRegistry")
+		if err != nil {
+			return nil, err
+		}
+		return providers.NewMCPSupervisor(log, config.GetMCPConfig(), registry)
+	})
+
 	// Register individual tool providers with specific names to avoid circular dependency
 	do.ProvideNamed(newInjector, "internalProvider", func(i *do.Injector) (types.ToolProvider, error) {
 		return providers.NewToolProvider(i)
@@ -54,8 +106,61 @@ func Setup(cfg *config.Config) *do.Injector {
 	do.ProvideNamed(newInjector, "mcpProvider", func(i *do.Injector) (types.ToolProvider, error) {
 		log := do.MustInvoke[*zap.Logger](i)
 		config := do.MustInvoke[*config.Config](i)
-		mcpConfig := config.GetMCPConfig()
-		return providers.NewMCPToolProvider(log, mcpConfig)
+		registry, err := do.InvokeNamed[types.MCPServerRegistry](i, "mcpRegistry")
+		if err != nil {
+			return nil, err
+		}
+		supervisor, err := do.Invoke[types.MCPSupervisor](i)
+		if err != nil {
+			return nil, err
+		}
+		return providers.NewMCPToolProviderWithRegistry(log, config.GetMCPConfig(), registry, supervisor)
+	})
+
+	// Register encrypted secret store
+	do.Provide(newInjector, func(i *do.Injector) (types.SecretStore, error) {
+		config := do.MustInvoke[*config.Config](i)
+		return config.NewSecretStore()
+	})
+
+	// Register tool runtime launching installed tool manifests
+	do.Provide(newInjector, func(i *do.Injector) (types.ToolRuntime, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		config := do.MustInvoke[*config.Config](i)
+		store, err := do.Invoke[types.SecretStore](i)
+		if err != nil {
+			return nil, err
+		}
+		return toolruntime.NewToolRuntime(log, toolruntime.Options{
+			Timeout:    config.GetTimeout(),
+			CgroupRoot: config.ToolCgroupRoot,
+			Secrets:    secrets.NewLookup(store),
+		}), nil
+	})
+
+	// Register health monitor evaluating the health checks of installed tools
+	do.Provide(newInjector, func(i *do.Injector) (types.ToolHealthMonitor, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		runtime, err := do.Invoke[types.ToolRuntime](i)
+		if err != nil {
+			return nil, err
+		}
+		return toolruntime.NewHealthMonitor(log, runtime)
+	})
+
+	// Register installed tool provider
+	do.ProvideNamed(newInjector, "installedProvider", func(i *do.Injector) (types.ToolProvider, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		config := do.MustInvoke[*config.Config](i)
+		runtime, err := do.Invoke[types.ToolRuntime](i)
+		if err != nil {
+			return nil, err
+		}
+		monitor, err := do.Invoke[types.ToolHealthMonitor](i)
+		if err != nil {
+			return nil, err
+		}
+		return providers.NewInstalledToolProvider(log, runtime, monitor, config.GetToolsDir())
 	})
 
 	// Register aggregated tool provider (what consumers actually use)
@@ -65,15 +170,64 @@ func Setup(cfg *config.Config) *do.Injector {
 		// Get individual providers by name to avoid circular dependency
 		internalProvider := do.MustInvokeNamed[types.ToolProvider](i, "internalProvider")
 		mcpProvider := do.MustInvokeNamed[types.ToolProvider](i, "mcpProvider")
+		installedProvider := do.MustInvokeNamed[types.ToolProvider](i, "installedProvider")
 
 		// Create aggregated provider
-		return providers.NewAggregatedToolProvider(log, internalProvider, mcpProvider), nil
+		return providers.NewAggregatedToolProvider(log, internalProvider, mcpProvider, installedProvider), nil
 	})
 
-	do.Provide(newInjector, func(i *do.Injector) (types.PromptProvider, error) {
+	// Register decorated tool provider for externally exposed tool execution
+	do.ProvideNamed(newInjector, "decoratedToolProvider", func(i *do.Injector) (types.ToolProvider, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		toolProvider := do.MustInvoke[types.ToolProvider](i)
+
+		decorated := decorators.NewCircuitBreakerToolProviderDecoratorWithDefaults(toolProvider, log)
+
+		// Feed tool health changes into the circuits of the affected tools
+		if circuitBreaker, ok := decorated.(*decorators.CircuitBreakerToolProviderDecorator); ok {
+			monitor, err := do.Invoke[types.ToolHealthMonitor](i)
+			if err != nil {
+				return nil, err
+			}
+			monitor.OnHealthChange(circuitBreaker.ReportHealth)
+		}
+
+		decorated = decorators.NewMetricsToolProviderDecorator(decorated, log)
+		return decorators.NewLoggingToolProviderDecorator(decorated, log), nil
+	})
+
+	// Register individual prompt providers
+	do.ProvideNamed(newInjector, "localPromptProvider", func(i *do.Injector) (types.PromptProvider, error) {
 		return providers.NewPromptProvider(i)
 	})
 
+	do.ProvideNamed(newInjector, "mcpPromptProvider", func(i *do.Injector) (types.PromptProvider, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		registry, err := do.InvokeNamed[types.MCPServerRegistry](i, "mcpRegistry")
+		if err != nil {
+			return nil, err
+		}
+		return providers.NewMCPPromptProvider(log, registry), nil
+	})
+
+	// Register aggregated prompt provider (local prompts take precedence over MCP prompts)
+	do.Provide(newInjector, func(i *do.Injector) (types.PromptProvider, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		localProvider := do.MustInvokeNamed[types.PromptProvider](i, "localPromptProvider")
+		mcpProvider := do.MustInvokeNamed[types.PromptProvider](i, "mcpPromptProvider")
+		return providers.NewAggregatedPromptProvider(log, localProvider, mcpProvider), nil
+	})
+
+	// Register MCP resource provider
+	do.Provide(newInjector, func(i *do.Injector) (types.ResourceProvider, error) {
+		log := do.MustInvoke[*zap.Logger](i)
+		registry, err := do.InvokeNamed[types.MCPServerRegistry](i, "mcpRegistry")
+		if err != nil {
+			return nil, err
+		}
+		return providers.NewMCPResourceProvider(log, registry), nil
+	})
+
 	// Register database services
 	do.Provide(newInjector, func(i *do.Injector) (database.DatabaseManager, error) {
 		cfg := do.MustInvoke[*config.Config](i)
@@ -101,7 +255,11 @@ func Setup(cfg *config.Config) *do.Injector {
 	// Register database services (RepositoryService and ConfigService)
 	do.Provide(newInjector, func(i *do.Injector) (database.RepositoryService, error) {
 		client := do.MustInvoke[database.DatabaseClient](i)
-		return database.NewRepositoryService(client), nil
+		store, err := do.Invoke[types.SecretStore](i)
+		if err != nil {
+			return nil, err
+		}
+		return database.NewRepositoryService(client, store), nil
 	})
 
 	do.Provide(newInjector, func(i *do.Injector) (database.ConfigService, error) {
@@ -112,24 +270,76 @@ func Setup(cfg *config.Config) *do.Injector {
 	// Register TUI manager
 	do.Provide(newInjector, func(i *do.Injector) (types.TUIManager, error) {
 		log := do.MustInvoke[*zap.Logger](i)
-		return tui.NewManager(log), nil
+		llmService := do.MustInvoke[types.LLMService](i)
+		cfg := do.MustInvoke[*config.Config](i)
+		history := prompteval.NewRunHistory(filepath.Join(cfg.GetHistoryDir(), "prompts"))
+		promptService := do.MustInvoke[prompts.PromptService](i)
+		return tui.NewManager(log, llmService, cfg, history, promptService), nil
 	})
 
 	// Register Prompt service
 	do.Provide(newInjector, func(i *do.Injector) (prompts.PromptService, error) {
-		return prompts.NewPromptService(), nil
+		cfg := do.MustInvoke[*config.Config](i)
+		gitClient := do.MustInvoke[*git.Client](i)
+		syncService := do.MustInvoke[database.SyncService](i)
+		repositories := do.MustInvoke[database.RepositoryService](i)
+		return prompts.NewPromptService(gitClient, syncService, repositories, cfg.GetPromptsDir(), cfg.GitHubToken), nil
+	})
+
+	// Register Sync service
+	do.Provide(newInjector, func(i *do.Injector) (database.SyncService, error) {
+		client := do.MustInvoke[database.DatabaseClient](i)
+		return database.NewSyncService(client), nil
 	})
 
 	// Register Tool service
 	do.Provide(newInjector, func(i *do.Injector) (database.ToolService, error) {
 		client := do.MustInvoke[database.DatabaseClient](i)
-		return database.NewToolService(client), nil
+		cfg := do.MustInvoke[*config.Config](i)
+		gitClient := do.MustInvoke[*git.Client](i)
+		syncService := do.MustInvoke[database.SyncService](i)
+		repositories := do.MustInvoke[database.RepositoryService](i)
+		return database.NewToolService(client, gitClient, syncService, repositories, cfg.GetToolsDir(), cfg.GitHubToken), nil
 	})
 
 	// Register Agent service
 	do.Provide(newInjector, func(i *do.Injector) (database.AgentService, error) {
 		client := do.MustInvoke[database.DatabaseClient](i)
-		return database.NewAgentService(client), nil
+		cfg := do.MustInvoke[*config.Config](i)
+		gitClient := do.MustInvoke[*git.Client](i)
+		toolService := do.MustInvoke[database.ToolService](i)
+		promptService := do.MustInvoke[prompts.PromptService](i)
+		syncService := do.MustInvoke[database.SyncService](i)
+		repositories := do.MustInvoke[database.RepositoryService](i)
+		return database.NewAgentService(client, gitClient, toolService, promptService, syncService,
+			repositories, cfg.GetAgentsDir(), cfg.GetPromptsDir(), cfg.GitHubToken), nil
+	})
+
+	// Register component source and dependency resolver
+	do.Provide(newInjector, func(i *do.Injector) (resolver.Source, error) {
+		gitClient := do.MustInvoke[*git.Client](i)
+		cfg := do.MustInvoke[*config.Config](i)
+		repositories := do.MustInvoke[database.RepositoryService](i)
+		token := func(ctx context.Context, repository string) string {
+			return database.RemoteAccessToken(ctx, repositories, repository, cfg.GitHubToken)
+		}
+		return resolver.NewGitSource(gitClient, token), nil
+	})
+
+	do.Provide(newInjector, func(i *do.Injector) (resolver.Resolver, error) {
+		source := do.MustInvoke[resolver.Source](i)
+		return resolver.NewResolver(source), nil
+	})
+
+	// Register Install service
+	do.Provide(newInjector, func(i *do.Injector) (database.InstallService, error) {
+		client := do.MustInvoke[database.DatabaseClient](i)
+		toolService := do.MustInvoke[database.ToolService](i)
+		promptService := do.MustInvoke[prompts.PromptService](i)
+		agentService := do.MustInvoke[database.AgentService](i)
+		source := do.MustInvoke[resolver.Source](i)
+		cfg := do.MustInvoke[*config.Config](i)
+		return database.NewInstallService(client, toolService, promptService, agentService, source, cfg.GetPromptsDir()), nil
 	})
 
 	return newInjector
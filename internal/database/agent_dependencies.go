@@ -0,0 +1,339 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database/ent"
+	"github.com/denkhaus/agentforge/internal/database/ent/agent"
+	"github.com/denkhaus/agentforge/internal/database/ent/agentdependency"
+	"github.com/denkhaus/agentforge/internal/database/ent/tool"
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// agentDependency is a dependency of an agent manifest and the version it is installed at.
+type agentDependency struct {
+	schema.AgentDependency
+	installed string
+}
+
+// dependenciesOf returns the dependencies declared by an agent manifest.
+func dependenciesOf(manifest *schema.Agent) []agentDependency {
+	declared := manifest.Dependencies()
+	dependencies := make([]agentDependency, len(declared))
+	for i, dependency := range declared {
+		dependencies[i] = agentDependency{AgentDependency: dependency}
+	}
+	return dependencies
+}
+
+// resolveDependencies installs the dependencies of an agent that are missing or
+// installed at a version outside their range. Optional dependencies that fail are skipped.
+func (as *agentService) resolveDependencies(ctx context.Context, manifest *schema.Agent, pulling map[string]bool) ([]agentDependency, error) {
+	dependencies := dependenciesOf(manifest)
+	for i := range dependencies {
+		dependency := &dependencies[i]
+		err := as.resolveDependency(ctx, dependency, pulling)
+		if err == nil {
+			continue
+		}
+		if dependency.Required {
+			return nil, fmt.Errorf("failed to resolve %s %s of agent %s: %w",
+				strings.ToLower(string(dependency.Kind)), dependency.Name, manifest.Metadata.Name, err)
+		}
+		log.Warn("Skipping optional agent dependency",
+			zap.String("kind", string(dependency.Kind)),
+			zap.String("name", dependency.Name),
+			zap.Error(err))
+	}
+	return dependencies, nil
+}
+
+// resolveDependency pulls a dependency unless it is installed at a version satisfying
+// the requested range and sets the installed version.
+func (as *agentService) resolveDependency(ctx context.Context, dependency *agentDependency, pulling map[string]bool) error {
+	installed, ok, err := as.installedVersion(ctx, dependency.Kind, dependency.Name)
+	if err != nil {
+		return err
+	}
+	if ok && resolver.Satisfies(installed, dependency.Version) {
+		dependency.installed = installed
+		return nil
+	}
+	if !schema.IsRepository(dependency.Repository) {
+		return fmt.Errorf("not installed and source %q is not a repository", dependency.Source)
+	}
+
+	// Ranges are resolved to the highest matching tag of the repository
+	url := git.RepositoryURL(dependency.Repository)
+	tags, err := as.git.ListTags(ctx, url, RemoteAccessToken(ctx, as.repositories, url, as.token))
+	if err != nil {
+		return err
+	}
+	ref, err := resolver.ResolveRef(tags, dependency.Version)
+	if err != nil {
+		return fmt.Errorf("failed to resolve version of %s: %w", dependency.Repository, err)
+	}
+
+	// An installed dependency at another version is replaced
+	var name, version string
+	switch dependency.Kind {
+	case schema.KindTool:
+		if as.tools == nil {
+			return fmt.Errorf("tool service not available")
+		}
+		manifest, err := as.tools.PullTool(ctx, dependency.Repository, ref, ok)
+		if err != nil {
+			return err
+		}
+		name, version = manifest.Metadata.Name, manifest.Metadata.Version
+	case schema.KindPrompt:
+		if as.prompts == nil {
+			return fmt.Errorf("prompt service not available")
+		}
+		manifest, err := as.prompts.PullPrompt(ctx, dependency.Repository, ref, ok)
+		if err != nil {
+			return err
+		}
+		name, version = manifest.Metadata.Name, manifest.Metadata.Version
+	default:
+		manifest, err := as.pullAgent(ctx, dependency.Repository, ref, ok, pulling)
+		if err != nil {
+			return err
+		}
+		name, version = manifest.Metadata.Name, manifest.Metadata.Version
+	}
+
+	if name != dependency.Name {
+		log.Warn("Dependency source installs a component of another name",
+			zap.String("dependency", dependency.Name),
+			zap.String("installed", name))
+	}
+	dependency.installed = version
+	return nil
+}
+
+// installedVersion returns the installed version of a dependency.
+func (as *agentService) installedVersion(ctx context.Context, kind schema.ComponentKind, name string) (string, bool, error) {
+	switch kind {
+	case schema.KindTool:
+		row, err := as.client.GetEnt().Tool.Query().Where(tool.Name(name), tool.IsInstalled(true)).First(ctx)
+		if ent.IsNotFound(err) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get installed tool: %w", err)
+		}
+		return row.Version, true, nil
+	case schema.KindAgent:
+		row, err := as.client.GetEnt().Agent.Query().Where(agent.Name(name), agent.IsInstalled(true)).First(ctx)
+		if ent.IsNotFound(err) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get installed agent: %w", err)
+		}
+		return row.Version, true, nil
+	default:
+		prompt, ok := installedPrompt(as.promptsDir, name)
+		if !ok {
+			return "", false, nil
+		}
+		return prompt.Metadata.Version, true, nil
+	}
+}
+
+// installedPrompt returns the manifest of a prompt installed in promptsDir.
+func installedPrompt(promptsDir, name string) (*schema.Prompt, bool) {
+	content, err := os.ReadFile(filepath.Join(promptsDir, name, schema.ManifestFileName))
+	if err != nil {
+		return nil, false
+	}
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	if err != nil {
+		return nil, false
+	}
+	prompt, ok := component.(*schema.Prompt)
+	return prompt, ok
+}
+
+// recordInstall creates or updates the agent row of an install and replaces its
+// dependency edges. Other installed versions of the agent are marked as uninstalled.
+func (as *agentService) recordInstall(ctx context.Context, manifest *schema.Agent, source componentSource, installPath string, dependencies []agentDependency) error {
+	repo, err := ensureRepository(ctx, as.client, source)
+	if err != nil {
+		return err
+	}
+
+	spec, err := schema.NewComponentParser().SerializeComponent(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to serialize agent manifest: %w", err)
+	}
+	hash := sha256.Sum256(spec)
+	specHash := hex.EncodeToString(hash[:])
+
+	if _, err := as.client.GetEnt().Agent.Update().
+		Where(agent.Name(manifest.Metadata.Name), agent.IsInstalled(true)).
+		SetIsInstalled(false).
+		ClearInstallPath().
+		ClearInstalledAt().
+		Save(ctx); err != nil {
+		return fmt.Errorf("failed to update installed agents: %w", err)
+	}
+
+	req := agentRequestFromManifest(manifest, string(spec), specHash, repo.ID, source, dependencies)
+	existing, err := as.GetAgentByName(ctx, manifest.Metadata.Name, manifest.Metadata.Version, repo.ID)
+	if err != nil {
+		if existing, err = as.CreateAgent(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	_, err = existing.Update().
+		SetSpec(string(spec)).
+		SetSpecHash(specHash).
+		SetCommitHash(source.commitHash).
+		SetBranch(source.branch).
+		SetToolDependencies(req.ToolDependencies).
+		SetPromptDependencies(req.PromptDependencies).
+		SetAgentDependencies(req.AgentDependencies).
+		SetIsInstalled(true).
+		SetInstallPath(installPath).
+		SetInstalledAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record agent install: %w", err)
+	}
+	return as.replaceDependencies(ctx, existing.ID, dependencies)
+}
+
+// replaceDependencies replaces the dependency edges of an agent. Dependency names
+// are prefixed with their kind, since tools, prompts and agents share the table.
+func (as *agentService) replaceDependencies(ctx context.Context, agentID string, dependencies []agentDependency) error {
+	client := as.client.GetEnt()
+	if _, err := client.AgentDependency.Delete().
+		Where(agentdependency.HasAgentWith(agent.ID(agentID))).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove agent dependencies: %w", err)
+	}
+
+	creates := make([]*ent.AgentDependencyCreate, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		dependencyType := agentdependency.TypeRUNTIME
+		if !dependency.Required {
+			dependencyType = agentdependency.TypeOPTIONAL
+		}
+		versionRange := dependency.Version
+		if versionRange == "" {
+			versionRange = "*"
+		}
+		creates = append(creates, client.AgentDependency.Create().
+			SetID(uuid.New().String()).
+			SetAgentID(agentID).
+			SetType(dependencyType).
+			SetDependencyName(strings.ToLower(string(dependency.Kind))+"/"+dependency.Name).
+			SetDependencyVersion(dependency.installed).
+			SetVersionRange(versionRange).
+			SetIsRequired(dependency.Required).
+			SetNillableCondition(nilIfEmpty(dependency.Condition)))
+	}
+	if _, err := client.AgentDependency.CreateBulk(creates...).Save(ctx); err != nil {
+		return fmt.Errorf("failed to record agent dependencies: %w", err)
+	}
+	return nil
+}
+
+// agentRequestFromManifest maps an Agent manifest to an agent row.
+func agentRequestFromManifest(manifest *schema.Agent, spec, specHash, repositoryID string, source componentSource, dependencies []agentDependency) CreateAgentRequest {
+	branch := source.branch
+	if branch == "" {
+		branch = "main"
+	}
+	model := manifest.Spec.Model
+
+	req := CreateAgentRequest{
+		Name:                  manifest.Metadata.Name,
+		Namespace:             "default",
+		Version:               manifest.Metadata.Version,
+		Description:           manifest.Metadata.Description,
+		Author:                manifest.Metadata.Author,
+		License:               manifest.Metadata.License,
+		Homepage:              nilIfEmpty(manifest.Metadata.Homepage),
+		Documentation:         nilIfEmpty(manifest.Metadata.Documentation),
+		Tags:                  manifest.Metadata.Tags,
+		Categories:            manifest.Metadata.Categories,
+		Keywords:              manifest.Metadata.Keywords,
+		Stability:             enumValue(string(manifest.Metadata.Stability), agent.DefaultStability.String()),
+		Maturity:              enumValue(string(manifest.Metadata.Maturity), agent.DefaultMaturity.String()),
+		ForgeVersion:          manifest.Metadata.ForgeVersion,
+		Platforms:             manifest.Metadata.Platforms,
+		Spec:                  spec,
+		SpecHash:              specHash,
+		RepositoryID:          repositoryID,
+		CommitHash:            source.commitHash,
+		Branch:                branch,
+		LLMProvider:           nilIfEmpty(model.Provider),
+		AgentType:             agentType(manifest.Spec.Type),
+		SupportsMemory:        len(manifest.Spec.Memory) > 0,
+		SupportsTools:         len(manifest.Spec.Tools) > 0,
+		ModelPreferences:      []string{model.Model},
+		DefaultTemperature:    model.Temperature,
+		DefaultMaxTokens:      model.MaxTokens,
+		SessionTimeoutMinutes: 30,
+	}
+	for _, fallback := range model.Fallback {
+		req.ModelPreferences = append(req.ModelPreferences, fallback.Model)
+	}
+	for _, capability := range manifest.Spec.Capabilities {
+		req.Capabilities = append(req.Capabilities, capability.Name)
+	}
+	for _, prompt := range manifest.Spec.Prompts {
+		if prompt.Type == "system" && req.SystemPromptID == nil {
+			req.SystemPromptID = nilIfEmpty(prompt.Name)
+		}
+	}
+	for _, dependency := range dependencies {
+		switch dependency.Kind {
+		case schema.KindTool:
+			req.ToolDependencies = append(req.ToolDependencies, dependency.Name)
+		case schema.KindPrompt:
+			req.PromptDependencies = append(req.PromptDependencies, dependency.Name)
+		default:
+			req.AgentDependencies = append(req.AgentDependencies, dependency.Name)
+		}
+	}
+	return req
+}
+
+// agentType maps an agent type to the agent type stored in the database.
+func agentType(manifestType schema.AgentType) string {
+	switch manifestType {
+	case schema.AgentTypeTask:
+		return agent.AgentTypeTASK_ORIENTED.String()
+	case schema.AgentTypeWorkflow:
+		return agent.AgentTypeCOMPOSITE.String()
+	case schema.AgentTypeReactive, schema.AgentTypeAutonomous:
+		return agent.AgentTypeSPECIALIZED.String()
+	default:
+		return agent.AgentTypeCONVERSATIONAL.String()
+	}
+}
+
+// enumValue returns the upper-cased manifest value of an enum field, or fallback if it is unset.
+func enumValue(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return strings.ToUpper(value)
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/database/ent/agent"
+	"github.com/denkhaus/agentforge/internal/database/ent/agentdependency"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestPullAgent_InstallsAgentDependencies(t *testing.T) {
+	ctx := context.Background()
+	service, client, gitClient := newTestAgentService(t)
+	translator := newAgentRepository(t, gitClient, newTestAgent("translator", "1.2.0"), "v1.0.0", "v1.2.0")
+
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0",
+		schema.AgentTool{Name: "translator", Type: "agent", Source: translator + "@^1.0.0", Required: true},
+		schema.AgentTool{Name: "search", Type: "tool", Source: "builtin"},
+		schema.AgentTool{Name: "notes", Type: "agent", Source: t.TempDir() + "@^1.0.0"},
+	))
+
+	_, err := service.PullAgent(ctx, repo, "", false)
+	require.NoError(t, err)
+
+	row, err := client.GetEnt().Agent.Query().Where(agent.Name("translator"), agent.IsInstalled(true)).Only(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", row.Branch, "ranges resolve to the highest matching tag")
+
+	reviewer, err := client.GetEnt().Agent.Query().Where(agent.Name("reviewer")).Only(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"translator", "notes"}, reviewer.AgentDependencies)
+
+	dependencies, err := reviewer.QueryDependencies().Order(agentdependency.ByDependencyName()).All(ctx)
+	require.NoError(t, err)
+	require.Len(t, dependencies, 3)
+	assert.Equal(t, "agent/notes", dependencies[0].DependencyName)
+	assert.Equal(t, agentdependency.TypeOPTIONAL, dependencies[0].Type)
+	assert.Empty(t, dependencies[0].DependencyVersion, "failed optional dependencies are skipped")
+	assert.Equal(t, "agent/translator", dependencies[1].DependencyName)
+	assert.Equal(t, "1.2.0", dependencies[1].DependencyVersion)
+	assert.Equal(t, "^1.0.0", dependencies[1].VersionRange)
+	assert.Equal(t, agentdependency.TypeRUNTIME, dependencies[1].Type)
+	assert.Equal(t, "tool/search", dependencies[2].DependencyName)
+	assert.Equal(t, "*", dependencies[2].VersionRange)
+}
+
+func TestPullAgent_DependencyErrors(t *testing.T) {
+	ctx := context.Background()
+	service, _, gitClient := newTestAgentService(t)
+
+	missing := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0",
+		schema.AgentTool{Name: "translator", Type: "agent", Source: t.TempDir() + "@^1.0.0", Required: true}))
+	_, err := service.PullAgent(ctx, missing, "", false)
+	assert.ErrorContains(t, err, "failed to resolve agent translator of agent reviewer")
+
+	builtin := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0",
+		schema.AgentTool{Name: "search", Type: "tool", Source: "builtin", Required: true}))
+	_, err = service.PullAgent(ctx, builtin, "", false)
+	assert.ErrorContains(t, err, `not installed and source "builtin" is not a repository`)
+
+	tagged := newAgentRepository(t, gitClient, newTestAgent("search", "1.0.0"), "v1.0.0")
+	tools := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0",
+		schema.AgentTool{Name: "search", Type: "tool", Source: tagged + "@^2.0.0", Required: true}))
+	_, err = service.PullAgent(ctx, tools, "", false)
+	assert.ErrorContains(t, err, "failed to resolve version of "+tagged)
+
+	tools = newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0",
+		schema.AgentTool{Name: "search", Type: "tool", Source: tagged + "@^1.0.0", Required: true}))
+	_, err = service.PullAgent(ctx, tools, "", false)
+	assert.ErrorContains(t, err, "tool service not available")
+}
+
+func TestPullAgent_DetectsDependencyCycles(t *testing.T) {
+	ctx := context.Background()
+	service, _, gitClient := newTestAgentService(t)
+
+	// The reviewer depends on itself through its own repository
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0"), "v1.0.0")
+	writeAgent(t, repo, newTestAgent("reviewer", "1.0.0",
+		schema.AgentTool{Name: "reviewer", Type: "agent", Source: repo + "@v1.0.0", Required: true}))
+	require.NoError(t, gitClient.AddAndCommit(ctx, repo, "Depend on itself"))
+
+	_, err := service.PullAgent(ctx, repo, "", false)
+	assert.ErrorContains(t, err, "agent dependency cycle through "+repo)
+}
+
+func TestAgentRequestFromManifest(t *testing.T) {
+	manifest := newTestAgent("reviewer", "1.0.0")
+	manifest.Spec.Type = schema.AgentTypeWorkflow
+	manifest.Spec.Model.Fallback = []schema.AgentModelFallback{{Provider: "anthropic", Model: "claude"}}
+	manifest.Spec.Prompts = []schema.AgentPrompt{{Name: "reviewer-system", Type: "system"}}
+	dependencies := []agentDependency{
+		{AgentDependency: schema.AgentDependency{Kind: schema.KindTool, Name: "search"}},
+		{AgentDependency: schema.AgentDependency{Kind: schema.KindPrompt, Name: "reviewer-system"}},
+		{AgentDependency: schema.AgentDependency{Kind: schema.KindAgent, Name: "translator"}},
+	}
+
+	req := agentRequestFromManifest(manifest, "spec", "hash", "repo", componentSource{commitHash: "abc"}, dependencies)
+	assert.Equal(t, "main", req.Branch, "installs without a ref are recorded on main")
+	assert.Equal(t, agent.AgentTypeCOMPOSITE.String(), req.AgentType)
+	assert.Equal(t, []string{"gpt-4o-mini", "claude"}, req.ModelPreferences)
+	assert.Equal(t, "reviewer-system", *req.SystemPromptID)
+	assert.Equal(t, []string{"search"}, req.ToolDependencies)
+	assert.Equal(t, []string{"reviewer-system"}, req.PromptDependencies)
+	assert.Equal(t, []string{"translator"}, req.AgentDependencies)
+	assert.Equal(t, "EXPERIMENTAL", req.Stability)
+}
+
+func TestPullAgent_AllowsSharedDependencies(t *testing.T) {
+	ctx := context.Background()
+	service, client, gitClient := newTestAgentService(t)
+
+	// Both dependencies of the reviewer depend on the shared agent, at different versions
+	shared := newAgentRepository(t, gitClient, newTestAgent("shared", "1.0.0"), "v1.0.0")
+	writeAgent(t, shared, newTestAgent("shared", "2.0.0"))
+	require.NoError(t, gitClient.AddAndCommit(ctx, shared, "Release 2.0.0"))
+	require.NoError(t, gitClient.CreateTag(ctx, shared, "v2.0.0", "Release v2.0.0"))
+
+	translator := newAgentRepository(t, gitClient, newTestAgent("translator", "1.0.0",
+		schema.AgentTool{Name: "shared", Type: "agent", Source: shared + "@^1.0.0", Required: true}), "v1.0.0")
+	notes := newAgentRepository(t, gitClient, newTestAgent("notes", "1.0.0",
+		schema.AgentTool{Name: "shared", Type: "agent", Source: shared + "@^2.0.0", Required: true}), "v1.0.0")
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0",
+		schema.AgentTool{Name: "translator", Type: "agent", Source: translator + "@^1.0.0", Required: true},
+		schema.AgentTool{Name: "notes", Type: "agent", Source: notes + "@^1.0.0", Required: true},
+	))
+
+	_, err := service.PullAgent(ctx, repo, "", false)
+	require.NoError(t, err)
+
+	row, err := client.GetEnt().Agent.Query().Where(agent.Name("shared"), agent.IsInstalled(true)).Only(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", row.Version)
+}
@@ -7,19 +7,41 @@ import (
 	"github.com/denkhaus/agentforge/internal/database/ent"
 	"github.com/denkhaus/agentforge/internal/database/ent/agent"
 	"github.com/denkhaus/agentforge/internal/database/ent/repository"
+	"github.com/denkhaus/agentforge/internal/git"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // agentService provides agent management operations (private implementation)
 type agentService struct {
-	client DatabaseClient
+	client       DatabaseClient
+	git          git.GitClient
+	tools        ToolService
+	prompts      PromptPuller
+	syncs        SyncService
+	repositories RepositoryService
+	agentsDir    string
+	promptsDir   string
+	token        string
 }
 
-// NewAgentService creates a new agent service
-func NewAgentService(client DatabaseClient) AgentService {
+// NewAgentService creates a new agent service installing agents into agentsDir.
+// Tool and prompt dependencies of pulled agents are installed with toolService
+// and promptPuller, which installs prompts into promptsDir. Pulls and pushes are recorded with syncService. They
+// authenticate with the access token of the repository from repositories, or
+// with token for repositories without one.
+func NewAgentService(client DatabaseClient, gitClient git.GitClient, toolService ToolService,
+	promptPuller PromptPuller, syncService SyncService, repositories RepositoryService, agentsDir, promptsDir, token string) AgentService {
 	return &agentService{
-		client: client,
+		client:       client,
+		git:          gitClient,
+		tools:        toolService,
+		prompts:      promptPuller,
+		syncs:        syncService,
+		repositories: repositories,
+		agentsDir:    agentsDir,
+		promptsDir:   promptsDir,
+		token:        token,
 	}
 }
 
@@ -48,6 +70,7 @@ func (as *agentService) CreateAgent(ctx context.Context, req CreateAgentRequest)
 		SetPlatforms(req.Platforms).
 		SetSpec(req.Spec).
 		SetSpecHash(req.SpecHash).
+		SetRepositoryID(req.RepositoryID).
 		SetCommitHash(req.CommitHash).
 		SetBranch(req.Branch).
 		SetNillableConfigPath(req.ConfigPath).
@@ -247,24 +270,3 @@ func (as *agentService) SearchAgents(ctx context.Context, query string, opts Sea
 	
 	return agents, nil
 }
-
-// CLI-specific methods (placeholder implementations)
-func (as *agentService) ListAgentsForCLI(ctx context.Context, opts interface{}) ([]interface{}, error) {
-	// TODO: Implement CLI-specific agent listing
-	return []interface{}{}, fmt.Errorf("CLI agent listing not yet implemented")
-}
-
-func (as *agentService) CreateAgentFiles(ctx context.Context, name string) error {
-	// TODO: Implement agent file creation
-	return fmt.Errorf("agent file creation not yet implemented")
-}
-
-func (as *agentService) PullAgent(ctx context.Context, repo string) error {
-	// TODO: Implement agent pulling from repository
-	return fmt.Errorf("agent pulling not yet implemented")
-}
-
-func (as *agentService) PushAgent(ctx context.Context, name string) error {
-	// TODO: Implement agent pushing to repository
-	return fmt.Errorf("agent pushing not yet implemented")
-}
\ No newline at end of file
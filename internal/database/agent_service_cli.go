@@ -0,0 +1,339 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database/ent"
+	"github.com/denkhaus/agentforge/internal/database/ent/agent"
+	"github.com/denkhaus/agentforge/internal/database/ent/syncoperation"
+	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/templates"
+)
+
+// AgentListEntry describes an agent as listed by the CLI, merged from the agents
+// directory and the database.
+type AgentListEntry struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	Description  string `json:"description"`
+	Model        string `json:"model,omitempty"`
+	Dependencies int    `json:"dependencies"`
+	Installed    bool   `json:"installed"`
+	InstallPath  string `json:"installPath,omitempty"`
+	Repository   string `json:"repository,omitempty"`
+	CommitHash   string `json:"commitHash,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ListAgentsForCLI lists the agents installed in the agents directory together with
+// the agents known to the database from pulled repositories. Only opts.IsInstalled is applied.
+func (as *agentService) ListAgentsForCLI(ctx context.Context, opts ListAgentsOptions) ([]AgentListEntry, error) {
+	entries := make(map[string]*AgentListEntry)
+
+	dirs, err := os.ReadDir(as.agentsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read agents directory: %w", err)
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(as.agentsDir, dir.Name())
+		if _, err := os.Stat(filepath.Join(path, schema.ManifestFileName)); !dir.IsDir() || err != nil {
+			continue
+		}
+
+		entry := &AgentListEntry{Name: dir.Name(), Installed: true, InstallPath: path}
+		if manifest, err := readAgentManifest(path); err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Name = manifest.Metadata.Name
+			entry.Version = manifest.Metadata.Version
+			entry.Type = string(manifest.Spec.Type)
+			entry.Description = manifest.Metadata.Description
+			entry.Model = manifest.Spec.Model.Provider + "/" + manifest.Spec.Model.Model
+			entry.Dependencies = len(dependenciesOf(manifest))
+		}
+		entries[entry.Name] = entry
+	}
+
+	rows, err := as.client.GetEnt().Agent.Query().
+		WithRepository().
+		WithDependencies().
+		Order(ent.Desc(agent.FieldUpdatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	for _, row := range rows {
+		repositoryURL := ""
+		if row.Edges.Repository != nil && row.Edges.Repository.Name != localRepositoryName {
+			repositoryURL = row.Edges.Repository.URL
+		}
+
+		if entry, exists := entries[row.Name]; exists {
+			if entry.Repository == "" && (row.IsInstalled || entry.Version == row.Version) {
+				entry.Repository = repositoryURL
+				entry.CommitHash = row.CommitHash
+			}
+			continue
+		}
+		entry := &AgentListEntry{
+			Name:         row.Name,
+			Version:      row.Version,
+			Type:         strings.ToLower(string(row.AgentType)),
+			Description:  row.Description,
+			Dependencies: len(row.Edges.Dependencies),
+			Repository:   repositoryURL,
+			CommitHash:   row.CommitHash,
+		}
+		if row.LlmProvider != nil && len(row.ModelPreferences) > 0 {
+			entry.Model = *row.LlmProvider + "/" + row.ModelPreferences[0]
+		}
+		entries[row.Name] = entry
+	}
+
+	result := make([]AgentListEntry, 0, len(entries))
+	for _, entry := range entries {
+		if opts.IsInstalled != nil && entry.Installed != *opts.IsInstalled {
+			continue
+		}
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// CreateAgentFiles scaffolds the manifest and README of a new agent into dir.
+func (as *agentService) CreateAgentFiles(ctx context.Context, dir string, data templates.AgentTemplateData) error {
+	if err := schema.ValidateName(data.Name); err != nil {
+		return fmt.Errorf("invalid agent name: %w: %w", err, internalErrors.ErrInvalidInput)
+	}
+
+	manifestPath := filepath.Join(dir, schema.ManifestFileName)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return fmt.Errorf("agent manifest %s already exists", manifestPath)
+	}
+
+	if err := templates.NewAgentTemplateGenerator().GenerateAgentFiles(data, dir); err != nil {
+		return fmt.Errorf("failed to generate agent files: %w", err)
+	}
+
+	log.Info("Agent files created", zap.String("name", data.Name), zap.String("dir", dir))
+	return nil
+}
+
+// PullAgent clones repo at the version tag or branch, pulls the tools, prompts and
+// agents the agent depends on and installs it. An installed agent is replaced with force only.
+func (as *agentService) PullAgent(ctx context.Context, repo, version string, force bool) (*schema.Agent, error) {
+	if as.git == nil {
+		return nil, fmt.Errorf("git client not available")
+	}
+	return as.pullAgent(ctx, repo, version, force, make(map[string]bool))
+}
+
+// PushAgent commits the agent in dir, optionally tags it and pushes it to repo.
+func (as *agentService) PushAgent(ctx context.Context, dir, repo, message, tag string) error {
+	if as.git == nil {
+		return fmt.Errorf("git client not available")
+	}
+	manifest, err := readAgentManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	url := git.RepositoryURL(repo)
+	log.Info("Pushing agent",
+		zap.String("name", manifest.Metadata.Name),
+		zap.String("url", url),
+		zap.String("tag", tag))
+
+	source, _ := as.git.HeadCommit(ctx, dir)
+	operation := StartSync(ctx, as.syncs, syncoperation.TypePUSH, url, "", source)
+	commit, err := as.push(ctx, dir, url, message, tag, manifest)
+	CompleteSync(ctx, as.syncs, operation, commit, err)
+	return err
+}
+
+// pullAgent pulls an agent and its dependencies. Pulling holds the repositories of
+// the agents on the current dependency path, an agent depending on one of them is a
+// cycle. Agents shared by several dependencies are not on each other's path.
+func (as *agentService) pullAgent(ctx context.Context, repo, version string, force bool, pulling map[string]bool) (*schema.Agent, error) {
+	url := git.RepositoryURL(repo)
+	if pulling[url] {
+		return nil, fmt.Errorf("agent dependency cycle through %s", url)
+	}
+	pulling[url] = true
+	defer delete(pulling, url)
+	log.Info("Pulling agent", zap.String("url", url), zap.String("version", version))
+
+	operation := StartSync(ctx, as.syncs, syncoperation.TypePULL, url, version, "")
+	commit, manifest, err := as.pull(ctx, url, version, force, pulling)
+	CompleteSync(ctx, as.syncs, operation, commit, err)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Agent pulled",
+		zap.String("name", manifest.Metadata.Name),
+		zap.String("version", manifest.Metadata.Version),
+		zap.String("commit", commit))
+	return manifest, nil
+}
+
+// pull clones url into a temporary directory, resolves the dependencies of its agent
+// and installs it. It returns the pulled commit.
+func (as *agentService) pull(ctx context.Context, url, version string, force bool, pulling map[string]bool) (string, *schema.Agent, error) {
+	tmpDir, err := os.MkdirTemp("", "forge-agent-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	checkout := filepath.Join(tmpDir, "repo")
+	branch, err := as.clone(ctx, url, version, checkout)
+	if err != nil {
+		return "", nil, err
+	}
+	commit, err := as.git.HeadCommit(ctx, checkout)
+	if err != nil {
+		return "", nil, err
+	}
+
+	agentDir, err := findAgentManifest(checkout)
+	if err != nil {
+		return commit, nil, fmt.Errorf("failed to locate agent in %s: %w", url, err)
+	}
+	manifest, err := readAgentManifest(agentDir)
+	if err != nil {
+		return commit, nil, err
+	}
+	// The name comes from the remote manifest and must stay inside the agents directory
+	if err := schema.ValidateName(manifest.Metadata.Name); err != nil {
+		return commit, nil, err
+	}
+	installPath := filepath.Join(as.agentsDir, manifest.Metadata.Name)
+	if _, err := os.Stat(installPath); err == nil && !force {
+		return commit, nil, fmt.Errorf("agent %s is already installed, use --force to overwrite it", manifest.Metadata.Name)
+	}
+
+	dependencies, err := as.resolveDependencies(ctx, manifest, pulling)
+	if err != nil {
+		return commit, nil, err
+	}
+
+	if err := ReplaceDir(agentDir, installPath); err != nil {
+		return commit, nil, fmt.Errorf("failed to install agent files: %w", err)
+	}
+
+	source := componentSource{
+		repositoryName: repositoryName(url),
+		repositoryURL:  url,
+		repositoryType: repositoryType(url),
+		commitHash:     commit,
+		branch:         branch,
+	}
+	if err := as.recordInstall(ctx, manifest, source, installPath, dependencies); err != nil {
+		return commit, nil, err
+	}
+	return commit, manifest, nil
+}
+
+// clone checks out version as tag, as v-prefixed tag or as branch and returns the
+// checked out ref, which is recorded as branch of the install. Without a version the default branch is checked out.
+func (as *agentService) clone(ctx context.Context, url, version, destination string) (string, error) {
+	token := RemoteAccessToken(ctx, as.repositories, url, as.token)
+	return git.CloneVersion(ctx, as.git, git.CloneOptions{URL: url, Destination: destination, Depth: 1, Token: token}, version)
+}
+
+// push commits dir, optionally tags it and pushes it to url. It returns the pushed commit.
+func (as *agentService) push(ctx context.Context, dir, url, message, tag string, manifest *schema.Agent) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := as.git.InitRepository(ctx, dir); err != nil {
+			return "", err
+		}
+	}
+	if err := as.git.SetRemote(ctx, dir, "origin", url); err != nil {
+		return "", err
+	}
+	if err := as.git.AddAndCommit(ctx, dir, message); err != nil && !errors.Is(err, gogit.ErrEmptyCommit) {
+		return "", err
+	}
+	if tag != "" {
+		if err := as.git.CreateTag(ctx, dir, tag, fmt.Sprintf("Release %s %s", manifest.Metadata.Name, tag)); err != nil {
+			return "", err
+		}
+	}
+
+	commit, err := as.git.HeadCommit(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	token := RemoteAccessToken(ctx, as.repositories, url, as.token)
+	return commit, as.git.Push(ctx, dir, git.PushOptions{Token: token, Tags: tag != ""})
+}
+
+// readAgentManifest reads and validates the agent manifest in dir.
+func readAgentManifest(dir string) (*schema.Agent, error) {
+	content, err := os.ReadFile(filepath.Join(dir, schema.ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent manifest: %w", err)
+	}
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent manifest: %w", err)
+	}
+	manifest, ok := component.(*schema.Agent)
+	if !ok {
+		return nil, fmt.Errorf("manifest in %s is not an Agent", dir)
+	}
+	return manifest, nil
+}
+
+// findAgentManifest returns the directory of the single Agent manifest below root.
+func findAgentManifest(root string) (string, error) {
+	var found []string
+	var invalid error
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || entry.Name() != schema.ManifestFileName {
+			return nil
+		}
+		if _, err := readAgentManifest(filepath.Dir(path)); err != nil {
+			invalid = err
+		} else {
+			found = append(found, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch len(found) {
+	case 0:
+		if invalid != nil {
+			return "", fmt.Errorf("no valid agent manifest found: %w", invalid)
+		}
+		return "", fmt.Errorf("no agent manifest found")
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("repository contains %d agent manifests, only single-agent repositories are supported", len(found))
+	}
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/database/ent/agent"
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// newTestAgent creates a valid agent manifest depending on tools.
+func newTestAgent(name, version string, tools ...schema.AgentTool) *schema.Agent {
+	manifest := schema.NewAgent(name, version)
+	manifest.Metadata.Description = "Agent " + name + " for agent service tests"
+	manifest.Metadata.Author = "Test"
+	manifest.Metadata.License = "MIT"
+	manifest.Metadata.ForgeVersion = ">=0.1.0"
+	manifest.Spec.Type = schema.AgentTypeConversational
+	manifest.Spec.Model = schema.AgentModel{Provider: "openai", Model: "gpt-4o-mini"}
+	manifest.Spec.Interface = schema.AgentInterface{Type: "cli"}
+	manifest.Spec.Tools = tools
+	return manifest
+}
+
+// writeAgent writes the manifest of an agent into dir.
+func writeAgent(t *testing.T, dir string, manifest *schema.Agent) {
+	t.Helper()
+	content, err := schema.NewComponentParser().SerializeComponent(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, schema.ManifestFileName), content, 0644))
+}
+
+// newAgentRepository commits manifest to a new local repository and tags the
+// commit with tags. It returns the path of the repository.
+func newAgentRepository(t *testing.T, gitClient git.GitClient, manifest *schema.Agent, tags ...string) string {
+	t.Helper()
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeAgent(t, dir, manifest)
+	require.NoError(t, gitClient.InitRepository(ctx, dir))
+	require.NoError(t, gitClient.AddAndCommit(ctx, dir, "Add agent "+manifest.Metadata.Name))
+	for _, tag := range tags {
+		require.NoError(t, gitClient.CreateTag(ctx, dir, tag, "Release "+tag))
+	}
+	return dir
+}
+
+// newTestAgentService returns an agent service installing into a temporary directory.
+func newTestAgentService(t *testing.T) (AgentService, DatabaseClient, git.GitClient) {
+	t.Helper()
+	client := newTestClient(t)
+	gitClient := git.NewClient(zaptest.NewLogger(t))
+	return NewAgentService(client, gitClient, nil, nil, nil, nil, t.TempDir(), t.TempDir(), ""), client, gitClient
+}
+
+func TestPullAgent_RecordsClonedRef(t *testing.T) {
+	ctx := context.Background()
+	service, client, gitClient := newTestAgentService(t)
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0"), "v1.0.0")
+
+	tests := []struct {
+		name    string
+		version string
+		branch  string
+	}{
+		{name: "default branch", version: "", branch: "master"},
+		{name: "v-prefixed tag", version: "1.0.0", branch: "v1.0.0"},
+		{name: "tag", version: "v1.0.0", branch: "v1.0.0"},
+		{name: "branch", version: "master", branch: "master"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := service.PullAgent(ctx, repo, tt.version, true)
+			require.NoError(t, err)
+			assert.Equal(t, "reviewer", manifest.Metadata.Name)
+
+			row, err := client.GetEnt().Agent.Query().Where(agent.Name("reviewer"), agent.IsInstalled(true)).Only(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.branch, row.Branch)
+			assert.NotEmpty(t, row.CommitHash)
+		})
+	}
+
+	_, err := service.PullAgent(ctx, repo, "", false)
+	assert.ErrorContains(t, err, "already installed")
+	_, err = service.PullAgent(ctx, repo, "2.0.0", true)
+	assert.ErrorContains(t, err, "version 2.0.0 is neither a tag nor a branch")
+}
+
+func TestFindAgentManifest(t *testing.T) {
+	valid := newTestAgent("reviewer", "1.0.0")
+	invalid := newTestAgent("broken", "1.0.0")
+	invalid.Spec.Model = schema.AgentModel{}
+
+	tests := []struct {
+		name    string
+		agents  map[string]*schema.Agent
+		found   string
+		wantErr string
+	}{
+		{name: "root", agents: map[string]*schema.Agent{".": valid}, found: "."},
+		{name: "nested", agents: map[string]*schema.Agent{"agents/reviewer": valid}, found: "agents/reviewer"},
+		{name: "invalid skipped", agents: map[string]*schema.Agent{"reviewer": valid, "broken": invalid}, found: "reviewer"},
+		{name: "empty", wantErr: "no agent manifest found"},
+		{name: "only invalid", agents: map[string]*schema.Agent{".": invalid}, wantErr: "no valid agent manifest found"},
+		{name: "multiple", agents: map[string]*schema.Agent{"a": valid, "b": valid}, wantErr: "contains 2 agent manifests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for dir, manifest := range tt.agents {
+				writeAgent(t, filepath.Join(root, dir), manifest)
+			}
+			found, err := findAgentManifest(root)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(root, tt.found), found)
+		})
+	}
+}
+
+func TestListAgentsForCLI_MergesDirectoryAndDatabase(t *testing.T) {
+	ctx := context.Background()
+	service, _, gitClient := newTestAgentService(t)
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0"))
+	_, err := service.PullAgent(ctx, repo, "", false)
+	require.NoError(t, err)
+
+	entries, err := service.ListAgentsForCLI(ctx, ListAgentsOptions{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "reviewer", entries[0].Name)
+	assert.True(t, entries[0].Installed)
+	assert.Equal(t, repo, entries[0].Repository)
+	assert.NotEmpty(t, entries[0].CommitHash)
+
+	notInstalled := false
+	entries, err = service.ListAgentsForCLI(ctx, ListAgentsOptions{IsInstalled: &notInstalled})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
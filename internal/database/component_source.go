@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database/ent"
+	"github.com/denkhaus/agentforge/internal/database/ent/repository"
+	"github.com/denkhaus/agentforge/internal/database/ent/syncoperation"
+)
+
+// componentSource describes where an installed component comes from.
+type componentSource struct {
+	repositoryName string
+	repositoryURL  string
+	repositoryType repository.Type
+	commitHash     string
+	branch         string
+}
+
+// ensureRepository returns the repository row of a component source, creating it if
+// needed, and records the sync time of the install.
+func ensureRepository(ctx context.Context, client DatabaseClient, source componentSource) (*ent.Repository, error) {
+	repo, err := client.GetEnt().Repository.Query().
+		Where(repository.Name(source.repositoryName)).
+		Only(ctx)
+	if err == nil {
+		if repo, err = repo.Update().SetLastSync(time.Now()).Save(ctx); err != nil {
+			return nil, fmt.Errorf("failed to update repository: %w", err)
+		}
+		return repo, nil
+	}
+	if !ent.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	repo, err = client.GetEnt().Repository.Create().
+		SetID(uuid.New().String()).
+		SetName(source.repositoryName).
+		SetURL(source.repositoryURL).
+		SetType(source.repositoryType).
+		SetLastSync(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+	return repo, nil
+}
+
+// repositoryName derives the repository name from a clone URL.
+func repositoryName(url string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://"), ".git")
+}
+
+// repositoryType derives the repository type from a clone URL.
+func repositoryType(url string) repository.Type {
+	switch {
+	case strings.Contains(url, "github.com"):
+		return repository.TypeGITHUB
+	case strings.Contains(url, "gitlab"):
+		return repository.TypeGITLAB
+	case strings.Contains(url, "bitbucket"):
+		return repository.TypeBITBUCKET
+	case !strings.Contains(url, "://") && !strings.HasPrefix(url, "git@"):
+		return repository.TypeLOCAL
+	default:
+		return repository.TypeOTHER
+	}
+}
+
+// ReplaceDir copies the directory tree src to dst, replacing an existing dst. The files
+// are staged next to dst and renamed into place, so a failed copy leaves an installed
+// component untouched.
+func ReplaceDir(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+	if err := copyDir(src, staging); err != nil {
+		return err
+	}
+	if err := os.Chmod(staging, 0755); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		return os.Rename(staging, dst)
+	}
+
+	// Replace the installed files, restoring them if the new ones cannot be moved in
+	backup := staging + ".old"
+	if err := os.Rename(dst, backup); err != nil {
+		return fmt.Errorf("failed to move installed files aside: %w", err)
+	}
+	if err := os.Rename(staging, dst); err != nil {
+		if restoreErr := os.Rename(backup, dst); restoreErr != nil {
+			log.Error("Failed to restore installed files", zap.String("path", dst), zap.Error(restoreErr))
+		}
+		return err
+	}
+	return os.RemoveAll(backup)
+}
+
+// StartSync records the start of a sync operation through the optional syncs service.
+// Recording is best effort, a failure is logged and the operation continues unrecorded.
+func StartSync(ctx context.Context, syncs SyncService, syncType syncoperation.Type, url, branch, source string) string {
+	if syncs == nil {
+		return ""
+	}
+	operation, err := syncs.StartSync(ctx, StartSyncRequest{
+		Type:          syncType,
+		RepositoryURL: url,
+		Branch:        branch,
+		SourceCommit:  source,
+	})
+	if err != nil {
+		log.Warn("Failed to record sync operation", zap.Error(err))
+		return ""
+	}
+	return operation.ID
+}
+
+// CompleteSync records the outcome of a sync operation started with StartSync.
+func CompleteSync(ctx context.Context, syncs SyncService, id, commit string, syncErr error) {
+	if syncs == nil || id == "" {
+		return
+	}
+	if _, err := syncs.CompleteSync(ctx, id, commit, syncErr); err != nil {
+		log.Warn("Failed to record sync operation result", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// copyDir copies a directory tree, skipping git metadata.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case entry.IsDir() && entry.Name() == ".git":
+			return filepath.SkipDir
+		case entry.IsDir():
+			return os.MkdirAll(target, 0755)
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target)
+		}
+	})
+}
+
+// copyFile copies a regular file, preserving its permissions.
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// samePath reports whether two paths refer to the same location.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// nilIfEmpty returns nil for an empty string.
+func nilIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -44,6 +44,7 @@ func (Repository) Fields() []ent.Field {
 			Nillable(),
 		field.Bool("has_write_access").
 			Default(false),
+		// Deprecated: access tokens live in the secret store, values are migrated on startup
 		field.String("access_token").
 			Optional().
 			Nillable().
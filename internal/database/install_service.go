@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database/ent/agent"
+	"github.com/denkhaus/agentforge/internal/database/ent/tool"
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// InstallResult is the outcome of installing a locked component.
+type InstallResult struct {
+	Component resolver.LockedComponent
+	// Installed is false if the component was already installed at the locked commit
+	Installed bool
+}
+
+// installService implements InstallService on the tool, prompt and agent services.
+type installService struct {
+	client  DatabaseClient
+	tools   ToolService
+	prompts PromptPuller
+	agents  AgentService
+	source  resolver.Source
+	// promptsDir is the directory promptPuller installs prompts into
+	promptsDir string
+}
+
+// NewInstallService creates a new install service. Components are verified against
+// source before they are pulled. Installed prompts are looked up in promptsDir.
+func NewInstallService(client DatabaseClient, toolService ToolService, promptPuller PromptPuller,
+	agentService AgentService, source resolver.Source, promptsDir string) InstallService {
+	return &installService{
+		client:     client,
+		tools:      toolService,
+		prompts:    promptPuller,
+		agents:     agentService,
+		source:     source,
+		promptsDir: promptsDir,
+	}
+}
+
+// Install installs the components of a lockfile, dependencies first. Components
+// installed at their locked commit are skipped unless force is set. A component
+// whose ref no longer points at the locked commit fails the install.
+func (is *installService) Install(ctx context.Context, lockfile *resolver.Lockfile, force bool) ([]InstallResult, error) {
+	ordered, err := installOrder(lockfile)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []InstallResult
+	for _, component := range ordered {
+		if !force {
+			current, err := is.isCurrent(ctx, component)
+			if err != nil {
+				return results, err
+			}
+			if current {
+				results = append(results, InstallResult{Component: component})
+				continue
+			}
+		}
+
+		if err := is.verify(ctx, component); err != nil {
+			return results, err
+		}
+		log.Info("Installing locked component",
+			zap.String("component", component.Key()),
+			zap.String("ref", component.Ref),
+			zap.String("commit", component.Commit))
+		if err := is.pull(ctx, component); err != nil {
+			return results, fmt.Errorf("failed to install %s: %w", component.Key(), err)
+		}
+		results = append(results, InstallResult{Component: component, Installed: true})
+	}
+	return results, nil
+}
+
+// isCurrent reports whether a component is installed at its locked commit.
+// Prompts have no install rows and are compared by spec hash.
+func (is *installService) isCurrent(ctx context.Context, component resolver.LockedComponent) (bool, error) {
+	switch component.Kind {
+	case schema.KindTool:
+		exists, err := is.client.GetEnt().Tool.Query().
+			Where(tool.Name(component.Name), tool.IsInstalled(true), tool.CommitHash(component.Commit)).
+			Exist(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get installed tool: %w", err)
+		}
+		return exists, nil
+	case schema.KindAgent:
+		exists, err := is.client.GetEnt().Agent.Query().
+			Where(agent.Name(component.Name), agent.IsInstalled(true), agent.CommitHash(component.Commit)).
+			Exist(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get installed agent: %w", err)
+		}
+		return exists, nil
+	default:
+		prompt, ok := installedPrompt(is.promptsDir, component.Name)
+		if !ok {
+			return false, nil
+		}
+		spec, err := schema.NewComponentParser().SerializeComponent(prompt)
+		if err != nil {
+			return false, nil
+		}
+		hash := sha256.Sum256(spec)
+		return hex.EncodeToString(hash[:]) == component.SpecHash, nil
+	}
+}
+
+// verify checks that the locked ref still resolves to the locked commit and spec.
+func (is *installService) verify(ctx context.Context, component resolver.LockedComponent) error {
+	fetched, err := is.source.Fetch(ctx, component.Repository, component.Ref)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", component.Key(), err)
+	}
+	switch {
+	case fetched.Commit != component.Commit:
+		return fmt.Errorf("%s of %s points at commit %s instead of the locked %s, run forge lock to resolve it again",
+			refLabel(component.Ref), component.Repository, fetched.Commit, component.Commit)
+	case fetched.SpecHash != component.SpecHash:
+		return fmt.Errorf("manifest of %s does not match the locked spec hash, run forge lock to resolve it again",
+			component.Key())
+	}
+	return nil
+}
+
+// pull installs a component at its locked ref, replacing other installed versions.
+func (is *installService) pull(ctx context.Context, component resolver.LockedComponent) error {
+	var err error
+	switch component.Kind {
+	case schema.KindTool:
+		_, err = is.tools.PullTool(ctx, component.Repository, component.Ref, true)
+	case schema.KindPrompt:
+		_, err = is.prompts.PullPrompt(ctx, component.Repository, component.Ref, true)
+	default:
+		// Dependencies are installed first and satisfy the agent's ranges
+		_, err = is.agents.PullAgent(ctx, component.Repository, component.Ref, true)
+	}
+	return err
+}
+
+// installOrder returns the locked components with dependencies before their dependents.
+func installOrder(lockfile *resolver.Lockfile) ([]resolver.LockedComponent, error) {
+	byKey := make(map[string]resolver.LockedComponent, len(lockfile.Components))
+	for _, component := range lockfile.Components {
+		byKey[component.Key()] = component
+	}
+
+	ordered := make([]resolver.LockedComponent, 0, len(lockfile.Components))
+	state := make(map[string]int) // 1 visiting, 2 done
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case 1:
+			return fmt.Errorf("lockfile has a dependency cycle through %s", key)
+		case 2:
+			return nil
+		}
+		component, ok := byKey[key]
+		if !ok {
+			return fmt.Errorf("lockfile references %s, which is not locked", key)
+		}
+		state[key] = 1
+		for _, dependency := range component.Dependencies {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[key] = 2
+		ordered = append(ordered, component)
+		return nil
+	}
+
+	for _, component := range lockfile.Components {
+		if err := visit(component.Key()); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// refLabel describes a locked ref for messages.
+func refLabel(ref string) string {
+	if ref == "" {
+		return "the default branch"
+	}
+	return ref
+}
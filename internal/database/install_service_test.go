@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// lockAgent locks the agent of repository at ref as fetched from source.
+func lockAgent(t *testing.T, source resolver.Source, repository, ref string) resolver.LockedComponent {
+	t.Helper()
+	fetched, err := source.Fetch(context.Background(), repository, ref)
+	require.NoError(t, err)
+	return resolver.LockedComponent{
+		Kind:       fetched.Kind,
+		Name:       fetched.Name,
+		Version:    fetched.Version,
+		Repository: repository,
+		Ref:        ref,
+		Commit:     fetched.Commit,
+		SpecHash:   fetched.SpecHash,
+	}
+}
+
+func TestInstall_InstallsLockedAgents(t *testing.T) {
+	ctx := context.Background()
+	agents, client, gitClient := newTestAgentService(t)
+	source := resolver.NewGitSource(gitClient, nil)
+	service := NewInstallService(client, nil, nil, agents, source, t.TempDir())
+
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0"), "v1.0.0")
+	lockfile := &resolver.Lockfile{Components: []resolver.LockedComponent{lockAgent(t, source, repo, "v1.0.0")}}
+
+	results, err := service.Install(ctx, lockfile, false)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Installed)
+
+	results, err = service.Install(ctx, lockfile, false)
+	require.NoError(t, err)
+	assert.False(t, results[0].Installed, "agents installed at the locked commit are skipped")
+
+	results, err = service.Install(ctx, lockfile, true)
+	require.NoError(t, err)
+	assert.True(t, results[0].Installed, "force reinstalls current agents")
+}
+
+func TestInstall_RejectsMovedRefs(t *testing.T) {
+	ctx := context.Background()
+	agents, client, gitClient := newTestAgentService(t)
+	source := resolver.NewGitSource(gitClient, nil)
+	service := NewInstallService(client, nil, nil, agents, source, t.TempDir())
+
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0"))
+	locked := lockAgent(t, source, repo, "")
+
+	writeAgent(t, repo, newTestAgent("reviewer", "1.1.0"))
+	require.NoError(t, gitClient.AddAndCommit(ctx, repo, "Release 1.1.0"))
+
+	results, err := service.Install(ctx, &resolver.Lockfile{Components: []resolver.LockedComponent{locked}}, false)
+	assert.ErrorContains(t, err, "the default branch of "+repo+" points at commit")
+	assert.ErrorContains(t, err, "run forge lock to resolve it again")
+	assert.Empty(t, results)
+
+	locked.Commit = lockAgent(t, source, repo, "").Commit
+	_, err = service.Install(ctx, &resolver.Lockfile{Components: []resolver.LockedComponent{locked}}, false)
+	assert.ErrorContains(t, err, "manifest of agent/reviewer does not match the locked spec hash")
+}
+
+func TestInstallOrder(t *testing.T) {
+	component := func(kind schema.ComponentKind, name string, dependencies ...string) resolver.LockedComponent {
+		return resolver.LockedComponent{Kind: kind, Name: name, Dependencies: dependencies}
+	}
+
+	tests := []struct {
+		name       string
+		components []resolver.LockedComponent
+		order      []string
+		wantErr    string
+	}{
+		{
+			name: "dependencies first",
+			components: []resolver.LockedComponent{
+				component(schema.KindAgent, "reviewer", "tool/search", "agent/translator"),
+				component(schema.KindAgent, "translator", "prompt/translate"),
+				component(schema.KindTool, "search"),
+				component(schema.KindPrompt, "translate"),
+			},
+			order: []string{"tool/search", "prompt/translate", "agent/translator", "agent/reviewer"},
+		},
+		{
+			name: "shared dependency once",
+			components: []resolver.LockedComponent{
+				component(schema.KindAgent, "a", "tool/search"),
+				component(schema.KindAgent, "b", "tool/search"),
+				component(schema.KindTool, "search"),
+			},
+			order: []string{"tool/search", "agent/a", "agent/b"},
+		},
+		{
+			name: "cycle",
+			components: []resolver.LockedComponent{
+				component(schema.KindAgent, "a", "agent/b"),
+				component(schema.KindAgent, "b", "agent/a"),
+			},
+			wantErr: "lockfile has a dependency cycle through agent/a",
+		},
+		{
+			name:       "missing dependency",
+			components: []resolver.LockedComponent{component(schema.KindAgent, "a", "tool/search")},
+			wantErr:    "lockfile references tool/search, which is not locked",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := installOrder(&resolver.Lockfile{Components: tt.components})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			keys := make([]string, len(ordered))
+			for i, component := range ordered {
+				keys[i] = component.Key()
+			}
+			assert.Equal(t, tt.order, keys)
+		})
+	}
+}
+
+func TestGitSource_FetchesVPrefixedTags(t *testing.T) {
+	_, _, gitClient := newTestAgentService(t)
+	source := resolver.NewGitSource(gitClient, nil)
+
+	repo := newAgentRepository(t, gitClient, newTestAgent("reviewer", "1.0.0"), "v1.0.0")
+	fetched, err := source.Fetch(context.Background(), repo, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "reviewer", fetched.Name)
+	assert.Equal(t, schema.KindAgent, fetched.Kind)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/denkhaus/agentforge/internal/database/ent"
+	"github.com/denkhaus/agentforge/internal/database/ent/syncoperation"
 )
 
 // DatabaseManager defines the interface for database lifecycle management.
@@ -26,6 +27,7 @@ type RepositoryService interface {
 	ListRepositories(ctx context.Context, opts ListRepositoriesOptions) ([]*ent.Repository, error)
 	UpdateRepository(ctx context.Context, id string, req UpdateRepositoryRequest) (*ent.Repository, error)
 	DeleteRepository(ctx context.Context, id string) error
+	GetAccessToken(ctx context.Context, name string) (string, error)
 }
 
 // ComponentService is an alias to the components package interface to avoid duplication
@@ -40,6 +42,12 @@ type ConfigService interface {
 	SetIntConfig(ctx context.Context, key string, value int) error
 }
 
+// SyncService defines the interface for recording pull and push operations.
+type SyncService interface {
+	StartSync(ctx context.Context, req StartSyncRequest) (*ent.SyncOperation, error)
+	CompleteSync(ctx context.Context, id, commit string, syncErr error) (*ent.SyncOperation, error)
+}
+
 // DatabaseClient defines the interface for low-level database operations.
 type DatabaseClient interface {
 	Connect(ctx context.Context) error
@@ -57,10 +65,13 @@ type CreateRepositoryRequest struct {
 	IsActive       bool
 	DefaultBranch  string
 	HasWriteAccess bool
-	AccessToken    *string
+	// AccessToken is kept in the secret store, not in the database
+	AccessToken *string
 }
 
 type UpdateRepositoryRequest struct {
+	// Name renames the repository, its access token moves along
+	Name         *string
 	LastSync     *time.Time
 	SyncStatus   *string
 	Manifest     interface{}
@@ -68,6 +79,14 @@ type UpdateRepositoryRequest struct {
 	IsActive     *bool
 }
 
+// StartSyncRequest describes a sync operation against a repository.
+type StartSyncRequest struct {
+	Type          syncoperation.Type
+	RepositoryURL string
+	Branch        string
+	SourceCommit  string
+}
+
 type ListRepositoriesOptions struct {
 	IsActive *bool
 	Type     *string
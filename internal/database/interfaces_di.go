@@ -3,6 +3,8 @@ package database
 
 import (
 	"github.com/samber/do"
+
+	"github.com/denkhaus/agentforge/internal/types"
 )
 
 // DIAwareDatabaseManager extends DatabaseManager with DI capabilities
@@ -34,7 +36,11 @@ func NewRepositoryServiceFromDI(injector *do.Injector) (RepositoryService, error
 	if err != nil {
 		return nil, err
 	}
-	return NewRepositoryService(client), nil
+	secrets, err := do.Invoke[types.SecretStore](injector)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepositoryService(client, secrets), nil
 }
 
 // NewConfigServiceFromDI creates a ConfigService using dependency injection
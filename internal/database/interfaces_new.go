@@ -5,6 +5,9 @@ import (
 	"context"
 
 	"github.com/denkhaus/agentforge/internal/database/ent"
+	"github.com/denkhaus/agentforge/internal/resolver"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/templates"
 )
 
 // DatabaseManager defines the interface for database lifecycle management.
@@ -32,10 +35,12 @@ type ToolService interface {
 	SearchTools(ctx context.Context, query string, opts SearchToolsOptions) ([]*ent.Tool, error)
 	
 	// CLI-specific methods
-	ListToolsForCLI(ctx context.Context, opts interface{}) ([]interface{}, error)
-	CreateToolFiles(ctx context.Context, name string) error
-	PullTool(ctx context.Context, repo string) error
-	PushTool(ctx context.Context, name string) error
+	ListToolsForCLI(ctx context.Context, opts ListToolsOptions) ([]ToolListEntry, error)
+	CreateToolFiles(ctx context.Context, dir string, data templates.ToolTemplateData) error
+	InstallToolFromDir(ctx context.Context, dir string, force bool) (*schema.Tool, error)
+	RemoveInstalledTool(ctx context.Context, name string) error
+	PullTool(ctx context.Context, repo, version string, force bool) (*schema.Tool, error)
+	PushTool(ctx context.Context, dir, repo, message, tag string) error
 }
 
 // PromptService defines the interface for prompt management operations.
@@ -70,10 +75,20 @@ type AgentService interface {
 	SearchAgents(ctx context.Context, query string, opts SearchAgentsOptions) ([]*ent.Agent, error)
 	
 	// CLI-specific methods
-	ListAgentsForCLI(ctx context.Context, opts interface{}) ([]interface{}, error)
-	CreateAgentFiles(ctx context.Context, name string) error
-	PullAgent(ctx context.Context, repo string) error
-	PushAgent(ctx context.Context, name string) error
+	ListAgentsForCLI(ctx context.Context, opts ListAgentsOptions) ([]AgentListEntry, error)
+	CreateAgentFiles(ctx context.Context, dir string, data templates.AgentTemplateData) error
+	PullAgent(ctx context.Context, repo, version string, force bool) (*schema.Agent, error)
+	PushAgent(ctx context.Context, dir, repo, message, tag string) error
+}
+
+// PromptPuller pulls the prompts agents depend on into the prompts directory.
+type PromptPuller interface {
+	PullPrompt(ctx context.Context, repo, version string, force bool) (*schema.Prompt, error)
+}
+
+// InstallService installs the components pinned by a lockfile.
+type InstallService interface {
+	Install(ctx context.Context, lockfile *resolver.Lockfile, force bool) ([]InstallResult, error)
 }
 
 // Request types for tool operations
@@ -44,7 +44,7 @@ func NewManager(cfg *config.Config) (DatabaseManager, error) {
 	}
 
 	// Create services
-	repositoryService := NewRepositoryService(client)
+	repositoryService := NewRepositoryService(client, nil)
 	configService := NewConfigService(client)
 
 	return &manager{
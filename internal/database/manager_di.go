@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/types"
 	"github.com/samber/do"
 	"go.uber.org/zap"
 )
@@ -60,6 +61,16 @@ func (m *diAwareManager) Initialize(ctx context.Context) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Move plaintext access tokens into the secret store
+	secrets, err := do.Invoke[types.SecretStore](m.diContainer)
+	if err != nil {
+		log.Warn("No secret store available, skipping access token migration", zap.Error(err))
+		return nil
+	}
+	if _, err := MigrateAccessTokens(ctx, m.client, secrets); err != nil {
+		return fmt.Errorf("failed to migrate access tokens: %w", err)
+	}
+
 	return nil
 }
 
@@ -82,7 +93,7 @@ func (m *diAwareManager) GetRepositoryService() RepositoryService {
 	if err != nil {
 		log.Error("Failed to get repository service from DI container", zap.Error(err))
 		// Fallback to direct creation for backward compatibility
-		return NewRepositoryService(m.client)
+		return NewRepositoryService(m.client, nil)
 	}
 	return service
 }
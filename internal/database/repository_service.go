@@ -2,23 +2,30 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/denkhaus/agentforge/internal/database/ent"
 	"github.com/denkhaus/agentforge/internal/database/ent/repository"
+	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/secrets"
+	"github.com/denkhaus/agentforge/internal/types"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // repositoryService provides repository management operations.
 type repositoryService struct {
-	client DatabaseClient
+	client  DatabaseClient
+	secrets types.SecretStore
 }
 
-// NewRepositoryService creates a new repository service.
-func NewRepositoryService(client DatabaseClient) RepositoryService {
+// NewRepositoryService creates a new repository service. Access tokens are kept in
+// the secret store, a nil store rejects repositories with access tokens.
+func NewRepositoryService(client DatabaseClient, secrets types.SecretStore) RepositoryService {
 	return &repositoryService{
-		client: client,
+		client:  client,
+		secrets: secrets,
 	}
 }
 
@@ -27,6 +34,12 @@ func (rs *repositoryService) CreateRepository(ctx context.Context, req CreateRep
 	log.Info("Creating repository", 
 		zap.String("name", req.Name),
 		zap.String("url", req.URL))
+
+	if req.AccessToken != nil {
+		if err := rs.storeAccessToken(req.Name, *req.AccessToken); err != nil {
+			return nil, err
+		}
+	}
 	
 	repo, err := rs.client.GetEnt().Repository.Create().
 		SetID(uuid.New().String()).
@@ -36,7 +49,6 @@ func (rs *repositoryService) CreateRepository(ctx context.Context, req CreateRep
 		SetIsActive(req.IsActive).
 		SetDefaultBranch(req.DefaultBranch).
 		SetHasWriteAccess(req.HasWriteAccess).
-		SetNillableAccessToken(req.AccessToken).
 		Save(ctx)
 	
 	if err != nil {
@@ -100,11 +112,28 @@ func (rs *repositoryService) ListRepositories(ctx context.Context, opts ListRepo
 	return repos, nil
 }
 
-// UpdateRepository updates repository information.
+// UpdateRepository updates repository information. Renaming a repository moves
+// its access token to the secret of the new name.
 func (rs *repositoryService) UpdateRepository(ctx context.Context, id string, req UpdateRepositoryRequest) (*ent.Repository, error) {
 	log.Info("Updating repository", zap.String("id", id))
 	
 	update := rs.client.GetEnt().Repository.UpdateOneID(id)
+
+	var previousName string
+	rollback := func() {}
+	if req.Name != nil {
+		current, err := rs.GetRepository(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if current.Name != *req.Name {
+			previousName = current.Name
+			if rollback, err = rs.copyAccessToken(previousName, *req.Name); err != nil {
+				return nil, err
+			}
+		}
+		update = update.SetName(*req.Name)
+	}
 	
 	if req.LastSync != nil {
 		update = update.SetNillableLastSync(req.LastSync)
@@ -129,8 +158,12 @@ func (rs *repositoryService) UpdateRepository(ctx context.Context, id string, re
 	
 	repo, err := update.Save(ctx)
 	if err != nil {
+		rollback()
 		return nil, fmt.Errorf("failed to update repository: %w", err)
 	}
+	if previousName != "" {
+		rs.deleteAccessToken(previousName)
+	}
 	
 	log.Info("Repository updated", zap.String("id", id))
 	return repo, nil
@@ -139,13 +172,117 @@ func (rs *repositoryService) UpdateRepository(ctx context.Context, id string, re
 // DeleteRepository deletes a repository and all its components.
 func (rs *repositoryService) DeleteRepository(ctx context.Context, id string) error {
 	log.Info("Deleting repository", zap.String("id", id))
+
+	repo, err := rs.GetRepository(ctx, id)
+	if err != nil {
+		return err
+	}
 	
-	err := rs.client.GetEnt().Repository.DeleteOneID(id).Exec(ctx)
+	err = rs.client.GetEnt().Repository.DeleteOneID(id).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to delete repository: %w", err)
 	}
+
+	rs.deleteAccessToken(repo.Name)
 	
 	log.Info("Repository deleted", zap.String("id", id))
 	return nil
 }
 
+// GetAccessToken returns the access token of a repository from the secret store,
+// or from the environment variable named like the secret.
+func (rs *repositoryService) GetAccessToken(ctx context.Context, name string) (string, error) {
+	secretName := AccessTokenSecretName(name)
+	if token, ok := secrets.NewLookup(rs.secrets)(secretName); ok {
+		return token, nil
+	}
+	return "", fmt.Errorf("access token %s of repository %s: %w", secretName, name, internalErrors.ErrSecretNotFound)
+}
+
+// copyAccessToken stores the access token of repository from under the name to.
+// Repositories without a stored token are left alone. The returned rollback
+// restores the previous token stored under to, or deletes the copy if there was none.
+func (rs *repositoryService) copyAccessToken(from, to string) (func(), error) {
+	noop := func() {}
+	if rs.secrets == nil {
+		return noop, nil
+	}
+	token, err := rs.secrets.Get(AccessTokenSecretName(from))
+	if errors.Is(err, internalErrors.ErrSecretNotFound) {
+		return noop, nil
+	}
+	if err != nil {
+		return noop, fmt.Errorf("failed to read repository access token: %w", err)
+	}
+
+	previous, err := rs.secrets.Get(AccessTokenSecretName(to))
+	existed := err == nil
+	if err != nil && !errors.Is(err, internalErrors.ErrSecretNotFound) {
+		return noop, fmt.Errorf("failed to read repository access token: %w", err)
+	}
+	if err := rs.storeAccessToken(to, token); err != nil {
+		return noop, err
+	}
+
+	return func() {
+		if !existed {
+			rs.deleteAccessToken(to)
+			return
+		}
+		if err := rs.storeAccessToken(to, previous); err != nil {
+			log.Warn("Failed to restore repository access token", zap.String("repository", to), zap.Error(err))
+		}
+	}, nil
+}
+
+// deleteAccessToken removes the stored access token of a repository. Failures
+// are logged, a leftover secret does not grant access to anything else.
+func (rs *repositoryService) deleteAccessToken(name string) {
+	if rs.secrets == nil {
+		return
+	}
+	if err := rs.secrets.Delete(AccessTokenSecretName(name)); err != nil && !errors.Is(err, internalErrors.ErrSecretNotFound) {
+		log.Warn("Failed to delete repository access token", zap.String("repository", name), zap.Error(err))
+	}
+}
+
+// storeAccessToken keeps the access token of a repository in the secret store.
+func (rs *repositoryService) storeAccessToken(name, token string) error {
+	if rs.secrets == nil {
+		return fmt.Errorf("no secret store configured for repository access tokens")
+	}
+	if err := rs.secrets.Set(AccessTokenSecretName(name), token); err != nil {
+		return fmt.Errorf("failed to store repository access token: %w", err)
+	}
+	return nil
+}
+
+// RemoteAccessToken returns the token authenticating git operations against url:
+// the access token of the repository registered with url, or fallback if it has
+// none. A nil repositories always returns fallback.
+func RemoteAccessToken(ctx context.Context, repositories RepositoryService, url, fallback string) string {
+	if repositories == nil {
+		return fallback
+	}
+
+	name := repositoryName(url)
+	repos, err := repositories.ListRepositories(ctx, ListRepositoriesOptions{})
+	if err != nil {
+		log.Warn("Failed to list repositories for access token", zap.String("url", url), zap.Error(err))
+	}
+	for _, repo := range repos {
+		if repo.URL == url {
+			name = repo.Name
+			break
+		}
+	}
+
+	token, err := repositories.GetAccessToken(ctx, name)
+	if err != nil {
+		if !errors.Is(err, internalErrors.ErrSecretNotFound) {
+			log.Warn("Failed to get repository access token", zap.String("repository", name), zap.Error(err))
+		}
+		return fallback
+	}
+	return token
+}
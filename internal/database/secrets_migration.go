@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database/ent/repository"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// AccessTokenSecretName returns the secret name holding the access token of a repository,
+// e.g. REPOSITORY_GITHUB_COM_ACME_TOOLS_ACCESS_TOKEN for github.com/acme/tools. The name
+// is a valid environment variable name, so the token can be set in the environment too.
+func AccessTokenSecretName(repositoryName string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(repositoryName))
+	return "REPOSITORY_" + name + "_ACCESS_TOKEN"
+}
+
+// MigrateAccessTokens moves repository access tokens from the plaintext access_token
+// column into the secret store and clears the column. It returns the number of migrated tokens.
+func MigrateAccessTokens(ctx context.Context, client DatabaseClient, secrets types.SecretStore) (int, error) {
+	repos, err := client.GetEnt().Repository.Query().
+		Where(repository.AccessTokenNotNil()).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query repository access tokens: %w", err)
+	}
+
+	for i, repo := range repos {
+		if repo.AccessToken != nil && *repo.AccessToken != "" {
+			if err := secrets.Set(AccessTokenSecretName(repo.Name), *repo.AccessToken); err != nil {
+				return i, fmt.Errorf("failed to migrate access token of repository %s: %w", repo.Name, err)
+			}
+		}
+		if err := client.GetEnt().Repository.UpdateOneID(repo.ID).ClearAccessToken().Exec(ctx); err != nil {
+			return i, fmt.Errorf("failed to clear access token of repository %s: %w", repo.Name, err)
+		}
+		log.Info("Migrated repository access token to secret store", zap.String("repository", repo.Name))
+	}
+	return len(repos), nil
+}
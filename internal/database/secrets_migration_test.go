@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/database/ent/repository"
+	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/secrets"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// newTestClient returns a connected client of a database in a temporary directory.
+func newTestClient(t *testing.T) DatabaseClient {
+	t.Helper()
+	client, err := NewClient(Config{DatabasePath: filepath.Join(t.TempDir(), "forge.db")})
+	require.NoError(t, err)
+	require.NoError(t, client.Connect(context.Background()))
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// newTestSecrets returns a secret store in a temporary directory.
+func newTestSecrets(t *testing.T) types.SecretStore {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := secrets.NewFileStore(secrets.Options{
+		Path:    filepath.Join(dir, "secrets.json"),
+		KeyFile: filepath.Join(dir, "secrets.key"),
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestAccessTokenSecretName(t *testing.T) {
+	name := AccessTokenSecretName("github.com/acme/forge-tools")
+	assert.Equal(t, "REPOSITORY_GITHUB_COM_ACME_FORGE_TOOLS_ACCESS_TOKEN", name)
+	assert.NoError(t, secrets.ValidateName(name))
+}
+
+func TestMigrateAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	store := newTestSecrets(t)
+
+	create := func(name string, token *string) {
+		require.NoError(t, client.GetEnt().Repository.Create().
+			SetID(uuid.New().String()).
+			SetName(name).
+			SetURL("https://"+name+".git").
+			SetType(repository.TypeGITHUB).
+			SetNillableAccessToken(token).
+			Exec(ctx))
+	}
+	token, empty := "ghp_secret", ""
+	create("github.com/acme/tools", &token)
+	create("github.com/acme/empty", &empty)
+	create("github.com/acme/public", nil)
+
+	migrated, err := MigrateAccessTokens(ctx, client, store)
+	require.NoError(t, err)
+	assert.Equal(t, 2, migrated)
+
+	value, err := store.Get(AccessTokenSecretName("github.com/acme/tools"))
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_secret", value)
+	_, err = store.Get(AccessTokenSecretName("github.com/acme/empty"))
+	assert.ErrorIs(t, err, internalErrors.ErrSecretNotFound, "empty tokens are not stored")
+
+	left, err := client.GetEnt().Repository.Query().Where(repository.AccessTokenNotNil()).Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, left, "the plaintext column is cleared")
+
+	migrated, err = MigrateAccessTokens(ctx, client, store)
+	require.NoError(t, err)
+	assert.Zero(t, migrated, "a second run finds nothing to migrate")
+}
+
+func TestUpdateRepository_RenameMovesAccessToken(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSecrets(t)
+	service := NewRepositoryService(newTestClient(t), store)
+
+	token := "ghp_secret"
+	repo, err := service.CreateRepository(ctx, CreateRepositoryRequest{
+		Name: "acme-tools", URL: "https://github.com/acme/tools.git", Type: "GITHUB", AccessToken: &token,
+	})
+	require.NoError(t, err)
+
+	name := "acme-forge-tools"
+	_, err = service.UpdateRepository(ctx, repo.ID, UpdateRepositoryRequest{Name: &name})
+	require.NoError(t, err)
+
+	value, err := service.GetAccessToken(ctx, "acme-forge-tools")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_secret", value)
+	_, err = store.Get(AccessTokenSecretName("acme-tools"))
+	assert.ErrorIs(t, err, internalErrors.ErrSecretNotFound, "the secret of the old name is removed")
+
+	assert.Equal(t, "ghp_secret", RemoteAccessToken(ctx, service, "https://github.com/acme/tools.git", "fallback"),
+		"the token is found by the repository URL")
+	assert.Equal(t, "fallback", RemoteAccessToken(ctx, service, "https://github.com/acme/other.git", "fallback"))
+}
+
+func TestUpdateRepository_FailedRenameKeepsExistingAccessToken(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSecrets(t)
+	service := NewRepositoryService(newTestClient(t), store)
+
+	tools, other := "ghp_tools", "ghp_other"
+	repo, err := service.CreateRepository(ctx, CreateRepositoryRequest{
+		Name: "acme-tools", URL: "https://github.com/acme/tools.git", Type: "GITHUB", AccessToken: &tools,
+	})
+	require.NoError(t, err)
+	_, err = service.CreateRepository(ctx, CreateRepositoryRequest{
+		Name: "acme-other", URL: "https://github.com/acme/other.git", Type: "GITHUB", AccessToken: &other,
+	})
+	require.NoError(t, err)
+
+	// The name is taken, so saving the rename fails
+	name := "acme-other"
+	_, err = service.UpdateRepository(ctx, repo.ID, UpdateRepositoryRequest{Name: &name})
+	require.Error(t, err)
+
+	value, err := service.GetAccessToken(ctx, "acme-other")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_other", value, "the token of the existing name is restored")
+	value, err = service.GetAccessToken(ctx, "acme-tools")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_tools", value)
+
+	// A failed rename to a new name removes the copied token
+	require.NoError(t, store.Delete(AccessTokenSecretName("acme-other")))
+	_, err = service.UpdateRepository(ctx, repo.ID, UpdateRepositoryRequest{Name: &name})
+	require.Error(t, err)
+	_, err = store.Get(AccessTokenSecretName("acme-other"))
+	assert.ErrorIs(t, err, internalErrors.ErrSecretNotFound)
+}
+
+func TestGetAccessToken_FallsBackToEnvironment(t *testing.T) {
+	t.Setenv("REPOSITORY_GITHUB_COM_ACME_TOOLS_ACCESS_TOKEN", "ghp_env")
+	service := NewRepositoryService(nil, nil)
+
+	value, err := service.GetAccessToken(context.Background(), "github.com/acme/tools")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_env", value)
+
+	_, err = service.GetAccessToken(context.Background(), "github.com/acme/other")
+	assert.ErrorIs(t, err, internalErrors.ErrSecretNotFound)
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database/ent"
+	"github.com/denkhaus/agentforge/internal/database/ent/repository"
+	"github.com/denkhaus/agentforge/internal/database/ent/syncoperation"
+)
+
+// syncService records pull and push operations as SyncOperation rows.
+type syncService struct {
+	client DatabaseClient
+}
+
+// NewSyncService creates a new sync service.
+func NewSyncService(client DatabaseClient) SyncService {
+	return &syncService{
+		client: client,
+	}
+}
+
+// StartSync records a running sync operation. Pulls sync upstream to local,
+// pushes local to upstream. Known repositories are linked by URL.
+func (ss *syncService) StartSync(ctx context.Context, req StartSyncRequest) (*ent.SyncOperation, error) {
+	direction := syncoperation.DirectionUPSTREAM_TO_LOCAL
+	if req.Type == syncoperation.TypePUSH {
+		direction = syncoperation.DirectionLOCAL_TO_UPSTREAM
+	}
+
+	create := ss.client.GetEnt().SyncOperation.Create().
+		SetID(uuid.New().String()).
+		SetType(req.Type).
+		SetDirection(direction).
+		SetStatus(syncoperation.StatusRUNNING).
+		SetNillableBranch(nilIfEmpty(req.Branch)).
+		SetNillableSourceCommit(nilIfEmpty(req.SourceCommit))
+
+	repo, err := ss.client.GetEnt().Repository.Query().
+		Where(repository.URL(req.RepositoryURL)).
+		First(ctx)
+	if err == nil {
+		create.SetRepositoryID(repo.ID)
+	} else if !ent.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	operation, err := create.Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync operation: %w", err)
+	}
+
+	log.Info("Sync operation started",
+		zap.String("id", operation.ID),
+		zap.String("type", string(req.Type)),
+		zap.String("repository", req.RepositoryURL))
+	return operation, nil
+}
+
+// CompleteSync marks a sync operation as completed at commit, or as failed with syncErr.
+func (ss *syncService) CompleteSync(ctx context.Context, id, commit string, syncErr error) (*ent.SyncOperation, error) {
+	update := ss.client.GetEnt().SyncOperation.UpdateOneID(id).
+		SetCompletedAt(time.Now()).
+		SetNillableTargetCommit(nilIfEmpty(commit))
+	if syncErr != nil {
+		update.SetStatus(syncoperation.StatusFAILED).SetErrorMessage(syncErr.Error())
+	} else {
+		update.SetStatus(syncoperation.StatusCOMPLETED).AddCompletedSteps(1)
+	}
+
+	operation, err := update.Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete sync operation: %w", err)
+	}
+
+	log.Info("Sync operation completed",
+		zap.String("id", operation.ID),
+		zap.String("status", string(operation.Status)))
+	return operation, nil
+}
@@ -7,19 +7,33 @@ import (
 	"github.com/denkhaus/agentforge/internal/database/ent"
 	"github.com/denkhaus/agentforge/internal/database/ent/repository"
 	"github.com/denkhaus/agentforge/internal/database/ent/tool"
+	"github.com/denkhaus/agentforge/internal/git"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // toolService provides tool management operations (private implementation)
 type toolService struct {
-	client DatabaseClient
+	client       DatabaseClient
+	git          git.GitClient
+	syncs        SyncService
+	repositories RepositoryService
+	toolsDir     string
+	token        string
 }
 
-// NewToolService creates a new tool service
-func NewToolService(client DatabaseClient) ToolService {
+// NewToolService creates a new tool service installing tools into toolsDir.
+// Pulls are recorded through the optional syncService. Pulls and pushes authenticate
+// with the access token of the repository from repositories, or with token for
+// repositories without one.
+func NewToolService(client DatabaseClient, gitClient git.GitClient, syncService SyncService, repositories RepositoryService, toolsDir, token string) ToolService {
 	return &toolService{
-		client: client,
+		client:       client,
+		git:          gitClient,
+		syncs:        syncService,
+		repositories: repositories,
+		toolsDir:     toolsDir,
+		token:        token,
 	}
 }
 
@@ -48,13 +62,14 @@ func (ts *toolService) CreateTool(ctx context.Context, req CreateToolRequest) (*
 		SetPlatforms(req.Platforms).
 		SetSpec(req.Spec).
 		SetSpecHash(req.SpecHash).
-		// Repository relationship handled via edge
+		SetRepositoryID(req.RepositoryID).
 		SetCommitHash(req.CommitHash).
 		SetBranch(req.Branch).
 		SetExecutionType(tool.ExecutionType(req.ExecutionType)).
 		SetNillableSchemaPath(req.SchemaPath).
 		SetCapabilities(req.Capabilities).
 		SetNillableEntryPoint(req.EntryPoint).
+		SetEnvironmentVariables(req.EnvironmentVars).
 		SetTimeoutSeconds(req.TimeoutSeconds).
 		SetSupportsStreaming(req.SupportsStreaming).
 		Save(ctx)
@@ -225,24 +240,3 @@ func (ts *toolService) SearchTools(ctx context.Context, query string, opts Searc
 	
 	return tools, nil
 }
-
-// CLI-specific methods (placeholder implementations)
-func (ts *toolService) ListToolsForCLI(ctx context.Context, opts interface{}) ([]interface{}, error) {
-	// TODO: Implement CLI-specific tool listing
-	return []interface{}{}, fmt.Errorf("CLI tool listing not yet implemented")
-}
-
-func (ts *toolService) CreateToolFiles(ctx context.Context, name string) error {
-	// TODO: Implement tool file creation
-	return fmt.Errorf("tool file creation not yet implemented")
-}
-
-func (ts *toolService) PullTool(ctx context.Context, repo string) error {
-	// TODO: Implement tool pulling from repository
-	return fmt.Errorf("tool pulling not yet implemented")
-}
-
-func (ts *toolService) PushTool(ctx context.Context, name string) error {
-	// TODO: Implement tool pushing to repository
-	return fmt.Errorf("tool pushing not yet implemented")
-}
\ No newline at end of file
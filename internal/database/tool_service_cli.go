@@ -0,0 +1,422 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database/ent"
+	"github.com/denkhaus/agentforge/internal/database/ent/repository"
+	"github.com/denkhaus/agentforge/internal/database/ent/syncoperation"
+	"github.com/denkhaus/agentforge/internal/database/ent/tool"
+	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/templates"
+	"github.com/denkhaus/agentforge/internal/toolruntime"
+)
+
+// localRepositoryName is the repository recorded for tools installed from a local directory.
+const localRepositoryName = "local"
+
+// ToolListEntry describes a tool as listed by the CLI, merged from the tools
+// directory and the database.
+type ToolListEntry struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Functions   int    `json:"functions"`
+	Installed   bool   `json:"installed"`
+	InstallPath string `json:"installPath,omitempty"`
+	Repository  string `json:"repository,omitempty"`
+	CommitHash  string `json:"commitHash,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ListToolsForCLI lists the tools installed in the tools directory together with the
+// tools known to the database. Only opts.IsInstalled is applied.
+func (ts *toolService) ListToolsForCLI(ctx context.Context, opts ListToolsOptions) ([]ToolListEntry, error) {
+	entries := make(map[string]*ToolListEntry)
+
+	dirs, err := os.ReadDir(ts.toolsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read tools directory: %w", err)
+	}
+	for _, dir := range dirs {
+		// Hidden directories are staged installs
+		if strings.HasPrefix(dir.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(ts.toolsDir, dir.Name())
+		if _, err := os.Stat(filepath.Join(path, schema.ManifestFileName)); !dir.IsDir() || err != nil {
+			continue
+		}
+
+		entry := &ToolListEntry{Name: dir.Name(), Installed: true, InstallPath: path}
+		if manifest, err := toolruntime.ReadManifest(path); err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Name = manifest.Metadata.Name
+			entry.Version = manifest.Metadata.Version
+			entry.Type = string(manifest.Spec.Type)
+			entry.Description = manifest.Metadata.Description
+			entry.Functions = len(manifest.Spec.Functions)
+		}
+		entries[entry.Name] = entry
+	}
+
+	rows, err := ts.client.GetEnt().Tool.Query().
+		WithRepository().
+		Order(ent.Desc(tool.FieldUpdatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+	for _, row := range rows {
+		repositoryURL := ""
+		if row.Edges.Repository != nil && row.Edges.Repository.Name != localRepositoryName {
+			repositoryURL = row.Edges.Repository.URL
+		}
+
+		if entry, exists := entries[row.Name]; exists {
+			if entry.Repository == "" && (row.IsInstalled || entry.Version == row.Version) {
+				entry.Repository = repositoryURL
+				entry.CommitHash = row.CommitHash
+			}
+			continue
+		}
+		entries[row.Name] = &ToolListEntry{
+			Name:        row.Name,
+			Version:     row.Version,
+			Type:        strings.ToLower(string(row.ExecutionType)),
+			Description: row.Description,
+			Repository:  repositoryURL,
+			CommitHash:  row.CommitHash,
+		}
+	}
+
+	result := make([]ToolListEntry, 0, len(entries))
+	for _, entry := range entries {
+		if opts.IsInstalled != nil && entry.Installed != *opts.IsInstalled {
+			continue
+		}
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// CreateToolFiles generates a Go MCP server project for a new tool into dir.
+func (ts *toolService) CreateToolFiles(ctx context.Context, dir string, data templates.ToolTemplateData) error {
+	if err := schema.ValidateName(data.Name); err != nil {
+		return fmt.Errorf("invalid tool name: %w: %w", err, internalErrors.ErrInvalidInput)
+	}
+
+	manifestPath := filepath.Join(dir, schema.ManifestFileName)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return fmt.Errorf("tool manifest %s already exists", manifestPath)
+	}
+
+	if err := templates.NewToolTemplateGenerator().GenerateToolProject(data, dir); err != nil {
+		return fmt.Errorf("failed to generate tool project: %w", err)
+	}
+
+	log.Info("Tool files created", zap.String("name", data.Name), zap.String("dir", dir))
+	return nil
+}
+
+// InstallToolFromDir copies the tool in dir into the tools directory and records it
+// as installed from the local repository.
+func (ts *toolService) InstallToolFromDir(ctx context.Context, dir string, force bool) (*schema.Tool, error) {
+	return ts.installTool(ctx, dir, componentSource{
+		repositoryName: localRepositoryName,
+		repositoryURL:  localRepositoryName,
+		repositoryType: repository.TypeLOCAL,
+	}, force)
+}
+
+// RemoveInstalledTool deletes an installed tool and marks its records as uninstalled.
+func (ts *toolService) RemoveInstalledTool(ctx context.Context, name string) error {
+	if err := schema.ValidateName(name); err != nil {
+		return fmt.Errorf("invalid tool name: %w: %w", err, internalErrors.ErrInvalidInput)
+	}
+
+	installPath := filepath.Join(ts.toolsDir, name)
+	_, statErr := os.Stat(filepath.Join(installPath, schema.ManifestFileName))
+
+	updated, err := ts.client.GetEnt().Tool.Update().
+		Where(tool.Name(name), tool.IsInstalled(true)).
+		SetIsInstalled(false).
+		ClearInstallPath().
+		ClearInstalledAt().
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to uninstall tool: %w", err)
+	}
+	if statErr != nil && updated == 0 {
+		return fmt.Errorf("tool %s is not installed: %w", name, internalErrors.ErrToolNotFound)
+	}
+
+	if err := os.RemoveAll(installPath); err != nil {
+		return fmt.Errorf("failed to remove tool files: %w", err)
+	}
+
+	log.Info("Tool uninstalled", zap.String("name", name))
+	return nil
+}
+
+// PullTool clones a repository at a tag, v-prefixed tag or branch and installs the
+// tool it contains. The pull is recorded as sync operation.
+func (ts *toolService) PullTool(ctx context.Context, repo, version string, force bool) (*schema.Tool, error) {
+	url := git.RepositoryURL(repo)
+	log.Info("Pulling tool", zap.String("url", url), zap.String("version", version))
+
+	operation := StartSync(ctx, ts.syncs, syncoperation.TypePULL, url, version, "")
+	commit, manifest, err := ts.pull(ctx, url, version, force)
+	CompleteSync(ctx, ts.syncs, operation, commit, err)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// pull clones url into a temporary directory and installs its tool. It returns the
+// pulled commit.
+func (ts *toolService) pull(ctx context.Context, url, version string, force bool) (string, *schema.Tool, error) {
+	tmpDir, err := os.MkdirTemp("", "forge-tool-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	checkout := filepath.Join(tmpDir, "repo")
+	token := RemoteAccessToken(ctx, ts.repositories, url, ts.token)
+	branch, err := git.CloneVersion(ctx, ts.git, git.CloneOptions{URL: url, Destination: checkout, Depth: 1, Token: token}, version)
+	if err != nil {
+		return "", nil, err
+	}
+
+	commit, err := ts.git.HeadCommit(ctx, checkout)
+	if err != nil {
+		return "", nil, err
+	}
+	toolDir, err := findToolManifest(checkout)
+	if err != nil {
+		return commit, nil, fmt.Errorf("failed to locate tool in %s: %w", url, err)
+	}
+
+	source := componentSource{
+		repositoryName: repositoryName(url),
+		repositoryURL:  url,
+		repositoryType: repositoryType(url),
+		commitHash:     commit,
+		branch:         branch,
+	}
+	manifest, err := ts.installTool(ctx, toolDir, source, force)
+	return commit, manifest, err
+}
+
+// PushTool commits the tool in dir, optionally tags it and pushes it to repo.
+func (ts *toolService) PushTool(ctx context.Context, dir, repo, message, tag string) error {
+	manifest, err := toolruntime.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	log.Info("Pushing tool",
+		zap.String("name", manifest.Metadata.Name),
+		zap.String("repo", repo),
+		zap.String("tag", tag))
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := ts.git.InitRepository(ctx, dir); err != nil {
+			return err
+		}
+	}
+	url := git.RepositoryURL(repo)
+	if err := ts.git.SetRemote(ctx, dir, "origin", url); err != nil {
+		return err
+	}
+	if err := ts.git.AddAndCommit(ctx, dir, message); err != nil && !errors.Is(err, gogit.ErrEmptyCommit) {
+		return err
+	}
+	if tag != "" {
+		if err := ts.git.CreateTag(ctx, dir, tag, fmt.Sprintf("Release %s %s", manifest.Metadata.Name, tag)); err != nil {
+			return err
+		}
+	}
+	token := RemoteAccessToken(ctx, ts.repositories, url, ts.token)
+	return ts.git.Push(ctx, dir, git.PushOptions{Token: token, Tags: tag != ""})
+}
+
+// installTool copies a tool directory into the tools directory and records the install.
+func (ts *toolService) installTool(ctx context.Context, dir string, source componentSource, force bool) (*schema.Tool, error) {
+	manifest, err := toolruntime.ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name comes from a possibly remote manifest and must stay inside the tools directory
+	if err := schema.ValidateName(manifest.Metadata.Name); err != nil {
+		return nil, err
+	}
+	installPath := filepath.Join(ts.toolsDir, manifest.Metadata.Name)
+	if !samePath(dir, installPath) {
+		if _, err := os.Stat(installPath); err == nil && !force {
+			return nil, fmt.Errorf("tool %s is already installed, use --force to overwrite it", manifest.Metadata.Name)
+		}
+		if err := ReplaceDir(dir, installPath); err != nil {
+			return nil, fmt.Errorf("failed to install tool files: %w", err)
+		}
+	}
+
+	if err := ts.recordInstall(ctx, manifest, source, installPath); err != nil {
+		return nil, err
+	}
+
+	log.Info("Tool installed",
+		zap.String("name", manifest.Metadata.Name),
+		zap.String("version", manifest.Metadata.Version),
+		zap.String("path", installPath))
+	return manifest, nil
+}
+
+// recordInstall creates or updates the tool row of an install. Other installed versions
+// of the tool are marked as uninstalled, since one version is installed per name.
+func (ts *toolService) recordInstall(ctx context.Context, manifest *schema.Tool, source componentSource, installPath string) error {
+	repo, err := ensureRepository(ctx, ts.client, source)
+	if err != nil {
+		return err
+	}
+
+	spec, err := schema.NewComponentParser().SerializeComponent(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool manifest: %w", err)
+	}
+	hash := sha256.Sum256(spec)
+	specHash := hex.EncodeToString(hash[:])
+
+	if _, err := ts.client.GetEnt().Tool.Update().
+		Where(tool.Name(manifest.Metadata.Name), tool.IsInstalled(true)).
+		SetIsInstalled(false).
+		ClearInstallPath().
+		ClearInstalledAt().
+		Save(ctx); err != nil {
+		return fmt.Errorf("failed to update installed tools: %w", err)
+	}
+
+	existing, err := ts.GetToolByName(ctx, manifest.Metadata.Name, manifest.Metadata.Version, repo.ID)
+	if err != nil {
+		req := toolRequestFromManifest(manifest, string(spec), specHash, repo.ID, source)
+		if existing, err = ts.CreateTool(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	_, err = existing.Update().
+		SetSpec(string(spec)).
+		SetSpecHash(specHash).
+		SetCommitHash(source.commitHash).
+		SetBranch(source.branch).
+		SetIsInstalled(true).
+		SetInstallPath(installPath).
+		SetInstalledAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record tool install: %w", err)
+	}
+	return nil
+}
+
+// toolRequestFromManifest maps a Tool manifest to a tool row.
+func toolRequestFromManifest(manifest *schema.Tool, spec, specHash, repositoryID string, source componentSource) CreateToolRequest {
+	branch := source.branch
+	if branch == "" {
+		branch = "main"
+	}
+	entryPoint := manifest.Spec.EntryPoint
+
+	return CreateToolRequest{
+		Name:            manifest.Metadata.Name,
+		Namespace:       "default",
+		Version:         manifest.Metadata.Version,
+		Description:     manifest.Metadata.Description,
+		Author:          manifest.Metadata.Author,
+		License:         manifest.Metadata.License,
+		Homepage:        nilIfEmpty(manifest.Metadata.Homepage),
+		Documentation:   nilIfEmpty(manifest.Metadata.Documentation),
+		Tags:            manifest.Metadata.Tags,
+		Categories:      manifest.Metadata.Categories,
+		Keywords:        manifest.Metadata.Keywords,
+		Stability:       strings.ToUpper(string(manifest.Metadata.Stability)),
+		Maturity:        strings.ToUpper(string(manifest.Metadata.Maturity)),
+		ForgeVersion:    manifest.Metadata.ForgeVersion,
+		Platforms:       manifest.Metadata.Platforms,
+		Spec:            spec,
+		SpecHash:        specHash,
+		RepositoryID:    repositoryID,
+		CommitHash:      source.commitHash,
+		Branch:          branch,
+		ExecutionType:   executionType(manifest.Spec.Type),
+		EntryPoint:      &entryPoint,
+		EnvironmentVars: manifest.Spec.Configuration.Environment,
+		TimeoutSeconds:  30,
+	}
+}
+
+// executionType maps a tool type to the execution type stored in the database.
+func executionType(toolType schema.ToolType) string {
+	switch toolType {
+	case schema.ToolTypeMCPServer:
+		return tool.ExecutionTypeMCP.String()
+	case schema.ToolTypeHTTP, schema.ToolTypeWebhook:
+		return tool.ExecutionTypeHTTP.String()
+	case schema.ToolTypeFunction:
+		return tool.ExecutionTypeFUNCTION.String()
+	default:
+		return tool.ExecutionTypeBINARY.String()
+	}
+}
+
+// findToolManifest returns the directory of the single Tool manifest below root.
+func findToolManifest(root string) (string, error) {
+	var found []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || entry.Name() != schema.ManifestFileName {
+			return nil
+		}
+		if _, err := toolruntime.ReadManifest(filepath.Dir(path)); err == nil {
+			found = append(found, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no valid tool manifest found: %w", internalErrors.ErrToolNotFound)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("repository contains %d tool manifests, only single-tool repositories are supported", len(found))
+	}
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/database/ent/syncoperation"
+	"github.com/denkhaus/agentforge/internal/database/ent/tool"
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// newTestToolManifest creates a valid http tool manifest.
+func newTestToolManifest(name, version string) *schema.Tool {
+	manifest := schema.NewTool(name, version)
+	manifest.Metadata.Description = "Tool " + name + " for tool service tests"
+	manifest.Metadata.Author = "Test"
+	manifest.Metadata.License = "MIT"
+	manifest.Metadata.ForgeVersion = ">=0.1.0"
+	manifest.Spec.Type = schema.ToolTypeHTTP
+	manifest.Spec.Runtime = schema.RuntimeGo
+	manifest.Spec.EntryPoint = "https://example.com"
+	manifest.Spec.Functions = []schema.ToolFunction{{Name: "lookup", Description: "Looks something up"}}
+	return manifest
+}
+
+// newToolRepository commits manifest to a new local repository and tags the
+// commit with tags. It returns the path of the repository.
+func newToolRepository(t *testing.T, gitClient git.GitClient, manifest *schema.Tool, tags ...string) string {
+	t.Helper()
+	ctx := context.Background()
+	dir := t.TempDir()
+	content, err := schema.NewComponentParser().SerializeComponent(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, schema.ManifestFileName), content, 0644))
+	require.NoError(t, gitClient.InitRepository(ctx, dir))
+	require.NoError(t, gitClient.AddAndCommit(ctx, dir, "Add tool "+manifest.Metadata.Name))
+	for _, tag := range tags {
+		require.NoError(t, gitClient.CreateTag(ctx, dir, tag, "Release "+tag))
+	}
+	return dir
+}
+
+func TestPullTool_RecordsClonedRefAndSync(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	gitClient := git.NewClient(zaptest.NewLogger(t))
+	service := NewToolService(client, gitClient, NewSyncService(client), nil, t.TempDir(), "")
+	repo := newToolRepository(t, gitClient, newTestToolManifest("lookup", "1.0.0"), "v1.0.0")
+
+	tests := []struct {
+		name    string
+		version string
+		branch  string
+	}{
+		{name: "default branch", version: "", branch: "master"},
+		{name: "v-prefixed tag", version: "1.0.0", branch: "v1.0.0"},
+		{name: "tag", version: "v1.0.0", branch: "v1.0.0"},
+		{name: "branch", version: "master", branch: "master"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := service.PullTool(ctx, repo, tt.version, true)
+			require.NoError(t, err)
+			assert.Equal(t, "lookup", manifest.Metadata.Name)
+
+			row, err := client.GetEnt().Tool.Query().Where(tool.Name("lookup"), tool.IsInstalled(true)).Only(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.branch, row.Branch)
+			assert.NotEmpty(t, row.CommitHash)
+		})
+	}
+
+	operations, err := client.GetEnt().SyncOperation.Query().Where(syncoperation.TypeEQ(syncoperation.TypePULL)).All(ctx)
+	require.NoError(t, err)
+	assert.Len(t, operations, len(tests))
+
+	_, err = service.PullTool(ctx, repo, "", false)
+	assert.ErrorContains(t, err, "already installed")
+}
+
+func TestReplaceDir_KeepsInstalledFilesOnFailedCopy(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "lookup")
+	require.NoError(t, os.MkdirAll(dst, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dst, schema.ManifestFileName), []byte("installed"), 0644))
+
+	err := ReplaceDir(filepath.Join(t.TempDir(), "missing"), dst)
+	require.Error(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dst, schema.ManifestFileName))
+	require.NoError(t, err)
+	assert.Equal(t, "installed", string(content))
+
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "staging directories are removed")
+}
+
+func TestListToolsForCLI_SkipsStagedInstalls(t *testing.T) {
+	toolsDir := t.TempDir()
+	service := NewToolService(newTestClient(t), nil, nil, nil, toolsDir, "")
+	writeToolManifest(t, filepath.Join(toolsDir, "lookup"), newTestToolManifest("lookup", "1.0.0"))
+	writeToolManifest(t, filepath.Join(toolsDir, ".search-123"), newTestToolManifest("search", "1.0.0"))
+
+	entries, err := service.ListToolsForCLI(context.Background(), ListToolsOptions{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "lookup", entries[0].Name)
+	assert.Equal(t, filepath.Join(toolsDir, "lookup"), entries[0].InstallPath)
+}
+
+// writeToolManifest writes manifest into dir.
+func writeToolManifest(t *testing.T, dir string, manifest *schema.Tool) {
+	t.Helper()
+	content, err := schema.NewComponentParser().SerializeComponent(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, schema.ManifestFileName), content, 0644))
+}
@@ -54,16 +54,22 @@ func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
 	}
 }
 
-// CircuitBreakerToolProviderDecorator adds circuit breaker pattern to tool operations.
-type CircuitBreakerToolProviderDecorator struct {
-	inner           types.ToolProvider
-	log             *zap.Logger
-	config          CircuitBreakerConfig
+// toolCircuit holds the circuit breaker state of a single tool.
+type toolCircuit struct {
 	state           CircuitState
 	failureCount    int
 	successCount    int
 	lastFailureTime time.Time
-	mutex           sync.RWMutex
+}
+
+// CircuitBreakerToolProviderDecorator adds circuit breaker pattern to tool operations.
+// Every tool has its own circuit, so a failing tool does not block the others.
+type CircuitBreakerToolProviderDecorator struct {
+	inner    types.ToolProvider
+	log      *zap.Logger
+	config   CircuitBreakerConfig
+	circuits map[string]*toolCircuit
+	mutex    sync.Mutex
 }
 
 // NewCircuitBreakerToolProviderDecorator creates a new circuit breaker decorator.
@@ -73,10 +79,10 @@ func NewCircuitBreakerToolProviderDecorator(
 	config CircuitBreakerConfig,
 ) types.ToolProvider {
 	return &CircuitBreakerToolProviderDecorator{
-		inner:  inner,
-		log:    log,
-		config: config,
-		state:  CircuitClosed,
+		inner:    inner,
+		log:      log,
+		config:   config,
+		circuits: make(map[string]*toolCircuit),
 	}
 }
 
@@ -91,43 +97,86 @@ func (d *CircuitBreakerToolProviderDecorator) ExecuteTool(
 	name string,
 	input string,
 ) (string, error) {
-	// Check if circuit allows execution
-	if !d.canExecute() {
+	// Check if the tool's circuit allows execution
+	if !d.canExecute(name) {
 		d.log.Warn("Circuit breaker is open, rejecting tool execution",
 			zap.String("tool_name", name),
-			zap.String("circuit_state", d.getState().String()))
+			zap.String("circuit_state", d.getState(name).String()))
 		return "", errors.ErrCircuitBreakerOpen
 	}
 
 	// Execute the tool
 	result, err := d.inner.ExecuteTool(ctx, name, input)
 
+	// Tools rejected as unhealthy were not called and do not count against the circuit
+	if errors.IsServiceUnavailable(err) {
+		return result, err
+	}
+
 	// Record the result
 	d.recordResult(name, err == nil)
 
 	return result, err
 }
 
-func (d *CircuitBreakerToolProviderDecorator) canExecute() bool {
-	d.mutex.RLock()
-	defer d.mutex.RUnlock()
+// ReportHealth feeds a tool health change into the circuits of the tool's functions.
+// An unhealthy tool opens its circuits; a recovered tool moves open circuits to
+// half-open, so the next calls probe it before the circuits close again.
+func (d *CircuitBreakerToolProviderDecorator) ReportHealth(health types.ToolHealth) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	switch d.state {
+	names := append([]string{health.Tool}, health.Functions...)
+	for _, name := range names {
+		circuit := d.circuit(name)
+		switch health.State {
+		case types.ToolHealthUnhealthy:
+			if circuit.state != CircuitOpen {
+				d.log.Error("Circuit breaker opened due to failed health check",
+					zap.String("tool_name", name),
+					zap.String("health_error", health.LastError))
+			}
+			circuit.state = CircuitOpen
+			circuit.successCount = 0
+			circuit.lastFailureTime = time.Now()
+		case types.ToolHealthHealthy:
+			if circuit.state == CircuitOpen {
+				circuit.state = CircuitHalfOpen
+				circuit.successCount = 0
+				d.log.Info("Circuit breaker transitioning to half-open state after health recovery",
+					zap.String("tool_name", name))
+			}
+		}
+	}
+}
+
+// circuit returns the circuit of a tool, creating a closed one on first use.
+// The caller must hold the mutex.
+func (d *CircuitBreakerToolProviderDecorator) circuit(toolName string) *toolCircuit {
+	circuit, exists := d.circuits[toolName]
+	if !exists {
+		circuit = &toolCircuit{state: CircuitClosed}
+		d.circuits[toolName] = circuit
+	}
+	return circuit
+}
+
+func (d *CircuitBreakerToolProviderDecorator) canExecute(toolName string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	circuit := d.circuit(toolName)
+	switch circuit.state {
 	case CircuitClosed:
 		return true
 	case CircuitOpen:
 		// Check if recovery timeout has passed
-		if time.Since(d.lastFailureTime) > d.config.RecoveryTimeout {
+		if time.Since(circuit.lastFailureTime) > d.config.RecoveryTimeout {
 			// Transition to half-open
-			d.mutex.RUnlock()
-			d.mutex.Lock()
-			if d.state == CircuitOpen { // Double-check after acquiring write lock
-				d.state = CircuitHalfOpen
-				d.successCount = 0
-				d.log.Info("Circuit breaker transitioning to half-open state")
-			}
-			d.mutex.Unlock()
-			d.mutex.RLock()
+			circuit.state = CircuitHalfOpen
+			circuit.successCount = 0
+			d.log.Info("Circuit breaker transitioning to half-open state",
+				zap.String("tool_name", toolName))
 			return true
 		}
 		return false
@@ -142,80 +191,85 @@ func (d *CircuitBreakerToolProviderDecorator) recordResult(toolName string, succ
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
+	circuit := d.circuit(toolName)
 	if success {
-		d.handleSuccess(toolName)
+		d.handleSuccess(toolName, circuit)
 	} else {
-		d.handleFailure(toolName)
+		d.handleFailure(toolName, circuit)
 	}
 }
 
-func (d *CircuitBreakerToolProviderDecorator) handleSuccess(toolName string) {
-	d.failureCount = 0
-	if d.state == CircuitHalfOpen {
-		d.successCount++
-		if d.successCount >= d.config.SuccessThreshold {
-			d.state = CircuitClosed
+func (d *CircuitBreakerToolProviderDecorator) handleSuccess(toolName string, circuit *toolCircuit) {
+	circuit.failureCount = 0
+	if circuit.state == CircuitHalfOpen {
+		circuit.successCount++
+		if circuit.successCount >= d.config.SuccessThreshold {
+			circuit.state = CircuitClosed
 			d.log.Info("Circuit breaker closed after successful recovery",
 				zap.String("tool_name", toolName),
-				zap.Int("success_count", d.successCount))
+				zap.Int("success_count", circuit.successCount))
 		}
 	}
 }
 
-func (d *CircuitBreakerToolProviderDecorator) handleFailure(toolName string) {
-	d.failureCount++
-	d.lastFailureTime = time.Now()
-	d.successCount = 0 // Reset success count on failure
+func (d *CircuitBreakerToolProviderDecorator) handleFailure(toolName string, circuit *toolCircuit) {
+	circuit.failureCount++
+	circuit.lastFailureTime = time.Now()
+	circuit.successCount = 0 // Reset success count on failure
 
 	// Determine new state based on current state and failure count
-	if d.state == CircuitClosed && d.failureCount >= d.config.FailureThreshold {
-		d.state = CircuitOpen
+	if circuit.state == CircuitClosed && circuit.failureCount >= d.config.FailureThreshold {
+		circuit.state = CircuitOpen
 		d.log.Error("Circuit breaker opened due to failures",
 			zap.String("tool_name", toolName),
-			zap.Int("failure_count", d.failureCount),
+			zap.Int("failure_count", circuit.failureCount),
 			zap.Int("failure_threshold", d.config.FailureThreshold))
-	} else if d.state == CircuitHalfOpen {
-		d.state = CircuitOpen // Reopen immediately on failure in half-open state
+	} else if circuit.state == CircuitHalfOpen {
+		circuit.state = CircuitOpen // Reopen immediately on failure in half-open state
 		d.log.Error("Circuit breaker reopened during recovery attempt",
 			zap.String("tool_name", toolName))
 	}
 }
 
-func (d *CircuitBreakerToolProviderDecorator) getState() CircuitState {
-	d.mutex.RLock()
-	defer d.mutex.RUnlock()
-	return d.state
+func (d *CircuitBreakerToolProviderDecorator) getState(toolName string) CircuitState {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.circuit(toolName).state
 }
 
-// GetCircuitBreakerStats returns current circuit breaker statistics.
-func (d *CircuitBreakerToolProviderDecorator) GetCircuitBreakerStats() map[string]any {
-	d.mutex.RLock()
-	defer d.mutex.RUnlock()
+// GetCircuitBreakerStats returns current circuit breaker statistics of a tool.
+func (d *CircuitBreakerToolProviderDecorator) GetCircuitBreakerStats(toolName string) map[string]any {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
+	circuit := d.circuit(toolName)
 	return map[string]any{
-		"state":             d.state.String(),
-		"failure_count":     d.failureCount,
-		"success_count":     d.successCount,
+		"tool_name":         toolName,
+		"state":             circuit.state.String(),
+		"failure_count":     circuit.failureCount,
+		"success_count":     circuit.successCount,
 		"failure_threshold": d.config.FailureThreshold,
 		"success_threshold": d.config.SuccessThreshold,
 		"recovery_timeout":  d.config.RecoveryTimeout.String(),
-		"last_failure_time": d.lastFailureTime,
+		"last_failure_time": circuit.lastFailureTime,
 	}
 }
 
-// ResetCircuitBreaker manually resets the circuit breaker to closed state.
-func (d *CircuitBreakerToolProviderDecorator) ResetCircuitBreaker() {
+// ResetCircuitBreaker manually resets the circuit of a tool to closed state.
+func (d *CircuitBreakerToolProviderDecorator) ResetCircuitBreaker(toolName string) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
-	oldState := d.state
-	d.state = CircuitClosed
-	d.failureCount = 0
-	d.successCount = 0
+	circuit := d.circuit(toolName)
+	oldState := circuit.state
+	circuit.state = CircuitClosed
+	circuit.failureCount = 0
+	circuit.successCount = 0
 
 	d.log.Info("Circuit breaker manually reset",
+		zap.String("tool_name", toolName),
 		zap.String("previous_state", oldState.String()),
-		zap.String("new_state", d.state.String()))
+		zap.String("new_state", circuit.state.String()))
 }
 
 // GetTools returns a list of tools.
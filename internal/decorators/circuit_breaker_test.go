@@ -3,6 +3,7 @@ package decorators
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings" // Added import
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 
 	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/types"
 )
 
 // Mocks from test_mocks.go
@@ -72,8 +74,8 @@ func TestNewCircuitBreakerToolProviderDecorator(t *testing.T) {
 	if cbDecorator.config != cfg {
 		t.Error("Config not set correctly")
 	}
-	if cbDecorator.state != CircuitClosed {
-		t.Errorf("Expected initial state CLOSED, got %s", cbDecorator.state)
+	if cbDecorator.circuit("test_tool").state != CircuitClosed {
+		t.Errorf("Expected initial state CLOSED, got %s", cbDecorator.circuit("test_tool").state)
 	}
 }
 
@@ -110,11 +112,11 @@ func TestCircuitBreaker_ClosedToOpen(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
-	if cbDecorator.getState() != CircuitClosed {
-		t.Errorf("Expected state CLOSED, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitClosed {
+		t.Errorf("Expected state CLOSED, got %s", cbDecorator.getState("test_tool"))
 	}
-	if cbDecorator.failureCount != 1 {
-		t.Errorf("Expected failureCount 1, got %d", cbDecorator.failureCount)
+	if cbDecorator.circuit("test_tool").failureCount != 1 {
+		t.Errorf("Expected failureCount 1, got %d", cbDecorator.circuit("test_tool").failureCount)
 	}
 
 	// Second failure - transitions to Open
@@ -122,11 +124,11 @@ func TestCircuitBreaker_ClosedToOpen(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
-	if cbDecorator.getState() != CircuitOpen {
-		t.Errorf("Expected state OPEN, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitOpen {
+		t.Errorf("Expected state OPEN, got %s", cbDecorator.getState("test_tool"))
 	}
-	if cbDecorator.failureCount != 2 {
-		t.Errorf("Expected failureCount 2, got %d", cbDecorator.failureCount)
+	if cbDecorator.circuit("test_tool").failureCount != 2 {
+		t.Errorf("Expected failureCount 2, got %d", cbDecorator.circuit("test_tool").failureCount)
 	}
 
 	// Verify log message for state change
@@ -151,20 +153,20 @@ func TestCircuitBreaker_OpenToHalfOpenToClosed(t *testing.T) {
 	cbDecorator := decorator.(*CircuitBreakerToolProviderDecorator)
 
 	// Force open state
-	cbDecorator.state = CircuitOpen
-	cbDecorator.failureCount = 1
-	cbDecorator.lastFailureTime = time.Now().Add(-20 * time.Millisecond) // Ensure timeout passed
+	cbDecorator.circuit("test_tool").state = CircuitOpen
+	cbDecorator.circuit("test_tool").failureCount = 1
+	cbDecorator.circuit("test_tool").lastFailureTime = time.Now().Add(-20 * time.Millisecond) // Ensure timeout passed
 
 	// Attempt execution - should transition to Half-Open
 	_, err := decorator.ExecuteTool(context.Background(), "test_tool", "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
-	if cbDecorator.getState() != CircuitClosed { // Should transition directly to Closed if success threshold met
-		t.Errorf("Expected state CLOSED, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitClosed { // Should transition directly to Closed if success threshold met
+		t.Errorf("Expected state CLOSED, got %s", cbDecorator.getState("test_tool"))
 	}
-	if cbDecorator.successCount != 1 {
-		t.Errorf("Expected successCount 1, got %d", cbDecorator.successCount)
+	if cbDecorator.circuit("test_tool").successCount != 1 {
+		t.Errorf("Expected successCount 1, got %d", cbDecorator.circuit("test_tool").successCount)
 	}
 
 	// Verify log messages
@@ -206,20 +208,20 @@ func TestCircuitBreaker_HalfOpenToClosed(t *testing.T) {
 	cbDecorator.inner = mockInner
 
 	// Force open state, ensure timeout has passed
-	cbDecorator.state = CircuitOpen
-	cbDecorator.failureCount = 1
-	cbDecorator.lastFailureTime = time.Now().Add(-20 * time.Millisecond)
+	cbDecorator.circuit("test_tool").state = CircuitOpen
+	cbDecorator.circuit("test_tool").failureCount = 1
+	cbDecorator.circuit("test_tool").lastFailureTime = time.Now().Add(-20 * time.Millisecond)
 
 	// Attempt execution - should transition to Half-Open and then immediately to Closed if successful
 	_, err := decorator.ExecuteTool(context.Background(), "test_tool", "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
-	if cbDecorator.getState() != CircuitClosed {
-		t.Errorf("Expected state CLOSED, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitClosed {
+		t.Errorf("Expected state CLOSED, got %s", cbDecorator.getState("test_tool"))
 	}
-	if cbDecorator.successCount != 1 {
-		t.Errorf("Expected successCount 1, got %d", cbDecorator.successCount)
+	if cbDecorator.circuit("test_tool").successCount != 1 {
+		t.Errorf("Expected successCount 1, got %d", cbDecorator.circuit("test_tool").successCount)
 	}
 
 	// Verify log messages
@@ -245,7 +247,7 @@ func TestCircuitBreaker_HalfOpenToOpen(t *testing.T) {
 
 	mockInner := &mockToolProvider{
 		executeToolFunc: func(_ context.Context, _ string, _ string) (string, error) {
-			if cbDecorator.successCount == 0 {
+			if cbDecorator.circuit("test_tool").successCount == 0 {
 				return "success", nil // First call in half-open succeeds
 			}
 			return "", errors.New("simulated error in half-open") // Second call fails
@@ -254,20 +256,20 @@ func TestCircuitBreaker_HalfOpenToOpen(t *testing.T) {
 	cbDecorator.inner = mockInner
 
 	// Force open state, ensure timeout has passed
-	cbDecorator.state = CircuitOpen
-	cbDecorator.failureCount = 1
-	cbDecorator.lastFailureTime = time.Now().Add(-20 * time.Millisecond)
+	cbDecorator.circuit("test_tool").state = CircuitOpen
+	cbDecorator.circuit("test_tool").failureCount = 1
+	cbDecorator.circuit("test_tool").lastFailureTime = time.Now().Add(-20 * time.Millisecond)
 
 	// First attempt in half-open (success)
 	_, err := decorator.ExecuteTool(context.Background(), "test_tool", "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
-	if cbDecorator.getState() != CircuitHalfOpen {
-		t.Errorf("Expected state HALF_OPEN, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitHalfOpen {
+		t.Errorf("Expected state HALF_OPEN, got %s", cbDecorator.getState("test_tool"))
 	}
-	if cbDecorator.successCount != 1 {
-		t.Errorf("Expected successCount 1, got %d", cbDecorator.successCount)
+	if cbDecorator.circuit("test_tool").successCount != 1 {
+		t.Errorf("Expected successCount 1, got %d", cbDecorator.circuit("test_tool").successCount)
 	}
 
 	// Second attempt in half-open (failure) - should transition back to Open
@@ -275,11 +277,11 @@ func TestCircuitBreaker_HalfOpenToOpen(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error, got nil")
 	}
-	if cbDecorator.getState() != CircuitOpen {
-		t.Errorf("Expected state OPEN, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitOpen {
+		t.Errorf("Expected state OPEN, got %s", cbDecorator.getState("test_tool"))
 	}
-	if cbDecorator.failureCount != 1 { // Failure count resets on state change, then increments
-		t.Errorf("Expected failureCount 1, got %d", cbDecorator.failureCount)
+	if cbDecorator.circuit("test_tool").failureCount != 1 { // Failure count resets on state change, then increments
+		t.Errorf("Expected failureCount 1, got %d", cbDecorator.circuit("test_tool").failureCount)
 	}
 
 	// Verify log messages
@@ -321,16 +323,16 @@ func TestCircuitBreaker_OpenStateRejection(t *testing.T) {
 	cbDecorator := decorator.(*CircuitBreakerToolProviderDecorator)
 
 	// Force open state, ensure recovery timeout has NOT passed
-	cbDecorator.state = CircuitOpen
-	cbDecorator.failureCount = 1
-	cbDecorator.lastFailureTime = time.Now().Add(-10 * time.Minute) // Still within recovery timeout
+	cbDecorator.circuit("test_tool").state = CircuitOpen
+	cbDecorator.circuit("test_tool").failureCount = 1
+	cbDecorator.circuit("test_tool").lastFailureTime = time.Now().Add(-10 * time.Minute) // Still within recovery timeout
 
 	_, err := decorator.ExecuteTool(context.Background(), "test_tool", "")
 	if err == nil || !errors.Is(err, internalErrors.ErrCircuitBreakerOpen) {
 		t.Errorf("Expected ErrCircuitBreakerOpen, got %v", err)
 	}
-	if cbDecorator.getState() != CircuitOpen {
-		t.Errorf("Expected state OPEN, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitOpen {
+		t.Errorf("Expected state OPEN, got %s", cbDecorator.getState("test_tool"))
 	}
 
 	// Verify log message
@@ -355,21 +357,21 @@ func TestCircuitBreaker_ResetCircuitBreaker(t *testing.T) {
 	cbDecorator := decorator.(*CircuitBreakerToolProviderDecorator)
 
 	// Force open state
-	cbDecorator.state = CircuitOpen
-	cbDecorator.failureCount = 5
-	cbDecorator.successCount = 5
-	cbDecorator.lastFailureTime = time.Now().Add(-1 * time.Hour) // irrelevant for manual reset
+	cbDecorator.circuit("test_tool").state = CircuitOpen
+	cbDecorator.circuit("test_tool").failureCount = 5
+	cbDecorator.circuit("test_tool").successCount = 5
+	cbDecorator.circuit("test_tool").lastFailureTime = time.Now().Add(-1 * time.Hour) // irrelevant for manual reset
 
-	cbDecorator.ResetCircuitBreaker() // Call on concrete type
+	cbDecorator.ResetCircuitBreaker("test_tool") // Call on concrete type
 
-	if cbDecorator.getState() != CircuitClosed {
-		t.Errorf("Expected state CLOSED after reset, got %s", cbDecorator.getState())
+	if cbDecorator.getState("test_tool") != CircuitClosed {
+		t.Errorf("Expected state CLOSED after reset, got %s", cbDecorator.getState("test_tool"))
 	}
-	if cbDecorator.failureCount != 0 {
-		t.Errorf("Expected failureCount 0 after reset, got %d", cbDecorator.failureCount)
+	if cbDecorator.circuit("test_tool").failureCount != 0 {
+		t.Errorf("Expected failureCount 0 after reset, got %d", cbDecorator.circuit("test_tool").failureCount)
 	}
-	if cbDecorator.successCount != 0 {
-		t.Errorf("Expected successCount 0 after reset, got %d", cbDecorator.successCount)
+	if cbDecorator.circuit("test_tool").successCount != 0 {
+		t.Errorf("Expected successCount 0 after reset, got %d", cbDecorator.circuit("test_tool").successCount)
 	}
 
 	foundLog := false
@@ -392,12 +394,12 @@ func TestCircuitBreaker_GetCircuitBreakerStats(t *testing.T) {
 	cbDecorator := decorator.(*CircuitBreakerToolProviderDecorator)
 
 	// Manually set some states for testing stats
-	cbDecorator.state = CircuitHalfOpen
-	cbDecorator.failureCount = 2
-	cbDecorator.successCount = 1
-	cbDecorator.lastFailureTime = time.Now().Add(-15 * time.Second)
+	cbDecorator.circuit("test_tool").state = CircuitHalfOpen
+	cbDecorator.circuit("test_tool").failureCount = 2
+	cbDecorator.circuit("test_tool").successCount = 1
+	cbDecorator.circuit("test_tool").lastFailureTime = time.Now().Add(-15 * time.Second)
 
-	stats := cbDecorator.GetCircuitBreakerStats()
+	stats := cbDecorator.GetCircuitBreakerStats("test_tool")
 
 	expectedStats := map[string]any{
 		"state":             "HALF_OPEN",
@@ -431,3 +433,95 @@ func TestCircuitBreaker_GetCircuitBreakerStats(t *testing.T) {
 // direct instantiation of llms.ToolFunction, as it is not exported.
 // We assume that the underlying inner.GetTools(), inner.GetToolsForAgent(),
 // and inner.RegisterTool() calls are correct.
+
+func TestCircuitBreaker_UnhealthyRejectionsDoNotOpenCircuit(t *testing.T) {
+	cfg := CircuitBreakerConfig{FailureThreshold: 1, RecoveryTimeout: 1 * time.Minute, SuccessThreshold: 1}
+	mockInner := &mockToolProvider{
+		executeToolFunc: func(ctx context.Context, name string, input string) (string, error) {
+			if name == "unhealthy_tool" {
+				return "", fmt.Errorf("tool %s is unhealthy: %w", name, internalErrors.ErrServiceUnavailable)
+			}
+			return "ok", nil
+		},
+	}
+	decorator := NewCircuitBreakerToolProviderDecorator(mockInner, zap.NewNop(), cfg)
+	cbDecorator := decorator.(*CircuitBreakerToolProviderDecorator)
+
+	for i := 0; i < 3; i++ {
+		_, err := decorator.ExecuteTool(context.Background(), "unhealthy_tool", "")
+		if !errors.Is(err, internalErrors.ErrServiceUnavailable) {
+			t.Fatalf("Expected service unavailable error, got %v", err)
+		}
+	}
+	if cbDecorator.getState("unhealthy_tool") != CircuitClosed {
+		t.Errorf("Expected state CLOSED after unhealthy rejections, got %s", cbDecorator.getState("unhealthy_tool"))
+	}
+
+	result, err := decorator.ExecuteTool(context.Background(), "healthy_tool", "")
+	if err != nil || result != "ok" {
+		t.Errorf("Expected healthy tool to execute, got %q, %v", result, err)
+	}
+}
+
+func TestCircuitBreaker_CircuitsArePerTool(t *testing.T) {
+	cfg := CircuitBreakerConfig{FailureThreshold: 1, RecoveryTimeout: 1 * time.Hour, SuccessThreshold: 1}
+	mockInner := &mockToolProvider{
+		executeToolFunc: func(_ context.Context, name string, _ string) (string, error) {
+			if name == "failing_tool" {
+				return "", errors.New("simulated error")
+			}
+			return "ok", nil
+		},
+	}
+	decorator := NewCircuitBreakerToolProviderDecorator(mockInner, zap.NewNop(), cfg)
+	cbDecorator := decorator.(*CircuitBreakerToolProviderDecorator)
+
+	if _, err := decorator.ExecuteTool(context.Background(), "failing_tool", ""); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if cbDecorator.getState("failing_tool") != CircuitOpen {
+		t.Errorf("Expected failing tool circuit OPEN, got %s", cbDecorator.getState("failing_tool"))
+	}
+
+	result, err := decorator.ExecuteTool(context.Background(), "other_tool", "")
+	if err != nil || result != "ok" {
+		t.Errorf("Expected other tool to execute, got %q, %v", result, err)
+	}
+	if cbDecorator.getState("other_tool") != CircuitClosed {
+		t.Errorf("Expected other tool circuit CLOSED, got %s", cbDecorator.getState("other_tool"))
+	}
+}
+
+func TestCircuitBreaker_ReportHealth(t *testing.T) {
+	cfg := CircuitBreakerConfig{FailureThreshold: 5, RecoveryTimeout: 1 * time.Hour, SuccessThreshold: 1}
+	decorator := NewCircuitBreakerToolProviderDecorator(&mockToolProvider{}, zap.NewNop(), cfg)
+	cbDecorator := decorator.(*CircuitBreakerToolProviderDecorator)
+
+	cbDecorator.ReportHealth(types.ToolHealth{
+		Tool:      "weather",
+		State:     types.ToolHealthUnhealthy,
+		Functions: []string{"weather_get_forecast"},
+	})
+	_, err := decorator.ExecuteTool(context.Background(), "weather_get_forecast", "")
+	if !errors.Is(err, internalErrors.ErrCircuitBreakerOpen) {
+		t.Fatalf("Expected circuit breaker open error, got %v", err)
+	}
+	if cbDecorator.getState("other_tool") != CircuitClosed {
+		t.Errorf("Expected other tool circuit CLOSED, got %s", cbDecorator.getState("other_tool"))
+	}
+
+	cbDecorator.ReportHealth(types.ToolHealth{
+		Tool:      "weather",
+		State:     types.ToolHealthHealthy,
+		Functions: []string{"weather_get_forecast"},
+	})
+	if cbDecorator.getState("weather_get_forecast") != CircuitHalfOpen {
+		t.Errorf("Expected state HALF_OPEN after recovery, got %s", cbDecorator.getState("weather_get_forecast"))
+	}
+	if _, err := decorator.ExecuteTool(context.Background(), "weather_get_forecast", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cbDecorator.getState("weather_get_forecast") != CircuitClosed {
+		t.Errorf("Expected state CLOSED after successful probe, got %s", cbDecorator.getState("weather_get_forecast"))
+	}
+}
@@ -37,6 +37,9 @@ var (
 
 	// ErrPromptNotFound indicates that a requested prompt was not found.
 	ErrPromptNotFound = errors.New("prompt not found")
+
+	// ErrSecretNotFound indicates that a requested secret was not found.
+	ErrSecretNotFound = errors.New("secret not found")
 )
 
 // ValidationError represents an error that occurs during validation.
@@ -132,3 +135,8 @@ func IsProvider(err error) bool {
 	var providerErr *ProviderError
 	return errors.As(err, &providerErr)
 }
+
+// IsServiceUnavailable checks if an error is a "service unavailable" error.
+func IsServiceUnavailable(err error) bool {
+	return errors.Is(err, ErrServiceUnavailable)
+}
@@ -2,8 +2,10 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"go.uber.org/zap"
 )
 
@@ -32,8 +37,12 @@ type CloneOptions struct {
 	URL         string
 	Destination string
 	Branch      string
-	Depth       int
-	Shallow     bool
+	// Tag checks out a tag instead of a branch
+	Tag     string
+	Depth   int
+	Shallow bool
+	// Token authenticates against http remotes, e.g. a GitHub token
+	Token string
 }
 
 // PushOptions contains options for pushing to a repository.
@@ -41,6 +50,10 @@ type PushOptions struct {
 	Repository string
 	Branch     string
 	Remote     string
+	// Token authenticates against http remotes, e.g. a GitHub token
+	Token string
+	// Tags pushes all local tags as well
+	Tags bool
 }
 
 // Clone clones a Git repository to the specified destination.
@@ -54,9 +67,15 @@ func (c *Client) Clone(ctx context.Context, opts CloneOptions) error {
 	cloneOpts := &git.CloneOptions{
 		URL: opts.URL,
 	}
+	if opts.Token != "" {
+		cloneOpts.Auth = &githttp.BasicAuth{Username: "x-access-token", Password: opts.Token}
+	}
 
-	// Add branch if specified
-	if opts.Branch != "" {
+	// Add tag or branch if specified
+	if opts.Tag != "" {
+		cloneOpts.ReferenceName = plumbing.NewTagReferenceName(opts.Tag)
+		cloneOpts.SingleBranch = true
+	} else if opts.Branch != "" {
 		cloneOpts.ReferenceName = plumbing.ReferenceName("refs/heads/" + opts.Branch)
 		cloneOpts.SingleBranch = true
 	}
@@ -83,7 +102,7 @@ func (c *Client) Clone(ctx context.Context, opts CloneOptions) error {
 	return nil
 }
 
-// Push pushes changes to a remote repository.
+// Push pushes the current branch, or opts.Branch, to a remote repository.
 func (c *Client) Push(ctx context.Context, repoPath string, opts PushOptions) error {
 	c.logger.Info("Pushing to repository",
 		zap.String("path", repoPath),
@@ -96,12 +115,40 @@ func (c *Client) Push(ctx context.Context, repoPath string, opts PushOptions) er
 		remote = "origin"
 	}
 
-	// TODO: Implement push with authentication
-	// For now, we'll just log that push is prepared
-	c.logger.Info("Repository prepared for push",
-		zap.String("remote", remote),
-		zap.String("path", repoPath))
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return fmt.Errorf("failed to resolve HEAD: %w", err)
+		}
+		branch = head.Name().Short()
+	}
 
+	refSpecs := []config.RefSpec{config.RefSpec("refs/heads/" + branch + ":refs/heads/" + branch)}
+	if opts.Tags {
+		refSpecs = append(refSpecs, config.RefSpec("refs/tags/*:refs/tags/*"))
+	}
+
+	pushOpts := &git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   refSpecs,
+	}
+	if opts.Token != "" {
+		pushOpts.Auth = &githttp.BasicAuth{Username: "x-access-token", Password: opts.Token}
+	}
+
+	if err := repo.PushContext(ctx, pushOpts); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to push to %s: %w", remote, err)
+	}
+
+	c.logger.Info("Repository pushed",
+		zap.String("remote", remote),
+		zap.String("branch", branch))
 	return nil
 }
 
@@ -145,11 +192,7 @@ func (c *Client) AddAndCommit(ctx context.Context, repoPath, message string) err
 
 	// Create commit
 	commit, err := worktree.Commit(message, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  "AgentForge",
-			Email: "agentforge@example.com",
-			When:  time.Now(),
-		},
+		Author: signature(),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to commit changes: %w", err)
@@ -190,6 +233,131 @@ func (c *Client) AddRemote(ctx context.Context, repoPath, name, url string) erro
 	return nil
 }
 
+// SetRemote points a remote at url, creating or replacing it.
+func (c *Client) SetRemote(ctx context.Context, repoPath, name, url string) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	if existing, err := repo.Remote(name); err == nil {
+		urls := existing.Config().URLs
+		if len(urls) == 1 && urls[0] == url {
+			return nil
+		}
+		if err := repo.DeleteRemote(name); err != nil {
+			return fmt.Errorf("failed to replace remote %s: %w", name, err)
+		}
+	}
+	return c.AddRemote(ctx, repoPath, name, url)
+}
+
+// CreateTag creates an annotated tag on HEAD.
+func (c *Client) CreateTag(ctx context.Context, repoPath, tag, message string) error {
+	c.logger.Info("Creating tag",
+		zap.String("path", repoPath),
+		zap.String("tag", tag))
+
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	_, err = repo.CreateTag(tag, head.Hash(), &git.CreateTagOptions{
+		Tagger:  signature(),
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", tag, err)
+	}
+	return nil
+}
+
+// HeadCommit returns the commit hash HEAD points to.
+func (c *Client) HeadCommit(ctx context.Context, repoPath string) (string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
+// HeadBranch returns the name of the branch HEAD points to.
+func (c *Client) HeadBranch(ctx context.Context, repoPath string) (string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("HEAD of %s is not a branch", repoPath)
+	}
+	return head.Name().Short(), nil
+}
+
+// ListTags returns the sorted tag names of a remote repository without cloning it.
+// A non-empty token authenticates against http remotes.
+func (c *Client) ListTags(ctx context.Context, url, token string) ([]string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+	listOpts := &git.ListOptions{}
+	if token != "" {
+		listOpts.Auth = &githttp.BasicAuth{Username: "x-access-token", Password: token}
+	}
+	refs, err := remote.ListContext(ctx, listOpts)
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", url, err)
+	}
+
+	var tags []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref.Name().Short())
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// signature returns the author used for commits and tags created by AgentForge.
+func signature() *object.Signature {
+	return &object.Signature{
+		Name:  "AgentForge",
+		Email: "agentforge@example.com",
+		When:  time.Now(),
+	}
+}
+
+// RepositoryURL expands a repository reference such as github.com/owner/repo or
+// owner/repo into a cloneable URL. URLs and local paths are returned unchanged.
+func RepositoryURL(repo string) string {
+	switch {
+	case strings.Contains(repo, "://"), strings.HasPrefix(repo, "git@"),
+		filepath.IsAbs(repo), strings.HasPrefix(repo, "."):
+		return repo
+	case strings.HasPrefix(repo, "github.com/"):
+		return "https://" + repo
+	default:
+		return "https://github.com/" + repo
+	}
+}
+
 // ParseRepositoryURL parses a repository URL and extracts useful information.
 func ParseRepositoryURL(url string) (owner, repo string, err error) {
 	// Handle GitHub URLs
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CloneVersion clones opts.URL into opts.Destination and checks out version as tag,
+// as v-prefixed tag or as branch. Without a version the default branch is checked out.
+// It returns the checked out ref, the tag or the name of the branch.
+func CloneVersion(ctx context.Context, client GitClient, opts CloneOptions, version string) (string, error) {
+	if version == "" {
+		if err := client.Clone(ctx, opts); err != nil {
+			return "", err
+		}
+		return client.HeadBranch(ctx, opts.Destination)
+	}
+
+	attempts := []CloneOptions{{Tag: version}}
+	if !strings.HasPrefix(version, "v") {
+		attempts = append(attempts, CloneOptions{Tag: "v" + version})
+	}
+	attempts = append(attempts, CloneOptions{Branch: version})
+
+	var err error
+	for _, attempt := range attempts {
+		// A failed attempt may leave a partial checkout behind
+		os.RemoveAll(opts.Destination)
+		clone := opts
+		clone.Tag, clone.Branch = attempt.Tag, attempt.Branch
+		if err = client.Clone(ctx, clone); err == nil {
+			return attempt.Tag + attempt.Branch, nil
+		}
+	}
+	return "", fmt.Errorf("version %s is neither a tag nor a branch of %s: %w", version, opts.URL, err)
+}
@@ -14,6 +14,11 @@ type GitClient interface {
 	InitRepository(ctx context.Context, path string) error
 	AddAndCommit(ctx context.Context, repoPath, message string) error
 	AddRemote(ctx context.Context, repoPath, name, url string) error
+	SetRemote(ctx context.Context, repoPath, name, url string) error
+	CreateTag(ctx context.Context, repoPath, tag, message string) error
+	HeadCommit(ctx context.Context, repoPath string) (string, error)
+	HeadBranch(ctx context.Context, repoPath string) (string, error)
+	ListTags(ctx context.Context, url, token string) ([]string, error)
 }
 
 // NewGitClientFromDI creates a GitClient using dependency injection
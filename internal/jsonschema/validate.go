@@ -0,0 +1,297 @@
+// Package jsonschema validates decoded JSON values against a JSON Schema.
+//
+// The supported keywords cover what tool parameters and structured prompt outputs
+// declare: type, enum, const, properties, required, additionalProperties, items,
+// minItems, maxItems, minLength, maxLength, pattern, minimum, maximum, allOf,
+// anyOf and oneOf. Unknown keywords are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxReportedErrors limits the violations listed in a validation error.
+const maxReportedErrors = 5
+
+// ValidationError lists the schema violations of a value.
+type ValidationError struct {
+	Violations []string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	violations := e.Violations
+	suffix := ""
+	if len(violations) > maxReportedErrors {
+		suffix = fmt.Sprintf(" (and %d more)", len(violations)-maxReportedErrors)
+		violations = violations[:maxReportedErrors]
+	}
+	return "schema validation failed: " + strings.Join(violations, "; ") + suffix
+}
+
+// Parse decodes a JSON Schema document.
+func Parse(data []byte) (map[string]any, error) {
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	return schema, nil
+}
+
+// Validate checks a value decoded by encoding/json against schema and returns a
+// *ValidationError listing all violations.
+func Validate(schema map[string]any, value any) error {
+	v := &validator{}
+	v.validate(schema, value, "$")
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+// ValidateJSON decodes data and validates it against schema.
+func ValidateJSON(schema map[string]any, data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return Validate(schema, value)
+}
+
+// validator collects violations while walking a value.
+type validator struct {
+	violations []string
+}
+
+// fail records a violation at path.
+func (v *validator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, path+": "+fmt.Sprintf(format, args...))
+}
+
+// validate checks value at path against schema.
+func (v *validator) validate(schema map[string]any, value any, path string) {
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !matchesAnyType(types, value) {
+		v.fail(path, "expected %s, got %s", strings.Join(types, " or "), typeName(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, value) {
+		v.fail(path, "must be one of %s", compact(enum))
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		v.fail(path, "must be %s", compact(constant))
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		v.validateObject(schema, value, path)
+	case []any:
+		v.validateArray(schema, value, path)
+	case string:
+		v.validateString(schema, value, path)
+	case float64:
+		v.validateNumber(schema, value, path)
+	}
+
+	v.validateCombinators(schema, value, path)
+}
+
+// validateObject checks properties, required and additionalProperties.
+func (v *validator) validateObject(schema map[string]any, value map[string]any, path string) {
+	properties, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, exists := value[key]; !exists {
+					v.fail(path, "missing required property %q", key)
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propertyPath := path + "." + key
+		if property, ok := properties[key].(map[string]any); ok {
+			v.validate(property, value[key], propertyPath)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(path, "unexpected property %q", key)
+			}
+		case map[string]any:
+			v.validate(additional, value[key], propertyPath)
+		}
+	}
+}
+
+// validateArray checks items, minItems and maxItems.
+func (v *validator) validateArray(schema map[string]any, value []any, path string) {
+	if minItems, ok := number(schema["minItems"]); ok && float64(len(value)) < minItems {
+		v.fail(path, "must have at least %v items", minItems)
+	}
+	if maxItems, ok := number(schema["maxItems"]); ok && float64(len(value)) > maxItems {
+		v.fail(path, "must have at most %v items", maxItems)
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range value {
+			v.validate(items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// validateString checks minLength, maxLength and pattern.
+func (v *validator) validateString(schema map[string]any, value string, path string) {
+	length := float64(utf8.RuneCountInString(value))
+	if minLength, ok := number(schema["minLength"]); ok && length < minLength {
+		v.fail(path, "must be at least %v characters", minLength)
+	}
+	if maxLength, ok := number(schema["maxLength"]); ok && length > maxLength {
+		v.fail(path, "must be at most %v characters", maxLength)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.fail(path, "invalid pattern %q in schema", pattern)
+		} else if !re.MatchString(value) {
+			v.fail(path, "must match pattern %q", pattern)
+		}
+	}
+}
+
+// validateNumber checks minimum and maximum.
+func (v *validator) validateNumber(schema map[string]any, value float64, path string) {
+	if minimum, ok := number(schema["minimum"]); ok && value < minimum {
+		v.fail(path, "must be >= %v", minimum)
+	}
+	if maximum, ok := number(schema["maximum"]); ok && value > maximum {
+		v.fail(path, "must be <= %v", maximum)
+	}
+}
+
+// validateCombinators checks allOf, anyOf and oneOf.
+func (v *validator) validateCombinators(schema map[string]any, value any, path string) {
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, member := range allOf {
+			if memberSchema, ok := member.(map[string]any); ok {
+				v.validate(memberSchema, value, path)
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok && countMatches(anyOf, value) == 0 {
+		v.fail(path, "must match at least one schema of anyOf")
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok && countMatches(oneOf, value) != 1 {
+		v.fail(path, "must match exactly one schema of oneOf")
+	}
+}
+
+// countMatches returns how many of the schemas value is valid against.
+func countMatches(schemas []any, value any) int {
+	matches := 0
+	for _, member := range schemas {
+		memberSchema, ok := member.(map[string]any)
+		if !ok {
+			continue
+		}
+		if Validate(memberSchema, value) == nil {
+			matches++
+		}
+	}
+	return matches
+}
+
+// schemaTypes returns the types allowed by a type keyword.
+func schemaTypes(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		types := make([]string, 0, len(value))
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesAnyType reports whether value has one of the JSON Schema types.
+func matchesAnyType(types []string, value any) bool {
+	for _, name := range types {
+		if name == typeName(value) || name == "number" && typeName(value) == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName returns the JSON Schema type of a decoded value.
+func typeName(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == math.Trunc(value) && !math.IsInf(value, 0) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// containsValue reports whether values contains value.
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// number converts a numeric schema keyword.
+func number(value any) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	}
+	return 0, false
+}
+
+// compact renders a value as JSON for messages.
+func compact(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,76 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const personSchema = `{
+	"type": "object",
+	"required": ["name", "age"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"age": {"type": "integer", "minimum": 0},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "maxItems": 2},
+		"score": {"type": ["number", "null"]}
+	}
+}`
+
+func TestValidateJSON(t *testing.T) {
+	schema, err := Parse([]byte(personSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		value     string
+		violation string
+	}{
+		{"valid", `{"name":"ada","age":36,"role":"admin","tags":["math"],"score":1.5}`, ""},
+		{"null allowed", `{"name":"ada","age":36,"score":null}`, ""},
+		{"missing required", `{"name":"ada"}`, `missing required property "age"`},
+		{"wrong type", `{"name":"ada","age":"36"}`, "$.age: expected integer, got string"},
+		{"integer required", `{"name":"ada","age":36.5}`, "expected integer, got number"},
+		{"minimum", `{"name":"ada","age":-1}`, "must be >= 0"},
+		{"enum", `{"name":"ada","age":1,"role":"root"}`, "must be one of"},
+		{"additional property", `{"name":"ada","age":1,"email":"a@b"}`, `unexpected property "email"`},
+		{"item pattern", `{"name":"ada","age":1,"tags":["Math"]}`, "$.tags[0]: must match pattern"},
+		{"max items", `{"name":"ada","age":1,"tags":["a","b","c"]}`, "at most 2 items"},
+		{"min length", `{"name":"","age":1}`, "at least 1 characters"},
+		{"root type", `[1,2]`, "$: expected object, got array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSON(schema, []byte(tt.value))
+			if tt.violation == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, err.Error(), tt.violation)
+		})
+	}
+}
+
+func TestValidate_Combinators(t *testing.T) {
+	schema, err := Parse([]byte(`{
+		"oneOf": [{"type": "string"}, {"type": "number", "minimum": 10}],
+		"anyOf": [{"type": "string", "maxLength": 3}, {"type": "number"}]
+	}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, Validate(schema, "abc"))
+	assert.NoError(t, Validate(schema, 12.0))
+	assert.ErrorContains(t, Validate(schema, 5.0), "oneOf")
+	assert.ErrorContains(t, Validate(schema, "abcd"), "anyOf")
+}
+
+func TestValidateJSON_InvalidJSON(t *testing.T) {
+	err := ValidateJSON(map[string]any{"type": "object"}, []byte("not json"))
+	assert.ErrorContains(t, err, "invalid JSON")
+}
@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// Supported LLM providers.
+const (
+	ProviderGoogleAI  = "googleai"
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
+)
+
+// providerAliases maps alternative provider names to the supported providers.
+var providerAliases = map[string]string{
+	"google":    ProviderGoogleAI,
+	"gemini":    ProviderGoogleAI,
+	"claude":    ProviderAnthropic,
+	"openai":    ProviderOpenAI,
+	"googleai":  ProviderGoogleAI,
+	"anthropic": ProviderAnthropic,
+	"ollama":    ProviderOllama,
+}
+
+// modelPrefixes infers the provider of bare model names.
+var modelPrefixes = []struct {
+	prefix   string
+	provider string
+}{
+	{"gemini", ProviderGoogleAI},
+	{"gpt-", ProviderOpenAI},
+	{"o1", ProviderOpenAI},
+	{"o3", ProviderOpenAI},
+	{"o4", ProviderOpenAI},
+	{"claude", ProviderAnthropic},
+}
+
+// defaultModels are fast, inexpensive models offered when no model is chosen.
+var defaultModels = map[string]string{
+	ProviderOpenAI:    "gpt-4o-mini",
+	ProviderAnthropic: "claude-3-5-haiku-latest",
+	ProviderGoogleAI:  "gemini-1.5-flash",
+	ProviderOllama:    "llama3.2",
+}
+
+// DefaultModel returns the default model reference of a provider as provider:model.
+func DefaultModel(provider string) string {
+	if canonical, ok := providerAliases[strings.ToLower(provider)]; ok {
+		provider = canonical
+	}
+	if model, ok := defaultModels[provider]; ok {
+		return provider + ":" + model
+	}
+	return ""
+}
+
+// Parameters read by InitializeLLM from LLMConfig.GetParameters.
+const (
+	// ParamBaseURL overrides the API endpoint of openai and anthropic compatible servers
+	ParamBaseURL = "base_url"
+	// ParamServerURL is the address of an ollama server
+	ParamServerURL = "server_url"
+)
+
+// modelConfig is a private implementation of types.LLMConfig interface.
+type modelConfig struct {
+	provider    string
+	model       string
+	temperature float64
+	maxTokens   int
+	parameters  map[string]any
+}
+
+// NewLLMConfig creates an LLM configuration. The provider may be an alias like gemini or claude.
+func NewLLMConfig(provider, model string, temperature float64, maxTokens int, parameters map[string]any) types.LLMConfig {
+	if canonical, ok := providerAliases[strings.ToLower(provider)]; ok {
+		provider = canonical
+	}
+	if parameters == nil {
+		parameters = make(map[string]any)
+	}
+	return &modelConfig{
+		provider:    provider,
+		model:       model,
+		temperature: temperature,
+		maxTokens:   maxTokens,
+		parameters:  parameters,
+	}
+}
+
+// ParseModel creates the configuration for a model reference of the form
+// provider:model or provider/model. The provider of bare model names like
+// gpt-4o, claude-3-5-sonnet or gemini-1.5-pro is inferred from the name.
+func ParseModel(ref string, temperature float64, maxTokens int) (types.LLMConfig, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+
+	for _, separator := range []string{":", "/"} {
+		provider, model, found := strings.Cut(ref, separator)
+		if !found {
+			continue
+		}
+		if _, known := providerAliases[strings.ToLower(provider)]; known {
+			config := NewLLMConfig(provider, model, temperature, maxTokens, nil)
+			return config, config.Validate()
+		}
+	}
+
+	lower := strings.ToLower(ref)
+	for _, candidate := range modelPrefixes {
+		if strings.HasPrefix(lower, candidate.prefix) {
+			return NewLLMConfig(candidate.provider, ref, temperature, maxTokens, nil), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot infer the provider of model %q, use provider:model with one of: %s",
+		ref, strings.Join(SupportedProviders(), ", "))
+}
+
+// SupportedProviders returns the canonical provider names.
+func SupportedProviders() []string {
+	return []string{ProviderAnthropic, ProviderGoogleAI, ProviderOllama, ProviderOpenAI}
+}
+
+// String returns the model reference as provider:model.
+func (c *modelConfig) String() string {
+	return c.provider + ":" + c.model
+}
+
+// GetProvider returns the LLM provider name.
+func (c *modelConfig) GetProvider() string {
+	return c.provider
+}
+
+// GetModel returns the model name.
+func (c *modelConfig) GetModel() string {
+	return c.model
+}
+
+// GetTemperature returns the sampling temperature.
+func (c *modelConfig) GetTemperature() float64 {
+	return c.temperature
+}
+
+// GetMaxTokens returns the maximum response length, 0 for the provider default.
+func (c *modelConfig) GetMaxTokens() int {
+	return c.maxTokens
+}
+
+// GetParameters returns provider-specific parameters.
+func (c *modelConfig) GetParameters() map[string]any {
+	return c.parameters
+}
+
+// GetParameter returns a specific parameter value.
+func (c *modelConfig) GetParameter(key string) (any, bool) {
+	value, ok := c.parameters[key]
+	return value, ok
+}
+
+// Validate checks that provider and model are set and the limits are in range.
+func (c *modelConfig) Validate() error {
+	if !slices.Contains(SupportedProviders(), c.provider) {
+		return fmt.Errorf("unsupported LLM provider %q, use one of: %s", c.provider, strings.Join(SupportedProviders(), ", "))
+	}
+	if c.model == "" {
+		return fmt.Errorf("model name is required for provider %s", c.provider)
+	}
+	if c.temperature < 0 || c.temperature > 2 {
+		return fmt.Errorf("temperature must be between 0 and 2, got %v", c.temperature)
+	}
+	if c.maxTokens < 0 {
+		return fmt.Errorf("max tokens must not be negative, got %d", c.maxTokens)
+	}
+	return nil
+}
+
+// stringParameter returns a string parameter of config, or "" when unset.
+func stringParameter(config types.LLMConfig, key string) string {
+	value, ok := config.GetParameter(key)
+	if !ok {
+		return ""
+	}
+	text, _ := value.(string)
+	return text
+}
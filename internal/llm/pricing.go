@@ -0,0 +1,83 @@
+package llm
+
+import "strings"
+
+// Pricing is the list price of a model in US dollars per million tokens.
+type Pricing struct {
+	InputPerMillion  float64 `json:"inputPerMillion"`
+	OutputPerMillion float64 `json:"outputPerMillion"`
+}
+
+// Cost returns the price of usage.
+func (p Pricing) Cost(usage Usage) float64 {
+	return (float64(usage.InputTokens)*p.InputPerMillion + float64(usage.OutputTokens)*p.OutputPerMillion) / 1e6
+}
+
+// modelPricing lists public list prices by provider and model name prefix. Prices
+// change, so costs derived from them are estimates.
+var modelPricing = map[string]map[string]Pricing{
+	ProviderOpenAI: {
+		"gpt-4o-mini":   {0.15, 0.60},
+		"gpt-4o":        {2.50, 10.00},
+		"gpt-4.1-nano":  {0.10, 0.40},
+		"gpt-4.1-mini":  {0.40, 1.60},
+		"gpt-4.1":       {2.00, 8.00},
+		"gpt-4-turbo":   {10.00, 30.00},
+		"gpt-4":         {30.00, 60.00},
+		"gpt-3.5-turbo": {0.50, 1.50},
+		"o1-mini":       {1.10, 4.40},
+		"o1":            {15.00, 60.00},
+		"o3-mini":       {1.10, 4.40},
+		"o3":            {2.00, 8.00},
+		"o4-mini":       {1.10, 4.40},
+	},
+	ProviderAnthropic: {
+		"claude-3-haiku":    {0.25, 1.25},
+		"claude-3-5-haiku":  {0.80, 4.00},
+		"claude-3-5-sonnet": {3.00, 15.00},
+		"claude-3-7-sonnet": {3.00, 15.00},
+		"claude-sonnet-4":   {3.00, 15.00},
+		"claude-3-opus":     {15.00, 75.00},
+		"claude-opus-4":     {15.00, 75.00},
+	},
+	ProviderGoogleAI: {
+		"gemini-1.5-flash": {0.075, 0.30},
+		"gemini-1.5-pro":   {1.25, 5.00},
+		"gemini-2.0-flash": {0.10, 0.40},
+		"gemini-2.5-flash": {0.30, 2.50},
+		"gemini-2.5-pro":   {1.25, 10.00},
+	},
+}
+
+// LookupPricing returns the pricing of a model, matching the longest known model
+// name prefix. Local ollama models are free.
+func LookupPricing(provider, model string) (Pricing, bool) {
+	if canonical, ok := providerAliases[strings.ToLower(provider)]; ok {
+		provider = canonical
+	}
+	if provider == ProviderOllama {
+		return Pricing{}, true
+	}
+
+	model = strings.ToLower(model)
+	var (
+		best    Pricing
+		longest int
+	)
+	for prefix, pricing := range modelPricing[provider] {
+		if strings.HasPrefix(model, prefix) && len(prefix) > longest {
+			best, longest = pricing, len(prefix)
+		}
+	}
+	return best, longest > 0
+}
+
+// EstimateCost returns the estimated price of usage, or false for models without
+// known pricing.
+func EstimateCost(provider, model string, usage Usage) (float64, bool) {
+	pricing, ok := LookupPricing(provider, model)
+	if !ok {
+		return 0, false
+	}
+	return pricing.Cost(usage), true
+}
@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupPricingMatchesLongestPrefix(t *testing.T) {
+	pricing, ok := LookupPricing("openai", "gpt-4o-mini-2024-07-18")
+	assert.True(t, ok)
+	assert.Equal(t, 0.15, pricing.InputPerMillion)
+
+	pricing, ok = LookupPricing("openai", "gpt-4o")
+	assert.True(t, ok)
+	assert.Equal(t, 2.50, pricing.InputPerMillion)
+
+	pricing, ok = LookupPricing("claude", "claude-3-5-haiku-latest")
+	assert.True(t, ok)
+	assert.Equal(t, 4.00, pricing.OutputPerMillion)
+}
+
+func TestLookupPricingUnknownAndLocalModels(t *testing.T) {
+	_, ok := LookupPricing("openai", "my-finetune")
+	assert.False(t, ok)
+
+	pricing, ok := LookupPricing("ollama", "llama3.2")
+	assert.True(t, ok)
+	assert.Zero(t, pricing.Cost(Usage{InputTokens: 1000, OutputTokens: 1000}))
+}
+
+func TestEstimateCost(t *testing.T) {
+	cost, ok := EstimateCost("anthropic", "claude-3-5-sonnet-latest", Usage{InputTokens: 1000, OutputTokens: 500})
+	assert.True(t, ok)
+	assert.InDelta(t, 0.0105, cost, 1e-9)
+}
@@ -0,0 +1,83 @@
+// Package llm creates langchaingo models for the configured LLM providers.
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/googleai"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+	"go.uber.org/zap"
+
+	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// llmService is a private implementation of types.LLMService interface.
+type llmService struct{}
+
+// NewLLMService creates a service initializing models from an LLM configuration.
+func NewLLMService() types.LLMService {
+	return &llmService{}
+}
+
+// InitializeLLM creates the model described by llmConfig, authenticated with the
+// API key of its provider from config.
+func (s *llmService) InitializeLLM(ctx context.Context, config types.Config, llmConfig types.LLMConfig) (llms.Model, error) {
+	if err := s.ValidateConfig(config, llmConfig); err != nil {
+		return nil, err
+	}
+
+	provider, model := llmConfig.GetProvider(), llmConfig.GetModel()
+	apiKey := config.GetAPIKey(provider)
+	log.Debug("Initializing LLM", zap.String("provider", provider), zap.String("model", model))
+
+	var (
+		llm llms.Model
+		err error
+	)
+	switch provider {
+	case ProviderGoogleAI:
+		llm, err = googleai.New(ctx, googleai.WithAPIKey(apiKey), googleai.WithDefaultModel(model))
+	case ProviderOpenAI:
+		opts := []openai.Option{openai.WithToken(apiKey), openai.WithModel(model)}
+		if baseURL := stringParameter(llmConfig, ParamBaseURL); baseURL != "" {
+			opts = append(opts, openai.WithBaseURL(baseURL))
+		}
+		llm, err = openai.New(opts...)
+	case ProviderAnthropic:
+		opts := []anthropic.Option{anthropic.WithToken(apiKey), anthropic.WithModel(model)}
+		if baseURL := stringParameter(llmConfig, ParamBaseURL); baseURL != "" {
+			opts = append(opts, anthropic.WithBaseURL(baseURL))
+		}
+		llm, err = anthropic.New(opts...)
+	case ProviderOllama:
+		opts := []ollama.Option{ollama.WithModel(model)}
+		if serverURL := stringParameter(llmConfig, ParamServerURL); serverURL != "" {
+			opts = append(opts, ollama.WithServerURL(serverURL))
+		}
+		llm, err = ollama.New(opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s model %s: %w", provider, model, err)
+	}
+	return llm, nil
+}
+
+// ValidateConfig checks llmConfig and that an API key is configured for its provider.
+// Local ollama models need no key.
+func (s *llmService) ValidateConfig(config types.Config, llmConfig types.LLMConfig) error {
+	if llmConfig == nil {
+		return fmt.Errorf("LLM configuration is required: %w", internalErrors.ErrInvalidInput)
+	}
+	if err := llmConfig.Validate(); err != nil {
+		return err
+	}
+	if llmConfig.GetProvider() != ProviderOllama && config.GetAPIKey(llmConfig.GetProvider()) == "" {
+		return fmt.Errorf("no API key configured for provider %s: %w", llmConfig.GetProvider(), internalErrors.ErrUnauthorized)
+	}
+	return nil
+}
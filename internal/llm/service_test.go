@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// testConfig is a types.Config with fixed API keys.
+type testConfig struct {
+	keys map[string]string
+}
+
+func (c testConfig) GetLogLevel() string              { return "info" }
+func (c testConfig) GetPort() int                     { return 0 }
+func (c testConfig) GetEnvironment() string           { return "test" }
+func (c testConfig) GetAPIKey(provider string) string { return c.keys[provider] }
+func (c testConfig) GetAPIKeys() map[string]string    { return c.keys }
+
+func TestParseModel(t *testing.T) {
+	tests := []struct {
+		ref      string
+		provider string
+		model    string
+	}{
+		{"openai:gpt-4o-mini", ProviderOpenAI, "gpt-4o-mini"},
+		{"claude/claude-3-5-haiku-latest", ProviderAnthropic, "claude-3-5-haiku-latest"},
+		{"ollama:llama3:8b", ProviderOllama, "llama3:8b"},
+		{"gemini-1.5-flash", ProviderGoogleAI, "gemini-1.5-flash"},
+		{"gpt-4", ProviderOpenAI, "gpt-4"},
+		{"claude-3-sonnet", ProviderAnthropic, "claude-3-sonnet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			config, err := ParseModel(tt.ref, 0.2, 100)
+			require.NoError(t, err)
+			assert.Equal(t, tt.provider, config.GetProvider())
+			assert.Equal(t, tt.model, config.GetModel())
+			assert.Equal(t, 0.2, config.GetTemperature())
+			assert.Equal(t, 100, config.GetMaxTokens())
+		})
+	}
+
+	for _, ref := range []string{"", "llama3", "openai:"} {
+		_, err := ParseModel(ref, 0, 0)
+		assert.Error(t, err, ref)
+	}
+}
+
+func TestLLMService_ValidateConfig(t *testing.T) {
+	service := NewLLMService()
+	config := testConfig{keys: map[string]string{ProviderOpenAI: "sk-test"}}
+
+	assert.NoError(t, service.ValidateConfig(config, NewLLMConfig("openai", "gpt-4o", 0.7, 0, nil)))
+	assert.NoError(t, service.ValidateConfig(config, NewLLMConfig("ollama", "llama3", 0.7, 0, nil)))
+	assert.ErrorContains(t, service.ValidateConfig(config, NewLLMConfig("anthropic", "claude-3-opus", 0.7, 0, nil)), "no API key")
+	assert.ErrorContains(t, service.ValidateConfig(config, NewLLMConfig("mistral", "large", 0.7, 0, nil)), "unsupported")
+	assert.ErrorContains(t, service.ValidateConfig(config, NewLLMConfig("openai", "gpt-4o", 3, 0, nil)), "temperature")
+}
+
+func TestLLMService_InitializeLLM(t *testing.T) {
+	service := NewLLMService()
+	config := testConfig{keys: map[string]string{ProviderOpenAI: "sk-test", ProviderAnthropic: "sk-ant"}}
+
+	model, err := service.InitializeLLM(context.Background(), config,
+		NewLLMConfig("openai", "gpt-4o-mini", 0.7, 0, map[string]any{ParamBaseURL: "http://localhost:1"}))
+	require.NoError(t, err)
+	assert.NotNil(t, model)
+
+	model, err = service.InitializeLLM(context.Background(), config, NewLLMConfig("claude", "claude-3-5-haiku-latest", 0.7, 0, nil))
+	require.NoError(t, err)
+	assert.NotNil(t, model)
+
+	_, err = service.InitializeLLM(context.Background(), testConfig{}, NewLLMConfig("openai", "gpt-4o-mini", 0.7, 0, nil))
+	assert.Error(t, err)
+}
+
+func TestResponseUsage(t *testing.T) {
+	response := &llms.ContentResponse{Choices: []*llms.ContentChoice{
+		{GenerationInfo: map[string]any{"PromptTokens": 10, "CompletionTokens": 5}},
+		{GenerationInfo: map[string]any{"input_tokens": int32(3), "output_tokens": int32(2)}},
+		{GenerationInfo: map[string]any{}},
+	}}
+
+	usage := ResponseUsage(response)
+	assert.Equal(t, Usage{InputTokens: 13, OutputTokens: 7}, usage)
+	assert.Equal(t, 20, usage.Total())
+	assert.Equal(t, Usage{}, ResponseUsage(nil))
+}
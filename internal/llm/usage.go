@@ -0,0 +1,62 @@
+package llm
+
+import "github.com/tmc/langchaingo/llms"
+
+// Usage is the token usage reported for a model response.
+type Usage struct {
+	InputTokens  int `json:"inputTokens" yaml:"inputTokens"`
+	OutputTokens int `json:"outputTokens" yaml:"outputTokens"`
+}
+
+// Total returns the sum of input and output tokens.
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+	}
+}
+
+// usageKeys are the generation info keys the providers report token counts under.
+var usageKeys = struct {
+	input  []string
+	output []string
+}{
+	input:  []string{"PromptTokens", "InputTokens", "input_tokens"},
+	output: []string{"CompletionTokens", "OutputTokens", "output_tokens"},
+}
+
+// ResponseUsage returns the token usage of a response, summed over its choices.
+// Providers that do not report usage yield zero counts.
+func ResponseUsage(response *llms.ContentResponse) Usage {
+	var usage Usage
+	if response == nil {
+		return usage
+	}
+	for _, choice := range response.Choices {
+		usage.InputTokens += firstCount(choice.GenerationInfo, usageKeys.input)
+		usage.OutputTokens += firstCount(choice.GenerationInfo, usageKeys.output)
+	}
+	return usage
+}
+
+// firstCount returns the first integer value found under one of keys.
+func firstCount(info map[string]any, keys []string) int {
+	for _, key := range keys {
+		switch value := info[key].(type) {
+		case int:
+			return value
+		case int32:
+			return int(value)
+		case int64:
+			return int(value)
+		case float64:
+			return int(value)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,7 @@
+package mcpserver
+
+import "github.com/denkhaus/agentforge/internal/logger"
+
+var (
+	log = logger.WithPackage("mcpserver")
+)
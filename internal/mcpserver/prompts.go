@@ -0,0 +1,109 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/prompts"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// promptArguments is implemented by prompts that declare their MCP arguments.
+type promptArguments interface {
+	GetArguments() []mcp.PromptArgument
+}
+
+// registerPrompts adds provider prompts and locally installed prompts as MCP prompts
+// and returns the prompt count. Provider prompts take precedence on name conflicts.
+func registerPrompts(s *server.MCPServer, promptProvider types.PromptProvider, promptService prompts.PromptService) int {
+	registered := make(map[string]bool)
+
+	providerPrompts := promptProvider.GetPrompts()
+	names := make([]string, 0, len(providerPrompts))
+	for name := range providerPrompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prompt := providerPrompts[name]
+		mcpPrompt := mcp.Prompt{
+			Name:        name,
+			Description: prompt.GetDescription(),
+		}
+		if withArguments, ok := prompt.(promptArguments); ok {
+			mcpPrompt.Arguments = withArguments.GetArguments()
+		}
+
+		s.AddPrompt(mcpPrompt, newPromptHandler(prompt))
+		registered[name] = true
+	}
+
+	if promptService == nil {
+		return len(registered)
+	}
+
+	localPrompts, err := promptService.ListLocalPrompts()
+	if err != nil {
+		log.Warn("Failed to list local prompts", zap.Error(err))
+		return len(registered)
+	}
+
+	for _, data := range localPrompts {
+		if registered[data.Name] {
+			log.Warn("Prompt name conflict, keeping provider prompt", zap.String("prompt", data.Name))
+			continue
+		}
+
+		options := []mcp.PromptOption{mcp.WithPromptDescription(data.Description)}
+		for _, variable := range data.Variables {
+			options = append(options, mcp.WithArgument(variable, mcp.RequiredArgument()))
+		}
+
+		s.AddPrompt(mcp.NewPrompt(data.Name, options...), newLocalPromptHandler(promptService, data.Name))
+		registered[data.Name] = true
+	}
+
+	return len(registered)
+}
+
+// newPromptHandler creates a handler that renders a provider prompt.
+func newPromptHandler(prompt types.Prompt) server.PromptHandlerFunc {
+	return func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		variables := make(map[string]any, len(request.Params.Arguments))
+		for key, value := range request.Params.Arguments {
+			variables[key] = value
+		}
+
+		text, err := prompt.Render(variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", prompt.GetName(), err)
+		}
+
+		return newPromptResult(prompt.GetDescription(), text), nil
+	}
+}
+
+// newLocalPromptHandler creates a handler that executes a locally installed prompt.
+func newLocalPromptHandler(promptService prompts.PromptService, name string) server.PromptHandlerFunc {
+	return func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		text, err := promptService.ExecutePrompt(name, request.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute prompt %s: %w", name, err)
+		}
+
+		return newPromptResult("", text), nil
+	}
+}
+
+// newPromptResult wraps rendered prompt text in a single user message.
+func newPromptResult(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
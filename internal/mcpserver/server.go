@@ -0,0 +1,154 @@
+// Package mcpserver exposes forge components over the Model Context Protocol.
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/prompts"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// shutdownTimeout bounds the graceful shutdown of the HTTP transport.
+const shutdownTimeout = 10 * time.Second
+
+// Options configures the forge MCP server.
+type Options struct {
+	Name    string
+	Version string
+
+	// ToolProvider provides the tools served as MCP tools
+	ToolProvider types.ToolProvider
+	// PromptProvider provides the prompts served as MCP prompts
+	PromptProvider types.PromptProvider
+	// PromptService optionally provides locally installed prompts
+	PromptService prompts.PromptService
+
+	// AgentProvider optionally provides agents served as MCP tools
+	AgentProvider types.AgentProvider
+	// SessionFactory creates the sessions used to run agents
+	SessionFactory types.SessionFactory
+	// Config is passed to agent sessions
+	Config types.Config
+
+	// HealthMonitor optionally reports the health of installed tools on /health
+	HealthMonitor types.ToolHealthMonitor
+}
+
+// mcpServer is a private implementation of types.MCPServer.
+type mcpServer struct {
+	server        *server.MCPServer
+	healthMonitor types.ToolHealthMonitor
+}
+
+// healthResponse is the body of the /health endpoint.
+type healthResponse struct {
+	Status string             `json:"status"`
+	Tools  []types.ToolHealth `json:"tools,omitempty"`
+}
+
+// NewServer creates a new MCP server exposing the configured forge components.
+func NewServer(opts Options) (types.MCPServer, error) {
+	if opts.ToolProvider == nil {
+		return nil, fmt.Errorf("tool provider is required")
+	}
+	if opts.PromptProvider == nil {
+		return nil, fmt.Errorf("prompt provider is required")
+	}
+	if opts.AgentProvider != nil && opts.SessionFactory == nil {
+		return nil, fmt.Errorf("session factory is required to serve agents")
+	}
+
+	s := server.NewMCPServer(opts.Name, opts.Version,
+		server.WithToolCapabilities(false),
+		server.WithPromptCapabilities(false),
+		server.WithRecovery(),
+	)
+
+	toolCount := registerTools(s, opts.ToolProvider)
+	agentCount := registerAgents(s, opts)
+	promptCount := registerPrompts(s, opts.PromptProvider, opts.PromptService)
+
+	log.Info("MCP server created",
+		zap.Int("tools", toolCount),
+		zap.Int("agents", agentCount),
+		zap.Int("prompts", promptCount))
+
+	return &mcpServer{server: s, healthMonitor: opts.HealthMonitor}, nil
+}
+
+// ServeStdio serves MCP over stdin/stdout until the context is cancelled.
+func (m *mcpServer) ServeStdio(ctx context.Context) error {
+	stdio := server.NewStdioServer(m.server)
+	stdio.SetErrorLogger(zap.NewStdLog(log))
+
+	log.Info("Serving MCP over stdio")
+
+	if err := stdio.Listen(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("failed to serve MCP over stdio: %w", err)
+	}
+	return nil
+}
+
+// ServeHTTP serves MCP over streamable HTTP on the given address until the context is cancelled.
+// The MCP endpoint is mounted at /mcp next to a /health endpoint.
+func (m *mcpServer) ServeHTTP(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", server.NewStreamableHTTPServer(m.server))
+	mux.HandleFunc("/health", m.handleHealth)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: shutdownTimeout,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Info("Serving MCP over streamable HTTP", zap.String("addr", addr))
+		errChan <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve MCP over HTTP: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down MCP HTTP server: %w", err)
+	}
+
+	log.Info("MCP HTTP server stopped")
+	return nil
+}
+
+// handleHealth reports that the server is up, and degraded if any installed tool is unhealthy.
+func (m *mcpServer) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	response := healthResponse{Status: "ok"}
+	if m.healthMonitor != nil {
+		response.Tools = m.healthMonitor.GetHealth()
+		for _, health := range response.Tools {
+			if health.State == types.ToolHealthUnhealthy {
+				response.Status = "degraded"
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,151 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/providers"
+	agenttools "github.com/denkhaus/agentforge/internal/tools"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+func newTestClient(t *testing.T) *mcpclient.Client {
+	injector := do.New()
+	t.Cleanup(func() { _ = injector.Shutdown() })
+
+	do.ProvideValue(injector, zap.NewNop())
+	do.ProvideValue(injector, agenttools.GetTools())
+	do.ProvideValue(injector, map[string]types.Prompt{
+		"greeting": providers.NewCustomPrompt("greeting", "Greets someone", "Hello {{.name}}"),
+	})
+
+	toolProvider, err := providers.NewToolProvider(injector)
+	require.NoError(t, err)
+	promptProvider, err := providers.NewPromptProvider(injector)
+	require.NoError(t, err)
+
+	srv, err := NewServer(Options{
+		Name:           "test",
+		Version:        "0.0.1",
+		ToolProvider:   toolProvider,
+		PromptProvider: promptProvider,
+	})
+	require.NoError(t, err)
+
+	client, err := mcpclient.NewInProcessClient(srv.(*mcpServer).server)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, client.Start(ctx))
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestNewServer_RequiresProviders(t *testing.T) {
+	_, err := NewServer(Options{})
+	assert.Error(t, err)
+}
+
+func TestServer_ToolsAndPrompts(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	t.Run("ListTools", func(t *testing.T) {
+		result, err := client.ListTools(ctx, mcp.ListToolsRequest{})
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(result.Tools))
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		assert.Contains(t, names, "getCurrentWeather")
+		assert.Contains(t, names, "createTask")
+	})
+
+	t.Run("CallTool", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "getCurrentWeather"
+		request.Params.Arguments = map[string]any{"location": "Chicago, IL"}
+
+		result, err := client.CallTool(ctx, request)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		require.NotEmpty(t, result.Content)
+	})
+
+	t.Run("GetPrompt", func(t *testing.T) {
+		request := mcp.GetPromptRequest{}
+		request.Params.Name = "greeting"
+		request.Params.Arguments = map[string]string{"name": "Ada"}
+
+		result, err := client.GetPrompt(ctx, request)
+		require.NoError(t, err)
+		require.Len(t, result.Messages, 1)
+
+		text, ok := mcp.AsTextContent(result.Messages[0].Content)
+		require.True(t, ok)
+		assert.Equal(t, "Hello Ada", text.Text)
+	})
+}
+
+func TestLastAssistantMessage(t *testing.T) {
+	history := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "system"),
+		llms.TextParts(llms.ChatMessageTypeAI, "first"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "question"),
+		llms.TextParts(llms.ChatMessageTypeAI, "answer"),
+	}
+
+	assert.Equal(t, "answer", lastAssistantMessage(history))
+	assert.Empty(t, lastAssistantMessage(nil))
+}
+
+// staticHealthMonitor is a types.ToolHealthMonitor reporting fixed tool health.
+type staticHealthMonitor struct {
+	health []types.ToolHealth
+}
+
+func (m *staticHealthMonitor) IsToolHealthy(string) bool             { return true }
+func (m *staticHealthMonitor) GetHealth() []types.ToolHealth         { return m.health }
+func (m *staticHealthMonitor) OnHealthChange(func(types.ToolHealth)) {}
+func (m *staticHealthMonitor) Close() error                          { return nil }
+func (m *staticHealthMonitor) CheckTool(context.Context, string) (types.ToolHealth, error) {
+	return types.ToolHealth{}, nil
+}
+
+func TestServer_HandleHealth(t *testing.T) {
+	srv := &mcpServer{}
+	recorder := httptest.NewRecorder()
+	srv.handleHealth(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+
+	srv.healthMonitor = &staticHealthMonitor{health: []types.ToolHealth{
+		{Tool: "weather", State: types.ToolHealthHealthy},
+		{Tool: "search", State: types.ToolHealthUnhealthy, LastError: "ping failed"},
+	}}
+	recorder = httptest.NewRecorder()
+	srv.handleHealth(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var response healthResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "degraded", response.Status)
+	require.Len(t, response.Tools, 2)
+	assert.Equal(t, "ping failed", response.Tools[1].LastError)
+}
@@ -0,0 +1,132 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tmc/langchaingo/llms"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// agentToolPrefix prefixes the names of the tools that run agents.
+const agentToolPrefix = "agent_"
+
+// registerTools adds every tool of the provider as an MCP tool and returns the tool count.
+// Calls are proxied through the provider so its decorators apply.
+func registerTools(s *server.MCPServer, toolProvider types.ToolProvider) int {
+	forgeTools := toolProvider.GetTools()
+	sort.Slice(forgeTools, func(i, j int) bool { return forgeTools[i].Name() < forgeTools[j].Name() })
+
+	for _, tool := range forgeTools {
+		mcpTool := mcp.NewTool(tool.Name(), mcp.WithDescription(tool.Description()))
+		if schema := types.ToolSchema(tool); schema != nil {
+			if raw, err := json.Marshal(schema); err == nil {
+				mcpTool = mcp.NewToolWithRawSchema(tool.Name(), tool.Description(), raw)
+			} else {
+				log.Warn("Failed to encode tool schema", zap.String("tool", tool.Name()), zap.Error(err))
+			}
+		}
+		s.AddTool(mcpTool, newToolHandler(toolProvider, tool.Name()))
+	}
+
+	return len(forgeTools)
+}
+
+// newToolHandler creates a handler that executes a tool with the call arguments as JSON input.
+func newToolHandler(toolProvider types.ToolProvider, name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		input, err := json.Marshal(request.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid tool arguments", err), nil
+		}
+
+		result, err := toolProvider.ExecuteTool(ctx, name, string(input))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr(fmt.Sprintf("tool %s failed", name), err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
+	}
+}
+
+// registerAgents adds every agent as an MCP tool that runs a full session and returns the agent count.
+func registerAgents(s *server.MCPServer, opts Options) int {
+	if opts.AgentProvider == nil {
+		return 0
+	}
+
+	agents := opts.AgentProvider.GetAgents()
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		agent := agents[name]
+		mcpTool := mcp.NewTool(agentToolPrefix+name,
+			mcp.WithDescription(fmt.Sprintf("Run the %s agent: %s", name, agent.GetDescription())),
+			mcp.WithString("message", mcp.Required(), mcp.Description("Message to send to the agent")),
+		)
+		s.AddTool(mcpTool, newAgentHandler(opts, agent))
+	}
+
+	return len(names)
+}
+
+// newAgentHandler creates a handler that runs the agent in a fresh session.
+func newAgentHandler(opts Options, agent types.Agent) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		message, err := request.RequireString("message")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		session, err := opts.SessionFactory.CreateSession(ctx, types.SessionOptions{
+			Config:        opts.Config,
+			Agent:         agent,
+			ToolProvider:  opts.ToolProvider,
+			AgentProvider: opts.AgentProvider,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to create agent session", err), nil
+		}
+
+		if err := session.Chat(ctx, message); err != nil {
+			log.Warn("Agent run failed", zap.String("agent", agent.GetName()), zap.Error(err))
+			return mcp.NewToolResultErrorFromErr(fmt.Sprintf("agent %s failed", agent.GetName()), err), nil
+		}
+
+		// Agents with an output schema return their validated reply as JSON
+		if value, ok := session.GetStructuredOutput(); ok {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to encode structured reply", err), nil
+			}
+			return mcp.NewToolResultText(string(data)), nil
+		}
+
+		return mcp.NewToolResultText(lastAssistantMessage(session.GetMessageHistory())), nil
+	}
+}
+
+// lastAssistantMessage returns the text of the last AI message in the history.
+func lastAssistantMessage(history []llms.MessageContent) string {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role != llms.ChatMessageTypeAI {
+			continue
+		}
+		for _, part := range history[i].Parts {
+			if text, ok := part.(llms.TextContent); ok && text.Text != "" {
+				return text.Text
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// collectSecuritySchemes maps the security schemes of the spec to credentials read
+// from secrets named after the tool and the scheme.
+func (g *generator) collectSecuritySchemes() {
+	schemes := objectValue(objectValue(g.doc.root, "components"), "securitySchemes")
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition, err := g.doc.resolve(schemes[name])
+		if err != nil || definition == nil {
+			g.warn("security scheme %s: %v", name, err)
+			continue
+		}
+
+		secret := secretName(g.toolName + "_" + name)
+		reference := "${" + secret + "}"
+		scheme := &authScheme{secret: secret}
+
+		switch schemeType := stringValue(definition, "type"); schemeType {
+		case "http":
+			switch strings.ToLower(stringValue(definition, "scheme")) {
+			case "bearer":
+				scheme.headers = map[string]string{"Authorization": "Bearer " + reference}
+			case "basic":
+				scheme.headers = map[string]string{"Authorization": "Basic " + reference}
+			default:
+				g.warn("security scheme %s: http scheme %q is not supported", name, stringValue(definition, "scheme"))
+				continue
+			}
+		case "apiKey":
+			keyName := stringValue(definition, "name")
+			switch stringValue(definition, "in") {
+			case "header":
+				scheme.headers = map[string]string{keyName: reference}
+			case "query":
+				scheme.query = map[string]string{keyName: reference}
+			default:
+				g.warn("security scheme %s: api keys in %q are not supported", name, stringValue(definition, "in"))
+				continue
+			}
+		case "oauth2", "openIdConnect":
+			// The access token is obtained outside of the tool and stored as a secret
+			scheme.headers = map[string]string{"Authorization": "Bearer " + reference}
+		default:
+			g.warn("security scheme %s: type %q is not supported", name, schemeType)
+			continue
+		}
+		g.auth[name] = scheme
+	}
+}
+
+// operationAuth returns the schemes of the first supported security requirement of an
+// operation, falling back to the global requirements. An empty operation list disables auth.
+func (g *generator) operationAuth(operation map[string]any) []*authScheme {
+	requirements, declared := operation["security"].([]any)
+	if !declared {
+		requirements = listValue(g.doc.root, "security")
+	}
+
+	for _, raw := range requirements {
+		requirement, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		schemes := make([]*authScheme, 0, len(names))
+		for _, name := range names {
+			if scheme, exists := g.auth[name]; exists {
+				schemes = append(schemes, scheme)
+			}
+		}
+		if len(schemes) == len(names) {
+			return schemes
+		}
+	}
+	return nil
+}
+
+// applyAuth adds the credential headers and query values of a scheme to a function request.
+func (g *generator) applyAuth(function *schema.ToolFunction, scheme *authScheme) {
+	for key, value := range scheme.headers {
+		if function.Request.Headers == nil {
+			function.Request.Headers = make(map[string]string)
+		}
+		function.Request.Headers[key] = value
+	}
+	for key, value := range scheme.query {
+		if function.Request.Query == nil {
+			function.Request.Query = make(map[string]string)
+		}
+		function.Request.Query[key] = value
+	}
+}
+
+// warn records a part of the spec that could not be mapped.
+func (g *generator) warn(format string, args ...any) {
+	g.result.Warnings = append(g.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+// secretName converts a name to an UPPER_SNAKE secret name.
+func secretName(name string) string {
+	return strings.ToUpper(slug(camelBoundary.ReplaceAllString(name, "${1}_${2}"), "_"))
+}
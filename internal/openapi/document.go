@@ -0,0 +1,185 @@
+// Package openapi generates http Tool manifests from OpenAPI 3 specifications.
+package openapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxRefDepth bounds chains of $refs pointing to $refs.
+const maxRefDepth = 32
+
+// document is a parsed OpenAPI document kept as generic YAML values, so that $ref
+// pointers can be resolved against any part of it.
+type document struct {
+	root map[string]any
+}
+
+// LoadSpec reads an OpenAPI specification from a file path or an http(s) URL.
+func LoadSpec(location string) ([]byte, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		client := &http.Client{Timeout: 30 * time.Second}
+		response, err := client.Get(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch OpenAPI spec: %w", err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch OpenAPI spec: status %d", response.StatusCode)
+		}
+		content, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OpenAPI spec: %w", err)
+		}
+		return content, nil
+	}
+
+	content, err := os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI spec: %w", err)
+	}
+	return content, nil
+}
+
+// parseDocument parses a JSON or YAML OpenAPI 3 document.
+func parseDocument(content []byte) (*document, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("OpenAPI spec is empty")
+	}
+
+	version := stringValue(root, "openapi")
+	if !strings.HasPrefix(version, "3.") {
+		if stringValue(root, "swagger") != "" {
+			return nil, fmt.Errorf("swagger 2.0 specs are not supported, convert the spec to OpenAPI 3 first")
+		}
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", version)
+	}
+	return &document{root: root}, nil
+}
+
+// resolve follows the $ref of an object until it reaches an inline object.
+func (d *document) resolve(value any) (map[string]any, error) {
+	object, _ := value.(map[string]any)
+	for depth := 0; object != nil; depth++ {
+		ref := stringValue(object, "$ref")
+		if ref == "" {
+			return object, nil
+		}
+		if depth >= maxRefDepth {
+			return nil, fmt.Errorf("$ref chain too deep at %s", ref)
+		}
+
+		target, err := d.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		object = target
+	}
+	return nil, nil
+}
+
+// lookup resolves a local JSON pointer such as #/components/schemas/Pet.
+func (d *document) lookup(ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("external $ref %s is not supported", ref)
+	}
+
+	var current any = d.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %s", ref)
+		}
+		current = object[part]
+	}
+
+	object, ok := current.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %s", ref)
+	}
+	return object, nil
+}
+
+// inlineSchema returns a copy of a schema with all $refs replaced by their targets,
+// so it can be forwarded to an LLM as a self-contained JSON Schema. Recursive
+// references are cut off as plain objects.
+func (d *document) inlineSchema(value any, expanding map[string]bool) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		object := typed
+		if ref := stringValue(typed, "$ref"); ref != "" {
+			target, err := d.lookup(ref)
+			if err != nil || expanding[ref] {
+				return map[string]any{"type": "object"}
+			}
+			expanding[ref] = true
+			defer delete(expanding, ref)
+			object = target
+		}
+
+		result := make(map[string]any, len(object))
+		for key, item := range object {
+			if isDocumentationKey(key) {
+				continue
+			}
+			if properties, ok := item.(map[string]any); ok && key == "properties" {
+				inlined := make(map[string]any, len(properties))
+				for name, property := range properties {
+					inlined[name] = d.inlineSchema(property, expanding)
+				}
+				result[key] = inlined
+				continue
+			}
+			result[key] = d.inlineSchema(item, expanding)
+		}
+		return result
+	case []any:
+		result := make([]any, len(typed))
+		for i, item := range typed {
+			result[i] = d.inlineSchema(item, expanding)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// isDocumentationKey reports whether a schema keyword only matters for documentation
+// or serialization and can be dropped from the schema sent to the LLM.
+func isDocumentationKey(key string) bool {
+	switch key {
+	case "example", "examples", "xml", "externalDocs", "discriminator", "readOnly", "deprecated":
+		return true
+	}
+	return strings.HasPrefix(key, "x-")
+}
+
+// stringValue returns the string value of a key, or "".
+func stringValue(object map[string]any, key string) string {
+	value, _ := object[key].(string)
+	return value
+}
+
+// objectValue returns the object value of a key, or nil.
+func objectValue(object map[string]any, key string) map[string]any {
+	value, _ := object[key].(map[string]any)
+	return value
+}
+
+// listValue returns the list value of a key, or nil.
+func listValue(object map[string]any, key string) []any {
+	value, _ := object[key].([]any)
+	return value
+}
@@ -0,0 +1,488 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// operationMethods are the OpenAPI operation keys of a path item, in output order.
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var (
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	camelBoundary   = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	semverPattern   = regexp.MustCompile(`^v?\d+\.\d+\.\d+`)
+	serverVariable  = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// Options configures manifest generation.
+type Options struct {
+	// Name of the generated tool, derived from info.title when empty
+	Name string
+	// BaseURL overrides the first server url of the spec
+	BaseURL string
+	// Author and License override the spec contact and license
+	Author  string
+	License string
+}
+
+// Result is a generated Tool manifest.
+type Result struct {
+	Tool *schema.Tool
+	// Secrets lists the secret names the auth schemes of the API read credentials from
+	Secrets []string
+	// Warnings lists the parts of the spec that could not be mapped
+	Warnings []string
+}
+
+// generator holds the state of a single manifest generation.
+type generator struct {
+	doc      *document
+	toolName string
+	auth     map[string]*authScheme
+	result   *Result
+}
+
+// authScheme describes how the credential of a security scheme is sent.
+type authScheme struct {
+	secret  string
+	headers map[string]string
+	query   map[string]string
+}
+
+// GenerateTool generates an http Tool manifest with one function per operation of
+// an OpenAPI 3 specification in JSON or YAML.
+func GenerateTool(content []byte, opts Options) (*Result, error) {
+	doc, err := parseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	info := objectValue(doc.root, "info")
+	title := stringValue(info, "title")
+
+	toolName := opts.Name
+	if toolName == "" {
+		toolName = slug(title, "-")
+		if len(toolName) > 63 {
+			toolName = strings.Trim(toolName[:63], "-")
+		}
+	}
+	if toolName == "" {
+		return nil, fmt.Errorf("cannot derive a tool name from the spec title, set a name explicitly")
+	}
+	if err := schema.ValidateName(toolName); err != nil {
+		return nil, err
+	}
+
+	entryPoint, err := serverURL(doc, opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		doc:      doc,
+		toolName: toolName,
+		auth:     make(map[string]*authScheme),
+		result:   &Result{},
+	}
+
+	version := stringValue(info, "version")
+	if !semverPattern.MatchString(version) {
+		g.warn("spec version %q is not a semantic version, using 1.0.0", version)
+		version = "1.0.0"
+	}
+
+	tool := schema.NewTool(toolName, strings.TrimPrefix(version, "v"))
+	tool.Metadata.Description = toolDescription(title, stringValue(info, "description"))
+	tool.Metadata.Author = firstNonEmpty(opts.Author, stringValue(objectValue(info, "contact"), "name"), "unknown")
+	tool.Metadata.License = firstNonEmpty(opts.License, stringValue(objectValue(info, "license"), "name"), "proprietary")
+	tool.Metadata.ForgeVersion = "1.0.0"
+	tool.Metadata.Tags = []string{"openapi"}
+	tool.Spec.Type = schema.ToolTypeHTTP
+	// The runtime is not used by http tools but is required by the schema
+	tool.Spec.Runtime = schema.RuntimeGo
+	tool.Spec.EntryPoint = entryPoint
+
+	g.collectSecuritySchemes()
+	tool.Spec.Functions = g.functions()
+	if len(tool.Spec.Functions) == 0 {
+		return nil, fmt.Errorf("spec does not define any operations")
+	}
+
+	sort.Strings(g.result.Secrets)
+	tool.Spec.Configuration.Secrets = g.result.Secrets
+	if err := tool.Validate(); err != nil {
+		return nil, fmt.Errorf("generated tool manifest is invalid: %w", err)
+	}
+	g.result.Tool = tool
+	return g.result, nil
+}
+
+// functions creates a function per operation, sorted by path and method.
+func (g *generator) functions() []schema.ToolFunction {
+	paths := objectValue(g.doc.root, "paths")
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	usedNames := make(map[string]bool)
+	usedSecrets := make(map[string]bool)
+	functions := make([]schema.ToolFunction, 0)
+	for _, path := range pathNames {
+		item, err := g.doc.resolve(paths[path])
+		if err != nil || item == nil {
+			g.warn("path %s: %v", path, err)
+			continue
+		}
+
+		for _, method := range operationMethods {
+			operation := objectValue(item, method)
+			if operation == nil {
+				continue
+			}
+
+			function, err := g.function(path, method, item, operation)
+			if err != nil {
+				g.warn("%s %s: %v", strings.ToUpper(method), path, err)
+				continue
+			}
+
+			baseName := function.Name
+			for i := 2; usedNames[function.Name]; i++ {
+				function.Name = fmt.Sprintf("%s_%d", baseName, i)
+			}
+			usedNames[function.Name] = true
+
+			for _, scheme := range g.operationAuth(operation) {
+				g.applyAuth(&function, scheme)
+				if !usedSecrets[scheme.secret] {
+					usedSecrets[scheme.secret] = true
+					g.result.Secrets = append(g.result.Secrets, scheme.secret)
+				}
+			}
+			functions = append(functions, function)
+		}
+	}
+	return functions
+}
+
+// function maps an operation to a tool function.
+func (g *generator) function(path, method string, item, operation map[string]any) (schema.ToolFunction, error) {
+	name := slug(camelBoundary.ReplaceAllString(stringValue(operation, "operationId"), "${1}_${2}"), "_")
+	if name == "" {
+		name = slug(method+"_"+path, "_")
+	}
+
+	description := firstNonEmpty(
+		stringValue(operation, "summary"),
+		stringValue(operation, "description"),
+		strings.ToUpper(method)+" "+path)
+
+	parameters, err := g.parameters(listValue(item, "parameters"), listValue(operation, "parameters"))
+	if err != nil {
+		return schema.ToolFunction{}, err
+	}
+
+	bodyParameters, err := g.bodyParameters(operation["requestBody"])
+	if err != nil {
+		return schema.ToolFunction{}, err
+	}
+	for _, param := range bodyParameters {
+		if slices.ContainsFunc(parameters, func(existing schema.ToolParameter) bool { return existing.Name == param.Name }) {
+			g.warn("%s %s: body property %s conflicts with a parameter and is skipped", strings.ToUpper(method), path, param.Name)
+			continue
+		}
+		parameters = append(parameters, param)
+	}
+
+	return schema.ToolFunction{
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Parameters:  parameters,
+		Request: &schema.ToolRequest{
+			Method: strings.ToUpper(method),
+			Path:   path,
+		},
+	}, nil
+}
+
+// parameters maps path item and operation parameters, operation parameters taking precedence.
+func (g *generator) parameters(itemParams, operationParams []any) ([]schema.ToolParameter, error) {
+	result := make([]schema.ToolParameter, 0, len(itemParams)+len(operationParams))
+	index := make(map[string]int)
+
+	for _, raw := range append(itemParams, operationParams...) {
+		param, err := g.doc.resolve(raw)
+		if err != nil {
+			return nil, err
+		}
+		if param == nil {
+			continue
+		}
+
+		name, in := stringValue(param, "name"), stringValue(param, "in")
+		if in == "cookie" {
+			g.warn("cookie parameter %s is not supported and is skipped", name)
+			continue
+		}
+
+		toolParam, err := g.toolParameter(name, param["schema"], stringValue(param, "description"))
+		if err != nil {
+			return nil, err
+		}
+		toolParam.In = in
+		toolParam.Required = in == "path" || param["required"] == true
+		if toolParam.Description == "" {
+			toolParam.Description = fmt.Sprintf("The %s %s parameter", name, in)
+		}
+
+		key := in + ":" + name
+		if i, exists := index[key]; exists {
+			result[i] = toolParam
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, toolParam)
+	}
+	return result, nil
+}
+
+// bodyParameters maps the properties of a JSON object request body to body parameters.
+func (g *generator) bodyParameters(raw any) ([]schema.ToolParameter, error) {
+	body, err := g.doc.resolve(raw)
+	if err != nil || body == nil {
+		return nil, err
+	}
+
+	mediaType := jsonMediaType(objectValue(body, "content"))
+	if mediaType == nil {
+		g.warn("request bodies without a JSON media type are not supported")
+		return nil, nil
+	}
+
+	bodySchema, err := g.objectSchema(mediaType["schema"])
+	if err != nil {
+		return nil, err
+	}
+	properties := objectValue(bodySchema, "properties")
+	if properties == nil {
+		g.warn("request bodies that are not JSON objects are not supported")
+		return nil, nil
+	}
+
+	bodyRequired := body["required"] == true
+	required := listValue(bodySchema, "required")
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]schema.ToolParameter, 0, len(names))
+	for _, name := range names {
+		param, err := g.toolParameter(name, properties[name], "")
+		if err != nil {
+			return nil, err
+		}
+		param.In = "body"
+		param.Required = bodyRequired && slices.Contains(required, any(name))
+		if param.Description == "" {
+			param.Description = fmt.Sprintf("The %s field of the request body", name)
+		}
+		result = append(result, param)
+	}
+	return result, nil
+}
+
+// objectSchema resolves a schema and merges the properties of allOf members.
+func (g *generator) objectSchema(raw any) (map[string]any, error) {
+	resolved, err := g.doc.resolve(raw)
+	if err != nil || resolved == nil {
+		return resolved, err
+	}
+
+	members := listValue(resolved, "allOf")
+	if len(members) == 0 {
+		return resolved, nil
+	}
+
+	properties := make(map[string]any)
+	required := append([]any{}, listValue(resolved, "required")...)
+	for name, property := range objectValue(resolved, "properties") {
+		properties[name] = property
+	}
+	for _, member := range members {
+		memberSchema, err := g.objectSchema(member)
+		if err != nil {
+			return nil, err
+		}
+		for name, property := range objectValue(memberSchema, "properties") {
+			properties[name] = property
+		}
+		required = append(required, listValue(memberSchema, "required")...)
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}, nil
+}
+
+// toolParameter maps a JSON Schema to a tool parameter. Objects and arrays keep their
+// full inlined schema so the LLM sees nested structure.
+func (g *generator) toolParameter(name string, raw any, description string) (schema.ToolParameter, error) {
+	paramSchema, err := g.doc.resolve(raw)
+	if err != nil {
+		return schema.ToolParameter{}, err
+	}
+
+	param := schema.ToolParameter{
+		Name:        name,
+		Type:        parameterType(paramSchema),
+		Description: strings.TrimSpace(firstNonEmpty(description, stringValue(paramSchema, "description"))),
+		Default:     paramSchema["default"],
+		Pattern:     stringValue(paramSchema, "pattern"),
+		MinLength:   intValue(paramSchema, "minLength"),
+		MaxLength:   intValue(paramSchema, "maxLength"),
+		Minimum:     floatValue(paramSchema, "minimum"),
+		Maximum:     floatValue(paramSchema, "maximum"),
+	}
+	for _, value := range listValue(paramSchema, "enum") {
+		if value != nil {
+			param.Enum = append(param.Enum, fmt.Sprint(value))
+		}
+	}
+	if param.Type == "object" || param.Type == "array" {
+		if inlined, ok := g.doc.inlineSchema(raw, map[string]bool{}).(map[string]any); ok {
+			param.Schema = inlined
+		}
+	}
+	return param, nil
+}
+
+// parameterType maps a JSON Schema type to a tool parameter type.
+func parameterType(paramSchema map[string]any) string {
+	switch stringValue(paramSchema, "type") {
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return "array"
+	case "object":
+		return "object"
+	case "string":
+		return "string"
+	}
+	if objectValue(paramSchema, "properties") != nil || listValue(paramSchema, "allOf") != nil {
+		return "object"
+	}
+	return "string"
+}
+
+// jsonMediaType returns the first JSON media type of a content map.
+func jsonMediaType(content map[string]any) map[string]any {
+	keys := make([]string, 0, len(content))
+	for key := range content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		mediaType := strings.ToLower(strings.TrimSpace(strings.Split(key, ";")[0]))
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || mediaType == "*/*" {
+			if value, ok := content[key].(map[string]any); ok {
+				return value
+			}
+		}
+	}
+	return nil
+}
+
+// serverURL returns the base url of the API with server variables set to their defaults.
+func serverURL(doc *document, baseURL string) (string, error) {
+	if baseURL == "" {
+		servers := listValue(doc.root, "servers")
+		if len(servers) > 0 {
+			if server, ok := servers[0].(map[string]any); ok {
+				variables := objectValue(server, "variables")
+				var missing string
+				baseURL = serverVariable.ReplaceAllStringFunc(stringValue(server, "url"), func(match string) string {
+					name := strings.Trim(match, "{}")
+					variable := objectValue(variables, name)
+					if variable == nil {
+						return match
+					}
+					value, exists := variable["default"]
+					if !exists || value == nil {
+						missing = name
+						return match
+					}
+					return fmt.Sprint(value)
+				})
+				if missing != "" {
+					return "", fmt.Errorf("server variable %s has no default, set a base url explicitly", missing)
+				}
+			}
+		}
+	}
+
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		return "", fmt.Errorf("spec declares no absolute server url (%q), set a base url explicitly", baseURL)
+	}
+	return strings.TrimRight(baseURL, "/"), nil
+}
+
+// toolDescription returns a manifest description within the schema length limits.
+func toolDescription(title, description string) string {
+	result := strings.Join(strings.Fields(firstNonEmpty(description, title)), " ")
+	if len(result) < 10 {
+		result = fmt.Sprintf("HTTP API generated from the %s OpenAPI spec", firstNonEmpty(title, "imported"))
+	}
+	if runes := []rune(result); len(runes) > 500 {
+		result = string(runes[:497]) + "..."
+	}
+	return result
+}
+
+// slug lowercases s and joins its alphanumeric runs with sep.
+func slug(s, sep string) string {
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(s), sep), sep)
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// intValue returns a numeric value of a key as int, or nil.
+func intValue(object map[string]any, key string) *int {
+	if value := floatValue(object, key); value != nil {
+		result := int(*value)
+		return &result
+	}
+	return nil
+}
+
+// floatValue returns a numeric value of a key as float64, or nil.
+func floatValue(object map[string]any, key string) *float64 {
+	var result float64
+	switch value := object[key].(type) {
+	case int:
+		result = float64(value)
+	case float64:
+		result = value
+	default:
+		return nil
+	}
+	return &result
+}
@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+const petstoreSpec = `
+openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.2.0
+  description: Manage the pets of the store.
+servers:
+  - url: https://{region}.pets.example.com/v1
+    variables:
+      region:
+        default: eu
+security:
+  - bearerAuth: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+        - name: session
+          in: cookie
+          schema:
+            type: string
+    post:
+      operationId: createPet
+      summary: Create a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get:
+      summary: Show a pet
+      security:
+        - apiKey: []
+    delete:
+      operationId: deletePet
+      security: []
+components:
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      description: The id of the pet
+      schema:
+        type: string
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+    apiKey:
+      type: apiKey
+      in: query
+      name: api_key
+  schemas:
+    NewPet:
+      allOf:
+        - $ref: '#/components/schemas/Named'
+        - type: object
+          required: [kind]
+          properties:
+            kind:
+              type: string
+              enum: [cat, dog]
+            owner:
+              $ref: '#/components/schemas/Owner'
+    Named:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          example: Rex
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+        pets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Owner'
+`
+
+func findFunction(t *testing.T, tool *schema.Tool, name string) schema.ToolFunction {
+	t.Helper()
+	for _, function := range tool.Spec.Functions {
+		if function.Name == name {
+			return function
+		}
+	}
+	require.Failf(t, "function not found", "function %s", name)
+	return schema.ToolFunction{}
+}
+
+func findParameter(t *testing.T, function schema.ToolFunction, name string) schema.ToolParameter {
+	t.Helper()
+	for _, param := range function.Parameters {
+		if param.Name == name {
+			return param
+		}
+	}
+	require.Failf(t, "parameter not found", "parameter %s", name)
+	return schema.ToolParameter{}
+}
+
+func TestGenerateTool_Petstore(t *testing.T) {
+	result, err := GenerateTool([]byte(petstoreSpec), Options{})
+	require.NoError(t, err)
+
+	tool := result.Tool
+	require.NoError(t, tool.Validate())
+	assert.Equal(t, "pet-store", tool.Metadata.Name)
+	assert.Equal(t, "1.2.0", tool.Metadata.Version)
+	assert.Equal(t, schema.ToolTypeHTTP, tool.Spec.Type)
+	assert.Equal(t, "https://eu.pets.example.com/v1", tool.Spec.EntryPoint)
+	assert.Equal(t, []string{"PET_STORE_API_KEY", "PET_STORE_BEARER_AUTH"}, tool.Spec.Configuration.Secrets)
+	assert.Equal(t, tool.Spec.Configuration.Secrets, result.Secrets)
+	require.Len(t, tool.Spec.Functions, 4)
+
+	list := findFunction(t, tool, "list_pets")
+	assert.Equal(t, "GET", list.Request.Method)
+	assert.Equal(t, "/pets", list.Request.Path)
+	assert.Equal(t, "Bearer ${PET_STORE_BEARER_AUTH}", list.Request.Headers["Authorization"])
+	require.Len(t, list.Parameters, 1)
+	limit := list.Parameters[0]
+	assert.Equal(t, "number", limit.Type)
+	assert.Equal(t, "query", limit.In)
+	assert.False(t, limit.Required)
+	require.NotNil(t, limit.Maximum)
+	assert.Equal(t, 100.0, *limit.Maximum)
+	assert.Contains(t, result.Warnings, "cookie parameter session is not supported and is skipped")
+
+	create := findFunction(t, tool, "create_pet")
+	assert.Equal(t, "POST", create.Request.Method)
+	assert.True(t, findParameter(t, create, "name").Required)
+	kind := findParameter(t, create, "kind")
+	assert.True(t, kind.Required)
+	assert.Equal(t, "body", kind.In)
+	assert.Equal(t, []string{"cat", "dog"}, kind.Enum)
+	owner := findParameter(t, create, "owner")
+	assert.False(t, owner.Required)
+	assert.Equal(t, "object", owner.Type)
+	require.NotNil(t, owner.Schema)
+	pets := owner.Schema["properties"].(map[string]any)["pets"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "object"}, pets["items"], "recursive refs are cut off")
+
+	show := findFunction(t, tool, "get_pets_petid")
+	assert.Equal(t, "Show a pet", show.Description)
+	assert.Equal(t, "${PET_STORE_API_KEY}", show.Request.Query["api_key"])
+	assert.Empty(t, show.Request.Headers)
+	petID := findParameter(t, show, "petId")
+	assert.True(t, petID.Required)
+	assert.Equal(t, "path", petID.In)
+	assert.Equal(t, "The id of the pet", petID.Description)
+
+	remove := findFunction(t, tool, "delete_pet")
+	assert.Equal(t, "DELETE", remove.Request.Method)
+	assert.Empty(t, remove.Request.Headers, "empty operation security disables auth")
+}
+
+func TestGenerateTool_Options(t *testing.T) {
+	result, err := GenerateTool([]byte(petstoreSpec), Options{
+		Name:    "pets",
+		BaseURL: "http://localhost:8080/",
+		Author:  "Jane Doe",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "pets", result.Tool.Metadata.Name)
+	assert.Equal(t, "http://localhost:8080", result.Tool.Spec.EntryPoint)
+	assert.Equal(t, "Jane Doe", result.Tool.Metadata.Author)
+	assert.Contains(t, result.Secrets, "PETS_BEARER_AUTH")
+}
+
+func TestGenerateTool_InvalidName(t *testing.T) {
+	for _, name := range []string{"../x", "My API"} {
+		_, err := GenerateTool([]byte(petstoreSpec), Options{Name: name})
+		assert.ErrorContains(t, err, "invalid component name", name)
+	}
+}
+
+func TestGenerateTool_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{
+			name: "swagger 2",
+			spec: "swagger: '2.0'\ninfo: {title: Old, version: 1.0.0}\n",
+			want: "swagger 2.0",
+		},
+		{
+			name: "relative server",
+			spec: "openapi: 3.0.0\ninfo: {title: Api, version: 1.0.0}\nservers: [{url: /v1}]\npaths: {}\n",
+			want: "base url",
+		},
+		{
+			name: "server variable without default",
+			spec: "openapi: 3.0.0\ninfo: {title: Api, version: 1.0.0}\nservers: [{url: 'https://{region}.example.com', variables: {region: {enum: [eu]}}}]\npaths: {}\n",
+			want: "server variable region has no default",
+		},
+		{
+			name: "no operations",
+			spec: "openapi: 3.0.0\ninfo: {title: Api, version: 1.0.0}\nservers: [{url: 'https://api.example.com'}]\npaths: {}\n",
+			want: "does not define any operations",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := GenerateTool([]byte(test.spec), Options{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.want)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package prompteval
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// Target is a model taking part in a comparison.
+type Target struct {
+	// Config identifies provider and model, used for naming and pricing
+	Config types.LLMConfig
+	// Model answers the prompt
+	Model llms.Model
+	// Ref names the target when the model could not be configured
+	Ref string
+	// Err is why the model could not be initialized, the target fails its
+	// result without a model call
+	Err error
+}
+
+// FailedTarget returns the target of a model reference that could not be
+// initialized. Its result reports err, the other targets still run.
+func FailedTarget(ref string, config types.LLMConfig, err error) Target {
+	return Target{Config: config, Ref: ref, Err: err}
+}
+
+// Name returns the target as provider:model.
+func (t Target) Name() string {
+	if t.Config == nil {
+		return t.Ref
+	}
+	return t.Config.GetProvider() + ":" + t.Config.GetModel()
+}
+
+// CompareOptions configures a comparison run.
+type CompareOptions struct {
+	// Timeout bounds each model call, defaults to two minutes
+	Timeout time.Duration
+	// CallOptions are passed to every model
+	CallOptions []llms.CallOption
+}
+
+// ComparisonResult is the answer of one model.
+type ComparisonResult struct {
+	Model     string        `json:"model"`
+	Output    string        `json:"output"`
+	Latency   time.Duration `json:"-"`
+	LatencyMS int64         `json:"latencyMs"`
+	Usage     llm.Usage     `json:"usage"`
+	// Cost is the estimated price in US dollars, nil for models without known pricing
+	Cost  *float64 `json:"estimatedCostUsd,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// Comparison holds the answers of several models to the same prompt.
+type Comparison struct {
+	Prompt  string             `json:"prompt"`
+	Input   string             `json:"input"`
+	Started time.Time          `json:"started"`
+	Results []ComparisonResult `json:"results"`
+}
+
+// Compare sends messages to all targets concurrently. Results keep the order of
+// targets, a failing model only fails its own result.
+func Compare(ctx context.Context, prompt string, messages []llms.MessageContent, targets []Target, opts CompareOptions) *Comparison {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultExampleTimeout
+	}
+
+	comparison := &Comparison{
+		Prompt:  prompt,
+		Input:   MessagesText(messages),
+		Started: time.Now(),
+		Results: make([]ComparisonResult, len(targets)),
+	}
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			comparison.Results[i] = RunTarget(ctx, messages, target, opts, nil)
+		}()
+	}
+	wg.Wait()
+	return comparison
+}
+
+// RunTarget sends messages to a single model. A non-nil onChunk receives the
+// output while the model streams it.
+func RunTarget(ctx context.Context, messages []llms.MessageContent, target Target, opts CompareOptions, onChunk func(chunk string)) ComparisonResult {
+	result := ComparisonResult{Model: target.Name()}
+	if target.Err != nil {
+		result.Error = target.Err.Error()
+		return result
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultExampleTimeout
+	}
+
+	callOpts := opts.CallOptions
+	if onChunk != nil {
+		callOpts = append(slices.Clone(callOpts), llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			onChunk(string(chunk))
+			return nil
+		}))
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	response, err := target.Model.GenerateContent(callCtx, messages, callOpts...)
+	result.Latency = time.Since(start)
+	result.LatencyMS = result.Latency.Milliseconds()
+	if err != nil {
+		result.Error = fmt.Sprintf("model call failed: %v", err)
+		return result
+	}
+	if len(response.Choices) == 0 {
+		result.Error = "model returned no choices"
+		return result
+	}
+
+	result.Output = response.Choices[0].Content
+	result.Usage = llm.ResponseUsage(response)
+	if cost, ok := llm.EstimateCost(target.Config.GetProvider(), target.Config.GetModel(), result.Usage); ok {
+		result.Cost = &cost
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package prompteval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+)
+
+func newTarget(provider, model string, fake *fakeModel) Target {
+	return Target{Config: llm.NewLLMConfig(provider, model, 0.7, 0, nil), Model: fake}
+}
+
+func TestCompareKeepsTargetOrderAndIsolatesFailures(t *testing.T) {
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Capital of France?")}
+	targets := []Target{
+		newTarget("openai", "gpt-4o-mini", &fakeModel{responses: []string{"Paris"}}),
+		newTarget("anthropic", "claude-3-5-haiku-latest", &fakeModel{errs: []error{errors.New("overloaded")}}),
+		newTarget("openai", "my-finetune", &fakeModel{responses: []string{"It is Paris"}}),
+	}
+
+	comparison := Compare(context.Background(), "capitals", messages, targets, CompareOptions{})
+	require.Len(t, comparison.Results, 3)
+
+	first := comparison.Results[0]
+	assert.Equal(t, "openai:gpt-4o-mini", first.Model)
+	assert.Equal(t, "Paris", first.Output)
+	assert.Equal(t, llm.Usage{InputTokens: 10, OutputTokens: 1}, first.Usage)
+	require.NotNil(t, first.Cost)
+	assert.InDelta(t, (10*0.15+1*0.60)/1e6, *first.Cost, 1e-12)
+
+	assert.Equal(t, "anthropic:claude-3-5-haiku-latest", comparison.Results[1].Model)
+	assert.Contains(t, comparison.Results[1].Error, "overloaded")
+	assert.Nil(t, comparison.Results[1].Cost)
+
+	assert.Equal(t, "It is Paris", comparison.Results[2].Output)
+	assert.Nil(t, comparison.Results[2].Cost)
+	assert.Equal(t, "human: Capital of France?", comparison.Input)
+}
+
+func TestCompareReportsFailedTargets(t *testing.T) {
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Capital of France?")}
+	targets := []Target{
+		FailedTarget("anthropic:claude-3-5-haiku-latest", llm.NewLLMConfig("anthropic", "claude-3-5-haiku-latest", 0.7, 0, nil),
+			errors.New("ANTHROPIC_API_KEY is not set")),
+		FailedTarget("unknown-model", nil, errors.New("cannot infer the provider")),
+		newTarget("openai", "gpt-4o-mini", &fakeModel{responses: []string{"Paris"}}),
+	}
+
+	comparison := Compare(context.Background(), "capitals", messages, targets, CompareOptions{})
+	require.Len(t, comparison.Results, 3)
+	assert.Equal(t, "anthropic:claude-3-5-haiku-latest", comparison.Results[0].Model)
+	assert.Equal(t, "ANTHROPIC_API_KEY is not set", comparison.Results[0].Error)
+	assert.Equal(t, "unknown-model", comparison.Results[1].Model)
+	assert.Equal(t, "cannot infer the provider", comparison.Results[1].Error)
+	assert.Equal(t, "Paris", comparison.Results[2].Output)
+}
+
+func TestRunTargetStreamsChunks(t *testing.T) {
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Greet")}
+	target := newTarget("ollama", "llama3.2", &fakeModel{responses: []string{"Hello there friend"}})
+
+	var chunks []string
+	result := RunTarget(context.Background(), messages, target, CompareOptions{}, func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+
+	assert.Equal(t, []string{"Hello ", "there ", "friend"}, chunks)
+	assert.Equal(t, "Hello there friend", result.Output)
+	require.NotNil(t, result.Cost)
+	assert.Zero(t, *result.Cost)
+}
+
+func TestWriteComparison(t *testing.T) {
+	cost := 0.0012
+	comparison := &Comparison{
+		Prompt: "capitals",
+		Input:  "human: Capital of France?",
+		Results: []ComparisonResult{
+			{Model: "openai:gpt-4o", Output: "Paris", LatencyMS: 120, Usage: llm.Usage{InputTokens: 10, OutputTokens: 2}, Cost: &cost},
+			{Model: "ollama:llama3", Error: "connection refused"},
+		},
+	}
+
+	var markdown bytes.Buffer
+	require.NoError(t, WriteComparison(&markdown, comparison, FormatMarkdown))
+	assert.Contains(t, markdown.String(), "| openai:gpt-4o | ok |")
+	assert.Contains(t, markdown.String(), "$0.001200")
+	assert.Contains(t, markdown.String(), "| ollama:llama3 | failed |")
+	assert.Contains(t, markdown.String(), "> Error: connection refused")
+
+	var encoded bytes.Buffer
+	require.NoError(t, WriteComparison(&encoded, comparison, FormatJSON))
+	var decoded Comparison
+	require.NoError(t, json.Unmarshal(encoded.Bytes(), &decoded))
+	assert.Equal(t, comparison.Results[0].Model, decoded.Results[0].Model)
+	assert.Equal(t, cost, *decoded.Results[0].Cost)
+
+	assert.Error(t, WriteComparison(&encoded, comparison, "csv"))
+}
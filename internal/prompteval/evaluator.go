@@ -0,0 +1,278 @@
+package prompteval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/logger"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+var log = logger.WithPackage("prompteval")
+
+// defaultExampleTimeout bounds a single model call when Options.Timeout is unset.
+const defaultExampleTimeout = 2 * time.Minute
+
+// Options configures an evaluation run.
+type Options struct {
+	// Model answers the rendered examples
+	Model llms.Model
+	// ModelName is reported and written to the metrics, e.g. openai:gpt-4o
+	ModelName string
+	// Scorers are the scorer names for examples that do not list their own, defaults to contains
+	Scorers []string
+	// ScorerOptions configures the scorers
+	ScorerOptions ScorerOptions
+	// Timeout bounds each model call
+	Timeout time.Duration
+	// Temperature overrides the temperature recommended by the prompt
+	Temperature *float64
+	// MaxTokens overrides the max tokens recommended by the prompt
+	MaxTokens int
+}
+
+// ExampleResult is the outcome of evaluating one example.
+type ExampleResult struct {
+	Name      string        `json:"name"`
+	Output    string        `json:"output"`
+	Scores    []Score       `json:"scores"`
+	Passed    bool          `json:"passed"`
+	Latency   time.Duration `json:"-"`
+	LatencyMS int64         `json:"latencyMs"`
+	Usage     llm.Usage     `json:"usage"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Report summarizes an evaluation run.
+type Report struct {
+	Prompt           string          `json:"prompt"`
+	Version          string          `json:"version"`
+	Model            string          `json:"model"`
+	Results          []ExampleResult `json:"results"`
+	Passed           int             `json:"passed"`
+	Total            int             `json:"total"`
+	SuccessRate      float64         `json:"successRate"`
+	AverageLatency   time.Duration   `json:"-"`
+	AverageLatencyMS int64           `json:"averageLatencyMs"`
+	AverageTokens    int             `json:"averageTokens"`
+	Usage            llm.Usage       `json:"usage"`
+}
+
+// Evaluate renders every example of prompt, sends it to the model and scores the output.
+// Model errors fail the example, errors in the setup fail the run.
+func Evaluate(ctx context.Context, prompt *schema.Prompt, opts Options) (*Report, error) {
+	if opts.Model == nil {
+		return nil, fmt.Errorf("a model is required to evaluate prompt %s", prompt.Metadata.Name)
+	}
+	if len(prompt.Spec.Examples) == 0 {
+		return nil, fmt.Errorf("prompt %s has no examples to evaluate", prompt.Metadata.Name)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultExampleTimeout
+	}
+	if len(opts.Scorers) == 0 {
+		opts.Scorers = []string{ScorerContains}
+	}
+
+	scorers, err := newScorerSet(prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Prompt:  prompt.Metadata.Name,
+		Version: prompt.Metadata.Version,
+		Model:   opts.ModelName,
+		Total:   len(prompt.Spec.Examples),
+		Results: make([]ExampleResult, 0, len(prompt.Spec.Examples)),
+	}
+
+	var latency time.Duration
+	answered := 0
+	for _, example := range prompt.Spec.Examples {
+		result := evaluateExample(ctx, prompt, example, scorers, opts)
+		log.Debug("Evaluated example",
+			zap.String("prompt", prompt.Metadata.Name),
+			zap.String("example", example.Name),
+			zap.Bool("passed", result.Passed),
+			zap.Duration("latency", result.Latency))
+
+		if result.Passed {
+			report.Passed++
+		}
+		if result.Error == "" {
+			answered++
+			latency += result.Latency
+			report.Usage = report.Usage.Add(result.Usage)
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	report.SuccessRate = math.Round(float64(report.Passed)/float64(report.Total)*1000) / 1000
+	if answered > 0 {
+		report.AverageLatency = latency / time.Duration(answered)
+		report.AverageLatencyMS = report.AverageLatency.Milliseconds()
+		report.AverageTokens = report.Usage.Total() / answered
+	}
+	return report, nil
+}
+
+// Score returns the mean score of the examples in 0..1. An example scores the
+// mean value of its scorers that were not skipped, failed model calls score 0.
+func (r *Report) Score() float64 {
+	if len(r.Results) == 0 {
+		return 0
+	}
+	var total float64
+	for _, result := range r.Results {
+		total += result.score()
+	}
+	return math.Round(total/float64(len(r.Results))*1000) / 1000
+}
+
+// score returns the score of one example.
+func (r ExampleResult) score() float64 {
+	if r.Error != "" {
+		return 0
+	}
+	var (
+		total  float64
+		scored int
+	)
+	for _, score := range r.Scores {
+		if !score.Skipped {
+			total += score.Value
+			scored++
+		}
+	}
+	if scored == 0 {
+		if r.Passed {
+			return 1
+		}
+		return 0
+	}
+	return total / float64(scored)
+}
+
+// evaluateExample answers and scores a single example.
+func evaluateExample(ctx context.Context, prompt *schema.Prompt, example schema.PromptExample, scorers map[string]Scorer, opts Options) ExampleResult {
+	result := ExampleResult{Name: example.Name, Scores: make([]Score, 0)}
+
+	messages, err := RenderMessages(prompt, example)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	response, err := opts.Model.GenerateContent(callCtx, messages, CallOptions(prompt, opts)...)
+	result.Latency = time.Since(start)
+	result.LatencyMS = result.Latency.Milliseconds()
+	if err != nil {
+		result.Error = fmt.Sprintf("model call failed: %v", err)
+		return result
+	}
+	if len(response.Choices) == 0 {
+		result.Error = "model returned no choices"
+		return result
+	}
+	result.Output = response.Choices[0].Content
+	result.Usage = llm.ResponseUsage(response)
+
+	c := Case{Example: example, Input: MessagesText(messages)}
+	result.Passed = true
+	for _, name := range exampleScorers(example, opts) {
+		score, err := scorers[name].Score(ctx, c, result.Output)
+		if err != nil {
+			score = Score{Scorer: name, Reason: err.Error()}
+		}
+		result.Passed = result.Passed && score.Passed
+		result.Scores = append(result.Scores, score)
+	}
+	return result
+}
+
+// CallOptions returns the model options from the prompt recommendations and the
+// temperature and max tokens overrides of opts.
+func CallOptions(prompt *schema.Prompt, opts Options) []llms.CallOption {
+	callOpts := make([]llms.CallOption, 0, 3)
+
+	temperature := prompt.Spec.Temperature
+	if opts.Temperature != nil {
+		temperature = opts.Temperature
+	}
+	if temperature != nil {
+		callOpts = append(callOpts, llms.WithTemperature(*temperature))
+	}
+
+	maxTokens := opts.MaxTokens
+	if maxTokens == 0 && prompt.Spec.MaxTokens != nil {
+		maxTokens = *prompt.Spec.MaxTokens
+	}
+	if maxTokens > 0 {
+		callOpts = append(callOpts, llms.WithMaxTokens(maxTokens))
+	}
+
+	if len(prompt.Spec.StopSequences) > 0 {
+		callOpts = append(callOpts, llms.WithStopWords(prompt.Spec.StopSequences))
+	}
+	return callOpts
+}
+
+// exampleScorers returns the scorer names for an example.
+func exampleScorers(example schema.PromptExample, opts Options) []string {
+	if len(example.Scorers) > 0 {
+		return example.Scorers
+	}
+	return opts.Scorers
+}
+
+// newScorerSet creates every scorer used by the run, so unknown scorers fail early.
+func newScorerSet(prompt *schema.Prompt, opts Options) (map[string]Scorer, error) {
+	scorers := make(map[string]Scorer)
+	for _, example := range prompt.Spec.Examples {
+		for _, name := range exampleScorers(example, opts) {
+			if _, exists := scorers[name]; exists {
+				continue
+			}
+			scorer, err := NewScorer(name, opts.ScorerOptions)
+			if err != nil {
+				return nil, err
+			}
+			scorers[name] = scorer
+		}
+	}
+	return scorers, nil
+}
+
+// ApplyMetrics writes the measured success rate, latency and tokens into the prompt
+// metrics. A model that passes all examples is added to the recommended models.
+func ApplyMetrics(prompt *schema.Prompt, report *Report) {
+	if prompt.Spec.Metrics == nil {
+		prompt.Spec.Metrics = &schema.PromptMetrics{}
+	}
+	metrics := prompt.Spec.Metrics
+
+	successRate := report.SuccessRate
+	metrics.SuccessRate = &successRate
+	latency := report.AverageLatency.Round(time.Millisecond).String()
+	metrics.AverageLatency = &latency
+	if report.AverageTokens > 0 {
+		tokens := report.AverageTokens
+		metrics.AverageTokens = &tokens
+	}
+
+	if report.Model != "" && report.Passed == report.Total && !slices.Contains(metrics.RecommendedModel, report.Model) {
+		metrics.RecommendedModel = append(metrics.RecommendedModel, report.Model)
+	}
+}
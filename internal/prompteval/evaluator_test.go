@@ -0,0 +1,164 @@
+package prompteval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// fakeModel answers with the queued responses and records the prompts it received.
+type fakeModel struct {
+	responses []string
+	errs      []error
+	prompts   []string
+	options   []llms.CallOptions
+	mutex     sync.Mutex
+}
+
+func (m *fakeModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var opts llms.CallOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	m.options = append(m.options, opts)
+	m.prompts = append(m.prompts, MessagesText(messages))
+
+	index := len(m.prompts) - 1
+	if index < len(m.errs) && m.errs[index] != nil {
+		return nil, m.errs[index]
+	}
+	response := ""
+	if index < len(m.responses) {
+		response = m.responses[index]
+	}
+	if opts.StreamingFunc != nil {
+		for _, word := range strings.SplitAfter(response, " ") {
+			if err := opts.StreamingFunc(ctx, []byte(word)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content:        response,
+		GenerationInfo: map[string]any{"PromptTokens": 10, "CompletionTokens": len(strings.Fields(response))},
+	}}}, nil
+}
+
+func (m *fakeModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// newEvalPrompt creates a template prompt with a city variable and examples.
+func newEvalPrompt(examples ...schema.PromptExample) *schema.Prompt {
+	temperature := 0.2
+	prompt := schema.NewPrompt("capitals", "1.0.0")
+	prompt.Spec.Type = schema.PromptTypeTemplate
+	prompt.Spec.Format = schema.FormatText
+	prompt.Spec.Template = "What is the capital of {{.country}}?"
+	prompt.Spec.Temperature = &temperature
+	prompt.Spec.Variables = []schema.PromptVariable{
+		{Name: "country", Type: "string", Description: "Country", Required: true},
+	}
+	prompt.Spec.Examples = examples
+	return prompt
+}
+
+func TestEvaluate(t *testing.T) {
+	prompt := newEvalPrompt(
+		schema.PromptExample{Name: "france", Variables: map[string]any{"country": "France"}, Expected: "Paris"},
+		schema.PromptExample{Name: "germany", Variables: map[string]any{"country": "Germany"}, Expected: "Berlin", Context: "Answer in one word."},
+		schema.PromptExample{Name: "spain", Variables: map[string]any{"country": "Spain"}, Expected: `^Madrid$`, Scorers: []string{ScorerRegex}},
+		schema.PromptExample{Name: "italy", Variables: map[string]any{"country": "Italy"}, Expected: "Rome"},
+	)
+	model := &fakeModel{
+		responses: []string{"The capital is Paris.", "Munich", "Madrid"},
+		errs:      []error{nil, nil, nil, errors.New("rate limited")},
+	}
+
+	report, err := Evaluate(context.Background(), prompt, Options{Model: model, ModelName: "test:model", Timeout: time.Second})
+	require.NoError(t, err)
+
+	assert.Equal(t, "capitals", report.Prompt)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.Passed)
+	assert.Equal(t, 0.5, report.SuccessRate)
+	require.Len(t, report.Results, 4)
+
+	assert.True(t, report.Results[0].Passed)
+	assert.Equal(t, ScorerContains, report.Results[0].Scores[0].Scorer)
+	assert.False(t, report.Results[1].Passed)
+	assert.True(t, report.Results[2].Passed)
+	assert.Equal(t, ScorerRegex, report.Results[2].Scores[0].Scorer)
+	assert.False(t, report.Results[3].Passed)
+	assert.Contains(t, report.Results[3].Error, "rate limited")
+
+	assert.Equal(t, 30, report.Usage.InputTokens)
+	assert.Equal(t, (30+6)/3, report.AverageTokens)
+
+	assert.Contains(t, model.prompts[0], "What is the capital of France?")
+	assert.Contains(t, model.prompts[1], "system: Answer in one word.")
+	assert.Equal(t, 0.2, model.options[0].Temperature)
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	model := &fakeModel{}
+
+	_, err := Evaluate(context.Background(), newEvalPrompt(), Options{Model: model})
+	assert.ErrorContains(t, err, "no examples")
+
+	prompt := newEvalPrompt(schema.PromptExample{Name: "x", Variables: map[string]any{"country": "x"}})
+	_, err = Evaluate(context.Background(), prompt, Options{Model: model, Scorers: []string{"fuzzy"}})
+	assert.ErrorContains(t, err, "unknown scorer")
+
+	_, err = Evaluate(context.Background(), prompt, Options{})
+	assert.ErrorContains(t, err, "model is required")
+
+	report, err := Evaluate(context.Background(), newEvalPrompt(schema.PromptExample{Name: "missing"}), Options{Model: model})
+	require.NoError(t, err)
+	assert.Contains(t, report.Results[0].Error, "required variable country")
+}
+
+func TestRenderMessages_Conversation(t *testing.T) {
+	prompt := schema.NewPrompt("chat", "1.0.0")
+	prompt.Spec.Type = schema.PromptTypeConversation
+	prompt.Spec.Messages = []schema.PromptMessage{
+		{Role: "system", Content: "You are {{.persona}}."},
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "Hi!"},
+	}
+	prompt.Spec.Variables = []schema.PromptVariable{{Name: "persona", Type: "string", Description: "Persona", Default: "helpful"}}
+
+	messages, err := RenderMessages(prompt, schema.PromptExample{Name: "default"})
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.Equal(t, llms.ChatMessageTypeSystem, messages[0].Role)
+	assert.Equal(t, llms.ChatMessageTypeAI, messages[2].Role)
+	assert.Equal(t, "system: You are helpful.\n\nhuman: Hello\n\nai: Hi!", MessagesText(messages))
+}
+
+func TestApplyMetrics(t *testing.T) {
+	prompt := newEvalPrompt()
+	report := &Report{Model: "openai:gpt-4o", Passed: 2, Total: 2, SuccessRate: 1, AverageLatency: 1234567 * time.Microsecond, AverageTokens: 42}
+
+	ApplyMetrics(prompt, report)
+	ApplyMetrics(prompt, report)
+
+	metrics := prompt.Spec.Metrics
+	require.NotNil(t, metrics)
+	assert.Equal(t, 1.0, *metrics.SuccessRate)
+	assert.Equal(t, "1.235s", *metrics.AverageLatency)
+	assert.Equal(t, 42, *metrics.AverageTokens)
+	assert.Equal(t, []string{"openai:gpt-4o"}, metrics.RecommendedModel)
+}
@@ -0,0 +1,78 @@
+package prompteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Export formats of a comparison.
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// WriteComparison writes comparison as JSON or Markdown.
+func WriteComparison(w io.Writer, comparison *Comparison, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(comparison); err != nil {
+			return fmt.Errorf("failed to encode comparison: %w", err)
+		}
+		return nil
+	case FormatMarkdown, "md":
+		if _, err := io.WriteString(w, ComparisonMarkdown(comparison)); err != nil {
+			return fmt.Errorf("failed to write comparison: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q, use %s or %s", format, FormatJSON, FormatMarkdown)
+	}
+}
+
+// ComparisonMarkdown renders comparison as a summary table followed by the
+// output of each model.
+func ComparisonMarkdown(comparison *Comparison) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Model comparison: %s\n\n", comparison.Prompt)
+	fmt.Fprintf(&builder, "Run at %s\n\n", comparison.Started.Format(time.RFC3339))
+
+	builder.WriteString("| Model | Status | Latency | Input tokens | Output tokens | Est. cost |\n")
+	builder.WriteString("|-------|--------|---------|--------------|---------------|-----------|\n")
+	for _, result := range comparison.Results {
+		status := "ok"
+		if result.Error != "" {
+			status = "failed"
+		}
+		fmt.Fprintf(&builder, "| %s | %s | %s | %d | %d | %s |\n",
+			result.Model, status, result.Latency.Round(time.Millisecond),
+			result.Usage.InputTokens, result.Usage.OutputTokens, FormatCost(result.Cost))
+	}
+
+	builder.WriteString("\n## Input\n\n```\n")
+	builder.WriteString(comparison.Input)
+	builder.WriteString("\n```\n")
+
+	for _, result := range comparison.Results {
+		fmt.Fprintf(&builder, "\n## %s\n\n", result.Model)
+		if result.Error != "" {
+			fmt.Fprintf(&builder, "> Error: %s\n", result.Error)
+			continue
+		}
+		builder.WriteString(strings.TrimSpace(result.Output))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// FormatCost formats an estimated cost in US dollars, "n/a" when unknown.
+func FormatCost(cost *float64) string {
+	if cost == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("$%.6f", *cost)
+}
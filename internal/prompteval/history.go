@@ -0,0 +1,128 @@
+package prompteval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// defaultHistoryLimit is the number of runs kept per prompt.
+const defaultHistoryLimit = 50
+
+// unsafeFileChars matches characters not allowed in history file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Run is a recorded test run of a prompt.
+type Run struct {
+	ID        int               `json:"id"`
+	Started   time.Time         `json:"started"`
+	Input     string            `json:"input"`
+	Variables map[string]string `json:"variables,omitempty"`
+	ComparisonResult
+}
+
+// RunHistory stores the test runs of each prompt as a JSON file in a directory.
+type RunHistory struct {
+	dir   string
+	limit int
+	mutex sync.Mutex
+}
+
+// NewRunHistory creates a history stored in dir, keeping the latest 50 runs per prompt.
+func NewRunHistory(dir string) *RunHistory {
+	return &RunHistory{dir: dir, limit: defaultHistoryLimit}
+}
+
+// Runs returns the recorded runs of prompt, oldest first.
+func (h *RunHistory) Runs(prompt string) ([]Run, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.load(prompt)
+}
+
+// Append records run for prompt and returns it with its assigned ID. The
+// oldest runs are dropped beyond the history limit.
+func (h *RunHistory) Append(prompt string, run Run) (Run, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	runs, err := h.load(prompt)
+	if err != nil {
+		return run, err
+	}
+	run.ID = 1
+	if len(runs) > 0 {
+		run.ID = runs[len(runs)-1].ID + 1
+	}
+	runs = append(runs, run)
+	if len(runs) > h.limit {
+		runs = runs[len(runs)-h.limit:]
+	}
+
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return run, fmt.Errorf("failed to encode run history: %w", err)
+	}
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return run, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := os.WriteFile(h.path(prompt), data, 0644); err != nil {
+		return run, fmt.Errorf("failed to write run history: %w", err)
+	}
+	return run, nil
+}
+
+// load reads the runs of prompt, an absent file is an empty history.
+func (h *RunHistory) load(prompt string) ([]Run, error) {
+	data, err := os.ReadFile(h.path(prompt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run history: %w", err)
+	}
+
+	var runs []Run
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse run history of %s: %w", prompt, err)
+	}
+	return runs, nil
+}
+
+// path returns the history file of prompt.
+func (h *RunHistory) path(prompt string) string {
+	return filepath.Join(h.dir, unsafeFileChars.ReplaceAllString(prompt, "_")+".json")
+}
+
+// DiffRuns returns a unified diff from the input and output of run a to run b.
+func DiffRuns(a, b Run) string {
+	var diff string
+	sections := []struct{ name, from, to string }{
+		{"input", a.Input, b.Input},
+		{"output", a.Output, b.Output},
+	}
+	for _, section := range sections {
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(section.from + "\n"),
+			B:        difflib.SplitLines(section.to + "\n"),
+			FromFile: fmt.Sprintf("run #%d %s (%s)", a.ID, section.name, a.Model),
+			ToFile:   fmt.Sprintf("run #%d %s (%s)", b.ID, section.name, b.Model),
+			Context:  3,
+		})
+		if err != nil {
+			continue
+		}
+		diff += text
+	}
+	if diff == "" {
+		return fmt.Sprintf("Runs #%d and #%d have the same input and output\n", a.ID, b.ID)
+	}
+	return diff
+}
@@ -0,0 +1,47 @@
+package prompteval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHistoryAppendAndLimit(t *testing.T) {
+	history := NewRunHistory(t.TempDir())
+	history.limit = 2
+
+	runs, err := history.Runs("code/review")
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	for _, output := range []string{"first", "second", "third"} {
+		_, err := history.Append("code/review", Run{Input: "in", ComparisonResult: ComparisonResult{Model: "openai:gpt-4o", Output: output}})
+		require.NoError(t, err)
+	}
+
+	runs, err = history.Runs("code/review")
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, 2, runs[0].ID)
+	assert.Equal(t, "second", runs[0].Output)
+	assert.Equal(t, 3, runs[1].ID)
+	assert.Equal(t, "openai:gpt-4o", runs[1].Model)
+
+	other, err := history.Runs("other")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestDiffRuns(t *testing.T) {
+	a := Run{ID: 1, Input: "Say hi", ComparisonResult: ComparisonResult{Model: "openai:gpt-4o", Output: "Hello\nWorld"}}
+	b := Run{ID: 2, Input: "Say hi", ComparisonResult: ComparisonResult{Model: "ollama:llama3", Output: "Hello\nThere"}}
+
+	diff := DiffRuns(a, b)
+	assert.Contains(t, diff, "run #1 output (openai:gpt-4o)")
+	assert.Contains(t, diff, "-World")
+	assert.Contains(t, diff, "+There")
+	assert.NotContains(t, diff, "run #1 input")
+
+	assert.Contains(t, DiffRuns(a, a), "same input and output")
+}
@@ -0,0 +1,77 @@
+package prompteval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// judgePrompt asks the judge model to grade an output against the example criteria.
+const judgePrompt = `You are grading the answer of an AI assistant.
+
+Question:
+%s
+
+Criteria the answer must meet:
+%s
+
+Answer:
+%s
+
+Rate how well the answer meets the criteria with a score between 0 and 1.
+Respond only with JSON of the form {"score": 0.8, "reason": "one sentence"}.`
+
+// defaultJudgeCriteria is used for examples without an expected output.
+const defaultJudgeCriteria = "The answer is correct, complete and directly addresses the question."
+
+// judgeScorer grades outputs with a model (LLM-as-judge). The expected output of
+// the example is used as grading criteria.
+type judgeScorer struct {
+	model     llms.Model
+	threshold float64
+}
+
+// newJudgeScorer creates the LLM-as-judge scorer.
+func newJudgeScorer(opts ScorerOptions) (Scorer, error) {
+	if opts.Judge == nil {
+		return nil, fmt.Errorf("the %s scorer needs a judge model", ScorerJudge)
+	}
+	return &judgeScorer{model: opts.Judge, threshold: opts.Threshold}, nil
+}
+
+func (s *judgeScorer) Name() string { return ScorerJudge }
+
+func (s *judgeScorer) Score(ctx context.Context, c Case, output string) (Score, error) {
+	criteria := strings.TrimSpace(c.Example.Expected)
+	if criteria == "" {
+		criteria = defaultJudgeCriteria
+	}
+
+	response, err := llms.GenerateFromSinglePrompt(ctx, s.model,
+		fmt.Sprintf(judgePrompt, c.Input, criteria, output),
+		llms.WithTemperature(0))
+	if err != nil {
+		return Score{}, fmt.Errorf("judge model failed: %w", err)
+	}
+
+	var verdict struct {
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(ExtractJSON(response)), &verdict); err != nil {
+		return Score{}, fmt.Errorf("judge returned an invalid verdict %q: %w", response, err)
+	}
+	if verdict.Score < 0 || verdict.Score > 1 {
+		return Score{}, fmt.Errorf("judge returned score %v outside of 0..1", verdict.Score)
+	}
+
+	return Score{
+		Scorer: ScorerJudge,
+		Value:  verdict.Score,
+		Passed: verdict.Score >= s.threshold,
+		Reason: verdict.Reason,
+	}, nil
+}
@@ -0,0 +1,334 @@
+package prompteval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/tmc/langchaingo/llms"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// Optimization defaults.
+const (
+	defaultIterations = 3
+	defaultCandidates = 2
+	// criteriaExample names the example created for prompts without examples
+	criteriaExample = "criteria"
+	// maxFeedbackOutput bounds the model output quoted in the meta-prompt
+	maxFeedbackOutput = 400
+)
+
+// metaPrompt asks the optimizer model for revised templates.
+const metaPrompt = `You are an expert prompt engineer. Improve the prompt template below so that
+a model answering it better meets the desired outcome.
+
+Desired outcome:
+%s
+
+Current template (score %.2f of 1):
+<template>
+%s
+</template>
+
+Evaluation of the current template:
+%s
+
+Rules:
+- Keep the template variables %s exactly as written and do not add new ones.
+- Return %d different improved templates, each a complete replacement.
+
+Respond only with JSON of the form {"candidates": [{"template": "...", "rationale": "one sentence"}]}.`
+
+// OptimizeOptions configures an optimization run.
+type OptimizeOptions struct {
+	// Optimizer proposes revisions, defaults to the evaluated model
+	Optimizer llms.Model
+	// Criteria describe the desired outcome, required for prompts without examples
+	Criteria string
+	// Iterations is the number of revision rounds, defaults to 3
+	Iterations int
+	// Candidates is the number of revisions proposed per round, defaults to 2
+	Candidates int
+	// Eval configures how candidates are evaluated
+	Eval Options
+	// Progress is called for every evaluated candidate
+	Progress func(candidate Candidate)
+}
+
+// Candidate is a template proposed and evaluated during optimization.
+type Candidate struct {
+	Iteration int     `json:"iteration"`
+	Template  string  `json:"template"`
+	Rationale string  `json:"rationale,omitempty"`
+	Score     float64 `json:"score"`
+	Report    *Report `json:"report,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Trail records every candidate of an optimization run.
+type Trail struct {
+	Prompt     string      `json:"prompt"`
+	Version    string      `json:"version"`
+	Model      string      `json:"model"`
+	Criteria   string      `json:"criteria,omitempty"`
+	Started    time.Time   `json:"started"`
+	Finished   time.Time   `json:"finished"`
+	Baseline   Candidate   `json:"baseline"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// Best returns up to n candidates scoring higher than the baseline, best first.
+func (t *Trail) Best(n int) []Candidate {
+	best := make([]Candidate, 0, len(t.Candidates))
+	for _, candidate := range t.Candidates {
+		if candidate.Error == "" && candidate.Score > t.Baseline.Score {
+			best = append(best, candidate)
+		}
+	}
+	sort.SliceStable(best, func(i, j int) bool { return best[i].Score > best[j].Score })
+	if len(best) > n {
+		best = best[:n]
+	}
+	return best
+}
+
+// Optimize improves the template of prompt. Each round the optimizer model
+// revises the best template so far, and every revision is scored against the
+// prompt examples, or by the judge against the criteria when there are none.
+func Optimize(ctx context.Context, prompt *schema.Prompt, opts OptimizeOptions) (*Trail, error) {
+	if len(prompt.Spec.Messages) > 0 {
+		return nil, fmt.Errorf("optimizing conversation prompts is not supported, %s has messages", prompt.Metadata.Name)
+	}
+	if len(prompt.Spec.Dependencies) > 0 || prompt.Spec.Extends != "" {
+		return nil, fmt.Errorf("optimizing composed prompts is not supported, %s has dependencies", prompt.Metadata.Name)
+	}
+	if opts.Eval.Model == nil {
+		return nil, fmt.Errorf("a model is required to optimize prompt %s", prompt.Metadata.Name)
+	}
+	if opts.Optimizer == nil {
+		opts.Optimizer = opts.Eval.Model
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = defaultIterations
+	}
+	if opts.Candidates <= 0 {
+		opts.Candidates = defaultCandidates
+	}
+
+	target, err := optimizationTarget(prompt, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	trail := &Trail{
+		Prompt:   prompt.Metadata.Name,
+		Version:  prompt.Metadata.Version,
+		Model:    opts.Eval.ModelName,
+		Criteria: opts.Criteria,
+		Started:  time.Now(),
+	}
+
+	template := prompt.Spec.Template
+	if template == "" {
+		template = prompt.Spec.Content
+	}
+	trail.Baseline = evaluateCandidate(ctx, target, Candidate{Template: template}, opts)
+	if trail.Baseline.Error != "" {
+		return nil, fmt.Errorf("failed to evaluate the current template: %s", trail.Baseline.Error)
+	}
+	notify(opts, trail.Baseline)
+
+	best := trail.Baseline
+	for iteration := 1; iteration <= opts.Iterations; iteration++ {
+		proposals, err := proposeRevisions(ctx, target, best, opts)
+		if err != nil {
+			log.Warn("Optimizer failed to propose revisions", zap.Int("iteration", iteration), zap.Error(err))
+			failed := Candidate{Iteration: iteration, Error: err.Error()}
+			trail.Candidates = append(trail.Candidates, failed)
+			notify(opts, failed)
+			continue
+		}
+
+		for _, proposal := range proposals {
+			proposal.Iteration = iteration
+			candidate := evaluateCandidate(ctx, target, proposal, opts)
+			trail.Candidates = append(trail.Candidates, candidate)
+			notify(opts, candidate)
+			if candidate.Error == "" && candidate.Score > best.Score {
+				best = candidate
+			}
+		}
+		if best.Score >= 1 {
+			break
+		}
+	}
+
+	trail.Finished = time.Now()
+	return trail, nil
+}
+
+// optimizationTarget returns the prompt evaluated for candidates. Prompts
+// without examples get an example judged against the criteria.
+func optimizationTarget(prompt *schema.Prompt, opts *OptimizeOptions) (*schema.Prompt, error) {
+	if len(prompt.Spec.Examples) > 0 {
+		return prompt, nil
+	}
+	if strings.TrimSpace(opts.Criteria) == "" {
+		return nil, fmt.Errorf("prompt %s has no examples, describe the desired outcome with criteria", prompt.Metadata.Name)
+	}
+
+	target := *prompt
+	target.Spec.Examples = []schema.PromptExample{{
+		Name:     criteriaExample,
+		Expected: opts.Criteria,
+		Scorers:  []string{ScorerJudge},
+	}}
+	if opts.Eval.ScorerOptions.Judge == nil {
+		opts.Eval.ScorerOptions.Judge = opts.Optimizer
+	}
+	return &target, nil
+}
+
+// evaluateCandidate scores the template of candidate.
+func evaluateCandidate(ctx context.Context, prompt *schema.Prompt, candidate Candidate, opts OptimizeOptions) Candidate {
+	revised := *prompt
+	revised.Spec.Template = candidate.Template
+
+	report, err := Evaluate(ctx, &revised, opts.Eval)
+	if err != nil {
+		candidate.Error = err.Error()
+		return candidate
+	}
+	candidate.Report = report
+	candidate.Score = report.Score()
+	return candidate
+}
+
+// proposeRevisions asks the optimizer model for revised templates.
+func proposeRevisions(ctx context.Context, prompt *schema.Prompt, best Candidate, opts OptimizeOptions) ([]Candidate, error) {
+	criteria := strings.TrimSpace(opts.Criteria)
+	if criteria == "" {
+		criteria = "Pass the evaluation of every example."
+	}
+
+	variables := make([]string, 0, len(prompt.Spec.Variables))
+	for _, variable := range prompt.Spec.Variables {
+		variables = append(variables, "{{."+variable.Name+"}}")
+	}
+	if len(variables) == 0 {
+		variables = append(variables, "(none)")
+	}
+
+	request := fmt.Sprintf(metaPrompt, criteria, best.Score, best.Template,
+		evaluationFeedback(best.Report), strings.Join(variables, ", "), opts.Candidates)
+	response, err := llms.GenerateFromSinglePrompt(ctx, opts.Optimizer, request, llms.WithTemperature(0.8))
+	if err != nil {
+		return nil, fmt.Errorf("optimizer model failed: %w", err)
+	}
+
+	var proposal struct {
+		Candidates []struct {
+			Template  string `json:"template"`
+			Rationale string `json:"rationale"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal([]byte(ExtractJSON(response)), &proposal); err != nil {
+		return nil, fmt.Errorf("optimizer returned invalid revisions: %w", err)
+	}
+
+	candidates := make([]Candidate, 0, len(proposal.Candidates))
+	for _, revision := range proposal.Candidates {
+		if template := strings.TrimSpace(revision.Template); template != "" && template != best.Template {
+			candidates = append(candidates, Candidate{Template: template, Rationale: revision.Rationale})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("optimizer returned no new templates")
+	}
+	if len(candidates) > opts.Candidates {
+		candidates = candidates[:opts.Candidates]
+	}
+	return candidates, nil
+}
+
+// evaluationFeedback summarizes a report for the meta-prompt.
+func evaluationFeedback(report *Report) string {
+	if report == nil {
+		return "(not evaluated)"
+	}
+
+	var builder strings.Builder
+	for _, result := range report.Results {
+		status := "passed"
+		if !result.Passed {
+			status = "failed"
+		}
+		fmt.Fprintf(&builder, "- Example %s %s (score %.2f)\n", result.Name, status, result.score())
+		for _, score := range result.Scores {
+			if score.Reason != "" && !score.Skipped {
+				fmt.Fprintf(&builder, "  %s: %s\n", score.Scorer, score.Reason)
+			}
+		}
+		if result.Error != "" {
+			fmt.Fprintf(&builder, "  error: %s\n", result.Error)
+		}
+		output := result.Output
+		if len(output) > maxFeedbackOutput {
+			output = output[:maxFeedbackOutput] + "..."
+		}
+		if output != "" {
+			fmt.Fprintf(&builder, "  output: %s\n", strings.ReplaceAll(output, "\n", " "))
+		}
+	}
+	return builder.String()
+}
+
+// notify reports a candidate to the progress callback.
+func notify(opts OptimizeOptions, candidate Candidate) {
+	if opts.Progress != nil {
+		opts.Progress(candidate)
+	}
+}
+
+// TemplateDiff returns a unified diff between two templates.
+func TemplateDiff(from, to string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(strings.TrimRight(from, "\n") + "\n"),
+		B:        difflib.SplitLines(strings.TrimRight(to, "\n") + "\n"),
+		FromFile: "current",
+		ToFile:   "candidate",
+		Context:  3,
+	})
+	if err != nil {
+		return ""
+	}
+	return diff
+}
+
+// SaveTrail writes trail as JSON into the optimizations directory below dir and
+// returns the file path.
+func SaveTrail(dir string, trail *Trail) (string, error) {
+	dir = filepath.Join(dir, "optimizations")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create optimizations directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(trail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode optimization trail: %w", err)
+	}
+	path := filepath.Join(dir, trail.Started.Format("20060102-150405")+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write optimization trail: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,105 @@
+package prompteval
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestOptimizeScoresRevisionsAgainstExamples(t *testing.T) {
+	prompt := newEvalPrompt(
+		schema.PromptExample{Name: "france", Variables: map[string]any{"country": "France"}, Expected: "Paris"},
+	)
+	model := &fakeModel{responses: []string{"Lyon", "Paris", "I don't know"}}
+	optimizer := &fakeModel{responses: []string{"```json\n" + `{"candidates": [
+		{"template": "Name the capital city of {{.country}}.", "rationale": "More direct"},
+		{"template": "Tell me about {{.country}}.", "rationale": "Broader"}
+	]}` + "\n```"}}
+
+	var progress []Candidate
+	trail, err := Optimize(context.Background(), prompt, OptimizeOptions{
+		Optimizer:  optimizer,
+		Iterations: 1,
+		Eval:       Options{Model: model, ModelName: "test:model"},
+		Progress:   func(candidate Candidate) { progress = append(progress, candidate) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "What is the capital of {{.country}}?", trail.Baseline.Template)
+	assert.Zero(t, trail.Baseline.Score)
+	require.Len(t, trail.Candidates, 2)
+	assert.Equal(t, 1.0, trail.Candidates[0].Score)
+	assert.Equal(t, 1, trail.Candidates[0].Iteration)
+	assert.Len(t, progress, 3)
+
+	assert.Contains(t, optimizer.prompts[0], "{{.country}}")
+	assert.Contains(t, optimizer.prompts[0], "output: Lyon")
+	assert.Contains(t, model.prompts[1], "Name the capital city of France.")
+
+	best := trail.Best(5)
+	require.Len(t, best, 1)
+	assert.Equal(t, "Name the capital city of {{.country}}.", best[0].Template)
+	assert.Contains(t, TemplateDiff(trail.Baseline.Template, best[0].Template), "+Name the capital city of {{.country}}.")
+}
+
+func TestOptimizeJudgesCriteriaWithoutExamples(t *testing.T) {
+	prompt := newEvalPrompt()
+	prompt.Spec.Variables[0].Required = false
+	prompt.Spec.Variables[0].Default = "France"
+
+	model := &fakeModel{responses: []string{"Paris", "Paris is the capital of France."}}
+	optimizer := &fakeModel{responses: []string{
+		`{"score": 0.4, "reason": "too terse"}`,
+		`{"candidates": [{"template": "Answer in a full sentence: what is the capital of {{.country}}?"}]}`,
+		`{"score": 0.9, "reason": "complete"}`,
+	}}
+
+	trail, err := Optimize(context.Background(), prompt, OptimizeOptions{
+		Optimizer:  optimizer,
+		Criteria:   "Answers in a full sentence",
+		Iterations: 1,
+		Eval:       Options{Model: model},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0.4, trail.Baseline.Score)
+	require.Len(t, trail.Candidates, 1)
+	assert.Equal(t, 0.9, trail.Candidates[0].Score)
+	assert.Empty(t, prompt.Spec.Examples)
+
+	_, err = Optimize(context.Background(), newEvalPrompt(), OptimizeOptions{Eval: Options{Model: model}})
+	assert.ErrorContains(t, err, "criteria")
+}
+
+func TestOptimizeRecordsInvalidProposals(t *testing.T) {
+	prompt := newEvalPrompt(schema.PromptExample{Name: "france", Variables: map[string]any{"country": "France"}, Expected: "Paris"})
+	trail, err := Optimize(context.Background(), prompt, OptimizeOptions{
+		Optimizer:  &fakeModel{responses: []string{"not json"}},
+		Iterations: 1,
+		Eval:       Options{Model: &fakeModel{responses: []string{"Lyon"}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, trail.Candidates, 1)
+	assert.Contains(t, trail.Candidates[0].Error, "invalid revisions")
+	assert.Empty(t, trail.Best(3))
+}
+
+func TestSaveTrail(t *testing.T) {
+	dir := t.TempDir()
+	trail := &Trail{Prompt: "capitals", Baseline: Candidate{Template: "a", Score: 0.5}}
+
+	path, err := SaveTrail(dir, trail)
+	require.NoError(t, err)
+	assert.Contains(t, path, "optimizations")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var saved Trail
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, trail.Baseline, saved.Baseline)
+}
@@ -0,0 +1,47 @@
+package prompteval
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// RenderMessages renders the prompt for an example into the messages sent to the model.
+// The example context is added as system message, or as user message for system prompts.
+func RenderMessages(prompt *schema.Prompt, example schema.PromptExample) ([]llms.MessageContent, error) {
+	rendered, err := promptrender.RenderMessages(prompt, example.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("example %s: %w", example.Name, err)
+	}
+	if example.Context == "" {
+		return rendered, nil
+	}
+
+	contextMessage := llms.TextParts(llms.ChatMessageTypeSystem, example.Context)
+	if prompt.Spec.Type == schema.PromptTypeSystem {
+		return append(rendered, llms.TextParts(llms.ChatMessageTypeHuman, example.Context)), nil
+	}
+	return append([]llms.MessageContent{contextMessage}, rendered...), nil
+}
+
+// MessagesText renders messages as role-prefixed text, for judges and reports.
+func MessagesText(messages []llms.MessageContent) string {
+	var builder strings.Builder
+	for i, message := range messages {
+		if i > 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString(string(message.Role))
+		builder.WriteString(": ")
+		for _, part := range message.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				builder.WriteString(text.Text)
+			}
+		}
+	}
+	return builder.String()
+}
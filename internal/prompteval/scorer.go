@@ -0,0 +1,229 @@
+// Package prompteval evaluates prompts against their examples with a model and
+// pluggable scorers.
+package prompteval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/jsonschema"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// Built-in scorer names.
+const (
+	ScorerExact      = "exact"
+	ScorerContains   = "contains"
+	ScorerRegex      = "regex"
+	ScorerJSONSchema = "jsonschema"
+	ScorerJudge      = "judge"
+)
+
+// defaultPassThreshold is the score a judged output needs to pass.
+const defaultPassThreshold = 0.7
+
+// Case is an example together with the rendered prompt sent to the model.
+type Case struct {
+	Example schema.PromptExample
+	// Input is the rendered prompt text, used by scorers that need the question
+	Input string
+}
+
+// Score is the result of one scorer for one output.
+type Score struct {
+	Scorer  string  `json:"scorer"`
+	Value   float64 `json:"value"`
+	Passed  bool    `json:"passed"`
+	Skipped bool    `json:"skipped,omitempty"`
+	Reason  string  `json:"reason,omitempty"`
+}
+
+// Scorer rates a model output for an example.
+type Scorer interface {
+	// Name returns the name the scorer is registered under
+	Name() string
+
+	// Score rates output, an error means the scorer itself failed
+	Score(ctx context.Context, c Case, output string) (Score, error)
+}
+
+// ScorerOptions configures the scorers created by NewScorer.
+type ScorerOptions struct {
+	// Judge is the model used by the LLM-as-judge scorer
+	Judge llms.Model
+	// Threshold is the score needed to pass for graded scorers, defaults to 0.7
+	Threshold float64
+}
+
+// ScorerFactory creates a scorer.
+type ScorerFactory func(opts ScorerOptions) (Scorer, error)
+
+var (
+	registry      = make(map[string]ScorerFactory)
+	registryMutex sync.RWMutex
+)
+
+func init() {
+	RegisterScorer(ScorerExact, func(ScorerOptions) (Scorer, error) { return exactScorer{}, nil })
+	RegisterScorer(ScorerContains, func(ScorerOptions) (Scorer, error) { return containsScorer{}, nil })
+	RegisterScorer(ScorerRegex, func(ScorerOptions) (Scorer, error) { return regexScorer{}, nil })
+	RegisterScorer(ScorerJSONSchema, func(ScorerOptions) (Scorer, error) { return jsonSchemaScorer{}, nil })
+	RegisterScorer(ScorerJudge, newJudgeScorer)
+}
+
+// RegisterScorer makes a scorer available under name, replacing any scorer of that name.
+func RegisterScorer(name string, factory ScorerFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[name] = factory
+}
+
+// ScorerNames returns the names of all registered scorers, sorted alphabetically.
+func ScorerNames() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewScorer creates the scorer registered under name.
+func NewScorer(name string, opts ScorerOptions) (Scorer, error) {
+	registryMutex.RLock()
+	factory, exists := registry[name]
+	registryMutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown scorer %q, use one of: %s", name, strings.Join(ScorerNames(), ", "))
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = defaultPassThreshold
+	}
+	return factory(opts)
+}
+
+// skipped returns the score of a scorer that has nothing to check.
+func skipped(name, reason string) Score {
+	return Score{Scorer: name, Passed: true, Skipped: true, Reason: reason}
+}
+
+// result returns a pass or fail score.
+func result(name string, passed bool, reason string) Score {
+	score := Score{Scorer: name, Passed: passed, Reason: reason}
+	if passed {
+		score.Value = 1
+	}
+	return score
+}
+
+// exactScorer passes outputs equal to the expected output, ignoring surrounding whitespace.
+type exactScorer struct{}
+
+func (exactScorer) Name() string { return ScorerExact }
+
+func (exactScorer) Score(_ context.Context, c Case, output string) (Score, error) {
+	expected := strings.TrimSpace(c.Example.Expected)
+	if expected == "" {
+		return skipped(ScorerExact, "no expected output"), nil
+	}
+	if strings.TrimSpace(output) == expected {
+		return result(ScorerExact, true, ""), nil
+	}
+	return result(ScorerExact, false, "output differs from the expected output"), nil
+}
+
+// containsScorer passes outputs containing every non-empty line of the expected
+// output, case-insensitively. The value is the fraction of lines found.
+type containsScorer struct{}
+
+func (containsScorer) Name() string { return ScorerContains }
+
+func (containsScorer) Score(_ context.Context, c Case, output string) (Score, error) {
+	fragments := make([]string, 0)
+	for _, line := range strings.Split(c.Example.Expected, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			fragments = append(fragments, line)
+		}
+	}
+	if len(fragments) == 0 {
+		return skipped(ScorerContains, "no expected output"), nil
+	}
+
+	lowerOutput := strings.ToLower(output)
+	missing := make([]string, 0)
+	for _, fragment := range fragments {
+		if !strings.Contains(lowerOutput, strings.ToLower(fragment)) {
+			missing = append(missing, fmt.Sprintf("%q", fragment))
+		}
+	}
+
+	score := result(ScorerContains, len(missing) == 0, "")
+	score.Value = float64(len(fragments)-len(missing)) / float64(len(fragments))
+	if len(missing) > 0 {
+		score.Reason = "missing " + strings.Join(missing, ", ")
+	}
+	return score, nil
+}
+
+// regexScorer passes outputs matching the expected output as a regular expression.
+type regexScorer struct{}
+
+func (regexScorer) Name() string { return ScorerRegex }
+
+func (regexScorer) Score(_ context.Context, c Case, output string) (Score, error) {
+	pattern := strings.TrimSpace(c.Example.Expected)
+	if pattern == "" {
+		return skipped(ScorerRegex, "no expected pattern"), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Score{}, fmt.Errorf("invalid expected pattern of example %s: %w", c.Example.Name, err)
+	}
+	if re.MatchString(output) {
+		return result(ScorerRegex, true, ""), nil
+	}
+	return result(ScorerRegex, false, fmt.Sprintf("output does not match /%s/", pattern)), nil
+}
+
+// jsonSchemaScorer passes outputs that are JSON documents valid against the
+// JSON Schema given as expected output. Markdown code fences are stripped.
+type jsonSchemaScorer struct{}
+
+func (jsonSchemaScorer) Name() string { return ScorerJSONSchema }
+
+func (jsonSchemaScorer) Score(_ context.Context, c Case, output string) (Score, error) {
+	if strings.TrimSpace(c.Example.Expected) == "" {
+		return skipped(ScorerJSONSchema, "no expected schema"), nil
+	}
+	outputSchema, err := jsonschema.Parse([]byte(c.Example.Expected))
+	if err != nil {
+		return Score{}, fmt.Errorf("invalid expected schema of example %s: %w", c.Example.Name, err)
+	}
+	if err := jsonschema.ValidateJSON(outputSchema, []byte(ExtractJSON(output))); err != nil {
+		return result(ScorerJSONSchema, false, err.Error()), nil
+	}
+	return result(ScorerJSONSchema, true, ""), nil
+}
+
+// ExtractJSON returns the JSON document in a model output, removing a surrounding
+// markdown code fence.
+func ExtractJSON(output string) string {
+	trimmed := strings.TrimSpace(output)
+	if !strings.HasPrefix(trimmed, "```") {
+		return trimmed
+	}
+	trimmed = strings.TrimPrefix(trimmed, "```")
+	if newline := strings.Index(trimmed, "\n"); newline >= 0 {
+		trimmed = trimmed[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(trimmed), "```"))
+}
@@ -0,0 +1,95 @@
+package prompteval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func scoreOutput(t *testing.T, name, expected, output string) Score {
+	t.Helper()
+	scorer, err := NewScorer(name, ScorerOptions{})
+	require.NoError(t, err)
+	score, err := scorer.Score(context.Background(), Case{Example: schema.PromptExample{Name: "ex", Expected: expected}}, output)
+	require.NoError(t, err)
+	return score
+}
+
+func TestBuiltinScorers(t *testing.T) {
+	tests := []struct {
+		scorer   string
+		expected string
+		output   string
+		passed   bool
+	}{
+		{ScorerExact, "Paris", " Paris\n", true},
+		{ScorerExact, "Paris", "paris", false},
+		{ScorerContains, "paris\nfrance", "The capital of France is Paris.", true},
+		{ScorerContains, "paris\nberlin", "The capital of France is Paris.", false},
+		{ScorerRegex, `^\d{4}-\d{2}-\d{2}$`, "2024-01-31", true},
+		{ScorerRegex, `^\d+$`, "twelve", false},
+		{ScorerJSONSchema, `{"type":"object","required":["city"]}`, "```json\n{\"city\":\"Paris\"}\n```", true},
+		{ScorerJSONSchema, `{"type":"object","required":["city"]}`, `{"country":"France"}`, false},
+		{ScorerJSONSchema, `{"type":"object"}`, "not json", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scorer+"/"+tt.output, func(t *testing.T) {
+			score := scoreOutput(t, tt.scorer, tt.expected, tt.output)
+			assert.Equal(t, tt.passed, score.Passed, score.Reason)
+			assert.Equal(t, tt.scorer, score.Scorer)
+		})
+	}
+}
+
+func TestContainsScorer_PartialValue(t *testing.T) {
+	score := scoreOutput(t, ScorerContains, "paris\nberlin", "Paris")
+	assert.False(t, score.Passed)
+	assert.Equal(t, 0.5, score.Value)
+	assert.Contains(t, score.Reason, "berlin")
+}
+
+func TestScorers_SkipWithoutExpected(t *testing.T) {
+	for _, name := range []string{ScorerExact, ScorerContains, ScorerRegex, ScorerJSONSchema} {
+		score := scoreOutput(t, name, "", "anything")
+		assert.True(t, score.Passed, name)
+		assert.True(t, score.Skipped, name)
+	}
+}
+
+func TestJudgeScorer(t *testing.T) {
+	_, err := NewScorer(ScorerJudge, ScorerOptions{})
+	assert.Error(t, err, "judge needs a model")
+
+	judge := &fakeModel{responses: []string{"```json\n{\"score\": 0.9, \"reason\": \"accurate\"}\n```", `{"score": 0.4, "reason": "vague"}`, "great answer"}}
+	scorer, err := NewScorer(ScorerJudge, ScorerOptions{Judge: judge})
+	require.NoError(t, err)
+
+	c := Case{Example: schema.PromptExample{Name: "ex", Expected: "Names Paris"}, Input: "human: capital of France?"}
+	score, err := scorer.Score(context.Background(), c, "Paris")
+	require.NoError(t, err)
+	assert.True(t, score.Passed)
+	assert.Equal(t, 0.9, score.Value)
+	assert.Equal(t, "accurate", score.Reason)
+	assert.Contains(t, judge.prompts[0], "Names Paris")
+	assert.Contains(t, judge.prompts[0], "capital of France?")
+
+	score, err = scorer.Score(context.Background(), c, "Somewhere")
+	require.NoError(t, err)
+	assert.False(t, score.Passed)
+
+	_, err = scorer.Score(context.Background(), c, "Paris")
+	assert.ErrorContains(t, err, "invalid verdict")
+}
+
+func TestRegisterScorer(t *testing.T) {
+	RegisterScorer("length", func(ScorerOptions) (Scorer, error) { return exactScorer{}, nil })
+	assert.Contains(t, ScorerNames(), "length")
+
+	_, err := NewScorer("missing", ScorerOptions{})
+	assert.ErrorContains(t, err, "unknown scorer")
+}
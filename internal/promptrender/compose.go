@@ -0,0 +1,305 @@
+package promptrender
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// Resolver finds the prompts referenced by dependencies in prompt directories
+// and composes prompts with their includes and base prompt.
+type Resolver struct {
+	dirs []string
+}
+
+// NewResolver creates a resolver searching dirs in order, e.g. the local
+// prompts directory before the installed prompts. A prompt is found as
+// <dir>/<name>/component.yaml or, for installed versions, as
+// <dir>/<name>/<version>/component.yaml.
+func NewResolver(dirs ...string) *Resolver {
+	return &Resolver{dirs: dirs}
+}
+
+// Find returns the highest version of the prompt matching dependency.
+func (r *Resolver) Find(dependency schema.PromptDependency) (*schema.Prompt, error) {
+	constraint, err := versionConstraint(dependency.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version range %q of prompt %s: %w", dependency.Version, dependency.Name, err)
+	}
+
+	var (
+		best        *schema.Prompt
+		bestVersion *semver.Version
+	)
+	for _, dir := range r.dirs {
+		manifests, _ := filepath.Glob(filepath.Join(dir, dependency.Name, "*", schema.ManifestFileName))
+		manifests = append([]string{filepath.Join(dir, dependency.Name, schema.ManifestFileName)}, manifests...)
+		for _, manifest := range manifests {
+			prompt, err := loadPrompt(manifest)
+			if err != nil || prompt == nil || prompt.Metadata.Name != dependency.Name {
+				continue
+			}
+			version, err := semver.NewVersion(prompt.Metadata.Version)
+			if err != nil || !constraint.Check(version) {
+				continue
+			}
+			if bestVersion == nil || version.GreaterThan(bestVersion) {
+				best, bestVersion = prompt, version
+			}
+		}
+	}
+
+	if best == nil {
+		if dependency.Version == "" {
+			return nil, fmt.Errorf("prompt %s not found", dependency.Name)
+		}
+		return nil, fmt.Errorf("prompt %s %s not found", dependency.Name, dependency.Version)
+	}
+	return best, nil
+}
+
+// Compose returns a copy of prompt whose templates define every included
+// prompt and block, so any engine renders it without a resolver. Included
+// prompts are available as {{template "name" .}}. A prompt extending a base
+// prompt inherits its templates and variables and overrides its
+// {{block "name" .}} sections with Blocks. Prompts without dependencies are
+// returned unchanged.
+func (r *Resolver) Compose(prompt *schema.Prompt) (*schema.Prompt, error) {
+	if len(prompt.Spec.Dependencies) == 0 && prompt.Spec.Extends == "" && len(prompt.Spec.Blocks) == 0 {
+		return prompt, nil
+	}
+
+	composed, defines, err := r.compose(prompt, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := definitions(defines)
+	if composed.Spec.Template != "" {
+		composed.Spec.Template += suffix
+	}
+	if composed.Spec.Content != "" {
+		composed.Spec.Content += suffix
+	}
+	for i := range composed.Spec.Messages {
+		composed.Spec.Messages[i].Content += suffix
+	}
+	return composed, nil
+}
+
+// compose resolves the dependencies of prompt. It returns a copy with the main
+// templates and the named templates they use.
+func (r *Resolver) compose(prompt *schema.Prompt, stack []string) (*schema.Prompt, map[string]string, error) {
+	name := prompt.Metadata.Name
+	for i, parent := range stack {
+		if parent == name {
+			return nil, nil, fmt.Errorf("prompt dependency cycle: %s", strings.Join(append(stack[i:], name), " -> "))
+		}
+	}
+	stack = append(stack, name)
+
+	composed := *prompt
+	composed.Spec.Variables = append([]schema.PromptVariable(nil), prompt.Spec.Variables...)
+	composed.Spec.Messages = append([]schema.PromptMessage(nil), prompt.Spec.Messages...)
+
+	own := make(map[string]string)
+	hasBody := false
+	for _, text := range promptTexts(&composed) {
+		main, defines, err := splitDefinitions(name, *text)
+		if err != nil {
+			return nil, nil, err
+		}
+		*text = main
+		hasBody = hasBody || strings.TrimSpace(main) != ""
+		if err := merge(own, defines, name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	defines := make(map[string]string)
+	for _, dependency := range prompt.Spec.Dependencies {
+		if dependency.TemplateName() == prompt.Spec.Extends {
+			continue
+		}
+		included, includedDefines, err := r.resolve(dependency, stack)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(included.Spec.Messages) > 0 {
+			return nil, nil, fmt.Errorf("prompt %s cannot include conversation prompt %s", name, dependency.Name)
+		}
+		body := included.Spec.Template
+		if body == "" {
+			body = included.Spec.Content
+		}
+		if err := merge(defines, includedDefines, name); err != nil {
+			return nil, nil, err
+		}
+		if err := merge(defines, map[string]string{dependency.TemplateName(): body}, name); err != nil {
+			return nil, nil, err
+		}
+		inheritVariables(&composed, included)
+	}
+
+	for block, text := range prompt.Spec.Blocks {
+		main, blockDefines, err := splitDefinitions(block, text)
+		if err != nil {
+			return nil, nil, err
+		}
+		own[block] = main
+		if err := merge(own, blockDefines, name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if prompt.Spec.Extends == "" {
+		if err := merge(defines, own, name); err != nil {
+			return nil, nil, err
+		}
+		return &composed, defines, nil
+	}
+
+	if hasBody {
+		return nil, nil, fmt.Errorf("prompt %s extends %s and may only override blocks", name, prompt.Spec.Extends)
+	}
+	base, baseDefines, err := r.resolve(extendedDependency(prompt), stack)
+	if err != nil {
+		return nil, nil, err
+	}
+	for block := range prompt.Spec.Blocks {
+		if _, exists := baseDefines[block]; !exists {
+			return nil, nil, fmt.Errorf("prompt %s overrides block %s not defined by %s", name, block, prompt.Spec.Extends)
+		}
+	}
+
+	composed.Spec.Template = base.Spec.Template
+	composed.Spec.Content = base.Spec.Content
+	composed.Spec.Messages = base.Spec.Messages
+	if composed.Spec.Validation == nil {
+		composed.Spec.Validation = base.Spec.Validation
+	}
+	inheritVariables(&composed, base)
+
+	if err := merge(defines, baseDefines, name); err != nil {
+		return nil, nil, err
+	}
+	for block, text := range own {
+		defines[block] = text
+	}
+	return &composed, defines, nil
+}
+
+// resolve finds and composes a dependency.
+func (r *Resolver) resolve(dependency schema.PromptDependency, stack []string) (*schema.Prompt, map[string]string, error) {
+	prompt, err := r.Find(dependency)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve dependency of %s: %w", stack[len(stack)-1], err)
+	}
+	return r.compose(prompt, stack)
+}
+
+// promptTexts returns pointers to the template texts of prompt.
+func promptTexts(prompt *schema.Prompt) []*string {
+	texts := []*string{&prompt.Spec.Template, &prompt.Spec.Content}
+	for i := range prompt.Spec.Messages {
+		texts = append(texts, &prompt.Spec.Messages[i].Content)
+	}
+	return texts
+}
+
+// inheritVariables adds the variables of other that prompt does not declare.
+func inheritVariables(prompt, other *schema.Prompt) {
+	for _, variable := range other.Spec.Variables {
+		if !prompt.HasVariable(variable.Name) {
+			prompt.Spec.Variables = append(prompt.Spec.Variables, variable)
+		}
+	}
+}
+
+// extendedDependency returns the dependency named by Extends.
+func extendedDependency(prompt *schema.Prompt) schema.PromptDependency {
+	for _, dependency := range prompt.Spec.Dependencies {
+		if dependency.TemplateName() == prompt.Spec.Extends {
+			return dependency
+		}
+	}
+	return schema.PromptDependency{Name: prompt.Spec.Extends}
+}
+
+// splitDefinitions separates the main text of a template from the templates it
+// defines, including {{block}} sections, which remain as {{template}} calls.
+func splitDefinitions(name, text string) (string, map[string]string, error) {
+	tmpl := template.New(name).Funcs(funcMap(nil))
+	if _, err := tmpl.Parse(Normalize(text)); err != nil {
+		return "", nil, fmt.Errorf("failed to parse template of %s: %w", name, err)
+	}
+
+	main := ""
+	defines := make(map[string]string)
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if t.Name() == name {
+			main = t.Tree.Root.String()
+		} else {
+			defines[t.Name()] = t.Tree.Root.String()
+		}
+	}
+	return main, defines, nil
+}
+
+// merge adds defines to target and fails on conflicting definitions.
+func merge(target, defines map[string]string, prompt string) error {
+	for name, text := range defines {
+		if existing, exists := target[name]; exists && existing != text {
+			return fmt.Errorf("template %s is defined differently by the dependencies of %s", name, prompt)
+		}
+		target[name] = text
+	}
+	return nil
+}
+
+// definitions renders defines as {{define}} actions in name order.
+func definitions(defines map[string]string) string {
+	names := make([]string, 0, len(defines))
+	for name := range defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&builder, "{{define %q}}%s{{end}}", name, defines[name])
+	}
+	return builder.String()
+}
+
+// versionConstraint parses a semver range, empty for any version.
+func versionConstraint(version string) (*semver.Constraints, error) {
+	if strings.TrimSpace(version) == "" {
+		version = "*"
+	}
+	return semver.NewConstraint(version)
+}
+
+// loadPrompt reads a prompt manifest, nil for other component kinds.
+func loadPrompt(path string) (*schema.Prompt, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt manifest %s: %w", path, err)
+	}
+	prompt, _ := component.(*schema.Prompt)
+	return prompt, nil
+}
@@ -0,0 +1,117 @@
+package promptrender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// newPrompt creates a valid template prompt.
+func newPrompt(name, version, template string, dependencies ...schema.PromptDependency) *schema.Prompt {
+	prompt := schema.NewPrompt(name, version)
+	prompt.Metadata.Description = "Prompt " + name + " for composition tests"
+	prompt.Metadata.Author = "Test"
+	prompt.Metadata.License = "MIT"
+	prompt.Metadata.ForgeVersion = ">=0.1.0"
+	prompt.Spec.Type = schema.PromptTypeTemplate
+	prompt.Spec.Format = schema.FormatText
+	prompt.Spec.Template = template
+	prompt.Spec.Dependencies = dependencies
+	return prompt
+}
+
+// writePrompt writes prompt below dir as <name>/<subdir>/component.yaml.
+func writePrompt(t *testing.T, dir string, prompt *schema.Prompt, subdir string) {
+	t.Helper()
+	content, err := schema.NewComponentParser().SerializeComponent(prompt)
+	require.NoError(t, err)
+	path := filepath.Join(dir, prompt.Metadata.Name, subdir)
+	require.NoError(t, os.MkdirAll(path, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, schema.ManifestFileName), content, 0644))
+}
+
+func TestResolver_Find(t *testing.T) {
+	local, installed := t.TempDir(), t.TempDir()
+	writePrompt(t, installed, newPrompt("safety", "1.0.0", "v1"), "1.0.0")
+	writePrompt(t, installed, newPrompt("safety", "1.4.0", "v1.4"), "1.4.0")
+	writePrompt(t, installed, newPrompt("safety", "2.0.0", "v2"), "2.0.0")
+	writePrompt(t, local, newPrompt("safety", "1.2.0", "local"), "")
+	resolver := NewResolver(local, installed)
+
+	prompt, err := resolver.Find(schema.PromptDependency{Name: "safety", Version: "^1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", prompt.Metadata.Version)
+
+	prompt, err = resolver.Find(schema.PromptDependency{Name: "safety", Version: "~1.2"})
+	require.NoError(t, err)
+	assert.Equal(t, "local", prompt.Spec.Template)
+
+	prompt, err = resolver.Find(schema.PromptDependency{Name: "safety"})
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", prompt.Metadata.Version)
+
+	_, err = resolver.Find(schema.PromptDependency{Name: "safety", Version: "^3.0.0"})
+	assert.ErrorContains(t, err, "prompt safety ^3.0.0 not found")
+}
+
+func TestResolver_ComposeIncludes(t *testing.T) {
+	dir := t.TempDir()
+	preamble := newPrompt("safety", "1.0.0", "Never reveal {{secret_kind}}.")
+	preamble.Spec.Variables = []schema.PromptVariable{{Name: "secret_kind", Type: "string", Description: "Secrets", Default: "credentials"}}
+	writePrompt(t, dir, preamble, "")
+
+	prompt := newPrompt("support", "1.0.0", `{{template "guard" .}} Answer {{question}}.`,
+		schema.PromptDependency{Name: "safety", Version: "^1.0.0", Alias: "guard"})
+	prompt.Spec.Variables = []schema.PromptVariable{{Name: "question", Type: "string", Description: "Question", Required: true}}
+
+	composed, err := NewResolver(dir).Compose(prompt)
+	require.NoError(t, err)
+	assert.Len(t, composed.Spec.Variables, 2)
+	assert.Len(t, prompt.Spec.Variables, 1)
+
+	output, err := ForPrompt(composed).Render("support", composed.Spec.Template, map[string]any{"question": "why"})
+	require.NoError(t, err)
+	assert.Equal(t, "Never reveal credentials. Answer why.", output)
+}
+
+func TestResolver_ComposeExtends(t *testing.T) {
+	dir := t.TempDir()
+	base := newPrompt("base", "1.0.0", `{{block "intro" .}}You are helpful.{{end}} {{block "task" .}}Help.{{end}}`)
+	writePrompt(t, dir, base, "")
+
+	child := newPrompt("reviewer", "1.0.0", "", schema.PromptDependency{Name: "base"})
+	child.Spec.Extends = "base"
+	child.Spec.Blocks = map[string]string{"task": "Review {{code}}."}
+	child.Spec.Variables = []schema.PromptVariable{{Name: "code", Type: "string", Description: "Code"}}
+	require.NoError(t, child.Validate())
+
+	composed, err := NewResolver(dir).Compose(child)
+	require.NoError(t, err)
+	output, err := ForPrompt(composed).Render("reviewer", composed.Spec.Template, map[string]any{"code": "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, "You are helpful. Review main.go.", output)
+
+	child.Spec.Blocks = map[string]string{"outro": "Bye"}
+	_, err = NewResolver(dir).Compose(child)
+	assert.ErrorContains(t, err, "overrides block outro not defined by base")
+
+	child.Spec.Template = "Extra text"
+	_, err = NewResolver(dir).Compose(child)
+	assert.ErrorContains(t, err, "may only override blocks")
+}
+
+func TestResolver_ComposeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writePrompt(t, dir, newPrompt("a", "1.0.0", `{{template "b" .}}`, schema.PromptDependency{Name: "b"}), "")
+	writePrompt(t, dir, newPrompt("b", "1.0.0", `{{template "c" .}}`, schema.PromptDependency{Name: "c"}), "")
+	writePrompt(t, dir, newPrompt("c", "1.0.0", `{{template "a" .}}`, schema.PromptDependency{Name: "a"}), "")
+
+	prompt := newPrompt("a", "1.0.0", `{{template "b" .}}`, schema.PromptDependency{Name: "b"})
+	_, err := NewResolver(dir).Compose(prompt)
+	assert.ErrorContains(t, err, "prompt dependency cycle: a -> b -> c -> a")
+}
@@ -0,0 +1,291 @@
+// Package promptrender renders prompt templates with typed variables.
+//
+// Templates use Go text/template syntax with conditionals, loops and partials.
+// The legacy {{name}} placeholder form is accepted as shorthand for {{.name}}.
+// Variables are checked against the declared PromptVariable types and
+// constraints and against the PromptValidation rules of the prompt.
+package promptrender
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// legacyPlaceholder matches {{name}} placeholders without the leading dot.
+var legacyPlaceholder = regexp.MustCompile(`\{\{(-?\s*)([A-Za-z_][A-Za-z0-9_]*)(\s*-?)\}\}`)
+
+// keywords are template actions that must not be rewritten as variables.
+var keywords = map[string]bool{
+	"end": true, "else": true, "break": true, "continue": true,
+	"nil": true, "true": true, "false": true,
+}
+
+// Engine renders prompt templates.
+type Engine struct {
+	variables  []schema.PromptVariable
+	validation *schema.PromptValidation
+	partials   map[string]string
+	missingKey string
+}
+
+// New creates an engine for the declared variables and validation rules.
+// Without declared variables any variable is accepted.
+func New(variables []schema.PromptVariable, validation *schema.PromptValidation) *Engine {
+	return &Engine{
+		variables:  variables,
+		validation: validation,
+		partials:   make(map[string]string),
+		missingKey: "error",
+	}
+}
+
+// ForPrompt creates an engine for the variables and validation of prompt.
+func ForPrompt(prompt *schema.Prompt) *Engine {
+	return New(prompt.Spec.Variables, prompt.Spec.Validation)
+}
+
+// WithPartial registers a named template that can be used with
+// {{template "name" .}} or {{include "name" .}}.
+func (e *Engine) WithPartial(name, text string) *Engine {
+	e.partials[name] = text
+	return e
+}
+
+// AllowMissing renders variables missing from the values as "<no value>",
+// the text/template default, instead of failing.
+func (e *Engine) AllowMissing() *Engine {
+	e.missingKey = "default"
+	return e
+}
+
+// Render resolves values against the declared variables and executes text.
+func (e *Engine) Render(name, text string, values map[string]any) (string, error) {
+	variables, err := e.Variables(values)
+	if err != nil {
+		return "", err
+	}
+	return e.Execute(name, text, variables)
+}
+
+// Execute executes text with variables already resolved by Variables.
+func (e *Engine) Execute(name, text string, variables map[string]any) (string, error) {
+	tmpl, err := e.parse(name, text)
+	if err != nil {
+		return "", err
+	}
+	if err := e.checkReferences(tmpl); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, variables); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return builder.String(), nil
+}
+
+// parse parses text and the registered partials into one template set.
+func (e *Engine) parse(name, text string) (*template.Template, error) {
+	tmpl := template.New(name).Option("missingkey=" + e.missingKey)
+	tmpl.Funcs(funcMap(tmpl))
+
+	names := make([]string, 0, len(e.partials))
+	for partial := range e.partials {
+		names = append(names, partial)
+	}
+	sort.Strings(names)
+	for _, partial := range names {
+		if _, err := tmpl.New(partial).Parse(Normalize(e.partials[partial])); err != nil {
+			return nil, fmt.Errorf("failed to parse partial %s: %w", partial, err)
+		}
+	}
+
+	if _, err := tmpl.Parse(Normalize(text)); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// checkReferences rejects templates using forbidden or undeclared variables.
+func (e *Engine) checkReferences(tmpl *template.Template) error {
+	declared := make(map[string]bool, len(e.variables))
+	for _, variable := range e.variables {
+		declared[variable.Name] = true
+	}
+	forbidden := e.forbidden()
+
+	referenced := make(map[string]bool)
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectReferences(t.Tree.Root, true, referenced)
+		}
+	}
+
+	var violations []string
+	for _, name := range sortedKeys(referenced) {
+		switch {
+		case forbidden[name]:
+			violations = append(violations, fmt.Sprintf("template uses forbidden variable %s", name))
+		case len(declared) > 0 && !declared[name]:
+			violations = append(violations, fmt.Sprintf("template uses undeclared variable %s", name))
+		}
+	}
+	if len(violations) > 0 {
+		return &VariableError{Violations: violations}
+	}
+	return nil
+}
+
+// References returns the sorted names of the variables text refers to.
+func References(text string) ([]string, error) {
+	tmpl, err := New(nil, nil).parse("references", text)
+	if err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]bool)
+	if tmpl.Tree != nil {
+		collectReferences(tmpl.Tree.Root, true, referenced)
+	}
+	return sortedKeys(referenced), nil
+}
+
+// forbidden returns the forbidden variable names of the validation rules.
+func (e *Engine) forbidden() map[string]bool {
+	forbidden := make(map[string]bool)
+	if e.validation != nil {
+		for _, name := range e.validation.ForbiddenVars {
+			forbidden[name] = true
+		}
+	}
+	return forbidden
+}
+
+// Normalize rewrites legacy {{name}} placeholders as {{.name}}.
+func Normalize(text string) string {
+	return legacyPlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		parts := legacyPlaceholder.FindStringSubmatch(placeholder)
+		if keywords[parts[2]] || builtinFuncs[parts[2]] {
+			return placeholder
+		}
+		return "{{" + parts[1] + "." + parts[2] + parts[3] + "}}"
+	})
+}
+
+// builtinFuncs are the function names available in templates.
+var builtinFuncs = map[string]bool{
+	"include": true, "default": true, "join": true, "upper": true,
+	"lower": true, "trim": true, "json": true,
+	// text/template builtins
+	"and": true, "or": true, "not": true, "len": true, "index": true, "slice": true,
+	"print": true, "printf": true, "println": true, "eq": true, "ne": true,
+	"lt": true, "le": true, "gt": true, "ge": true, "html": true, "js": true,
+	"urlquery": true, "call": true,
+}
+
+// funcMap returns the helper functions of templates in tmpl.
+func funcMap(tmpl *template.Template) template.FuncMap {
+	return template.FuncMap{
+		"include": func(name string, data any) (string, error) {
+			var builder strings.Builder
+			if err := tmpl.ExecuteTemplate(&builder, name, data); err != nil {
+				return "", err
+			}
+			return builder.String(), nil
+		},
+		"default": func(fallback, value any) any {
+			if value == nil || value == "" {
+				return fallback
+			}
+			return value
+		},
+		"join": func(separator string, values any) (string, error) {
+			list := reflect.ValueOf(values)
+			if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+				return "", fmt.Errorf("join expects a list, got %T", values)
+			}
+			parts := make([]string, list.Len())
+			for i := range parts {
+				parts[i] = fmt.Sprint(list.Index(i).Interface())
+			}
+			return strings.Join(parts, separator), nil
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+		"json": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+	}
+}
+
+// collectReferences adds the root variables used below node to referenced.
+// Fields are only root variables where dot is the template data, outside the
+// bodies of range and with actions.
+func collectReferences(node parse.Node, root bool, referenced map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectReferences(child, root, referenced)
+		}
+	case *parse.ActionNode:
+		collectReferences(n.Pipe, root, referenced)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, command := range n.Cmds {
+			collectReferences(command, root, referenced)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectReferences(arg, root, referenced)
+		}
+	case *parse.FieldNode:
+		if root && len(n.Ident) > 0 {
+			referenced[n.Ident[0]] = true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			referenced[n.Ident[1]] = true
+		}
+	case *parse.ChainNode:
+		collectReferences(n.Node, root, referenced)
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, root, root, referenced)
+	case *parse.RangeNode:
+		collectBranch(&n.BranchNode, false, root, referenced)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, false, root, referenced)
+	case *parse.TemplateNode:
+		collectReferences(n.Pipe, root, referenced)
+	}
+}
+
+// collectBranch collects the references of an if, range or with action.
+func collectBranch(branch *parse.BranchNode, bodyRoot, root bool, referenced map[string]bool) {
+	collectReferences(branch.Pipe, root, referenced)
+	collectReferences(branch.List, bodyRoot, referenced)
+	collectReferences(branch.ElseList, root, referenced)
+}
+
+// sortedKeys returns the keys of set in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
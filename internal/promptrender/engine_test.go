@@ -0,0 +1,150 @@
+package promptrender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func intPtr(value int) *int { return &value }
+
+func floatPtr(value float64) *float64 { return &value }
+
+// reviewVariables declares variables of every type with constraints.
+func reviewVariables() []schema.PromptVariable {
+	return []schema.PromptVariable{
+		{Name: "language", Type: "string", Description: "Language", Required: true, Enum: []string{"go", "rust"}},
+		{Name: "ticket", Type: "string", Description: "Ticket", Pattern: `^[A-Z]+-\d+$`},
+		{Name: "summary", Type: "string", Description: "Summary", MinLength: intPtr(3), MaxLength: intPtr(20)},
+		{Name: "depth", Type: "number", Description: "Depth", Default: 2, Minimum: floatPtr(1), Maximum: floatPtr(5)},
+		{Name: "strict", Type: "boolean", Description: "Strict"},
+		{Name: "files", Type: "array", Description: "Files"},
+	}
+}
+
+func TestRender_LegacyAndTemplateSyntax(t *testing.T) {
+	engine := New(nil, nil)
+
+	output, err := engine.Render("legacy", "Hello {{name}}, {{ .greeting }}!", map[string]any{"name": "Ada", "greeting": "welcome"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ada, welcome!", output)
+
+	output, err = engine.Render("keywords", "{{if .flag}}on{{else}}off{{end}}", map[string]any{"flag": false})
+	require.NoError(t, err)
+	assert.Equal(t, "off", output)
+}
+
+func TestRender_TypedVariables(t *testing.T) {
+	engine := New(reviewVariables(), nil)
+	template := `Review {{.language}} at depth {{.depth}}{{if .strict}} strictly{{end}}:
+{{range $i, $file := .files}}{{if $i}}, {{end}}{{$file}}{{end}}`
+
+	output, err := engine.Render("review", template, map[string]any{
+		"language": "go",
+		"strict":   "true",
+		"files":    `["main.go", "util.go"]`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Review go at depth 2 strictly:\nmain.go, util.go", output)
+
+	variables, err := engine.Variables(StringValues(map[string]string{"language": "rust", "depth": "4"}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), variables["depth"])
+	assert.Equal(t, false, variables["strict"])
+	assert.Equal(t, "", variables["ticket"])
+}
+
+func TestVariables_Violations(t *testing.T) {
+	engine := New(reviewVariables(), nil)
+
+	_, err := engine.Variables(map[string]any{
+		"ticket":  "abc",
+		"summary": "ok",
+		"depth":   9,
+		"strict":  "maybe",
+		"extra":   "x",
+	})
+	var variableErr *VariableError
+	require.ErrorAs(t, err, &variableErr)
+	assert.Equal(t, []string{
+		"missing required variable language",
+		"variable ticket must match ^[A-Z]+-\\d+$",
+		"variable summary must have at least 3 characters or items, got 2",
+		"variable depth must be at most 5, got 9",
+		`variable strict must be a boolean, got "maybe"`,
+		"unknown variable extra",
+	}, variableErr.Violations)
+	assert.Contains(t, err.Error(), "(and 1 more)")
+
+	_, err = engine.Variables(map[string]any{"language": "java"})
+	assert.ErrorContains(t, err, "variable language must be one of go, rust, got java")
+}
+
+func TestRender_Validation(t *testing.T) {
+	validation := &schema.PromptValidation{RequiredVars: []string{"topic"}, ForbiddenVars: []string{"password"}}
+	engine := New(nil, validation)
+
+	_, err := engine.Render("forbidden", "{{.topic}}", map[string]any{"topic": "x", "password": "secret"})
+	assert.ErrorContains(t, err, "variable password is forbidden")
+
+	_, err = engine.Render("missing", "{{.topic}}", map[string]any{})
+	assert.ErrorContains(t, err, "missing required variable topic")
+
+	_, err = engine.Render("uses", "{{.topic}} {{$.password}}", map[string]any{"topic": "x"})
+	assert.ErrorContains(t, err, "template uses forbidden variable password")
+}
+
+func TestRender_UndeclaredReferences(t *testing.T) {
+	engine := New([]schema.PromptVariable{{Name: "items", Type: "array", Description: "Items"}}, nil)
+
+	output, err := engine.Render("loop", "{{range .items}}{{.name}};{{end}}", map[string]any{
+		"items": []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a;b;", output)
+
+	_, err = engine.Render("typo", "{{range .itemz}}{{end}}", map[string]any{})
+	assert.ErrorContains(t, err, "template uses undeclared variable itemz")
+}
+
+func TestRender_Partials(t *testing.T) {
+	engine := New(nil, nil).
+		WithPartial("preamble", "Be safe, {{.name}}.").
+		WithPartial("list", "{{range .}}- {{.}}\n{{end}}")
+
+	output, err := engine.Render("main", `{{template "preamble" .}}
+{{include "list" .items | trim | upper}}`, map[string]any{"name": "Ada", "items": []any{"x", "y"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Be safe, Ada.\n- X\n- Y", output)
+
+	_, err = engine.Render("missing", `{{template "unknown" .}}`, nil)
+	assert.ErrorContains(t, err, "failed to render template missing")
+}
+
+func TestRender_MissingKey(t *testing.T) {
+	_, err := New(nil, nil).Render("strict", "Hello {{.name}}", map[string]any{})
+	assert.Error(t, err)
+
+	output, err := New(nil, nil).AllowMissing().Render("lenient", "Hello {{.name}}", map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello <no value>", output)
+}
+
+func TestRender_JoinTypedSlices(t *testing.T) {
+	engine := New(nil, nil)
+	values := map[string]any{
+		"files":  []string{"a.go", "b.go"},
+		"counts": []int{1, 2},
+		"mixed":  []any{"x", 3},
+	}
+
+	output, err := engine.Render("join", `{{join ", " .files}} {{join "+" .counts}} {{join "," .mixed}}`, values)
+	require.NoError(t, err)
+	assert.Equal(t, "a.go, b.go 1+2 x,3", output)
+
+	_, err = engine.Render("join", `{{join ", " .name}}`, map[string]any{"name": "a.go"})
+	assert.ErrorContains(t, err, "join expects a list")
+}
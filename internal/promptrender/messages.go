@@ -0,0 +1,70 @@
+package promptrender
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// RenderMessages renders prompt into the messages sent to a model. Conversation
+// prompts render every message with the variables, other prompts their template
+// or content as one system message for system prompts and a user message else.
+func RenderMessages(prompt *schema.Prompt, values map[string]any) ([]llms.MessageContent, error) {
+	engine := ForPrompt(prompt)
+	variables, err := engine.Variables(values)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(prompt.Spec.Messages) > 0 {
+		messages := make([]llms.MessageContent, 0, len(prompt.Spec.Messages))
+		for i, message := range prompt.Spec.Messages {
+			content, err := engine.Execute(fmt.Sprintf("message %d", i+1), message.Content, variables)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, llms.TextParts(MessageType(message.Role), content))
+		}
+		return messages, nil
+	}
+
+	text := prompt.Spec.Template
+	if text == "" {
+		text = prompt.Spec.Content
+	}
+	content, err := engine.Execute(prompt.Metadata.Name, text, variables)
+	if err != nil {
+		return nil, err
+	}
+	role := llms.ChatMessageTypeHuman
+	if prompt.Spec.Type == schema.PromptTypeSystem {
+		role = llms.ChatMessageTypeSystem
+	}
+	return []llms.MessageContent{llms.TextParts(role, content)}, nil
+}
+
+// MessageText returns the text parts of message.
+func MessageText(message llms.MessageContent) string {
+	var builder strings.Builder
+	for _, part := range message.Parts {
+		if text, ok := part.(llms.TextContent); ok {
+			builder.WriteString(text.Text)
+		}
+	}
+	return builder.String()
+}
+
+// MessageType maps a prompt message role to the langchaingo message type.
+func MessageType(role string) llms.ChatMessageType {
+	switch role {
+	case "system":
+		return llms.ChatMessageTypeSystem
+	case "assistant":
+		return llms.ChatMessageTypeAI
+	default:
+		return llms.ChatMessageTypeHuman
+	}
+}
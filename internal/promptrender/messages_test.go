@@ -0,0 +1,39 @@
+package promptrender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestRenderMessages(t *testing.T) {
+	prompt := newPrompt("few-shot", "1.0.0", "")
+	prompt.Spec.Type = schema.PromptTypeConversation
+	prompt.Spec.Messages = []schema.PromptMessage{
+		{Role: "system", Content: "You translate to {{language}}."},
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "{{if eq .language \"German\"}}Hallo{{else}}?{{end}}"},
+	}
+	prompt.Spec.Variables = []schema.PromptVariable{{Name: "language", Type: "string", Description: "Target", Required: true}}
+
+	messages, err := RenderMessages(prompt, map[string]any{"language": "German"})
+	require.NoError(t, err)
+	assert.Equal(t, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You translate to German."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "Hello"),
+		llms.TextParts(llms.ChatMessageTypeAI, "Hallo"),
+	}, messages)
+
+	_, err = RenderMessages(prompt, nil)
+	assert.ErrorContains(t, err, "missing required variable language")
+
+	system := newPrompt("system", "1.0.0", "Be brief.")
+	system.Spec.Type = schema.PromptTypeSystem
+	messages, err = RenderMessages(system, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeSystem, "Be brief.")}, messages)
+}
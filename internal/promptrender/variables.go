@@ -0,0 +1,269 @@
+package promptrender
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// maxReportedViolations limits the violations listed in a variable error.
+const maxReportedViolations = 5
+
+// VariableError lists the variables violating their declaration.
+type VariableError struct {
+	Violations []string
+}
+
+// Error implements the error interface.
+func (e *VariableError) Error() string {
+	violations := e.Violations
+	suffix := ""
+	if len(violations) > maxReportedViolations {
+		suffix = fmt.Sprintf(" (and %d more)", len(violations)-maxReportedViolations)
+		violations = violations[:maxReportedViolations]
+	}
+	return "invalid prompt variables: " + strings.Join(violations, "; ") + suffix
+}
+
+// StringValues converts string values, e.g. from the command line, for Variables.
+func StringValues(values map[string]string) map[string]any {
+	converted := make(map[string]any, len(values))
+	for name, value := range values {
+		converted[name] = value
+	}
+	return converted
+}
+
+// Variables completes values with the declared defaults, converts them to the
+// declared types and checks their constraints. Optional variables without a
+// value get the zero value of their type, so templates can test them.
+func (e *Engine) Variables(values map[string]any) (map[string]any, error) {
+	var violations []string
+	forbidden := e.forbidden()
+	required := make(map[string]bool)
+	if e.validation != nil {
+		for _, name := range e.validation.RequiredVars {
+			required[name] = true
+		}
+	}
+
+	declared := make(map[string]bool, len(e.variables))
+	variables := make(map[string]any, len(values)+len(e.variables))
+	for _, variable := range e.variables {
+		declared[variable.Name] = true
+
+		value, exists := values[variable.Name]
+		if !exists || value == nil {
+			value, exists = variable.Default, variable.Default != nil
+		}
+		if !exists {
+			if variable.Required || required[variable.Name] {
+				violations = append(violations, fmt.Sprintf("missing required variable %s", variable.Name))
+			}
+			variables[variable.Name] = zeroValue(variable.Type)
+			continue
+		}
+
+		converted, err := convert(variable, value)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("variable %s %s", variable.Name, err))
+			continue
+		}
+		violations = append(violations, check(variable, converted)...)
+		variables[variable.Name] = converted
+	}
+
+	for _, name := range sortedKeys(keySet(values)) {
+		switch {
+		case forbidden[name]:
+			violations = append(violations, fmt.Sprintf("variable %s is forbidden", name))
+		case len(declared) > 0 && !declared[name]:
+			violations = append(violations, fmt.Sprintf("unknown variable %s", name))
+		case !declared[name]:
+			variables[name] = values[name]
+		}
+	}
+	for _, name := range sortedKeys(required) {
+		if _, exists := variables[name]; !exists && !declared[name] {
+			violations = append(violations, fmt.Sprintf("missing required variable %s", name))
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, &VariableError{Violations: violations}
+	}
+	return variables, nil
+}
+
+// convert converts value to the declared type of variable. Strings are parsed
+// for non-string types, objects and arrays as JSON.
+func convert(variable schema.PromptVariable, value any) (any, error) {
+	text, isText := value.(string)
+	switch variable.Type {
+	case "string":
+		switch value.(type) {
+		case map[string]any, []any:
+			return nil, fmt.Errorf("must be a string")
+		}
+		return fmt.Sprint(value), nil
+	case "number":
+		if isText {
+			if number, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64); err == nil {
+				return number, nil
+			}
+			number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+			if err != nil {
+				return nil, fmt.Errorf("must be a number, got %q", text)
+			}
+			return number, nil
+		}
+		if _, ok := toFloat(value); !ok {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return value, nil
+	case "boolean":
+		if isText {
+			flag, err := strconv.ParseBool(strings.TrimSpace(text))
+			if err != nil {
+				return nil, fmt.Errorf("must be a boolean, got %q", text)
+			}
+			return flag, nil
+		}
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return value, nil
+	case "object":
+		if isText {
+			var object map[string]any
+			if err := json.Unmarshal([]byte(text), &object); err != nil {
+				return nil, fmt.Errorf("must be a JSON object: %w", err)
+			}
+			return object, nil
+		}
+		if reflect.ValueOf(value).Kind() != reflect.Map {
+			return nil, fmt.Errorf("must be an object")
+		}
+		return value, nil
+	case "array":
+		if isText {
+			var array []any
+			if err := json.Unmarshal([]byte(text), &array); err != nil {
+				return nil, fmt.Errorf("must be a JSON array: %w", err)
+			}
+			return array, nil
+		}
+		if kind := reflect.ValueOf(value).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return nil, fmt.Errorf("must be an array")
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// check returns the constraint violations of a converted value.
+func check(variable schema.PromptVariable, value any) []string {
+	var violations []string
+	fail := func(format string, args ...any) {
+		violations = append(violations, fmt.Sprintf("variable %s ", variable.Name)+fmt.Sprintf(format, args...))
+	}
+
+	if len(variable.Enum) > 0 {
+		allowed := false
+		for _, option := range variable.Enum {
+			if fmt.Sprint(value) == option {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			fail("must be one of %s, got %v", strings.Join(variable.Enum, ", "), value)
+		}
+	}
+
+	if text, ok := value.(string); ok && variable.Pattern != "" {
+		pattern, err := regexp.Compile(variable.Pattern)
+		if err != nil {
+			fail("has an invalid pattern: %v", err)
+		} else if !pattern.MatchString(text) {
+			fail("must match %s", variable.Pattern)
+		}
+	}
+
+	if length, ok := valueLength(value); ok {
+		if variable.MinLength != nil && length < *variable.MinLength {
+			fail("must have at least %d characters or items, got %d", *variable.MinLength, length)
+		}
+		if variable.MaxLength != nil && length > *variable.MaxLength {
+			fail("must have at most %d characters or items, got %d", *variable.MaxLength, length)
+		}
+	}
+
+	if number, ok := toFloat(value); ok {
+		if variable.Minimum != nil && number < *variable.Minimum {
+			fail("must be at least %v, got %v", *variable.Minimum, value)
+		}
+		if variable.Maximum != nil && number > *variable.Maximum {
+			fail("must be at most %v, got %v", *variable.Maximum, value)
+		}
+	}
+	return violations
+}
+
+// valueLength returns the character count of strings and the length of arrays.
+func valueLength(value any) (int, bool) {
+	if text, ok := value.(string); ok {
+		return utf8.RuneCountInString(text), true
+	}
+	if reflected := reflect.ValueOf(value); reflected.Kind() == reflect.Slice || reflected.Kind() == reflect.Array {
+		return reflected.Len(), true
+	}
+	return 0, false
+}
+
+// toFloat converts numeric values to float64.
+func toFloat(value any) (float64, bool) {
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// zeroValue returns the empty value of a declared type.
+func zeroValue(kind string) any {
+	switch kind {
+	case "number":
+		return 0
+	case "boolean":
+		return false
+	case "object":
+		return map[string]any{}
+	case "array":
+		return []any{}
+	default:
+		return ""
+	}
+}
+
+// keySet returns the keys of values.
+func keySet(values map[string]any) map[string]bool {
+	keys := make(map[string]bool, len(values))
+	for key := range values {
+		keys[key] = true
+	}
+	return keys
+}
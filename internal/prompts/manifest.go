@@ -1,8 +1,25 @@
 package prompts
 
-// PromptData contains all the data needed to generate prompt files
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// languageLabel is the manifest label holding the prompt language.
+const languageLabel = "language"
+
+// PromptData is the editable view of a prompt manifest. Edits of the view are
+// applied to the manifest on save, everything else in it is kept as is.
 type PromptData struct {
 	Name        string
+	Version     string
 	Description string
 	Author      string
 	License     string
@@ -10,37 +27,199 @@ type PromptData struct {
 	Language    string
 	Template    string
 	Variables   []string
+	// Manifest is the prompt manifest the view was created from
+	Manifest *schema.Prompt
 }
 
-// ManifestGenerator handles prompt manifest file generation (legacy interface)
-type ManifestGenerator interface {
-	CreateComponentYAML(promptDir string, data PromptData) error
-	CreateTemplateFile(promptDir string, template string) error
-	CreateVariablesFile(promptDir string, variables []string) error
-	CreateReadmeFile(promptDir string, data PromptData) error
+// NewPromptData returns the editable view of manifest.
+func NewPromptData(manifest *schema.Prompt) *PromptData {
+	data := &PromptData{
+		Name:        manifest.Metadata.Name,
+		Version:     manifest.Metadata.Version,
+		Description: manifest.Metadata.Description,
+		Author:      manifest.Metadata.Author,
+		License:     manifest.Metadata.License,
+		PromptType:  string(manifest.Spec.Type),
+		Language:    manifest.Metadata.Labels[languageLabel],
+		Template:    manifest.Spec.Template,
+		Manifest:    manifest,
+	}
+	if data.Template == "" {
+		data.Template = manifest.Spec.Content
+	}
+	for _, variable := range manifest.Spec.Variables {
+		data.Variables = append(data.Variables, variable.Name)
+	}
+	return data
 }
 
-// manifestGenerator implements ManifestGenerator (empty implementation)
-type manifestGenerator struct{}
+// ToManifest returns a copy of the manifest with the edits of the view applied.
+// Declarations of kept variables are preserved, new variables are required strings.
+func (d *PromptData) ToManifest() *schema.Prompt {
+	manifest := schema.NewPrompt(d.Name, d.Version)
+	if d.Manifest != nil {
+		copied := *d.Manifest
+		manifest = &copied
+		manifest.Metadata.Name = d.Name
+		if d.Version != "" {
+			manifest.Metadata.Version = d.Version
+		}
+	}
 
-// NewManifestGenerator creates a new manifest generator
-func NewManifestGenerator() ManifestGenerator {
-	return &manifestGenerator{}
+	manifest.Metadata.Description = d.Description
+	manifest.Metadata.Author = d.Author
+	manifest.Metadata.License = d.License
+	labels := make(map[string]string, len(manifest.Metadata.Labels)+1)
+	for key, value := range manifest.Metadata.Labels {
+		labels[key] = value
+	}
+	delete(labels, languageLabel)
+	if d.Language != "" {
+		labels[languageLabel] = d.Language
+	}
+	manifest.Metadata.Labels = nil
+	if len(labels) > 0 {
+		manifest.Metadata.Labels = labels
+	}
+
+	if d.PromptType != "" {
+		manifest.Spec.Type = schema.PromptType(d.PromptType)
+	}
+	if manifest.Spec.Format == "" {
+		manifest.Spec.Format = schema.FormatText
+	}
+	// Conversation prompts have no template unless one was edited
+	switch {
+	case manifest.Spec.Template == "" && manifest.Spec.Content != "":
+		manifest.Spec.Content = d.Template
+	case d.Template != "" || len(manifest.Spec.Messages) == 0:
+		manifest.Spec.Template = d.Template
+	}
+
+	variables := make([]schema.PromptVariable, 0, len(d.Variables))
+	for _, name := range d.Variables {
+		if declared := manifest.GetVariableByName(name); declared != nil {
+			variables = append(variables, *declared)
+			continue
+		}
+		variables = append(variables, schema.PromptVariable{
+			Name:        name,
+			Type:        "string",
+			Description: fmt.Sprintf("Description for %s", name),
+			Required:    true,
+		})
+	}
+	manifest.Spec.Variables = variables
+	return manifest
 }
 
-// Legacy methods - empty implementations
-func (mg *manifestGenerator) CreateComponentYAML(promptDir string, data PromptData) error {
-	return nil
+// LoadManifest reads and validates the prompt manifest in promptDir.
+func LoadManifest(promptDir string) (*schema.Prompt, error) {
+	content, err := os.ReadFile(filepath.Join(promptDir, schema.ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", schema.ManifestFileName, err)
+	}
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", schema.ManifestFileName, err)
+	}
+	prompt, ok := component.(*schema.Prompt)
+	if !ok {
+		return nil, fmt.Errorf("%s in %s is not a Prompt", schema.ManifestFileName, promptDir)
+	}
+	return prompt, nil
 }
 
-func (mg *manifestGenerator) CreateTemplateFile(promptDir string, template string) error {
+// SaveManifest validates prompt and writes it as manifest into promptDir.
+func SaveManifest(promptDir string, prompt *schema.Prompt) error {
+	if err := prompt.Validate(); err != nil {
+		return fmt.Errorf("invalid prompt manifest: %w", err)
+	}
+	content, err := schema.NewComponentParser().SerializeComponent(prompt)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(promptDir, 0755); err != nil {
+		return fmt.Errorf("failed to create prompt directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(promptDir, schema.ManifestFileName), content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", schema.ManifestFileName, err)
+	}
 	return nil
 }
 
-func (mg *manifestGenerator) CreateVariablesFile(promptDir string, variables []string) error {
-	return nil
+// legacyVariables is the variables.json of prompts created before manifests.
+type legacyVariables struct {
+	Variables map[string]struct {
+		Type        string      `json:"type"`
+		Description string      `json:"description"`
+		Required    bool        `json:"required"`
+		Default     interface{} `json:"default"`
+	} `json:"variables"`
 }
 
-func (mg *manifestGenerator) CreateReadmeFile(promptDir string, data PromptData) error {
-	return nil
-}
\ No newline at end of file
+// loadLegacyManifest builds a manifest from the template.txt and variables.json
+// of prompts created before manifests. Saving it migrates the prompt.
+func loadLegacyManifest(promptDir, name string) (*schema.Prompt, error) {
+	template, err := os.ReadFile(filepath.Join(promptDir, "template.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template.txt: %w", err)
+	}
+
+	manifest := schema.NewPrompt(name, "1.0.0")
+	manifest.Metadata.Description = fmt.Sprintf("AI prompt for %s", name)
+	manifest.Metadata.License = "MIT"
+	manifest.Metadata.ForgeVersion = "0.1.0"
+	manifest.Spec.Type = schema.PromptTypeTemplate
+	manifest.Spec.Format = schema.FormatText
+	manifest.Spec.Template = string(template)
+
+	// The component.yaml of legacy prompts has usable metadata but no valid spec
+	var legacyComponent schema.BaseComponent
+	if content, err := os.ReadFile(filepath.Join(promptDir, schema.ManifestFileName)); err == nil &&
+		yaml.Unmarshal(content, &legacyComponent) == nil {
+		metadata := legacyComponent.Metadata
+		if metadata.Version != "" {
+			manifest.Metadata.Version = metadata.Version
+		}
+		if metadata.Description != "" {
+			manifest.Metadata.Description = metadata.Description
+		}
+		if metadata.License != "" {
+			manifest.Metadata.License = metadata.License
+		}
+		manifest.Metadata.Author = metadata.Author
+	}
+
+	content, err := os.ReadFile(filepath.Join(promptDir, "variables.json"))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variables.json: %w", err)
+	}
+	var legacy legacyVariables
+	if err := json.Unmarshal(content, &legacy); err != nil {
+		return nil, fmt.Errorf("failed to parse variables.json: %w", err)
+	}
+
+	names := make([]string, 0, len(legacy.Variables))
+	for name := range legacy.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		variable := legacy.Variables[name]
+		if variable.Type == "" {
+			variable.Type = "string"
+		}
+		manifest.Spec.Variables = append(manifest.Spec.Variables, schema.PromptVariable{
+			Name:        name,
+			Type:        variable.Type,
+			Description: variable.Description,
+			Required:    variable.Required,
+			Default:     variable.Default,
+		})
+	}
+	return manifest, nil
+}
@@ -0,0 +1,157 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// newTestManifest creates a prompt using everything the editable view does not cover.
+func newTestManifest() *schema.Prompt {
+	maxLength := 2000
+	prompt := newTestPrompt("reviewer", "1.2.0", "Review {{.file}} in {{.language}}.")
+	prompt.Metadata.Labels = map[string]string{languageLabel: "en", "team": "platform"}
+	prompt.Metadata.Tags = []string{"review"}
+	prompt.Spec.Variables = []schema.PromptVariable{
+		{Name: "file", Type: "string", Description: "File to review", Required: true, MaxLength: &maxLength},
+		{Name: "language", Type: "string", Description: "Language of the file", Default: "Go", Enum: []string{"Go", "Rust"}},
+	}
+	prompt.Spec.OutputSchema = map[string]any{"type": "object"}
+	return prompt
+}
+
+func TestPromptData_RoundTrip(t *testing.T) {
+	manifest := newTestManifest()
+
+	data := NewPromptData(manifest)
+	assert.Equal(t, "reviewer", data.Name)
+	assert.Equal(t, "1.2.0", data.Version)
+	assert.Equal(t, "template", data.PromptType)
+	assert.Equal(t, "en", data.Language)
+	assert.Equal(t, []string{"file", "language"}, data.Variables)
+	assert.Same(t, manifest, data.Manifest)
+
+	assert.Equal(t, newTestManifest(), data.ToManifest(), "an unedited view returns the manifest unchanged")
+
+	dir := t.TempDir()
+	require.NoError(t, SaveManifest(dir, data.ToManifest()))
+	loaded, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, newTestManifest(), loaded, "the manifest survives a save")
+}
+
+func TestPromptData_ToManifestAppliesEdits(t *testing.T) {
+	manifest := newTestManifest()
+	data := NewPromptData(manifest)
+	data.Description = "Reviews a single file"
+	data.Language = ""
+	data.Template = "Review {{.file}} for {{.focus}}."
+	data.Variables = []string{"file", "focus"}
+
+	edited := data.ToManifest()
+	assert.Equal(t, "Reviews a single file", edited.Metadata.Description)
+	assert.Equal(t, map[string]string{"team": "platform"}, edited.Metadata.Labels)
+	assert.Equal(t, "Review {{.file}} for {{.focus}}.", edited.Spec.Template)
+	require.Len(t, edited.Spec.Variables, 2)
+	assert.Equal(t, manifest.Spec.Variables[0], edited.Spec.Variables[0], "declarations of kept variables are preserved")
+	assert.Equal(t, schema.PromptVariable{Name: "focus", Type: "string", Description: "Description for focus", Required: true},
+		edited.Spec.Variables[1])
+	assert.Equal(t, newTestManifest(), manifest, "the manifest of the view is not modified")
+	require.NoError(t, edited.Validate())
+}
+
+func TestPromptData_ToManifestKeepsConversations(t *testing.T) {
+	manifest := newTestPrompt("translator", "1.0.0", "")
+	manifest.Spec.Type = schema.PromptTypeConversation
+	manifest.Spec.Messages = []schema.PromptMessage{{Role: "system", Content: "Translate."}}
+
+	edited := NewPromptData(manifest).ToManifest()
+	assert.Empty(t, edited.Spec.Template, "conversation prompts get no template")
+	assert.Equal(t, manifest.Spec.Messages, edited.Spec.Messages)
+
+	data := NewPromptData(schema.NewPrompt("fresh", ""))
+	data.Template = "Hello"
+	created := data.ToManifest()
+	assert.Equal(t, "Hello", created.Spec.Template)
+	assert.Equal(t, schema.FormatText, created.Spec.Format)
+	assert.Nil(t, created.Metadata.Labels)
+}
+
+// writeLegacyPrompt writes a prompt in the layout used before manifests.
+func writeLegacyPrompt(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+func TestLoadLegacyManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeLegacyPrompt(t, dir, map[string]string{
+		"template.txt": "Summarize {{.text}} in {{.words}} words.",
+		"variables.json": `{"variables": {
+			"words": {"type": "number", "description": "Summary length", "default": 50},
+			"text": {"description": "Text to summarize", "required": true}
+		}}`,
+		schema.ManifestFileName: "apiVersion: forge.dev/v1\nkind: Prompt\nmetadata:\n  name: summarizer\n  version: 0.3.0\n  author: Ada\nspec:\n  template: old\n",
+	})
+
+	manifest, err := loadLegacyManifest(dir, "summarizer")
+	require.NoError(t, err)
+	assert.Equal(t, "summarizer", manifest.Metadata.Name)
+	assert.Equal(t, "0.3.0", manifest.Metadata.Version, "metadata of the legacy component.yaml is kept")
+	assert.Equal(t, "Ada", manifest.Metadata.Author)
+	assert.Equal(t, "AI prompt for summarizer", manifest.Metadata.Description)
+	assert.Equal(t, "Summarize {{.text}} in {{.words}} words.", manifest.Spec.Template)
+	assert.Equal(t, []schema.PromptVariable{
+		{Name: "text", Type: "string", Description: "Text to summarize", Required: true},
+		{Name: "words", Type: "number", Description: "Summary length", Default: float64(50)},
+	}, manifest.Spec.Variables, "variables are sorted by name and default to strings")
+	require.NoError(t, manifest.Validate())
+
+	t.Run("template only", func(t *testing.T) {
+		dir := t.TempDir()
+		writeLegacyPrompt(t, dir, map[string]string{"template.txt": "Hello"})
+		manifest, err := loadLegacyManifest(dir, "greeting")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", manifest.Metadata.Version)
+		assert.Empty(t, manifest.Spec.Variables)
+	})
+
+	t.Run("missing template", func(t *testing.T) {
+		_, err := loadLegacyManifest(t.TempDir(), "greeting")
+		assert.ErrorContains(t, err, "failed to read template.txt")
+	})
+
+	t.Run("invalid variables", func(t *testing.T) {
+		dir := t.TempDir()
+		writeLegacyPrompt(t, dir, map[string]string{"template.txt": "Hello", "variables.json": "{"})
+		_, err := loadLegacyManifest(dir, "greeting")
+		assert.ErrorContains(t, err, "failed to parse variables.json")
+	})
+}
+
+func TestLoadPromptData_MigratesLegacyPrompts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeLegacyPrompt(t, filepath.Join("prompts", "greeting"), map[string]string{
+		"template.txt":   "Hello {{.name}}",
+		"variables.json": `{"variables": {"name": {"description": "Name to greet", "required": true}}}`,
+	})
+
+	service := NewPromptService(nil, nil, nil, "", "")
+	data, err := service.LoadPromptData("greeting")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello {{.name}}", data.Template)
+	assert.Equal(t, []string{"name"}, data.Variables)
+
+	require.NoError(t, service.SavePromptData("greeting", data))
+	manifest, err := LoadManifest(filepath.Join("prompts", "greeting"))
+	require.NoError(t, err, "saving writes a manifest")
+	assert.Equal(t, "Name to greet", manifest.Spec.Variables[0].Description)
+}
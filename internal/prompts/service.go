@@ -1,11 +1,16 @@
 package prompts
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
 	"github.com/denkhaus/agentforge/internal/templates"
 	"go.uber.org/zap"
 )
@@ -17,22 +22,37 @@ type PromptService interface {
 	SavePromptData(name string, data *PromptData) error
 	ValidatePromptName(name string) error
 	ListLocalPrompts() ([]*PromptData, error)
-	PullPrompt(repo, version string, force bool) error
-	PushPrompt(name, repo, message, tag string) error
+	PullPrompt(ctx context.Context, repo, version string, force bool) (*schema.Prompt, error)
+	PushPrompt(ctx context.Context, name, repo, message, tag string) (string, error)
 	ExecutePrompt(name string, variables map[string]string) (string, error)
+	ComposePrompt(manifestPath string, prompt *schema.Prompt) (*schema.Prompt, error)
 }
 
 // promptService implements PromptService interface
 type promptService struct {
-	manifestGenerator ManifestGenerator
 	templateGenerator templates.PromptTemplateGenerator
+	git               git.GitClient
+	syncs             database.SyncService
+	repositories      database.RepositoryService
+	installedDir      string
+	token             string
 }
 
-// NewPromptService creates a new prompt service
-func NewPromptService() PromptService {
+// NewPromptService creates a new prompt service. Pulls and pushes use gitClient
+// and are recorded with syncService, which may be nil. They authenticate with the
+// access token of the repository from repositories, or with token for repositories
+// without one. Pulled prompts are installed into installedDir. Prompts are
+// loaded from the local prompts directory or installedDir and composed with the
+// prompts of both.
+func NewPromptService(gitClient git.GitClient, syncService database.SyncService, repositories database.RepositoryService,
+	installedDir, token string) PromptService {
 	return &promptService{
-		manifestGenerator: NewManifestGenerator(),
 		templateGenerator: templates.NewPromptTemplateGenerator(),
+		git:               gitClient,
+		syncs:             syncService,
+		repositories:      repositories,
+		installedDir:      installedDir,
+		token:             token,
 	}
 }
 
@@ -54,10 +74,11 @@ func (ps *promptService) CreatePromptStructure(name string) error {
 	// Create default prompt data
 	defaultData := &PromptData{
 		Name:        name,
+		Version:     "1.0.0",
 		Description: fmt.Sprintf("AI prompt for %s", name),
 		Author:      "Your Name <your.email@example.com>",
 		License:     "MIT",
-		PromptType:  "TEMPLATE",
+		PromptType:  string(schema.PromptTypeTemplate),
 		Language:    "en",
 		Template:    fmt.Sprintf("# %s\n\nYou are an AI assistant. Please help with:\n\n{{task}}\n\nProvide a detailed and helpful response.", name),
 		Variables:   []string{"task"},
@@ -66,6 +87,7 @@ func (ps *promptService) CreatePromptStructure(name string) error {
 	// Convert PromptData to PromptTemplateData and use template generator
 	templateData := templates.PromptTemplateData{
 		Name:         defaultData.Name,
+		Version:      defaultData.Version,
 		DisplayName:  strings.Title(strings.ReplaceAll(defaultData.Name, "-", " ")),
 		Description:  defaultData.Description,
 		Author:       defaultData.Author,
@@ -75,6 +97,7 @@ func (ps *promptService) CreatePromptStructure(name string) error {
 		Instructions: "Please analyze the following input and provide a comprehensive response.",
 		OutputFormat: "Provide a structured and detailed response.",
 		PrimaryVariable: "task",
+		Template:        defaultData.Template,
 	}
 	
 	// Convert variables
@@ -107,62 +130,55 @@ func (ps *promptService) CreatePromptStructure(name string) error {
 	return nil
 }
 
-// LoadPromptData loads existing prompt data from filesystem
+// promptDir returns the directory of a prompt. Prompts of the local prompts
+// directory take precedence over installed prompts of the same name.
+func (ps *promptService) promptDir(name string) string {
+	local := filepath.Join("prompts", name)
+	if _, err := os.Stat(local); err == nil || ps.installedDir == "" {
+		return local
+	}
+	installed := filepath.Join(ps.installedDir, name)
+	if _, err := os.Stat(installed); err == nil {
+		return installed
+	}
+	return local
+}
+
+// LoadPromptData loads the prompt manifest from filesystem. Prompts without a
+// valid manifest are loaded from their legacy template.txt and variables.json.
 func (ps *promptService) LoadPromptData(name string) (*PromptData, error) {
 	log.Info("Loading prompt data", zap.String("name", name))
 
-	promptDir := filepath.Join("prompts", name)
+	promptDir := ps.promptDir(name)
 
 	// Check if prompt exists
 	if _, err := os.Stat(promptDir); os.IsNotExist(err) {
 		return nil, fmt.Errorf("prompt '%s' does not exist", name)
 	}
 
-	// Load template content
-	templatePath := filepath.Join(promptDir, "template.txt")
-	templateContent, err := os.ReadFile(templatePath)
+	manifest, err := LoadManifest(promptDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read template.txt: %w", err)
-	}
-
-	// TODO: Parse component.yaml and variables.json for complete data
-	// For now, return basic data structure
-	data := &PromptData{
-		Name:        name,
-		Description: fmt.Sprintf("AI prompt for %s", name),
-		Author:      "Your Name <your.email@example.com>",
-		License:     "MIT",
-		PromptType:  "TEMPLATE",
-		Language:    "en",
-		Template:    string(templateContent),
-		Variables:   []string{}, // TODO: Parse from variables.json
+		legacy, legacyErr := loadLegacyManifest(promptDir, name)
+		if legacyErr != nil {
+			return nil, fmt.Errorf("failed to load prompt '%s': %w", name, err)
+		}
+		log.Warn("Loaded legacy prompt, saving it writes a manifest",
+			zap.String("name", name), zap.Error(err))
+		manifest = legacy
 	}
 
-	return data, nil
+	return NewPromptData(manifest), nil
 }
 
-// SavePromptData saves prompt data to filesystem
+// SavePromptData applies the edits of data to its manifest and writes it to filesystem
 func (ps *promptService) SavePromptData(name string, data *PromptData) error {
 	log.Info("Saving prompt data", zap.String("name", name))
 
-	promptDir := filepath.Join("prompts", name)
-
-	// Update all files
-	if err := ps.manifestGenerator.CreateComponentYAML(promptDir, *data); err != nil {
-		return fmt.Errorf("failed to update component.yaml: %w", err)
-	}
-
-	if err := ps.manifestGenerator.CreateTemplateFile(promptDir, data.Template); err != nil {
-		return fmt.Errorf("failed to update template.txt: %w", err)
-	}
-
-	if err := ps.manifestGenerator.CreateVariablesFile(promptDir, data.Variables); err != nil {
-		return fmt.Errorf("failed to update variables.json: %w", err)
-	}
-
-	if err := ps.manifestGenerator.CreateReadmeFile(promptDir, *data); err != nil {
-		return fmt.Errorf("failed to update README.md: %w", err)
+	manifest := data.ToManifest()
+	if err := SaveManifest(filepath.Join("prompts", name), manifest); err != nil {
+		return fmt.Errorf("failed to save prompt '%s': %w", name, err)
 	}
+	data.Manifest = manifest
 
 	log.Info("Prompt data saved successfully", zap.String("name", name))
 	return nil
@@ -209,7 +225,8 @@ func (ps *promptService) ListLocalPrompts() ([]*PromptData, error) {
 
 	var prompts []*PromptData
 	for _, entry := range entries {
-		if entry.IsDir() {
+		// Hidden directories are staged installs
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 			// Try to load prompt data
 			if data, err := ps.LoadPromptData(entry.Name()); err == nil {
 				prompts = append(prompts, data)
@@ -221,32 +238,8 @@ func (ps *promptService) ListLocalPrompts() ([]*PromptData, error) {
 	return prompts, nil
 }
 
-// PullPrompt pulls a prompt from a remote repository
-func (ps *promptService) PullPrompt(repo, version string, force bool) error {
-	log.Info("Pulling prompt",
-		zap.String("repo", repo),
-		zap.String("version", version),
-		zap.Bool("force", force))
-
-	// TODO: Implement actual Git pulling logic
-	// For now, this is a placeholder
-	return fmt.Errorf("pull functionality not yet implemented")
-}
-
-// PushPrompt pushes a prompt to a remote repository
-func (ps *promptService) PushPrompt(name, repo, message, tag string) error {
-	log.Info("Pushing prompt",
-		zap.String("name", name),
-		zap.String("repo", repo),
-		zap.String("message", message),
-		zap.String("tag", tag))
-
-	// TODO: Implement actual Git pushing logic
-	// For now, this is a placeholder
-	return fmt.Errorf("push functionality not yet implemented")
-}
-
-// ExecutePrompt executes a prompt with given variables
+// ExecutePrompt composes a prompt with its includes and base prompt and renders
+// it with the given variables. The messages of conversation prompts are joined.
 func (ps *promptService) ExecutePrompt(name string, variables map[string]string) (string, error) {
 	log.Info("Executing prompt",
 		zap.String("name", name),
@@ -258,13 +251,34 @@ func (ps *promptService) ExecutePrompt(name string, variables map[string]string)
 		return "", err
 	}
 
-	// Replace variables in template
-	result := data.Template
-	for key, value := range variables {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-		result = strings.ReplaceAll(result, placeholder, value)
+	composed, err := ps.ComposePrompt(filepath.Join(ps.promptDir(name), schema.ManifestFileName), data.Manifest)
+	if err != nil {
+		return "", err
+	}
+	messages, err := promptrender.RenderMessages(composed, promptrender.StringValues(variables))
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt '%s': %w", name, err)
+	}
+	texts := make([]string, len(messages))
+	for i, message := range messages {
+		texts[i] = promptrender.MessageText(message)
 	}
 
 	log.Info("Prompt executed successfully", zap.String("name", name))
-	return result, nil
+	return strings.Join(texts, "\n\n"), nil
+}
+
+// ComposePrompt resolves the includes and base prompt of the prompt whose
+// manifest is at manifestPath. Dependencies are found next to the prompt, in
+// the local prompts directory and in the installed prompts, in this order.
+func (ps *promptService) ComposePrompt(manifestPath string, prompt *schema.Prompt) (*schema.Prompt, error) {
+	dirs := []string{filepath.Dir(filepath.Dir(manifestPath)), "prompts"}
+	if ps.installedDir != "" {
+		dirs = append(dirs, ps.installedDir)
+	}
+	composed, err := promptrender.NewResolver(dirs...).Compose(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose prompt '%s': %w", prompt.Metadata.Name, err)
+	}
+	return composed, nil
 }
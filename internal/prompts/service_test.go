@@ -0,0 +1,59 @@
+package prompts
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// newTestPrompt creates a valid template prompt.
+func newTestPrompt(name, version, template string, dependencies ...schema.PromptDependency) *schema.Prompt {
+	prompt := schema.NewPrompt(name, version)
+	prompt.Metadata.Description = "Prompt " + name + " for service tests"
+	prompt.Metadata.Author = "Test"
+	prompt.Metadata.License = "MIT"
+	prompt.Metadata.ForgeVersion = ">=0.1.0"
+	prompt.Spec.Type = schema.PromptTypeTemplate
+	prompt.Spec.Format = schema.FormatText
+	prompt.Spec.Template = template
+	prompt.Spec.Dependencies = dependencies
+	return prompt
+}
+
+func TestExecutePrompt_ComposesIncludes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	installed := t.TempDir()
+	require.NoError(t, SaveManifest(filepath.Join(installed, "safety"), newTestPrompt("safety", "1.2.0", "Never share secrets.")))
+	require.NoError(t, SaveManifest(filepath.Join("prompts", "reviewer"), newTestPrompt("reviewer", "1.0.0",
+		`{{template "safety" .}} Review {{.file}}.`, schema.PromptDependency{Name: "safety", Version: "^1.0.0"})))
+
+	service := NewPromptService(nil, nil, nil, installed, "")
+	text, err := service.ExecutePrompt("reviewer", map[string]string{"file": "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, "Never share secrets. Review main.go.", text)
+
+	// Without the installed prompts the include cannot be resolved
+	_, err = NewPromptService(nil, nil, nil, "", "").ExecutePrompt("reviewer", map[string]string{"file": "main.go"})
+	assert.ErrorContains(t, err, "prompt safety ^1.0.0 not found")
+}
+
+func TestComposePrompt_FindsSiblingPrompts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	workspace := t.TempDir()
+	require.NoError(t, SaveManifest(filepath.Join(workspace, "safety"), newTestPrompt("safety", "2.0.0", "Sibling safety.")))
+	require.NoError(t, SaveManifest(filepath.Join("prompts", "safety"), newTestPrompt("safety", "1.0.0", "Local safety.")))
+
+	prompt := newTestPrompt("reviewer", "1.0.0", `{{template "safety" .}}`, schema.PromptDependency{Name: "safety"})
+	composed, err := NewPromptService(nil, nil, nil, "", "").ComposePrompt(filepath.Join(workspace, "reviewer", schema.ManifestFileName), prompt)
+	require.NoError(t, err)
+	assert.Contains(t, composed.Spec.Template, "Sibling safety.", "the highest version matching the range is used")
+
+	unchanged := newTestPrompt("plain", "1.0.0", "Hello")
+	composed, err = NewPromptService(nil, nil, nil, "", "").ComposePrompt(filepath.Join("prompts", "plain", schema.ManifestFileName), unchanged)
+	require.NoError(t, err)
+	assert.Same(t, unchanged, composed, "prompts without dependencies are returned unchanged")
+}
@@ -0,0 +1,216 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	gogit "github.com/go-git/go-git/v5"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/database"
+	"github.com/denkhaus/agentforge/internal/database/ent/syncoperation"
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// PullPrompt clones repo at the version tag or branch and installs the prompt
+// it contains into the installed prompts directory. An installed prompt is
+// replaced with force only.
+func (ps *promptService) PullPrompt(ctx context.Context, repo, version string, force bool) (*schema.Prompt, error) {
+	if ps.git == nil {
+		return nil, fmt.Errorf("git client not available")
+	}
+	if ps.installedDir == "" {
+		return nil, fmt.Errorf("installed prompts directory not configured")
+	}
+	url := git.RepositoryURL(repo)
+	log.Info("Pulling prompt", zap.String("url", url), zap.String("version", version))
+
+	operation := database.StartSync(ctx, ps.syncs, syncoperation.TypePULL, url, version, "")
+	commit, prompt, err := ps.pull(ctx, url, version, force)
+	database.CompleteSync(ctx, ps.syncs, operation, commit, err)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Prompt pulled",
+		zap.String("name", prompt.Metadata.Name),
+		zap.String("version", prompt.Metadata.Version),
+		zap.String("commit", commit))
+	return prompt, nil
+}
+
+// PushPrompt commits the local prompt, tags it with its manifest version
+// unless tag is given and pushes it to repo. It returns the created tag.
+func (ps *promptService) PushPrompt(ctx context.Context, name, repo, message, tag string) (string, error) {
+	if ps.git == nil {
+		return "", fmt.Errorf("git client not available")
+	}
+	dir := filepath.Join("prompts", name)
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to load prompt '%s': %w", name, err)
+	}
+	if tag == "" {
+		tag, err = versionTag(manifest.Metadata.Version)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	url := git.RepositoryURL(repo)
+	log.Info("Pushing prompt",
+		zap.String("name", name),
+		zap.String("url", url),
+		zap.String("tag", tag))
+
+	source, _ := ps.git.HeadCommit(ctx, dir)
+	operation := database.StartSync(ctx, ps.syncs, syncoperation.TypePUSH, url, "", source)
+	commit, err := ps.push(ctx, dir, url, message, tag, manifest)
+	database.CompleteSync(ctx, ps.syncs, operation, commit, err)
+	if err != nil {
+		return "", err
+	}
+	return tag, nil
+}
+
+// pull clones url into a temporary directory and installs its prompt.
+func (ps *promptService) pull(ctx context.Context, url, version string, force bool) (string, *schema.Prompt, error) {
+	tmpDir, err := os.MkdirTemp("", "forge-prompt-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	checkout := filepath.Join(tmpDir, "repo")
+	if err := ps.clone(ctx, url, version, checkout); err != nil {
+		return "", nil, err
+	}
+	commit, err := ps.git.HeadCommit(ctx, checkout)
+	if err != nil {
+		return "", nil, err
+	}
+
+	promptDir, err := findPromptManifest(checkout)
+	if err != nil {
+		return commit, nil, fmt.Errorf("failed to locate prompt in %s: %w", url, err)
+	}
+	prompt, err := LoadManifest(promptDir)
+	if err != nil {
+		return commit, nil, err
+	}
+	// The name comes from the remote manifest and must stay inside the prompts directory
+	if err := schema.ValidateName(prompt.Metadata.Name); err != nil {
+		return commit, nil, err
+	}
+	if err := installPrompt(promptDir, filepath.Join(ps.installedDir, prompt.Metadata.Name), force); err != nil {
+		return commit, nil, err
+	}
+	return commit, prompt, nil
+}
+
+// clone checks out version as tag, as v-prefixed tag or as branch. Without
+// a version the default branch is checked out.
+func (ps *promptService) clone(ctx context.Context, url, version, destination string) error {
+	token := database.RemoteAccessToken(ctx, ps.repositories, url, ps.token)
+	_, err := git.CloneVersion(ctx, ps.git, git.CloneOptions{URL: url, Destination: destination, Depth: 1, Token: token}, version)
+	return err
+}
+
+// push commits dir, tags it and pushes branch and tags to url. It returns the pushed commit.
+func (ps *promptService) push(ctx context.Context, dir, url, message, tag string, manifest *schema.Prompt) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := ps.git.InitRepository(ctx, dir); err != nil {
+			return "", err
+		}
+	}
+	if err := ps.git.SetRemote(ctx, dir, "origin", url); err != nil {
+		return "", err
+	}
+	if err := ps.git.AddAndCommit(ctx, dir, message); err != nil && !errors.Is(err, gogit.ErrEmptyCommit) {
+		return "", err
+	}
+
+	err := ps.git.CreateTag(ctx, dir, tag, fmt.Sprintf("Release %s %s", manifest.Metadata.Name, tag))
+	if errors.Is(err, gogit.ErrTagExists) {
+		return "", fmt.Errorf("tag %s already exists, bump metadata.version to publish changes: %w", tag, err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := ps.git.HeadCommit(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	token := database.RemoteAccessToken(ctx, ps.repositories, url, ps.token)
+	if err := ps.git.Push(ctx, dir, git.PushOptions{Token: token, Tags: true}); err != nil {
+		return commit, err
+	}
+	return commit, nil
+}
+
+// versionTag returns the git tag of a semantic manifest version.
+func versionTag(version string) (string, error) {
+	parsed, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return "", fmt.Errorf("prompt version %q is not a semantic version: %w", version, err)
+	}
+	return "v" + parsed.String(), nil
+}
+
+// findPromptManifest returns the directory of the single Prompt manifest below root.
+func findPromptManifest(root string) (string, error) {
+	var found []string
+	var invalid error
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || entry.Name() != schema.ManifestFileName {
+			return nil
+		}
+		if _, err := LoadManifest(filepath.Dir(path)); err != nil {
+			invalid = err
+		} else {
+			found = append(found, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch len(found) {
+	case 0:
+		if invalid != nil {
+			return "", fmt.Errorf("no valid prompt manifest found: %w", invalid)
+		}
+		return "", fmt.Errorf("no prompt manifest found")
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("repository contains %d prompt manifests, only single-prompt repositories are supported", len(found))
+	}
+}
+
+// installPrompt copies the prompt in src to dst, see database.ReplaceDir. Git
+// metadata is not copied.
+func installPrompt(src, dst string, force bool) error {
+	if _, err := os.Stat(dst); err == nil && !force {
+		return fmt.Errorf("prompt %s already exists, use --force to overwrite it", filepath.Base(dst))
+	}
+	if err := database.ReplaceDir(src, dst); err != nil {
+		return fmt.Errorf("failed to install prompt files: %w", err)
+	}
+	return nil
+}
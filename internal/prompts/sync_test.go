@@ -0,0 +1,146 @@
+package prompts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestVersionTag(t *testing.T) {
+	tests := []struct {
+		version string
+		tag     string
+		wantErr bool
+	}{
+		{version: "1.2.0", tag: "v1.2.0"},
+		{version: "v1.2.0", tag: "v1.2.0"},
+		{version: "1.2.0-beta.1", tag: "v1.2.0-beta.1"},
+		{version: "1.2", wantErr: true},
+		{version: "latest", wantErr: true},
+		{version: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			tag, err := versionTag(tt.version)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "is not a semantic version")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.tag, tag)
+		})
+	}
+}
+
+func TestFindPromptManifest(t *testing.T) {
+	valid := newTestPrompt("reviewer", "1.0.0", "Review {{.file}}.")
+	writeInvalid := func(t *testing.T, dir string) {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, schema.ManifestFileName), []byte("kind: Prompt\n"), 0644))
+	}
+
+	tests := []struct {
+		name    string
+		valid   []string
+		invalid []string
+		found   string
+		wantErr string
+	}{
+		{name: "root", valid: []string{"."}, found: "."},
+		{name: "nested", valid: []string{"prompts/reviewer"}, found: "prompts/reviewer"},
+		{name: "invalid skipped", valid: []string{"reviewer"}, invalid: []string{"broken"}, found: "reviewer"},
+		{name: "git metadata skipped", valid: []string{"reviewer", ".git/reviewer"}, found: "reviewer"},
+		{name: "empty", wantErr: "no prompt manifest found"},
+		{name: "only invalid", invalid: []string{"."}, wantErr: "no valid prompt manifest found"},
+		{name: "multiple", valid: []string{"a", "b"}, wantErr: "contains 2 prompt manifests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, dir := range tt.valid {
+				require.NoError(t, SaveManifest(filepath.Join(root, dir), valid))
+			}
+			for _, dir := range tt.invalid {
+				writeInvalid(t, filepath.Join(root, dir))
+			}
+			found, err := findPromptManifest(root)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(root, tt.found), found)
+		})
+	}
+}
+
+func TestInstallPrompt(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, SaveManifest(src, newTestPrompt("reviewer", "1.0.0", "Review {{.file}}.")))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "README.md"), []byte("# Reviewer"), 0644))
+
+	prompts := t.TempDir()
+	dst := filepath.Join(prompts, "reviewer")
+	require.NoError(t, installPrompt(src, dst, false))
+
+	prompt, err := LoadManifest(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", prompt.Metadata.Version)
+	assert.FileExists(t, filepath.Join(dst, "README.md"))
+	assert.NoDirExists(t, filepath.Join(dst, ".git"), "git metadata is not installed")
+
+	err = installPrompt(src, dst, false)
+	assert.ErrorContains(t, err, "prompt reviewer already exists")
+
+	require.NoError(t, SaveManifest(src, newTestPrompt("reviewer", "1.1.0", "Review {{.file}} again.")))
+	require.NoError(t, os.Remove(filepath.Join(src, "README.md")))
+	require.NoError(t, installPrompt(src, dst, true))
+
+	prompt, err = LoadManifest(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", prompt.Metadata.Version)
+	assert.NoFileExists(t, filepath.Join(dst, "README.md"), "the installed prompt is replaced, not merged")
+
+	entries, err := os.ReadDir(prompts)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "staging and backup directories are removed")
+
+	err = installPrompt(filepath.Join(src, "missing"), filepath.Join(prompts, "other"), false)
+	assert.ErrorContains(t, err, "failed to install prompt files")
+	assert.NoDirExists(t, filepath.Join(prompts, "other"))
+}
+
+func TestPullPrompt_InstallsIntoInstalledDir(t *testing.T) {
+	ctx := context.Background()
+	t.Chdir(t.TempDir())
+	installed := t.TempDir()
+	gitClient := git.NewClient(zaptest.NewLogger(t))
+
+	repo := t.TempDir()
+	require.NoError(t, SaveManifest(repo, newTestPrompt("reviewer", "1.0.0", "Review {{.file}}.")))
+	require.NoError(t, gitClient.InitRepository(ctx, repo))
+	require.NoError(t, gitClient.AddAndCommit(ctx, repo, "Add prompt"))
+	require.NoError(t, gitClient.CreateTag(ctx, repo, "v1.0.0", "Release v1.0.0"))
+
+	service := NewPromptService(gitClient, nil, nil, installed, "")
+	prompt, err := service.PullPrompt(ctx, repo, "1.0.0", false)
+	require.NoError(t, err)
+	assert.Equal(t, "reviewer", prompt.Metadata.Name)
+
+	assert.FileExists(t, filepath.Join(installed, "reviewer", schema.ManifestFileName))
+	assert.NoDirExists(t, "prompts", "pulled prompts are not installed into the working directory")
+
+	data, err := service.LoadPromptData("reviewer")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", data.Version)
+}
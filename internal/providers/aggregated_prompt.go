@@ -0,0 +1,82 @@
+// Package providers contains the aggregated prompt provider implementation.
+package providers
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// aggregatedPromptProvider is a private implementation of types.PromptProvider that aggregates multiple providers.
+// Providers are consulted in order; the first provider that knows a prompt wins.
+type aggregatedPromptProvider struct {
+	log       *zap.Logger
+	providers []types.PromptProvider
+}
+
+// NewAggregatedPromptProvider creates a new aggregated prompt provider.
+func NewAggregatedPromptProvider(log *zap.Logger, providers ...types.PromptProvider) types.PromptProvider {
+	log.Info("Aggregated prompt provider initialized",
+		zap.Int("provider_count", len(providers)))
+
+	return &aggregatedPromptProvider{
+		log:       log,
+		providers: providers,
+	}
+}
+
+// GetSystemPrompt returns the system prompt of the first provider.
+func (p *aggregatedPromptProvider) GetSystemPrompt() string {
+	if len(p.providers) == 0 {
+		return "You are a helpful AI assistant."
+	}
+	return p.providers[0].GetSystemPrompt()
+}
+
+// FormatPrompt formats a prompt template with the given variables.
+func (p *aggregatedPromptProvider) FormatPrompt(templateStr string, vars map[string]any) (string, error) {
+	return formatTemplate(templateStr, vars)
+}
+
+// GetPrompts returns all prompts from all providers.
+func (p *aggregatedPromptProvider) GetPrompts() map[string]types.Prompt {
+	result := make(map[string]types.Prompt)
+
+	for _, provider := range p.providers {
+		for name, prompt := range provider.GetPrompts() {
+			if _, exists := result[name]; exists {
+				p.log.Warn("Prompt name conflict, keeping first provider's prompt",
+					zap.String("prompt", name))
+				continue
+			}
+			result[name] = prompt
+		}
+	}
+
+	return result
+}
+
+// GetPrompt retrieves a prompt by name from the first provider that has it.
+func (p *aggregatedPromptProvider) GetPrompt(name string) types.Prompt {
+	for _, provider := range p.providers {
+		if prompt, exists := provider.GetPrompts()[name]; exists {
+			return prompt
+		}
+	}
+
+	// Keep the fallback behaviour of the first provider
+	if len(p.providers) > 0 {
+		return p.providers[0].GetPrompt(name)
+	}
+	return nil
+}
+
+// RegisterPrompt registers a new prompt with the first (local) provider.
+func (p *aggregatedPromptProvider) RegisterPrompt(prompt types.Prompt) error {
+	if len(p.providers) == 0 {
+		return fmt.Errorf("no prompt provider available to register %s", prompt.GetName())
+	}
+	return p.providers[0].RegisterPrompt(prompt)
+}
@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+func TestNewMCPServerRegistry_Disabled(t *testing.T) {
+	log := zaptest.NewLogger(t)
+
+	registry, err := NewMCPServerRegistry(log, &config.MCPConfig{Enabled: false})
+	require.NoError(t, err)
+
+	assert.Empty(t, registry.GetServerNames())
+	_, ok := registry.GetClient("any")
+	assert.False(t, ok)
+	assert.NoError(t, registry.Close())
+}
+
+func TestNewMCPServerRegistry_InvalidConfig(t *testing.T) {
+	log := zaptest.NewLogger(t)
+
+	_, err := NewMCPServerRegistry(log, &config.MCPConfig{
+		Enabled:    true,
+		ConfigPath: "nonexistent-config.json",
+	})
+	assert.Error(t, err)
+}
+
+func TestMCPResourceAndPromptProviders_NoServers(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	registry, err := NewMCPServerRegistry(log, &config.MCPConfig{Enabled: false})
+	require.NoError(t, err)
+
+	resources, err := NewMCPResourceProvider(log, registry).ListResources(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+
+	_, err = NewMCPResourceProvider(log, registry).ReadResource(context.Background(), "file:///missing")
+	assert.Error(t, err)
+
+	promptProvider := NewMCPPromptProvider(log, registry)
+	assert.Empty(t, promptProvider.GetPrompts())
+	assert.Nil(t, promptProvider.GetPrompt("missing"))
+	assert.Error(t, promptProvider.RegisterPrompt(&testPrompt{name: "new"}))
+}
+
+func TestAggregatedPromptProvider(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	local := createTestPromptProvider(t)
+	registry, err := NewMCPServerRegistry(log, &config.MCPConfig{Enabled: false})
+	require.NoError(t, err)
+
+	provider := NewAggregatedPromptProvider(log, local, NewMCPPromptProvider(log, registry))
+
+	t.Run("GetPrompts", func(t *testing.T) {
+		prompts := provider.GetPrompts()
+		assert.Contains(t, prompts, "test")
+	})
+
+	t.Run("GetPrompt", func(t *testing.T) {
+		assert.Equal(t, "test", provider.GetPrompt("test").GetName())
+	})
+
+	t.Run("GetSystemPrompt", func(t *testing.T) {
+		assert.Equal(t, "You are a helpful AI assistant.", provider.GetSystemPrompt())
+	})
+
+	t.Run("RegisterPrompt", func(t *testing.T) {
+		require.NoError(t, provider.RegisterPrompt(&testPrompt{name: "extra", template: "x"}))
+		assert.Contains(t, provider.GetPrompts(), "extra")
+		assert.Error(t, provider.RegisterPrompt(&testPrompt{name: "extra"}))
+	})
+
+	t.Run("FormatPrompt", func(t *testing.T) {
+		result, err := provider.FormatPrompt("Hi {{.name}}", map[string]any{"name": "Ada"})
+		require.NoError(t, err)
+		assert.Equal(t, "Hi Ada", result)
+	})
+}
+
+var _ types.PromptProvider = (*aggregatedPromptProvider)(nil)
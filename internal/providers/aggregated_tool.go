@@ -26,7 +26,9 @@ type aggregatedToolProvider struct {
 	cacheValid bool         // Whether cache is valid
 }
 
-// NewAggregatedToolProvider creates a new aggregated tool provider.
+// NewAggregatedToolProvider creates a new aggregated tool provider. The tool cache is
+// built on the first lookup, so providers starting their tools on first use (like the
+// installed tool provider) do not spawn tool processes at construction.
 func NewAggregatedToolProvider(log *zap.Logger, providers ...types.ToolProvider) types.ToolProvider {
 	provider := &aggregatedToolProvider{
 		log:       log,
@@ -34,32 +36,33 @@ func NewAggregatedToolProvider(log *zap.Logger, providers ...types.ToolProvider)
 		toolCache: make(map[string]types.ToolProvider),
 	}
 
-	// Build initial cache
-	if err := provider.rebuildCache(); err != nil {
-		log.Error("Failed to build initial tool cache", zap.Error(err))
-	}
-
 	log.Info("Aggregated tool provider initialized",
-		zap.Int("provider_count", len(providers)),
-		zap.Int("total_tools", len(provider.allTools)))
+		zap.Int("provider_count", len(providers)))
 
 	return provider
 }
 
+// ensureCache builds the tool cache if it is missing or was invalidated.
+func (p *aggregatedToolProvider) ensureCache() {
+	p.mutex.RLock()
+	valid := p.cacheValid
+	p.mutex.RUnlock()
+
+	if valid {
+		return
+	}
+	if err := p.rebuildCache(); err != nil {
+		p.log.Error("Failed to rebuild tool cache", zap.Error(err))
+	}
+}
+
 // GetTools returns all available tools from all providers.
 func (p *aggregatedToolProvider) GetTools() []tools.Tool {
+	p.ensureCache()
+
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
 
-	if !p.cacheValid {
-		p.mutex.RUnlock()
-		if err := p.rebuildCache(); err != nil {
-			p.log.Error("Failed to rebuild tool cache", zap.Error(err))
-			return []tools.Tool{}
-		}
-		p.mutex.RLock()
-	}
-
 	// Return copy of cached tools
 	result := make([]tools.Tool, len(p.allTools))
 	copy(result, p.allTools)
@@ -67,10 +70,11 @@ func (p *aggregatedToolProvider) GetTools() []tools.Tool {
 }
 
 // GetToolsForAgent returns tools required by the agent from all providers.
+// Tools marked unhealthy by their provider are left out.
 func (p *aggregatedToolProvider) GetToolsForAgent(agent types.Agent) ([]tools.Tool, error) {
 	requiredTools := agent.GetRequiredTools()
 	
-	// Validate that all required tools are available
+	// Validate that all required tools are available; this builds the cache if needed
 	if err := p.ValidateAgentRequirements(agent); err != nil {
 		return nil, err
 	}
@@ -80,6 +84,12 @@ func (p *aggregatedToolProvider) GetToolsForAgent(agent types.Agent) ([]tools.To
 	p.mutex.RLock()
 	for _, toolName := range requiredTools {
 		if provider, exists := p.toolCache[toolName]; exists {
+			if !isProviderToolHealthy(provider, toolName) {
+				p.log.Warn("Skipping unhealthy tool for agent",
+					zap.String("agent", agent.GetName()),
+					zap.String("tool", toolName))
+				continue
+			}
 			// Get the specific tool from the provider
 			providerTools := provider.GetTools()
 			for _, tool := range providerTools {
@@ -100,8 +110,26 @@ func (p *aggregatedToolProvider) GetToolsForAgent(agent types.Agent) ([]tools.To
 	return agentTools, nil
 }
 
+// IsToolHealthy reports whether the provider serving a tool does not mark it unhealthy.
+// It does not build the tool cache, so it never starts tools.
+func (p *aggregatedToolProvider) IsToolHealthy(name string) bool {
+	p.mutex.RLock()
+	provider, exists := p.toolCache[name]
+	p.mutex.RUnlock()
+
+	return !exists || isProviderToolHealthy(provider, name)
+}
+
+// isProviderToolHealthy asks providers implementing types.ToolHealthChecker about a tool.
+func isProviderToolHealthy(provider types.ToolProvider, name string) bool {
+	checker, ok := provider.(types.ToolHealthChecker)
+	return !ok || checker.IsToolHealthy(name)
+}
+
 // ExecuteTool executes a specific tool by routing to the appropriate provider.
 func (p *aggregatedToolProvider) ExecuteTool(ctx context.Context, name string, input string) (string, error) {
+	p.ensureCache()
+
 	p.mutex.RLock()
 	provider, exists := p.toolCache[name]
 	p.mutex.RUnlock()
@@ -143,6 +171,8 @@ func (p *aggregatedToolProvider) RegisterTool(tool tools.Tool) error {
 
 // HasTool checks if a tool with the given name is available in any provider.
 func (p *aggregatedToolProvider) HasTool(name string) bool {
+	p.ensureCache()
+
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
 	
@@ -154,7 +184,9 @@ func (p *aggregatedToolProvider) HasTool(name string) bool {
 func (p *aggregatedToolProvider) ValidateAgentRequirements(agent types.Agent) error {
 	requiredTools := agent.GetRequiredTools()
 	missingTools := make([]string, 0)
-	
+
+	p.ensureCache()
+
 	p.mutex.RLock()
 	for _, toolName := range requiredTools {
 		if _, exists := p.toolCache[toolName]; !exists {
@@ -172,18 +204,11 @@ func (p *aggregatedToolProvider) ValidateAgentRequirements(agent types.Agent) er
 
 // GetToolNames returns the names of all available tools from all providers.
 func (p *aggregatedToolProvider) GetToolNames() []string {
+	p.ensureCache()
+
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
 
-	if !p.cacheValid {
-		p.mutex.RUnlock()
-		if err := p.rebuildCache(); err != nil {
-			p.log.Error("Failed to rebuild tool cache", zap.Error(err))
-			return []string{}
-		}
-		p.mutex.RLock()
-	}
-
 	// Return copy of cached tool names
 	result := make([]string, len(p.toolNames))
 	copy(result, p.toolNames)
@@ -139,9 +139,6 @@ func TestAggregatedToolProvider_RegisterTool(t *testing.T) {
 	provider1 := &MockToolProvider{}
 	provider2 := &MockToolProvider{}
 	
-	provider1.On("GetTools").Return([]tools.Tool{})
-	provider2.On("GetTools").Return([]tools.Tool{})
-	
 	newTool := &MockTool{name: "newTool", description: "New Tool"}
 	
 	// First provider fails, second succeeds
@@ -341,9 +338,6 @@ func TestAggregatedToolProvider_RegisterTool_AllProvidersFail(t *testing.T) {
 	provider1 := &MockToolProvider{}
 	provider2 := &MockToolProvider{}
 	
-	provider1.On("GetTools").Return([]tools.Tool{})
-	provider2.On("GetTools").Return([]tools.Tool{})
-	
 	newTool := &MockTool{name: "newTool", description: "New Tool"}
 	
 	// Both providers fail to register
@@ -395,4 +389,17 @@ func TestAggregatedToolProvider_ConcurrentAccess(t *testing.T) {
 	
 	// Should still work correctly
 	assert.True(t, aggregated.HasTool("tool1"))
-}
\ No newline at end of file
+}
+
+func TestAggregatedToolProvider_LazyCache(t *testing.T) {
+	log := zaptest.NewLogger(t)
+
+	provider := &MockToolProvider{}
+	provider.On("GetTools").Return([]tools.Tool{&MockTool{name: "tool1", description: "Tool 1"}})
+
+	aggregated := NewAggregatedToolProvider(log, provider)
+	provider.AssertNotCalled(t, "GetTools")
+
+	assert.True(t, aggregated.HasTool("tool1"))
+	provider.AssertNumberOfCalls(t, "GetTools", 1)
+}
@@ -0,0 +1,171 @@
+// Package providers contains the installed tool provider implementation.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/tools"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// installedToolLoadTimeout bounds starting the installed tools.
+const installedToolLoadTimeout = time.Minute
+
+// installedToolProvider serves the tools installed below a directory. The tools
+// are started through the runtime on first use, not at construction.
+// This is a private implementation of types.ToolProvider interface.
+type installedToolProvider struct {
+	log      *zap.Logger
+	runtime  types.ToolRuntime
+	health   types.ToolHealthChecker
+	toolsDir string
+	dirs     []string
+
+	once     sync.Once
+	provider *toolProvider
+}
+
+// NewInstalledToolProvider serves every tool installed below toolsDir through the
+// runtime. The tools are started on the first GetTools, ExecuteTool or other
+// lookup, so resolving the provider does not spawn tool processes. Tools that fail
+// to load are logged and skipped; tools marked unhealthy by the optional health
+// checker are hidden from agents.
+func NewInstalledToolProvider(
+	log *zap.Logger,
+	runtime types.ToolRuntime,
+	health types.ToolHealthChecker,
+	toolsDir string,
+) (types.ToolProvider, error) {
+	dirs, err := findInstalledTools(toolsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Installed tool provider initialized",
+		zap.String("tools_dir", toolsDir),
+		zap.Int("installed_tools", len(dirs)))
+
+	return &installedToolProvider{
+		log:      log,
+		runtime:  runtime,
+		health:   health,
+		toolsDir: toolsDir,
+		dirs:     dirs,
+	}, nil
+}
+
+// tools starts the installed tools once and returns the provider serving them.
+// The tools start concurrently within installedToolLoadTimeout, so a hung tool
+// does not delay the others.
+func (p *installedToolProvider) tools() *toolProvider {
+	p.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), installedToolLoadTimeout)
+		defer cancel()
+
+		results := make([][]tools.Tool, len(p.dirs))
+		var wg sync.WaitGroup
+		for i, dir := range p.dirs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				functionTools, err := p.runtime.LoadTool(ctx, dir)
+				if err != nil {
+					p.log.Warn("Failed to load installed tool",
+						zap.String("dir", dir),
+						zap.Error(err))
+					return
+				}
+				results[i] = functionTools
+			}()
+		}
+		wg.Wait()
+
+		loaded := make([]tools.Tool, 0)
+		for _, functionTools := range results {
+			loaded = append(loaded, functionTools...)
+		}
+
+		p.provider = newToolProvider(p.log, loaded)
+		p.provider.health = p.health
+		p.log.Info("Installed tools started",
+			zap.String("tools_dir", p.toolsDir),
+			zap.Strings("loaded_tools", p.runtime.GetLoadedTools()),
+			zap.Int("tool_count", len(p.provider.tools)))
+	})
+	return p.provider
+}
+
+// GetTools starts the installed tools if needed and returns their functions.
+func (p *installedToolProvider) GetTools() []tools.Tool {
+	return p.tools().GetTools()
+}
+
+// GetToolsForAgent returns the installed tools required by the agent.
+func (p *installedToolProvider) GetToolsForAgent(agent types.Agent) ([]tools.Tool, error) {
+	return p.tools().GetToolsForAgent(agent)
+}
+
+// ExecuteTool starts the installed tools if needed and executes a function.
+func (p *installedToolProvider) ExecuteTool(ctx context.Context, name string, input string) (string, error) {
+	return p.tools().ExecuteTool(ctx, name, input)
+}
+
+// RegisterTool registers a tool alongside the installed tools.
+func (p *installedToolProvider) RegisterTool(tool tools.Tool) error {
+	return p.tools().RegisterTool(tool)
+}
+
+// HasTool checks if an installed tool with the given name is available.
+func (p *installedToolProvider) HasTool(name string) bool {
+	return p.tools().HasTool(name)
+}
+
+// ValidateAgentRequirements checks if all required tools for an agent are installed.
+func (p *installedToolProvider) ValidateAgentRequirements(agent types.Agent) error {
+	return p.tools().ValidateAgentRequirements(agent)
+}
+
+// GetToolNames returns the names of all installed tool functions.
+func (p *installedToolProvider) GetToolNames() []string {
+	return p.tools().GetToolNames()
+}
+
+// IsToolHealthy reports whether a tool is not marked unhealthy by the health checker.
+// It does not start the installed tools.
+func (p *installedToolProvider) IsToolHealthy(name string) bool {
+	return p.health == nil || p.health.IsToolHealthy(name)
+}
+
+// findInstalledTools returns the directories below toolsDir that contain a manifest.
+// A missing tools directory means that no tools are installed.
+func findInstalledTools(toolsDir string) ([]string, error) {
+	entries, err := os.ReadDir(toolsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read tools directory: %w", err)
+	}
+
+	dirs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		// Hidden directories are staged installs
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(toolsDir, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, schema.ManifestFileName)); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,143 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/tools"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/toolruntime"
+)
+
+const installedToolManifest = `apiVersion: forge.dev/v1
+kind: Tool
+metadata:
+  name: weather-api
+  version: 1.0.0
+  description: Weather lookups over http
+  author: Test Author
+  license: MIT
+spec:
+  type: http
+  runtime: go
+  entryPoint: http://localhost:1
+  functions:
+    - name: current
+      description: Current weather
+`
+
+func TestNewInstalledToolProvider(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	toolsDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(toolsDir, "weather-api"), 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(toolsDir, "weather-api", schema.ManifestFileName), []byte(installedToolManifest), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(toolsDir, "broken"), 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(toolsDir, "broken", schema.ManifestFileName), []byte("kind: Tool"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(toolsDir, "empty"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(toolsDir, ".weather-api-123"), 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(toolsDir, ".weather-api-123", schema.ManifestFileName), []byte(installedToolManifest), 0644))
+
+	runtime := toolruntime.NewToolRuntime(log, toolruntime.Options{Timeout: time.Second})
+	defer runtime.Close()
+
+	provider, err := NewInstalledToolProvider(log, runtime, nil, toolsDir)
+	require.NoError(t, err)
+	assert.Empty(t, runtime.GetLoadedTools(), "tools are not started at construction")
+
+	assert.Equal(t, []string{"weather-api_current"}, provider.GetToolNames())
+	assert.Equal(t, []string{"weather-api"}, runtime.GetLoadedTools())
+}
+
+func TestFindInstalledTools_SkipsStagedInstalls(t *testing.T) {
+	toolsDir := t.TempDir()
+	for _, name := range []string{"weather-api", ".weather-api-123", ".weather-api-456.old"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(toolsDir, name), 0755))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(toolsDir, name, schema.ManifestFileName), []byte(installedToolManifest), 0644))
+	}
+
+	dirs, err := findInstalledTools(toolsDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(toolsDir, "weather-api")}, dirs)
+}
+
+func TestInstalledToolProvider_StartsToolsOnFirstExecute(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	toolsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(toolsDir, "weather-api"), 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(toolsDir, "weather-api", schema.ManifestFileName), []byte(installedToolManifest), 0644))
+
+	runtime := toolruntime.NewToolRuntime(log, toolruntime.Options{Timeout: time.Second})
+	defer runtime.Close()
+
+	provider, err := NewInstalledToolProvider(log, runtime, nil, toolsDir)
+	require.NoError(t, err)
+
+	// The endpoint is unreachable, the call fails after the tool was started
+	_, err = provider.ExecuteTool(context.Background(), "weather-api_current", "{}")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"weather-api"}, runtime.GetLoadedTools())
+
+	provider.GetTools()
+	assert.Equal(t, []string{"weather-api"}, runtime.GetLoadedTools(), "tools are started once")
+}
+
+func TestNewInstalledToolProvider_MissingDir(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	runtime := toolruntime.NewToolRuntime(log, toolruntime.Options{Timeout: time.Second})
+
+	provider, err := NewInstalledToolProvider(log, runtime, nil, filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, provider.GetTools())
+}
+
+// unhealthyTools is a types.ToolHealthChecker marking the listed tools unhealthy.
+type unhealthyTools []string
+
+func (u unhealthyTools) IsToolHealthy(name string) bool {
+	for _, unhealthy := range u {
+		if unhealthy == name {
+			return false
+		}
+	}
+	return true
+}
+
+func TestToolProvider_HidesUnhealthyTools(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	provider := newToolProvider(log, []tools.Tool{
+		&testTool{name: "healthy_tool", description: "Healthy"},
+		&testTool{name: "broken_tool", description: "Broken"},
+	})
+	provider.health = unhealthyTools{"broken_tool"}
+
+	agent := &testAgent{name: "agent", requiredTools: []string{"healthy_tool", "broken_tool"}}
+
+	agentTools, err := provider.GetToolsForAgent(agent)
+	require.NoError(t, err)
+	require.Len(t, agentTools, 1)
+	assert.Equal(t, "healthy_tool", agentTools[0].Name())
+
+	_, err = provider.ExecuteTool(context.Background(), "broken_tool", "{}")
+	assert.ErrorIs(t, err, errors.ErrServiceUnavailable)
+
+	aggregated := NewAggregatedToolProvider(log, provider)
+	agentTools, err = aggregated.GetToolsForAgent(agent)
+	require.NoError(t, err)
+	require.Len(t, agentTools, 1)
+	assert.Equal(t, "healthy_tool", agentTools[0].Name())
+	assert.False(t, aggregated.(*aggregatedToolProvider).IsToolHealthy("broken_tool"))
+}
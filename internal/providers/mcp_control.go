@@ -0,0 +1,262 @@
+// Package providers contains the MCP control channel implementation.
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+const (
+	// mcpControlTimeout bounds a control request, restarts wait for the server to run.
+	mcpControlTimeout = 2 * time.Minute
+	// mcpControlDialTimeout bounds connecting to the control socket.
+	mcpControlDialTimeout = time.Second
+)
+
+// Commands understood by the control socket.
+const (
+	mcpControlList    = "list"
+	mcpControlStatus  = "status"
+	mcpControlRestart = "restart"
+)
+
+// mcpControlRequest is sent over the control socket, one request per connection.
+type mcpControlRequest struct {
+	Command string `json:"command"`
+	Server  string `json:"server,omitempty"`
+}
+
+// mcpControlResponse answers a control request.
+type mcpControlResponse struct {
+	Servers []types.MCPServerInfo `json:"servers,omitempty"`
+	Error   string                `json:"error,omitempty"`
+}
+
+// mcpControlServer serves the supervisor of this process on a unix socket.
+type mcpControlServer struct {
+	log        *zap.Logger
+	controller types.MCPController
+	listener   net.Listener
+	done       chan struct{}
+}
+
+// ListenMCPControl serves supervisor on the unix socket at path, so forge mcp
+// commands of other processes can inspect and restart the servers of this process.
+// A socket left behind by a crashed process is replaced, a socket served by a
+// running process is an error.
+func ListenMCPControl(log *zap.Logger, supervisor types.MCPSupervisor, path string) (io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create control socket directory: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", path, mcpControlDialTimeout); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("MCP control socket %s is served by another forge process", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale control socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+
+	server := &mcpControlServer{
+		log:        log,
+		controller: NewMCPController(supervisor),
+		listener:   listener,
+		done:       make(chan struct{}),
+	}
+	go server.serve()
+
+	log.Info("MCP control socket listening", zap.String("path", path))
+	return server, nil
+}
+
+// Close stops accepting control requests and removes the socket.
+func (s *mcpControlServer) Close() error {
+	err := s.listener.Close()
+	<-s.done
+	return err
+}
+
+// serve accepts connections until the listener is closed.
+func (s *mcpControlServer) serve() {
+	defer close(s.done)
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.log.Error("MCP control socket failed", zap.Error(err))
+			}
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle answers the request of a single connection.
+func (s *mcpControlServer) handle(conn net.Conn) {
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(mcpControlTimeout)); err != nil {
+		s.log.Debug("Failed to set control connection deadline", zap.Error(err))
+	}
+
+	var request mcpControlRequest
+	if err := json.NewDecoder(conn).Decode(&request); err != nil {
+		s.log.Debug("Invalid MCP control request", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mcpControlTimeout)
+	defer cancel()
+
+	var response mcpControlResponse
+	servers, err := s.execute(ctx, request)
+	if err != nil {
+		response.Error = err.Error()
+	}
+	response.Servers = servers
+
+	if err := json.NewEncoder(conn).Encode(response); err != nil {
+		s.log.Debug("Failed to answer MCP control request", zap.Error(err))
+	}
+}
+
+// execute runs a control request against the supervisor.
+func (s *mcpControlServer) execute(ctx context.Context, request mcpControlRequest) ([]types.MCPServerInfo, error) {
+	s.log.Info("MCP control request",
+		zap.String("command", request.Command),
+		zap.String("server", request.Server))
+
+	var info types.MCPServerInfo
+	var err error
+	switch request.Command {
+	case mcpControlList:
+		return s.controller.ListServers(ctx)
+	case mcpControlStatus:
+		info, err = s.controller.CheckServer(ctx, request.Server)
+	case mcpControlRestart:
+		info, err = s.controller.RestartServer(ctx, request.Server)
+	default:
+		err = fmt.Errorf("unknown MCP control command %q", request.Command)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []types.MCPServerInfo{info}, nil
+}
+
+// mcpControlClient is a private implementation of types.MCPController for the
+// servers of the forge process serving a control socket.
+type mcpControlClient struct {
+	path string
+}
+
+// DialMCPControl returns a controller for the forge process serving the control
+// socket at path. It returns false if no running process serves the socket.
+func DialMCPControl(path string) (types.MCPController, bool) {
+	conn, err := net.DialTimeout("unix", path, mcpControlDialTimeout)
+	if err != nil {
+		return nil, false
+	}
+	conn.Close()
+	return &mcpControlClient{path: path}, true
+}
+
+// ListServers returns the servers of the remote process.
+func (c *mcpControlClient) ListServers(ctx context.Context) ([]types.MCPServerInfo, error) {
+	return c.call(ctx, mcpControlRequest{Command: mcpControlList})
+}
+
+// CheckServer pings a server of the remote process.
+func (c *mcpControlClient) CheckServer(ctx context.Context, name string) (types.MCPServerInfo, error) {
+	return c.callServer(ctx, mcpControlRequest{Command: mcpControlStatus, Server: name})
+}
+
+// RestartServer restarts a server of the remote process.
+func (c *mcpControlClient) RestartServer(ctx context.Context, name string) (types.MCPServerInfo, error) {
+	return c.callServer(ctx, mcpControlRequest{Command: mcpControlRestart, Server: name})
+}
+
+// callServer sends a request answered with a single server.
+func (c *mcpControlClient) callServer(ctx context.Context, request mcpControlRequest) (types.MCPServerInfo, error) {
+	servers, err := c.call(ctx, request)
+	if err != nil {
+		return types.MCPServerInfo{}, err
+	}
+	if len(servers) != 1 {
+		return types.MCPServerInfo{}, fmt.Errorf("invalid MCP control response with %d servers", len(servers))
+	}
+	return servers[0], nil
+}
+
+// call sends a request over a new connection and returns the answered servers.
+func (c *mcpControlClient) call(ctx context.Context, request mcpControlRequest) ([]types.MCPServerInfo, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP control socket: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(mcpControlTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("failed to set control connection deadline: %w", err)
+	}
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return nil, fmt.Errorf("failed to send MCP control request: %w", err)
+	}
+	var response mcpControlResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to read MCP control response: %w", err)
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return response.Servers, nil
+}
+
+// mcpLocalController is a private implementation of types.MCPController for the
+// servers supervised by this process.
+type mcpLocalController struct {
+	supervisor types.MCPSupervisor
+}
+
+// NewMCPController returns a controller for the servers of supervisor.
+func NewMCPController(supervisor types.MCPSupervisor) types.MCPController {
+	return &mcpLocalController{supervisor: supervisor}
+}
+
+// ListServers returns the servers of the supervisor.
+func (c *mcpLocalController) ListServers(_ context.Context) ([]types.MCPServerInfo, error) {
+	return c.supervisor.ListServers(), nil
+}
+
+// CheckServer pings a server of the supervisor.
+func (c *mcpLocalController) CheckServer(ctx context.Context, name string) (types.MCPServerInfo, error) {
+	return c.supervisor.CheckServer(ctx, name)
+}
+
+// RestartServer restarts a server of the supervisor.
+func (c *mcpLocalController) RestartServer(ctx context.Context, name string) (types.MCPServerInfo, error) {
+	if err := c.supervisor.RestartServer(ctx, name); err != nil {
+		return types.MCPServerInfo{}, err
+	}
+	return c.supervisor.GetServer(name)
+}
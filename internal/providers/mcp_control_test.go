@@ -0,0 +1,135 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// fakeSupervisor supervises fixed servers, a restart bumps the PID.
+type fakeSupervisor struct {
+	servers map[string]types.MCPServerInfo
+}
+
+func (s *fakeSupervisor) ListServers() []types.MCPServerInfo {
+	return []types.MCPServerInfo{s.servers["files"], s.servers["search"]}
+}
+
+func (s *fakeSupervisor) GetServer(name string) (types.MCPServerInfo, error) {
+	info, ok := s.servers[name]
+	if !ok {
+		return types.MCPServerInfo{}, fmt.Errorf("MCP server %s not found", name)
+	}
+	return info, nil
+}
+
+func (s *fakeSupervisor) CheckServer(ctx context.Context, name string) (types.MCPServerInfo, error) {
+	return s.GetServer(name)
+}
+
+func (s *fakeSupervisor) RestartServer(ctx context.Context, name string) error {
+	info, err := s.GetServer(name)
+	if err != nil {
+		return err
+	}
+	if info.State == types.MCPServerStateError {
+		return errors.New("server failed to start")
+	}
+	info.PID++
+	s.servers[name] = info
+	return nil
+}
+
+func (s *fakeSupervisor) OnRestart(callback func(serverName string)) {}
+func (s *fakeSupervisor) Close() error                               { return nil }
+
+func newFakeSupervisor() *fakeSupervisor {
+	return &fakeSupervisor{servers: map[string]types.MCPServerInfo{
+		"files":  {Name: "files", State: types.MCPServerStateRunning, PID: 100, ToolCount: 3},
+		"search": {Name: "search", State: types.MCPServerStateError, LastError: "health ping failed"},
+	}}
+}
+
+func TestMCPControl_ManagesServersOfAnotherProcess(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "mcp.sock")
+	supervisor := newFakeSupervisor()
+
+	_, ok := DialMCPControl(path)
+	assert.False(t, ok, "no process serves the socket yet")
+
+	control, err := ListenMCPControl(zaptest.NewLogger(t), supervisor, path)
+	require.NoError(t, err)
+
+	controller, ok := DialMCPControl(path)
+	require.True(t, ok)
+
+	servers, err := controller.ListServers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, supervisor.ListServers(), servers)
+
+	info, err := controller.CheckServer(ctx, "search")
+	require.NoError(t, err)
+	assert.Equal(t, "health ping failed", info.LastError)
+
+	info, err = controller.RestartServer(ctx, "files")
+	require.NoError(t, err)
+	assert.Equal(t, 101, info.PID, "the server is restarted in the serving process")
+	assert.Equal(t, 101, supervisor.servers["files"].PID)
+
+	_, err = controller.RestartServer(ctx, "search")
+	assert.EqualError(t, err, "server failed to start")
+	_, err = controller.CheckServer(ctx, "missing")
+	assert.EqualError(t, err, "MCP server missing not found")
+
+	_, err = ListenMCPControl(zaptest.NewLogger(t), supervisor, path)
+	assert.ErrorContains(t, err, "served by another forge process")
+
+	require.NoError(t, control.Close())
+	_, ok = DialMCPControl(path)
+	assert.False(t, ok, "closing stops serving the socket")
+}
+
+func TestListenMCPControl_ReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.sock")
+
+	// A listener that does not remove its socket on close, like a crashed process
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+
+	control, err := ListenMCPControl(zaptest.NewLogger(t), newFakeSupervisor(), path)
+	require.NoError(t, err)
+	defer control.Close()
+
+	controller, ok := DialMCPControl(path)
+	require.True(t, ok)
+	servers, err := controller.ListServers(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, servers, 2)
+}
+
+func TestMCPController_Local(t *testing.T) {
+	supervisor := newFakeSupervisor()
+	controller := NewMCPController(supervisor)
+
+	info, err := controller.RestartServer(context.Background(), "files")
+	require.NoError(t, err)
+	assert.Equal(t, 101, info.PID)
+
+	_, err = controller.RestartServer(context.Background(), "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,226 @@
+// Package providers contains the MCP prompt provider implementation.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// mcpPromptTimeout bounds every prompt request sent to an MCP server.
+const mcpPromptTimeout = 30 * time.Second
+
+// mcpPromptProvider is a private implementation of types.PromptProvider for MCP servers.
+type mcpPromptProvider struct {
+	log      *zap.Logger
+	registry types.MCPServerRegistry
+	prompts  map[string]types.Prompt
+	// servers are the running servers the prompts were listed from, nil before the first listing
+	servers []string
+	mutex   sync.RWMutex
+
+	// loading serializes the listings, which run without holding mutex
+	loading sync.Mutex
+}
+
+// NewMCPPromptProvider creates a new prompt provider backed by the running MCP servers.
+// Prompts are exposed under the name "server.prompt", matching the MCP tool naming.
+func NewMCPPromptProvider(log *zap.Logger, registry types.MCPServerRegistry) types.PromptProvider {
+	return &mcpPromptProvider{
+		log:      log,
+		registry: registry,
+		prompts:  make(map[string]types.Prompt),
+	}
+}
+
+// GetSystemPrompt returns the "system" prompt of the first server, by server name,
+// that publishes one. Prompts are named "server.prompt", so it is found as "*.system".
+func (p *mcpPromptProvider) GetSystemPrompt() string {
+	prompts := p.GetPrompts()
+
+	names := make([]string, 0, len(prompts))
+	for name := range prompts {
+		if strings.HasSuffix(name, ".system") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return prompts[names[0]].GetTemplate()
+}
+
+// FormatPrompt formats a prompt template with the given variables.
+func (p *mcpPromptProvider) FormatPrompt(templateStr string, vars map[string]any) (string, error) {
+	return formatTemplate(templateStr, vars)
+}
+
+// GetPrompts returns all prompts published by the running servers.
+func (p *mcpPromptProvider) GetPrompts() map[string]types.Prompt {
+	p.ensureLoaded()
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	result := make(map[string]types.Prompt, len(p.prompts))
+	for name, prompt := range p.prompts {
+		result[name] = prompt
+	}
+	return result
+}
+
+// GetPrompt retrieves a prompt by name, returns nil if not found.
+func (p *mcpPromptProvider) GetPrompt(name string) types.Prompt {
+	p.ensureLoaded()
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if prompt, exists := p.prompts[name]; exists {
+		return prompt
+	}
+	return nil
+}
+
+// RegisterPrompt registers a new prompt (not supported for MCP provider).
+func (p *mcpPromptProvider) RegisterPrompt(_ types.Prompt) error {
+	return fmt.Errorf("registering prompts not supported for MCP provider - prompts are loaded from MCP servers")
+}
+
+// ensureLoaded lists the prompts of all running servers. The listing is cached
+// until the set of running servers changes, so servers started or stopped after
+// the first access are picked up. The servers are asked without holding the
+// mutex, so readers are not blocked by slow servers.
+func (p *mcpPromptProvider) ensureLoaded() {
+	p.loading.Lock()
+	defer p.loading.Unlock()
+
+	servers := slices.Clone(p.registry.GetServerNames())
+	sort.Strings(servers)
+
+	p.mutex.RLock()
+	current := p.servers != nil && slices.Equal(p.servers, servers)
+	p.mutex.RUnlock()
+	if current {
+		return
+	}
+
+	prompts := p.listPrompts(servers)
+
+	p.mutex.Lock()
+	p.prompts = prompts
+	p.servers = servers
+	p.mutex.Unlock()
+
+	p.log.Info("MCP prompts loaded", zap.Int("prompt_count", len(prompts)))
+}
+
+// listPrompts asks all running servers for their prompts. Servers that fail
+// are logged and skipped.
+func (p *mcpPromptProvider) listPrompts(servers []string) map[string]types.Prompt {
+	ctx, cancel := context.WithTimeout(context.Background(), mcpPromptTimeout)
+	defer cancel()
+
+	prompts := make(map[string]types.Prompt)
+
+	for _, serverName := range servers {
+		client, ok := p.registry.GetClient(serverName)
+		if !ok {
+			continue
+		}
+
+		result, err := client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			p.log.Warn("Failed to list MCP prompts",
+				zap.String("server", serverName),
+				zap.Error(err))
+			continue
+		}
+
+		for _, prompt := range result.Prompts {
+			name := serverName + "." + prompt.Name
+			prompts[name] = &mcpPrompt{
+				log:        p.log,
+				name:       name,
+				remoteName: prompt.Name,
+				prompt:     prompt,
+				client:     client,
+			}
+		}
+	}
+	return prompts
+}
+
+// mcpPrompt is a private implementation of types.Prompt for a prompt published by an MCP server.
+type mcpPrompt struct {
+	log        *zap.Logger
+	name       string
+	remoteName string
+	prompt     mcp.Prompt
+	client     mcpclient.MCPClient
+}
+
+func (p *mcpPrompt) GetName() string        { return p.name }
+func (p *mcpPrompt) GetDescription() string { return p.prompt.Description }
+
+// GetTemplate returns the prompt rendered without arguments. Prompts that cannot
+// be rendered, e.g. with required arguments, are logged and return "".
+func (p *mcpPrompt) GetTemplate() string {
+	text, err := p.Render(map[string]any{})
+	if err != nil {
+		p.log.Warn("Failed to render MCP prompt template",
+			zap.String("prompt", p.name),
+			zap.Error(err))
+		return ""
+	}
+	return text
+}
+
+// GetArguments returns the arguments declared by the MCP server.
+func (p *mcpPrompt) GetArguments() []mcp.PromptArgument {
+	return p.prompt.Arguments
+}
+
+// Render requests the prompt from the server and joins the text of all returned messages.
+func (p *mcpPrompt) Render(variables map[string]any) (string, error) {
+	for _, argument := range p.prompt.Arguments {
+		if _, exists := variables[argument.Name]; argument.Required && !exists {
+			return "", fmt.Errorf("prompt %s requires argument %s", p.name, argument.Name)
+		}
+	}
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = p.remoteName
+	request.Params.Arguments = make(map[string]string, len(variables))
+	for key, value := range variables {
+		request.Params.Arguments[key] = fmt.Sprint(value)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mcpPromptTimeout)
+	defer cancel()
+
+	result, err := p.client.GetPrompt(ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("failed to get prompt %s: %w", p.name, err)
+	}
+
+	parts := make([]string, 0, len(result.Messages))
+	for _, message := range result.Messages {
+		if text, ok := mcp.AsTextContent(message.Content); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+
+	return strings.Join(parts, "\n\n"), nil
+}
@@ -0,0 +1,260 @@
+// Package providers contains the MCP server registry implementation.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	mcpadapter "github.com/denkhaus/mcp-server-adapter"
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// mcpProcess records the process details of a server started by the registry.
+type mcpProcess struct {
+	cmd       *exec.Cmd
+	startedAt time.Time
+	logFile   string
+}
+
+// pid returns the process id, or 0 when the server is not a local process.
+func (p *mcpProcess) pid() int {
+	if p == nil || p.cmd == nil || p.cmd.Process == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+// mcpServerRegistry is a private implementation of types.MCPServerRegistry.
+// It is installed as the adapter's client factory so that the clients the adapter
+// creates can be reused for resources and prompts, which the adapter does not expose,
+// and so that process details and stderr of stdio servers can be captured.
+type mcpServerRegistry struct {
+	log       *zap.Logger
+	adapter   mcpadapter.MCPAdapter
+	factory   mcpadapter.ClientFactoryInterface
+	logDir    string
+	clients   map[string]mcpclient.MCPClient
+	processes map[string]*mcpProcess
+	mutex     sync.RWMutex
+}
+
+// NewMCPServerRegistry creates the MCP adapter, starts all configured servers and
+// waits for them to become ready. A disabled configuration yields an empty registry.
+func NewMCPServerRegistry(log *zap.Logger, cfg *config.MCPConfig) (types.MCPServerRegistry, error) {
+	registry := &mcpServerRegistry{
+		log:       log,
+		factory:   mcpadapter.NewClientFactory(),
+		logDir:    cfg.GetLogDir(),
+		clients:   make(map[string]mcpclient.MCPClient),
+		processes: make(map[string]*mcpProcess),
+	}
+
+	if !cfg.Enabled {
+		return registry, nil
+	}
+
+	adapter, err := mcpadapter.New(
+		mcpadapter.WithConfigPath(cfg.ConfigPath),
+		mcpadapter.WithClientFactory(registry),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MCP adapter: %w", err)
+	}
+	registry.adapter = adapter
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ServerTimeout)*time.Second)
+	defer cancel()
+
+	if err := adapter.StartAllServers(ctx); err != nil {
+		log.Warn("Failed to start all MCP servers", zap.Error(err))
+	}
+	if err := adapter.WaitForServersReady(ctx, time.Duration(cfg.ServerTimeout)*time.Second); err != nil {
+		log.Warn("Not all MCP servers became ready", zap.Error(err))
+	}
+
+	log.Info("MCP server registry initialized",
+		zap.Strings("running_servers", registry.GetServerNames()))
+
+	return registry, nil
+}
+
+// CreateClient implements mcpadapter.ClientFactoryInterface and records the created client.
+func (r *mcpServerRegistry) CreateClient(serverConfig *mcpadapter.ServerConfig) (mcpclient.MCPClient, error) {
+	name := r.resolveServerName(serverConfig)
+	process := &mcpProcess{startedAt: time.Now()}
+
+	client, err := r.createClient(serverConfig, process)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		process.logFile = r.captureStderr(name, client)
+
+		r.mutex.Lock()
+		r.clients[name] = client
+		r.processes[name] = process
+		r.mutex.Unlock()
+	}
+
+	return client, nil
+}
+
+// createClient creates stdio clients itself to keep hold of the process and delegates
+// all other transports to the adapter's default factory.
+func (r *mcpServerRegistry) createClient(
+	serverConfig *mcpadapter.ServerConfig,
+	process *mcpProcess,
+) (mcpclient.MCPClient, error) {
+	isStdio := serverConfig.Transport == "" || serverConfig.Transport == mcpadapter.TransportStdio
+	isGoRun := serverConfig.Command == "go" && len(serverConfig.Args) > 0 && serverConfig.Args[0] == "run"
+	if !isStdio || isGoRun || serverConfig.Command == "" {
+		return r.factory.CreateClient(serverConfig)
+	}
+
+	env := make([]string, 0, len(serverConfig.Env))
+	for key, value := range serverConfig.Env {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(env)
+
+	commandFunc := func(ctx context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+		// #nosec G204 -- command comes from the user's MCP server configuration
+		cmd := exec.CommandContext(ctx, command, args...)
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Dir = serverConfig.Cwd
+		process.cmd = cmd
+		return cmd, nil
+	}
+
+	return mcpclient.NewStdioMCPClientWithOptions(
+		serverConfig.Command, env, serverConfig.Args, transport.WithCommandFunc(commandFunc))
+}
+
+// captureStderr drains the stderr of a stdio client into the server's rotating log file.
+// Draining is required in any case, a full stderr pipe would block the server.
+func (r *mcpServerRegistry) captureStderr(name string, client mcpclient.MCPClient) string {
+	stdioClient, ok := client.(*mcpclient.Client)
+	if !ok {
+		return ""
+	}
+	stderr, ok := mcpclient.GetStderr(stdioClient)
+	if !ok {
+		return ""
+	}
+
+	logFile := filepath.Join(r.logDir, name+".log")
+	writer, err := newRotatingWriter(logFile, mcpLogMaxSize, mcpLogMaxBackups)
+	if err != nil {
+		r.log.Warn("Failed to open MCP server log, discarding stderr",
+			zap.String("server", name),
+			zap.Error(err))
+		go func() { _, _ = io.Copy(io.Discard, stderr) }()
+		return ""
+	}
+
+	go func() {
+		defer writer.Close()
+		_, _ = fmt.Fprintf(writer, "=== %s started at %s ===\n", name, time.Now().Format(time.RFC3339))
+		_, _ = io.Copy(writer, stderr)
+	}()
+
+	return logFile
+}
+
+// resolveServerName finds the configured name of a server config.
+// The adapter only hands out copies of its configuration, so configs are compared by value.
+func (r *mcpServerRegistry) resolveServerName(serverConfig *mcpadapter.ServerConfig) string {
+	if r.adapter == nil {
+		return ""
+	}
+
+	adapterConfig := r.adapter.GetConfig()
+	if adapterConfig == nil {
+		return ""
+	}
+
+	for name, candidate := range adapterConfig.McpServers {
+		if reflect.DeepEqual(candidate, serverConfig) {
+			return name
+		}
+	}
+
+	r.log.Warn("Unable to resolve MCP server name for client",
+		zap.String("command", serverConfig.Command),
+		zap.String("url", serverConfig.URL))
+	return ""
+}
+
+// GetClient returns the initialized client for a running server.
+func (r *mcpServerRegistry) GetClient(serverName string) (mcpclient.MCPClient, bool) {
+	if r.adapter == nil || r.adapter.GetServerStatus(serverName) != mcpadapter.StatusRunning {
+		return nil, false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	client, exists := r.clients[serverName]
+	return client, exists
+}
+
+// GetServerNames returns the names of all running servers, sorted alphabetically.
+func (r *mcpServerRegistry) GetServerNames() []string {
+	if r.adapter == nil {
+		return []string{}
+	}
+
+	names := make([]string, 0)
+	for name, status := range r.adapter.GetAllServerStatuses() {
+		if status == mcpadapter.StatusRunning {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// GetAdapter returns the underlying MCP adapter, or nil when MCP is disabled.
+func (r *mcpServerRegistry) GetAdapter() mcpadapter.MCPAdapter {
+	return r.adapter
+}
+
+// getProcess returns the process details of a server, or nil if unknown.
+func (r *mcpServerRegistry) getProcess(serverName string) *mcpProcess {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.processes[serverName]
+}
+
+// getLogFile returns the log file path of a server.
+func (r *mcpServerRegistry) getLogFile(serverName string) string {
+	return filepath.Join(r.logDir, serverName+".log")
+}
+
+// Close shuts down all server connections.
+func (r *mcpServerRegistry) Close() error {
+	if r.adapter == nil {
+		return nil
+	}
+	return r.adapter.Close()
+}
+
+// Shutdown implements do.Shutdownable so the container closes the servers.
+func (r *mcpServerRegistry) Shutdown() error {
+	return r.Close()
+}
@@ -0,0 +1,205 @@
+// Package providers contains the MCP resource provider implementation.
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tmc/langchaingo/tools"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// mcpResourceProvider is a private implementation of types.ResourceProvider for MCP servers.
+type mcpResourceProvider struct {
+	log      *zap.Logger
+	registry types.MCPServerRegistry
+}
+
+// NewMCPResourceProvider creates a new resource provider backed by the running MCP servers.
+func NewMCPResourceProvider(log *zap.Logger, registry types.MCPServerRegistry) types.ResourceProvider {
+	return &mcpResourceProvider{
+		log:      log,
+		registry: registry,
+	}
+}
+
+// ListResources returns the resources of all running servers.
+// Servers that fail to list their resources are logged and skipped.
+func (p *mcpResourceProvider) ListResources(ctx context.Context) ([]types.MCPResource, error) {
+	resources := make([]types.MCPResource, 0)
+
+	for _, serverName := range p.registry.GetServerNames() {
+		serverResources, err := p.listServerResources(ctx, serverName)
+		if err != nil {
+			p.log.Warn("Failed to list MCP resources",
+				zap.String("server", serverName),
+				zap.Error(err))
+			continue
+		}
+		resources = append(resources, serverResources...)
+	}
+
+	return resources, nil
+}
+
+// ReadResource reads a resource by URI from the server that published it.
+func (p *mcpResourceProvider) ReadResource(ctx context.Context, uri string) ([]types.MCPResourceContent, error) {
+	resources, err := p.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		if resource.URI != uri {
+			continue
+		}
+
+		client, ok := p.registry.GetClient(resource.Server)
+		if !ok {
+			return nil, fmt.Errorf("MCP server %s is not running", resource.Server)
+		}
+
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = uri
+
+		result, err := client.ReadResource(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource %s: %w", uri, err)
+		}
+
+		return convertResourceContents(result.Contents), nil
+	}
+
+	return nil, fmt.Errorf("MCP resource %s not found", uri)
+}
+
+// listServerResources lists the resources of a single server.
+func (p *mcpResourceProvider) listServerResources(ctx context.Context, serverName string) ([]types.MCPResource, error) {
+	client, ok := p.registry.GetClient(serverName)
+	if !ok {
+		return nil, fmt.Errorf("MCP server %s is not running", serverName)
+	}
+
+	result, err := client.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	resources := make([]types.MCPResource, 0, len(result.Resources))
+	for _, resource := range result.Resources {
+		resources = append(resources, types.MCPResource{
+			Server:      serverName,
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Description: resource.Description,
+			MIMEType:    resource.MIMEType,
+		})
+	}
+
+	return resources, nil
+}
+
+// convertResourceContents converts MCP resource contents to the internal representation.
+func convertResourceContents(contents []mcp.ResourceContents) []types.MCPResourceContent {
+	result := make([]types.MCPResourceContent, 0, len(contents))
+
+	for _, content := range contents {
+		switch c := content.(type) {
+		case mcp.TextResourceContents:
+			result = append(result, types.MCPResourceContent{URI: c.URI, MIMEType: c.MIMEType, Text: c.Text})
+		case mcp.BlobResourceContents:
+			result = append(result, types.MCPResourceContent{URI: c.URI, MIMEType: c.MIMEType, Blob: c.Blob})
+		}
+	}
+
+	return result
+}
+
+// Names of the tools exposing MCP resources to agents.
+const (
+	mcpListResourcesName = "mcp_list_resources"
+	mcpReadResourceName  = "mcp_read_resource"
+)
+
+// NewMCPResourceTools returns the agent tools listing and reading the resources of the running MCP servers.
+func NewMCPResourceTools(resources types.ResourceProvider) []tools.Tool {
+	return []tools.Tool{
+		&mcpListResourcesTool{resources: resources},
+		&mcpReadResourceTool{resources: resources},
+	}
+}
+
+// mcpListResourcesTool is a private implementation of tools.Tool listing the resources of all running servers.
+type mcpListResourcesTool struct {
+	resources types.ResourceProvider
+}
+
+func (t *mcpListResourcesTool) Name() string { return mcpListResourcesName }
+
+func (t *mcpListResourcesTool) Description() string {
+	return "Lists the resources published by the MCP servers as JSON array of server, uri, name, description " +
+		"and mimeType. Read a resource with " + mcpReadResourceName + ". Input is ignored."
+}
+
+// Call returns the resources as JSON.
+func (t *mcpListResourcesTool) Call(ctx context.Context, _ string) (string, error) {
+	resources, err := t.resources.ListResources(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(resources)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode resources: %w", err)
+	}
+	return string(data), nil
+}
+
+// mcpReadResourceTool is a private implementation of tools.Tool reading a resource by URI.
+type mcpReadResourceTool struct {
+	resources types.ResourceProvider
+}
+
+func (t *mcpReadResourceTool) Name() string { return mcpReadResourceName }
+
+func (t *mcpReadResourceTool) Description() string {
+	return "Reads an MCP resource and returns its text. Input is the resource URI as listed by " +
+		mcpListResourcesName + `, or JSON {"uri": "..."}.`
+}
+
+// Call reads the resource of the URI in input. Binary contents are described, not returned.
+func (t *mcpReadResourceTool) Call(ctx context.Context, input string) (string, error) {
+	uri := strings.TrimSpace(input)
+	if strings.HasPrefix(uri, "{") {
+		var request struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal([]byte(uri), &request); err != nil {
+			return "", fmt.Errorf("invalid input, expected a resource URI: %w", err)
+		}
+		uri = request.URI
+	}
+	if uri == "" {
+		return "", fmt.Errorf("invalid input, expected a resource URI")
+	}
+
+	contents, err := t.resources.ReadResource(ctx, uri)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0, len(contents))
+	for _, content := range contents {
+		if content.Blob != "" {
+			parts = append(parts, fmt.Sprintf("[binary content %s, %s, %d bytes base64]", content.URI, content.MIMEType, len(content.Blob)))
+			continue
+		}
+		parts = append(parts, content.Text)
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
@@ -0,0 +1,137 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// staticRegistry is an MCP server registry of fixed clients.
+type staticRegistry map[string]mcpclient.MCPClient
+
+func (r staticRegistry) GetClient(serverName string) (mcpclient.MCPClient, bool) {
+	client, ok := r[serverName]
+	return client, ok
+}
+
+func (r staticRegistry) GetServerNames() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (r staticRegistry) Close() error { return nil }
+
+// newDocsServer returns a registry with the in-process server "docs" publishing
+// a readme resource, a greeting prompt with a required argument and a system prompt.
+func newDocsServer(t *testing.T) staticRegistry {
+	t.Helper()
+	mcpServer := server.NewMCPServer("docs", "1.0.0",
+		server.WithResourceCapabilities(false, false), server.WithPromptCapabilities(false))
+
+	mcpServer.AddResource(mcp.NewResource("file:///readme.md", "readme", mcp.WithMIMEType("text/markdown")),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "text/markdown", Text: "# Forge"}}, nil
+		})
+	mcpServer.AddPrompt(mcp.NewPrompt("greeting", mcp.WithArgument("name", mcp.RequiredArgument())),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("Greeting", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("Hello "+request.Params.Arguments["name"])),
+			}), nil
+		})
+	mcpServer.AddPrompt(mcp.NewPrompt("system"),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("System", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("You document Forge.")),
+			}), nil
+		})
+
+	client, err := mcpclient.NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+	require.NoError(t, client.Start(context.Background()))
+	initialize := mcp.InitializeRequest{}
+	initialize.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(context.Background(), initialize)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return staticRegistry{"docs": client}
+}
+
+func TestMCPResourceTools(t *testing.T) {
+	ctx := context.Background()
+	resourceTools := NewMCPResourceTools(NewMCPResourceProvider(zaptest.NewLogger(t), newDocsServer(t)))
+	require.Len(t, resourceTools, 2)
+	list, read := resourceTools[0], resourceTools[1]
+	assert.Equal(t, "mcp_list_resources", list.Name())
+	assert.Equal(t, "mcp_read_resource", read.Name())
+
+	output, err := list.Call(ctx, "")
+	require.NoError(t, err)
+	var resources []types.MCPResource
+	require.NoError(t, json.Unmarshal([]byte(output), &resources))
+	assert.Equal(t, []types.MCPResource{{Server: "docs", URI: "file:///readme.md", Name: "readme", MIMEType: "text/markdown"}}, resources)
+
+	for _, input := range []string{"file:///readme.md", ` {"uri": "file:///readme.md"} `} {
+		text, err := read.Call(ctx, input)
+		require.NoError(t, err, input)
+		assert.Equal(t, "# Forge", text, input)
+	}
+
+	_, err = read.Call(ctx, "file:///missing.md")
+	assert.ErrorContains(t, err, "not found")
+	_, err = read.Call(ctx, "{}")
+	assert.ErrorContains(t, err, "expected a resource URI")
+}
+
+func TestMCPPromptProvider_RendersServerPrompts(t *testing.T) {
+	provider := NewMCPPromptProvider(zaptest.NewLogger(t), newDocsServer(t))
+
+	prompt := provider.GetPrompt("docs.greeting")
+	require.NotNil(t, prompt)
+	renderer, ok := prompt.(interface {
+		Render(variables map[string]any) (string, error)
+	})
+	require.True(t, ok)
+
+	text, err := renderer.Render(map[string]any{"name": "Ada"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ada", text)
+
+	_, err = renderer.Render(map[string]any{})
+	assert.ErrorContains(t, err, "requires argument name")
+	assert.Empty(t, prompt.GetTemplate(), "templates of prompts with required arguments cannot be rendered")
+}
+
+func TestMCPPromptProvider_SystemPrompt(t *testing.T) {
+	provider := NewMCPPromptProvider(zaptest.NewLogger(t), newDocsServer(t))
+	assert.Equal(t, "You document Forge.", provider.GetSystemPrompt())
+
+	empty := NewMCPPromptProvider(zaptest.NewLogger(t), staticRegistry{})
+	assert.Empty(t, empty.GetSystemPrompt())
+}
+
+func TestMCPPromptProvider_ReloadsWhenServersStart(t *testing.T) {
+	docs := newDocsServer(t)
+	registry := staticRegistry{}
+	provider := NewMCPPromptProvider(zaptest.NewLogger(t), registry)
+
+	assert.Empty(t, provider.GetPrompts(), "no servers are running yet")
+
+	registry["docs"] = docs["docs"]
+	assert.NotNil(t, provider.GetPrompt("docs.greeting"), "prompts of servers started later are listed")
+
+	delete(registry, "docs")
+	assert.Nil(t, provider.GetPrompt("docs.greeting"), "prompts of stopped servers are dropped")
+}
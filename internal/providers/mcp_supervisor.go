@@ -0,0 +1,413 @@
+// Package providers contains the MCP server supervisor implementation.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	mcpadapter "github.com/denkhaus/mcp-server-adapter"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+const (
+	// mcpRestartMaxDelay caps the exponential restart backoff.
+	mcpRestartMaxDelay = 5 * time.Minute
+	// mcpPingTimeout bounds a single health ping.
+	mcpPingTimeout = 5 * time.Second
+)
+
+// mcpServerHealth holds the supervision state of a single server.
+type mcpServerHealth struct {
+	lastPing    time.Time
+	lastError   string
+	toolCount   int
+	restarts    int
+	nextRestart time.Time
+}
+
+// mcpSupervisor is a private implementation of types.MCPSupervisor.
+type mcpSupervisor struct {
+	log         *zap.Logger
+	registry    *mcpServerRegistry
+	interval    time.Duration
+	timeout     time.Duration
+	maxRestarts int
+	health      map[string]*mcpServerHealth
+	callbacks   []func(serverName string)
+	cancel      context.CancelFunc
+	done        chan struct{}
+	mutex       sync.RWMutex
+}
+
+// NewMCPSupervisor creates a supervisor for the servers of the registry and starts
+// the periodic health check loop when MCP is enabled.
+func NewMCPSupervisor(log *zap.Logger, cfg *config.MCPConfig, registry types.MCPServerRegistry) (types.MCPSupervisor, error) {
+	serverRegistry, ok := registry.(*mcpServerRegistry)
+	if !ok {
+		return nil, fmt.Errorf("unsupported MCP server registry %T", registry)
+	}
+
+	supervisor := &mcpSupervisor{
+		log:         log,
+		registry:    serverRegistry,
+		interval:    time.Duration(cfg.HealthInterval) * time.Second,
+		timeout:     time.Duration(cfg.ServerTimeout) * time.Second,
+		maxRestarts: cfg.MaxRestarts,
+		health:      make(map[string]*mcpServerHealth),
+		done:        make(chan struct{}),
+	}
+
+	if serverRegistry.GetAdapter() == nil || supervisor.interval <= 0 {
+		close(supervisor.done)
+		return supervisor, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	supervisor.cancel = cancel
+	go supervisor.run(ctx)
+
+	return supervisor, nil
+}
+
+// ListServers returns a snapshot of all configured servers, sorted by name.
+func (s *mcpSupervisor) ListServers() []types.MCPServerInfo {
+	adapter := s.registry.GetAdapter()
+	if adapter == nil || adapter.GetConfig() == nil {
+		return []types.MCPServerInfo{}
+	}
+
+	servers := adapter.GetConfig().McpServers
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	infos := make([]types.MCPServerInfo, 0, len(names))
+	for _, name := range names {
+		infos = append(infos, s.snapshot(name, servers[name]))
+	}
+	return infos
+}
+
+// GetServer returns a snapshot of a single server.
+func (s *mcpSupervisor) GetServer(name string) (types.MCPServerInfo, error) {
+	serverConfig, err := s.serverConfig(name)
+	if err != nil {
+		return types.MCPServerInfo{}, err
+	}
+	return s.snapshot(name, serverConfig), nil
+}
+
+// CheckServer pings a server immediately and returns the updated snapshot.
+func (s *mcpSupervisor) CheckServer(ctx context.Context, name string) (types.MCPServerInfo, error) {
+	serverConfig, err := s.serverConfig(name)
+	if err != nil {
+		return types.MCPServerInfo{}, err
+	}
+
+	s.checkServer(ctx, name)
+	return s.snapshot(name, serverConfig), nil
+}
+
+// RestartServer stops and starts a server and waits until it is running.
+func (s *mcpSupervisor) RestartServer(ctx context.Context, name string) error {
+	if _, err := s.serverConfig(name); err != nil {
+		return err
+	}
+
+	if err := s.restart(ctx, name); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	health := s.healthFor(name)
+	health.restarts = 0
+	health.nextRestart = time.Time{}
+	s.mutex.Unlock()
+
+	s.checkServer(ctx, name)
+	return nil
+}
+
+// OnRestart registers a callback invoked after a server was restarted.
+func (s *mcpSupervisor) OnRestart(callback func(serverName string)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// Close stops supervision.
+func (s *mcpSupervisor) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	<-s.done
+	return nil
+}
+
+// Shutdown implements do.Shutdownable so the container stops supervision.
+func (s *mcpSupervisor) Shutdown() error {
+	return s.Close()
+}
+
+// run executes the health check loop until the context is cancelled. The first check
+// runs here rather than in the constructor, so unreachable servers do not block it.
+func (s *mcpSupervisor) run(ctx context.Context) {
+	defer close(s.done)
+
+	for _, info := range s.ListServers() {
+		if ctx.Err() != nil {
+			return
+		}
+		if info.State != types.MCPServerStateDisabled {
+			s.checkServer(ctx, info.Name)
+		}
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkAll(ctx)
+		}
+	}
+}
+
+// checkAll checks every enabled server and restarts failed ones whose backoff elapsed.
+func (s *mcpSupervisor) checkAll(ctx context.Context) {
+	for _, info := range s.ListServers() {
+		if info.State == types.MCPServerStateDisabled {
+			continue
+		}
+
+		healthy := s.checkServer(ctx, info.Name)
+		if !healthy && s.shouldRestart(info.Name) {
+			s.restartWithBackoff(ctx, info.Name)
+		}
+	}
+}
+
+// checkServer pings a running server, refreshes its tool count and reports whether it is healthy.
+func (s *mcpSupervisor) checkServer(ctx context.Context, name string) bool {
+	adapter := s.registry.GetAdapter()
+	if adapter == nil || adapter.GetServerStatus(name) != mcpadapter.StatusRunning {
+		if adapter != nil && adapter.GetServerStatus(name) == mcpadapter.StatusError {
+			s.recordError(name, "server failed to start")
+		}
+		return false
+	}
+
+	client, ok := s.registry.GetClient(name)
+	if !ok {
+		s.recordError(name, "no client available")
+		return false
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, mcpPingTimeout)
+	defer cancel()
+
+	if err := client.Ping(pingCtx); err != nil {
+		s.recordError(name, fmt.Sprintf("health ping failed: %v", err))
+		return false
+	}
+
+	toolCount := -1
+	if result, err := client.ListTools(pingCtx, mcp.ListToolsRequest{}); err == nil {
+		toolCount = len(result.Tools)
+	}
+
+	s.mutex.Lock()
+	health := s.healthFor(name)
+	health.lastPing = time.Now()
+	health.lastError = ""
+	// Only forgive earlier restarts once the server stayed up longer than the maximum backoff
+	if process := s.registry.getProcess(name); process != nil && time.Since(process.startedAt) > mcpRestartMaxDelay {
+		health.restarts = 0
+	}
+	if toolCount >= 0 {
+		health.toolCount = toolCount
+	}
+	s.mutex.Unlock()
+
+	return true
+}
+
+// shouldRestart reports whether a failed server may be restarted now.
+func (s *mcpSupervisor) shouldRestart(name string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	health := s.health[name]
+	if health == nil {
+		return true
+	}
+	if s.maxRestarts > 0 && health.restarts >= s.maxRestarts {
+		return false
+	}
+	return !time.Now().Before(health.nextRestart)
+}
+
+// restartWithBackoff restarts a failed server and schedules the next allowed restart.
+func (s *mcpSupervisor) restartWithBackoff(ctx context.Context, name string) {
+	s.mutex.Lock()
+	health := s.healthFor(name)
+	health.restarts++
+	health.nextRestart = time.Now().Add(restartBackoff(s.interval, health.restarts))
+	attempt := health.restarts
+	s.mutex.Unlock()
+
+	s.log.Warn("Restarting unhealthy MCP server",
+		zap.String("server", name),
+		zap.Int("attempt", attempt))
+
+	if err := s.restart(ctx, name); err != nil {
+		s.recordError(name, err.Error())
+		s.log.Error("Failed to restart MCP server", zap.String("server", name), zap.Error(err))
+	}
+}
+
+// restart stops a server, starts it again and waits until it is running.
+func (s *mcpSupervisor) restart(ctx context.Context, name string) error {
+	adapter := s.registry.GetAdapter()
+	if adapter == nil {
+		return fmt.Errorf("MCP integration is disabled")
+	}
+
+	if err := adapter.StopServer(name); err != nil {
+		s.log.Debug("Stopping MCP server before restart failed", zap.String("server", name), zap.Error(err))
+	}
+	if err := adapter.StartServer(context.Background(), name); err != nil {
+		return fmt.Errorf("failed to start server %s: %w", name, err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	for adapter.GetServerStatus(name) != mcpadapter.StatusRunning {
+		if adapter.GetServerStatus(name) == mcpadapter.StatusError {
+			return fmt.Errorf("server %s failed to start", name)
+		}
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("timeout waiting for server %s to start", name)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	s.log.Info("MCP server restarted", zap.String("server", name))
+
+	s.mutex.RLock()
+	callbacks := append([]func(string){}, s.callbacks...)
+	s.mutex.RUnlock()
+	for _, callback := range callbacks {
+		callback(name)
+	}
+
+	return nil
+}
+
+// snapshot builds the public view of a server.
+func (s *mcpSupervisor) snapshot(name string, serverConfig *mcpadapter.ServerConfig) types.MCPServerInfo {
+	info := types.MCPServerInfo{
+		Name:      name,
+		Transport: serverConfig.Transport,
+		State:     types.MCPServerStateDisabled,
+		LogFile:   s.registry.getLogFile(name),
+	}
+	if info.Transport == "" {
+		info.Transport = mcpadapter.TransportStdio
+	}
+
+	if !serverConfig.Disabled {
+		info.State = convertServerStatus(s.registry.GetAdapter().GetServerStatus(name))
+	}
+
+	if process := s.registry.getProcess(name); process != nil {
+		info.PID = process.pid()
+		info.StartedAt = process.startedAt
+		if process.logFile == "" {
+			info.LogFile = ""
+		}
+	}
+
+	s.mutex.RLock()
+	if health := s.health[name]; health != nil {
+		info.LastPing = health.lastPing
+		info.LastError = health.lastError
+		info.ToolCount = health.toolCount
+		info.Restarts = health.restarts
+	}
+	s.mutex.RUnlock()
+
+	return info
+}
+
+// serverConfig returns the configuration of a server or an error if it is unknown.
+func (s *mcpSupervisor) serverConfig(name string) (*mcpadapter.ServerConfig, error) {
+	adapter := s.registry.GetAdapter()
+	if adapter == nil || adapter.GetConfig() == nil {
+		return nil, fmt.Errorf("MCP integration is disabled")
+	}
+
+	serverConfig, exists := adapter.GetConfig().McpServers[name]
+	if !exists {
+		return nil, fmt.Errorf("MCP server %s not found", name)
+	}
+	return serverConfig, nil
+}
+
+// recordError stores the last error of a server.
+func (s *mcpSupervisor) recordError(name, message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.healthFor(name).lastError = message
+}
+
+// healthFor returns the health record of a server, creating it if needed. Callers hold the lock.
+func (s *mcpSupervisor) healthFor(name string) *mcpServerHealth {
+	health, exists := s.health[name]
+	if !exists {
+		health = &mcpServerHealth{}
+		s.health[name] = health
+	}
+	return health
+}
+
+// restartBackoff returns the exponential backoff delay for the given restart attempt.
+// The delay starts at the health check interval and doubles with every attempt.
+func restartBackoff(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= mcpRestartMaxDelay {
+			return mcpRestartMaxDelay
+		}
+	}
+	return delay
+}
+
+// convertServerStatus maps adapter statuses to supervisor states.
+func convertServerStatus(status mcpadapter.ServerStatus) types.MCPServerState {
+	switch status {
+	case mcpadapter.StatusStarting:
+		return types.MCPServerStateStarting
+	case mcpadapter.StatusRunning:
+		return types.MCPServerStateRunning
+	case mcpadapter.StatusError:
+		return types.MCPServerStateError
+	default:
+		return types.MCPServerStateStopped
+	}
+}
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mcpadapter "github.com/denkhaus/mcp-server-adapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/config"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+func TestRotatingWriter_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+
+	writer, err := newRotatingWriter(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		_, err := writer.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "dddddddd\n", string(current))
+
+	newest, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "cccccccc\n", string(newest))
+
+	oldest, err := os.ReadFile(path + ".2")
+	require.NoError(t, err)
+	assert.Equal(t, "bbbbbbbb\n", string(oldest))
+
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = writer.Write([]byte("closed"))
+	assert.Error(t, err)
+}
+
+func TestRotatingWriter_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "server.log")
+
+	first, err := newRotatingWriter(path, 1024, 1)
+	require.NoError(t, err)
+	_, _ = first.Write([]byte("first\n"))
+	require.NoError(t, first.Close())
+
+	second, err := newRotatingWriter(path, 1024, 1)
+	require.NoError(t, err)
+	_, _ = second.Write([]byte("second\n"))
+	require.NoError(t, second.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, strings.Fields(string(content)))
+}
+
+func TestRestartBackoff(t *testing.T) {
+	base := 10 * time.Second
+
+	assert.Equal(t, base, restartBackoff(base, 1))
+	assert.Equal(t, 20*time.Second, restartBackoff(base, 2))
+	assert.Equal(t, 40*time.Second, restartBackoff(base, 3))
+	assert.Equal(t, mcpRestartMaxDelay, restartBackoff(base, 20))
+}
+
+func TestConvertServerStatus(t *testing.T) {
+	assert.Equal(t, types.MCPServerStateStarting, convertServerStatus(mcpadapter.StatusStarting))
+	assert.Equal(t, types.MCPServerStateRunning, convertServerStatus(mcpadapter.StatusRunning))
+	assert.Equal(t, types.MCPServerStateError, convertServerStatus(mcpadapter.StatusError))
+	assert.Equal(t, types.MCPServerStateStopped, convertServerStatus(mcpadapter.StatusStopped))
+}
+
+func TestMCPSupervisor_Disabled(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	cfg := &config.MCPConfig{Enabled: false, HealthInterval: 30, MaxRestarts: 5}
+
+	registry, err := NewMCPServerRegistry(log, cfg)
+	require.NoError(t, err)
+
+	supervisor, err := NewMCPSupervisor(log, cfg, registry)
+	require.NoError(t, err)
+
+	assert.Empty(t, supervisor.ListServers())
+	_, err = supervisor.GetServer("missing")
+	assert.Error(t, err)
+	assert.NoError(t, supervisor.Close())
+}
@@ -22,6 +22,9 @@ type mcpToolProvider struct {
 	tools   map[string]tools.Tool
 	mutex   sync.RWMutex
 
+	// resources are exposed as list and read tools, optional
+	resources types.ResourceProvider
+
 	// Performance optimizations
 	toolsSlice []tools.Tool // Pre-built slice for GetTools()
 }
@@ -47,11 +50,66 @@ func NewMCPToolProvider(log *zap.Logger, cfg *config.MCPConfig) (types.ToolProvi
 		return nil, fmt.Errorf("failed to create MCP adapter: %w", err)
 	}
 
+	return newMCPToolProviderWithAdapter(log, cfg, mcpAdapter, nil)
+}
+
+// NewMCPToolProviderWithRegistry creates an MCP tool provider that shares the
+// adapter of the given registry, so tools, resources and prompts use the same servers.
+// The resources of the servers are exposed as the tools mcp_list_resources and
+// mcp_read_resource. When a supervisor is given, the tools are reloaded after
+// every server restart.
+func NewMCPToolProviderWithRegistry(
+	log *zap.Logger,
+	cfg *config.MCPConfig,
+	registry types.MCPServerRegistry,
+	supervisor types.MCPSupervisor,
+) (types.ToolProvider, error) {
+	source, ok := registry.(mcpAdapterSource)
+	if !cfg.Enabled || !ok || source.GetAdapter() == nil {
+		disabled := *cfg
+		disabled.Enabled = false
+		return NewMCPToolProvider(log, &disabled)
+	}
+
+	resources := NewMCPResourceProvider(log, registry)
+	provider, err := newMCPToolProviderWithAdapter(log, cfg, source.GetAdapter(), resources)
+	if err != nil {
+		return nil, err
+	}
+
+	if supervisor != nil {
+		mcpProvider := provider.(*mcpToolProvider)
+		supervisor.OnRestart(func(serverName string) {
+			if err := mcpProvider.reloadMCPTools(); err != nil {
+				log.Error("Failed to reload MCP tools after restart",
+					zap.String("server", serverName),
+					zap.Error(err))
+			}
+		})
+	}
+
+	return provider, nil
+}
+
+// mcpAdapterSource is implemented by registries that expose their MCP adapter.
+type mcpAdapterSource interface {
+	GetAdapter() mcpadapter.MCPAdapter
+}
+
+// newMCPToolProviderWithAdapter creates a provider and loads the tools of the given
+// adapter, plus the resource tools if resources is not nil.
+func newMCPToolProviderWithAdapter(
+	log *zap.Logger,
+	cfg *config.MCPConfig,
+	mcpAdapter mcpadapter.MCPAdapter,
+	resources types.ResourceProvider,
+) (types.ToolProvider, error) {
 	provider := &mcpToolProvider{
-		log:     log,
-		adapter: mcpAdapter,
-		config:  cfg,
-		tools:   make(map[string]tools.Tool),
+		log:       log,
+		adapter:   mcpAdapter,
+		config:    cfg,
+		tools:     make(map[string]tools.Tool),
+		resources: resources,
 	}
 
 	// Load tools from MCP servers
@@ -192,6 +250,10 @@ func (p *mcpToolProvider) loadMCPTools() error {
 		return fmt.Errorf("failed to get tools from MCP adapter: %w", err)
 	}
 
+	if p.resources != nil {
+		mcpTools = append(mcpTools, NewMCPResourceTools(p.resources)...)
+	}
+
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
@@ -220,6 +282,15 @@ func (p *mcpToolProvider) loadMCPTools() error {
 	return nil
 }
 
+// reloadMCPTools discards all loaded tools and loads them again from the running servers.
+func (p *mcpToolProvider) reloadMCPTools() error {
+	p.mutex.Lock()
+	p.tools = make(map[string]tools.Tool)
+	p.mutex.Unlock()
+
+	return p.loadMCPTools()
+}
+
 // rebuildToolsSlice rebuilds the pre-built tools slice for GetTools().
 func (p *mcpToolProvider) rebuildToolsSlice() {
 	p.toolsSlice = make([]tools.Tool, 0, len(p.tools))
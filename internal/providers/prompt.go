@@ -4,13 +4,13 @@ package providers
 import (
 	"bytes"
 	"fmt"
-	"strings"
 	"sync"
 	"text/template"
 
 	"github.com/samber/do"
 	"go.uber.org/zap"
 
+	"github.com/denkhaus/agentforge/internal/promptrender"
 	"github.com/denkhaus/agentforge/internal/types"
 )
 
@@ -62,17 +62,13 @@ func (p *promptProvider) GetSystemPrompt() string {
 
 // FormatPrompt formats a prompt template with the given variables.
 func (p *promptProvider) FormatPrompt(templateStr string, vars map[string]any) (string, error) {
-	tmpl, err := template.New("prompt").Parse(templateStr)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	var result strings.Builder
-	if err := tmpl.Execute(&result, vars); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
-	}
+	return formatTemplate(templateStr, vars)
+}
 
-	return result.String(), nil
+// formatTemplate renders a prompt template with the given variables. Missing
+// variables render as "<no value>" like they did before templates were checked.
+func formatTemplate(templateStr string, vars map[string]any) (string, error) {
+	return promptrender.New(nil, nil).AllowMissing().Render("prompt", templateStr, vars)
 }
 
 // Removed GetPromptTemplate - use GetPrompt().GetTemplate() instead
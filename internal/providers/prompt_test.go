@@ -86,6 +86,20 @@ func TestPromptProviderFormatPromptError(t *testing.T) {
 	}
 }
 
+func TestPromptProviderFormatPromptMissingVariable(t *testing.T) {
+	provider := createTestPromptProvider(t)
+
+	result, err := provider.FormatPrompt("Hello {{.name}}", map[string]any{})
+	if err != nil {
+		t.Fatalf("Failed to format prompt: %v", err)
+	}
+
+	expected := "Hello <no value>"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
 func TestPromptProviderGetPrompts(t *testing.T) {
 	provider := createTestPromptProvider(t)
 
@@ -11,6 +11,7 @@ import (
 	"github.com/tmc/langchaingo/tools"
 	"go.uber.org/zap"
 
+	"github.com/denkhaus/agentforge/internal/errors"
 	"github.com/denkhaus/agentforge/internal/types"
 )
 
@@ -20,6 +21,9 @@ type toolProvider struct {
 	tools map[string]tools.Tool
 	mutex sync.RWMutex
 
+	// health marks tools unhealthy, optional
+	health types.ToolHealthChecker
+
 	// Performance optimizations
 	toolsSlice []tools.Tool            // Pre-built slice for GetTools()
 	toolsCache map[string][]tools.Tool // Cache tools by agent required tools hash
@@ -37,6 +41,14 @@ func NewToolProvider(injector *do.Injector) (types.ToolProvider, error) {
 		return nil, fmt.Errorf("failed to get tools slice: %w", err)
 	}
 	
+	provider := newToolProvider(log, toolsSlice)
+	provider.log.Info("Tool provider initialized", zap.Int("tool_count", len(provider.tools)))
+
+	return provider, nil
+}
+
+// newToolProvider creates a tool provider serving the given tools.
+func newToolProvider(log *zap.Logger, toolsSlice []tools.Tool) *toolProvider {
 	// Convert slice to map for efficient lookup
 	toolsMap := make(map[string]tools.Tool)
 	for _, tool := range toolsSlice {
@@ -52,9 +64,7 @@ func NewToolProvider(injector *do.Injector) (types.ToolProvider, error) {
 	// Pre-build tools slice for better performance
 	provider.rebuildToolsSlice()
 
-	provider.log.Info("Tool provider initialized", zap.Int("tool_count", len(provider.tools)))
-
-	return provider, nil
+	return provider
 }
 
 // Removed Startup method - Provider interface eliminated
@@ -82,9 +92,7 @@ func (p *toolProvider) GetToolsForAgent(agent types.Agent) ([]tools.Tool, error)
 	if cachedTools, exists := p.toolsCache[cacheKey]; exists {
 		p.mutex.RUnlock()
 		// Return copy of cached tools
-		result := make([]tools.Tool, len(cachedTools))
-		copy(result, cachedTools)
-		return result, nil
+		return p.healthyTools(cachedTools), nil
 	}
 	p.mutex.RUnlock()
 
@@ -114,9 +122,23 @@ func (p *toolProvider) GetToolsForAgent(agent types.Agent) ([]tools.Tool, error)
 		zap.Int("loaded_tools", len(agentTools)))
 
 	// Return copy
-	result := make([]tools.Tool, len(agentTools))
-	copy(result, agentTools)
-	return result, nil
+	return p.healthyTools(agentTools), nil
+}
+
+// IsToolHealthy reports whether a tool is not marked unhealthy by the health checker.
+func (p *toolProvider) IsToolHealthy(name string) bool {
+	return p.health == nil || p.health.IsToolHealthy(name)
+}
+
+// healthyTools returns a copy of the given tools without those marked unhealthy.
+func (p *toolProvider) healthyTools(agentTools []tools.Tool) []tools.Tool {
+	result := make([]tools.Tool, 0, len(agentTools))
+	for _, tool := range agentTools {
+		if p.IsToolHealthy(tool.Name()) {
+			result = append(result, tool)
+		}
+	}
+	return result
 }
 
 // HasTool checks if a tool with the given name is available.
@@ -171,6 +193,10 @@ func (p *toolProvider) ExecuteTool(ctx context.Context, name string, input strin
 		return "", fmt.Errorf("tool %s not found", name)
 	}
 
+	if !p.IsToolHealthy(name) {
+		return "", fmt.Errorf("tool %s is unhealthy: %w", name, errors.ErrServiceUnavailable)
+	}
+
 	p.log.Info("Executing tool",
 		zap.String("name", name),
 		zap.String("input", input))
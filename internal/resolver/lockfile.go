@@ -0,0 +1,109 @@
+package resolver
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// LockfileName is the file resolved dependencies are locked in.
+const LockfileName = "forge.lock"
+
+// lockfileVersion is the format version written into lockfiles.
+const lockfileVersion = 1
+
+// lockfileHeader is written above the locked components.
+const lockfileHeader = "# Generated by forge lock. Do not edit.\n"
+
+// Lockfile pins every component of a workspace to a commit and spec hash.
+type Lockfile struct {
+	LockfileVersion int               `yaml:"lockfileVersion"`
+	Components      []LockedComponent `yaml:"components"`
+}
+
+// LockedComponent is a resolved component.
+type LockedComponent struct {
+	Kind       schema.ComponentKind `yaml:"kind"`
+	Name       string               `yaml:"name"`
+	Version    string               `yaml:"version"`
+	Repository string               `yaml:"repository"`
+	// Ref is the checked out tag or branch, empty for the default branch
+	Ref      string `yaml:"ref,omitempty"`
+	Commit   string `yaml:"commit"`
+	SpecHash string `yaml:"specHash"`
+	// Requirements are the ranges the version was resolved against
+	Requirements []string `yaml:"requirements,omitempty"`
+	// Dependencies are the locked components this one depends on, as kind/name
+	Dependencies []string `yaml:"dependencies,omitempty"`
+}
+
+// Key returns the kind/name key components are referenced by.
+func (c LockedComponent) Key() string {
+	return componentKey(c.Kind, c.Name)
+}
+
+// Find returns the locked component of kind and name, or nil.
+func (l *Lockfile) Find(kind schema.ComponentKind, name string) *LockedComponent {
+	for i := range l.Components {
+		if l.Components[i].Kind == kind && l.Components[i].Name == name {
+			return &l.Components[i]
+		}
+	}
+	return nil
+}
+
+// ReadLockfile reads the lockfile at path.
+func ReadLockfile(path string) (*Lockfile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	var lockfile Lockfile
+	if err := yaml.Unmarshal(content, &lockfile); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if lockfile.LockfileVersion != lockfileVersion {
+		return nil, fmt.Errorf("lockfile %s has unsupported version %d", path, lockfile.LockfileVersion)
+	}
+	return &lockfile, nil
+}
+
+// Write writes the lockfile to path with components in a stable order.
+func (l *Lockfile) Write(path string) error {
+	l.LockfileVersion = lockfileVersion
+	sort.Slice(l.Components, func(i, j int) bool {
+		return l.Components[i].Key() < l.Components[j].Key()
+	})
+
+	content, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to serialize lockfile: %w", err)
+	}
+	if err := os.WriteFile(path, append([]byte(lockfileHeader), content...), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// Check returns an error if a requirement is not met by the locked components,
+// which means the workspace changed since the lockfile was written.
+func (l *Lockfile) Check(requirements []Requirement) error {
+	for _, requirement := range requirements {
+		locked := l.Find(requirement.Kind, requirement.Name)
+		switch {
+		case locked == nil:
+			return fmt.Errorf("%s is not locked", componentKey(requirement.Kind, requirement.Name))
+		case locked.Repository != requirement.Repository:
+			return fmt.Errorf("%s is locked from %s instead of %s", locked.Key(), locked.Repository, requirement.Repository)
+		case requirement.Range != "" && !slices.Contains(locked.Requirements, requirement.Range) &&
+			!Satisfies(locked.Version, requirement.Range):
+			return fmt.Errorf("%s is locked at %s, which does not satisfy %s", locked.Key(), locked.Version, requirement.Range)
+		}
+	}
+	return nil
+}
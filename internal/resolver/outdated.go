@@ -0,0 +1,121 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Update describes the newer tags of a locked component's repository.
+type Update struct {
+	Component LockedComponent
+	// Compatible is the highest tag satisfying the component's requirements
+	Compatible string
+	// Latest is the highest released tag
+	Latest string
+}
+
+// HasCompatible reports whether a newer tag satisfies the requirements.
+func (u Update) HasCompatible() bool {
+	return newerTag(u.Compatible, u.Component.Ref)
+}
+
+// HasBreaking reports whether a newer tag outside the requirements exists.
+func (u Update) HasBreaking() bool {
+	return newerTag(u.Latest, u.Component.Ref) && newerTag(u.Latest, u.Compatible)
+}
+
+// Outdated returns the locked components whose repositories have tags newer than
+// the locked one. Components locked to a branch are not compared.
+func Outdated(ctx context.Context, source Source, lockfile *Lockfile) ([]Update, error) {
+	var updates []Update
+	for _, component := range lockfile.Components {
+		if _, err := semver.NewVersion(component.Ref); err != nil {
+			continue
+		}
+		tags, err := source.Tags(ctx, component.Repository)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s: %w", component.Repository, err)
+		}
+
+		update := Update{Component: component}
+		update.Compatible, _ = ResolveRef(tags, component.Requirements...)
+		update.Latest, _ = ResolveRef(tags)
+		if update.HasCompatible() || update.HasBreaking() {
+			updates = append(updates, update)
+		}
+	}
+	return updates, nil
+}
+
+// newerTag reports whether tag is a version above ref. Any version is newer
+// than a ref that is not a version, such as no compatible tag.
+func newerTag(tag, ref string) bool {
+	version, err := semver.NewVersion(tag)
+	if err != nil {
+		return false
+	}
+	current, err := semver.NewVersion(ref)
+	return err != nil || version.GreaterThan(current)
+}
+
+// Change is a component added, removed or moved to another commit between two lockfiles.
+type Change struct {
+	Key string
+	// From is nil for added components
+	From *LockedComponent
+	// To is nil for removed components
+	To *LockedComponent
+}
+
+// SpecChanged reports whether the manifest of a changed component differs.
+func (c Change) SpecChanged() bool {
+	return c.From != nil && c.To != nil && c.From.SpecHash != c.To.SpecHash
+}
+
+// Changes returns the changes from one lockfile to another in key order. A nil
+// from lockfile adds every component.
+func Changes(from, to *Lockfile) []Change {
+	previous := make(map[string]*LockedComponent)
+	if from != nil {
+		for i := range from.Components {
+			previous[from.Components[i].Key()] = &from.Components[i]
+		}
+	}
+
+	var changes []Change
+	for i := range to.Components {
+		component := &to.Components[i]
+		old := previous[component.Key()]
+		delete(previous, component.Key())
+		if old == nil || old.Commit != component.Commit || old.Repository != component.Repository {
+			changes = append(changes, Change{Key: component.Key(), From: old, To: component})
+		}
+	}
+	for key, old := range previous {
+		changes = append(changes, Change{Key: key, From: old})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// SpecDiff returns the unified diff of two manifests of a component.
+func SpecDiff(from, to *Component) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(strings.TrimRight(string(from.Spec), "\n") + "\n"),
+		B:        difflib.SplitLines(strings.TrimRight(string(to.Spec), "\n") + "\n"),
+		FromFile: fmt.Sprintf("%s %s", componentKey(from.Kind, from.Name), from.Version),
+		ToFile:   fmt.Sprintf("%s %s", componentKey(to.Kind, to.Name), to.Version),
+		Context:  3,
+	})
+	if err != nil {
+		return ""
+	}
+	return diff
+}
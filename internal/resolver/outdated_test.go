@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestOutdated(t *testing.T) {
+	source := &fakeSource{}
+	for _, tag := range []string{"v1.0.0", "v1.1.0", "v2.0.0"} {
+		source.add("/repos/search", tag, &Component{Kind: schema.KindTool, Name: "search"})
+	}
+	for _, tag := range []string{"v0.1.0", "v0.2.0"} {
+		source.add("/repos/greeting", tag, &Component{Kind: schema.KindPrompt, Name: "greeting"})
+	}
+	source.add("/repos/notes", "v1.0.0", &Component{Kind: schema.KindTool, Name: "notes"})
+
+	lockfile := &Lockfile{Components: []LockedComponent{
+		{Kind: schema.KindTool, Name: "search", Repository: "/repos/search", Ref: "v1.0.0", Requirements: []string{"^1.0.0"}},
+		{Kind: schema.KindPrompt, Name: "greeting", Repository: "/repos/greeting", Ref: "v0.1.0", Requirements: []string{"~0.1.0"}},
+		{Kind: schema.KindTool, Name: "notes", Repository: "/repos/notes", Ref: "v1.0.0"},
+		{Kind: schema.KindAgent, Name: "helper", Repository: "/repos/helper", Ref: "main"},
+	}}
+
+	updates, err := Outdated(context.Background(), source, lockfile)
+	require.NoError(t, err)
+	require.Len(t, updates, 2, "up to date and branch components are not listed")
+
+	search := updates[0]
+	assert.Equal(t, "search", search.Component.Name)
+	assert.Equal(t, "v1.1.0", search.Compatible)
+	assert.Equal(t, "v2.0.0", search.Latest)
+	assert.True(t, search.HasCompatible())
+	assert.True(t, search.HasBreaking())
+
+	greeting := updates[1]
+	assert.Equal(t, "greeting", greeting.Component.Name)
+	assert.False(t, greeting.HasCompatible(), "~0.1.0 excludes v0.2.0")
+	assert.True(t, greeting.HasBreaking())
+}
+
+func TestChanges(t *testing.T) {
+	from := &Lockfile{Components: []LockedComponent{
+		{Kind: schema.KindTool, Name: "search", Version: "1.0.0", Commit: "a", SpecHash: "x"},
+		{Kind: schema.KindTool, Name: "notes", Version: "1.0.0", Commit: "b", SpecHash: "y"},
+		{Kind: schema.KindPrompt, Name: "greeting", Version: "0.1.0", Commit: "c", SpecHash: "z"},
+	}}
+	to := &Lockfile{Components: []LockedComponent{
+		{Kind: schema.KindTool, Name: "search", Version: "1.1.0", Commit: "d", SpecHash: "w"},
+		{Kind: schema.KindTool, Name: "notes", Version: "1.0.0", Commit: "b", SpecHash: "y"},
+		{Kind: schema.KindAgent, Name: "helper", Version: "1.0.0", Commit: "e", SpecHash: "v"},
+	}}
+
+	changes := Changes(from, to)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "agent/helper", changes[0].Key)
+	assert.Nil(t, changes[0].From)
+	assert.Equal(t, "prompt/greeting", changes[1].Key)
+	assert.Nil(t, changes[1].To)
+	assert.Equal(t, "tool/search", changes[2].Key)
+	assert.True(t, changes[2].SpecChanged())
+
+	assert.Len(t, Changes(nil, to), 3, "without a lockfile every component is added")
+}
+
+func TestSpecDiff(t *testing.T) {
+	from := &Component{Kind: schema.KindTool, Name: "search", Version: "1.0.0", Spec: []byte("name: search\nversion: 1.0.0\n")}
+	to := &Component{Kind: schema.KindTool, Name: "search", Version: "1.1.0", Spec: []byte("name: search\nversion: 1.1.0\n")}
+
+	diff := SpecDiff(from, to)
+	assert.Contains(t, diff, "--- tool/search 1.0.0")
+	assert.Contains(t, diff, "+++ tool/search 1.1.0")
+	assert.Contains(t, diff, "-version: 1.0.0")
+	assert.Contains(t, diff, "+version: 1.1.0")
+}
+
+func TestLockfileCheck(t *testing.T) {
+	lockfile := &Lockfile{Components: []LockedComponent{
+		{Kind: schema.KindTool, Name: "search", Version: "1.1.0", Repository: "/repos/search", Ref: "v1.1.0", Requirements: []string{"^1.0.0"}},
+	}}
+
+	assert.NoError(t, lockfile.Check([]Requirement{
+		{Kind: schema.KindTool, Name: "search", Repository: "/repos/search", Range: "^1.0.0"},
+	}))
+	assert.NoError(t, lockfile.Check([]Requirement{
+		{Kind: schema.KindTool, Name: "search", Repository: "/repos/search", Range: ">=1.1.0"},
+	}))
+	assert.ErrorContains(t, lockfile.Check([]Requirement{
+		{Kind: schema.KindTool, Name: "search", Repository: "/repos/search", Range: "^2.0.0"},
+	}), "does not satisfy ^2.0.0")
+	assert.ErrorContains(t, lockfile.Check([]Requirement{
+		{Kind: schema.KindPrompt, Name: "greeting", Repository: "/repos/greeting"},
+	}), "prompt/greeting is not locked")
+}
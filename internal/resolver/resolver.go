@@ -0,0 +1,289 @@
+// Package resolver resolves the tool, prompt and agent dependency graph of a
+// workspace to versions satisfying every version range and locks them.
+//
+// Versions come from the semver tags of the component repositories. Each
+// repository resolves to the highest tag satisfying the ranges of all its
+// requirers. The resolver does not backtrack to older versions of a requirer
+// to avoid a conflict, conflicts are reported with the requirements involved.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/logger"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+var log = logger.WithPackage("resolver")
+
+// maxRounds bounds the selection rounds, as re-selected versions change the
+// requirements of the next round.
+const maxRounds = 50
+
+// Requirement is a version requirement on the component of a repository.
+type Requirement struct {
+	Kind       schema.ComponentKind
+	Name       string
+	Repository string
+	// Range is a semver range, a tag or a branch, empty for the latest version
+	Range string
+	// RequiredBy names the requirer, the workspace or a component and its version
+	RequiredBy string
+}
+
+// String describes the requirement for messages.
+func (r Requirement) String() string {
+	version := r.Range
+	if version == "" {
+		version = "any version"
+	}
+	return fmt.Sprintf("%s requires %s %s %s", r.RequiredBy, strings.ToLower(string(r.Kind)), r.Name, version)
+}
+
+// ConflictError reports a repository no version of which satisfies all requirements.
+type ConflictError struct {
+	Repository   string
+	Requirements []Requirement
+	Tags         []string
+	Reason       string
+}
+
+func (e *ConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "no version of %s satisfies all requirements (%s):", e.Repository, e.Reason)
+	for _, requirement := range e.Requirements {
+		fmt.Fprintf(&b, "\n  %s", requirement)
+	}
+	tags := "none"
+	if len(e.Tags) > 0 {
+		tags = strings.Join(e.Tags, ", ")
+	}
+	fmt.Fprintf(&b, "\n  available tags: %s", tags)
+	return b.String()
+}
+
+// Resolver resolves requirements to locked components.
+type Resolver interface {
+	Resolve(ctx context.Context, requirements []Requirement) (*Lockfile, error)
+}
+
+// resolver implements Resolver on a Source.
+type resolver struct {
+	source Source
+}
+
+// NewResolver creates a resolver reading versions and manifests from source.
+func NewResolver(source Source) Resolver {
+	return &resolver{source: source}
+}
+
+// selection is the version a repository resolved to.
+type selection struct {
+	ref       string
+	component *Component
+}
+
+// resolveRun holds the caches of one resolution.
+type resolveRun struct {
+	source     Source
+	tags       map[string][]string
+	components map[string]*Component
+}
+
+// Resolve selects a version for every repository reachable from requirements and
+// returns the lockfile of the selected components.
+func (r *resolver) Resolve(ctx context.Context, requirements []Requirement) (*Lockfile, error) {
+	run := &resolveRun{
+		source:     r.source,
+		tags:       make(map[string][]string),
+		components: make(map[string]*Component),
+	}
+	selected := make(map[string]*selection)
+
+	for round := 0; round < maxRounds; round++ {
+		grouped := reachable(requirements, selected)
+		for repository := range selected {
+			if _, ok := grouped[repository]; !ok {
+				delete(selected, repository)
+			}
+		}
+
+		changed := false
+		var conflicts []error
+		for _, repository := range sortedKeys(grouped) {
+			ref, err := run.selectRef(ctx, repository, grouped[repository])
+			if err != nil {
+				conflicts = append(conflicts, err)
+				continue
+			}
+			if current := selected[repository]; current != nil && current.ref == ref {
+				continue
+			}
+			component, err := run.fetch(ctx, repository, ref)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkKind(component, grouped[repository]); err != nil {
+				return nil, err
+			}
+			selected[repository] = &selection{ref: ref, component: component}
+			changed = true
+		}
+		if len(conflicts) > 0 {
+			return nil, errors.Join(conflicts...)
+		}
+		if !changed {
+			return lockfileOf(grouped, selected), nil
+		}
+	}
+	return nil, fmt.Errorf("dependency resolution did not settle after %d rounds", maxRounds)
+}
+
+// selectRef returns the ref of repository satisfying all requirements.
+func (run *resolveRun) selectRef(ctx context.Context, repository string, requirements []Requirement) (string, error) {
+	tags, ok := run.tags[repository]
+	if !ok {
+		var err error
+		if tags, err = run.source.Tags(ctx, repository); err != nil {
+			return "", err
+		}
+		run.tags[repository] = tags
+	}
+
+	ranges := make([]string, len(requirements))
+	for i, requirement := range requirements {
+		ranges[i] = requirement.Range
+	}
+	ref, err := ResolveRef(tags, ranges...)
+	if err != nil {
+		return "", &ConflictError{Repository: repository, Requirements: requirements, Tags: tags, Reason: err.Error()}
+	}
+	return ref, nil
+}
+
+// fetch returns the component of repository at ref.
+func (run *resolveRun) fetch(ctx context.Context, repository, ref string) (*Component, error) {
+	key := repository + "@" + ref
+	if component, ok := run.components[key]; ok {
+		return component, nil
+	}
+	log.Debug("Fetching component", zap.String("repository", repository), zap.String("ref", ref))
+	component, err := run.source.Fetch(ctx, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	run.components[key] = component
+	return component, nil
+}
+
+// reachable returns the requirements per repository reachable from the roots
+// through the dependencies of the selected components.
+func reachable(roots []Requirement, selected map[string]*selection) map[string][]Requirement {
+	grouped := make(map[string][]Requirement)
+	visited := make(map[string]bool)
+	queue := append([]Requirement(nil), roots...)
+	for len(queue) > 0 {
+		requirement := queue[0]
+		queue = queue[1:]
+		grouped[requirement.Repository] = append(grouped[requirement.Repository], requirement)
+
+		current := selected[requirement.Repository]
+		if current == nil || visited[requirement.Repository] {
+			continue
+		}
+		visited[requirement.Repository] = true
+		queue = append(queue, dependencyRequirements(current.component)...)
+	}
+	return grouped
+}
+
+// dependencyRequirements returns the requirements a component places on the
+// repositories of its dependencies. Built-in dependencies are not resolved.
+func dependencyRequirements(component *Component) []Requirement {
+	var requirements []Requirement
+	for _, dependency := range component.Dependencies {
+		if !schema.IsRepository(dependency.Repository) {
+			continue
+		}
+		requirements = append(requirements, Requirement{
+			Kind:       dependency.Kind,
+			Name:       dependency.Name,
+			Repository: git.RepositoryURL(dependency.Repository),
+			Range:      dependency.Version,
+			RequiredBy: fmt.Sprintf("%s %s %s", strings.ToLower(string(component.Kind)), component.Name, component.Version),
+		})
+	}
+	return requirements
+}
+
+// checkKind verifies that a fetched component is of the kind its requirers expect.
+func checkKind(component *Component, requirements []Requirement) error {
+	for _, requirement := range requirements {
+		if requirement.Kind != component.Kind {
+			return fmt.Errorf("%s, but its repository %s contains %s %s",
+				requirement, requirement.Repository, strings.ToLower(string(component.Kind)), component.Name)
+		}
+		if requirement.Name != component.Name {
+			log.Warn("Requirement names a component of another name",
+				zap.String("required", requirement.Name),
+				zap.String("component", component.Name),
+				zap.String("repository", requirement.Repository))
+		}
+	}
+	return nil
+}
+
+// lockfileOf builds the lockfile of the selected components.
+func lockfileOf(grouped map[string][]Requirement, selected map[string]*selection) *Lockfile {
+	lockfile := &Lockfile{LockfileVersion: lockfileVersion}
+	for _, repository := range sortedKeys(grouped) {
+		current := selected[repository]
+		component := current.component
+		locked := LockedComponent{
+			Kind:       component.Kind,
+			Name:       component.Name,
+			Version:    component.Version,
+			Repository: repository,
+			Ref:        current.ref,
+			Commit:     component.Commit,
+			SpecHash:   component.SpecHash,
+		}
+		for _, requirement := range grouped[repository] {
+			if requirement.Range != "" {
+				locked.Requirements = append(locked.Requirements, requirement.Range)
+			}
+		}
+		for _, dependency := range dependencyRequirements(component) {
+			if dependent := selected[dependency.Repository]; dependent != nil {
+				locked.Dependencies = append(locked.Dependencies, componentKey(dependent.component.Kind, dependent.component.Name))
+			}
+		}
+		lockfile.Components = append(lockfile.Components, locked)
+	}
+	sort.Slice(lockfile.Components, func(i, j int) bool {
+		return lockfile.Components[i].Key() < lockfile.Components[j].Key()
+	})
+	return lockfile
+}
+
+// componentKey returns the kind/name key of a component.
+func componentKey(kind schema.ComponentKind, name string) string {
+	return strings.ToLower(string(kind)) + "/" + name
+}
+
+// sortedKeys returns the repositories of grouped in order.
+func sortedKeys(grouped map[string][]Requirement) []string {
+	keys := make([]string, 0, len(grouped))
+	for key := range grouped {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
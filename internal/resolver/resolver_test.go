@@ -0,0 +1,192 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// fakeSource serves components per repository and tag.
+type fakeSource struct {
+	components map[string]map[string]*Component
+	fetched    []string
+}
+
+func (s *fakeSource) add(repository, tag string, component *Component) {
+	if s.components == nil {
+		s.components = make(map[string]map[string]*Component)
+	}
+	if s.components[repository] == nil {
+		s.components[repository] = make(map[string]*Component)
+	}
+	component.Commit = "commit-" + component.Name + "-" + tag
+	component.SpecHash = "hash-" + component.Name + "-" + tag
+	s.components[repository][tag] = component
+}
+
+func (s *fakeSource) Tags(ctx context.Context, repository string) ([]string, error) {
+	var tags []string
+	for tag := range s.components[repository] {
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (s *fakeSource) Fetch(ctx context.Context, repository, ref string) (*Component, error) {
+	s.fetched = append(s.fetched, repository+"@"+ref)
+	component, ok := s.components[repository][ref]
+	if !ok {
+		return nil, fmt.Errorf("no %s in %s", ref, repository)
+	}
+	return component, nil
+}
+
+func dependency(kind schema.ComponentKind, name, repository, version string) schema.AgentDependency {
+	return schema.AgentDependency{Kind: kind, Name: name, Repository: repository, Version: version, Required: true}
+}
+
+func TestResolveRef(t *testing.T) {
+	tags := []string{"v0.1.0", "v0.2.0", "v1.0.0", "v1.1.0-rc.1", "latest-build"}
+
+	tests := []struct {
+		name         string
+		requirements []string
+		want         string
+		wantErr      string
+	}{
+		{name: "latest release", want: "v1.0.0"},
+		{name: "caret range", requirements: []string{"^0.1.0"}, want: "v0.1.0"},
+		{name: "intersection", requirements: []string{">=0.1.0", "<1.0.0"}, want: "v0.2.0"},
+		{name: "prerelease on request", requirements: []string{">=1.1.0-rc.0"}, want: "v1.1.0-rc.1"},
+		{name: "exact tag", requirements: []string{"v0.1.0"}, want: "v0.1.0"},
+		{name: "branch", requirements: []string{"main", "main"}, want: "main"},
+		{name: "no match", requirements: []string{"^2.0.0"}, wantErr: "no version tag satisfies"},
+		{name: "different branches", requirements: []string{"main", "dev"}, wantErr: "differ"},
+		{name: "branch and range", requirements: []string{"main", "^1.0.0"}, wantErr: "cannot be checked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ResolveRef(tags, tt.requirements...)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ref)
+		})
+	}
+
+	ref, err := ResolveRef(nil)
+	require.NoError(t, err)
+	assert.Empty(t, ref, "repositories without tags resolve to the default branch")
+}
+
+func TestSatisfies(t *testing.T) {
+	assert.True(t, Satisfies("1.2.0", "^1.0.0"))
+	assert.False(t, Satisfies("2.0.0", "^1.0.0"))
+	assert.True(t, Satisfies("1.0.0", "v1.0.0"))
+	assert.True(t, Satisfies("main", "main"))
+	assert.True(t, Satisfies("anything", ""))
+	assert.False(t, Satisfies("not-a-version", "^1.0.0"))
+}
+
+func TestResolveSharedDependency(t *testing.T) {
+	source := &fakeSource{}
+	source.add("/repos/search", "v1.0.0", &Component{Kind: schema.KindTool, Name: "search", Version: "1.0.0"})
+	source.add("/repos/search", "v1.2.0", &Component{Kind: schema.KindTool, Name: "search", Version: "1.2.0"})
+	source.add("/repos/search", "v2.0.0", &Component{Kind: schema.KindTool, Name: "search", Version: "2.0.0"})
+	source.add("/repos/greeting", "v0.1.0", &Component{Kind: schema.KindPrompt, Name: "greeting", Version: "0.1.0"})
+	source.add("/repos/helper", "v1.0.0", &Component{
+		Kind: schema.KindAgent, Name: "helper", Version: "1.0.0",
+		Dependencies: []schema.AgentDependency{
+			dependency(schema.KindTool, "search", "/repos/search", "~1.0.0"),
+			dependency(schema.KindTool, "calculator", "builtin", ""),
+		},
+	})
+	source.add("/repos/assistant", "v1.0.0", &Component{
+		Kind: schema.KindAgent, Name: "assistant", Version: "1.0.0",
+		Dependencies: []schema.AgentDependency{
+			dependency(schema.KindTool, "search", "/repos/search", "^1.0.0"),
+			dependency(schema.KindPrompt, "greeting", "/repos/greeting", ""),
+			dependency(schema.KindAgent, "helper", "/repos/helper", "^1.0.0"),
+		},
+	})
+
+	lockfile, err := NewResolver(source).Resolve(context.Background(), []Requirement{
+		{Kind: schema.KindAgent, Name: "assistant", Repository: "/repos/assistant", Range: "^1.0.0", RequiredBy: WorkspaceFileName},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, lockfile.Components, 4)
+	assert.Equal(t, []string{"agent/assistant", "agent/helper", "prompt/greeting", "tool/search"},
+		[]string{lockfile.Components[0].Key(), lockfile.Components[1].Key(), lockfile.Components[2].Key(), lockfile.Components[3].Key()})
+
+	search := lockfile.Find(schema.KindTool, "search")
+	require.NotNil(t, search)
+	assert.Equal(t, "v1.0.0", search.Ref, "the helper's ~1.0.0 narrows the assistant's ^1.0.0")
+	assert.Equal(t, "commit-search-v1.0.0", search.Commit)
+	assert.Equal(t, "hash-search-v1.0.0", search.SpecHash)
+	assert.ElementsMatch(t, []string{"^1.0.0", "~1.0.0"}, search.Requirements)
+
+	assistant := lockfile.Find(schema.KindAgent, "assistant")
+	require.NotNil(t, assistant)
+	assert.Equal(t, []string{"tool/search", "prompt/greeting", "agent/helper"}, assistant.Dependencies)
+	assert.Empty(t, lockfile.Find(schema.KindTool, "calculator"), "built-in tools are not locked")
+}
+
+func TestResolveConflict(t *testing.T) {
+	source := &fakeSource{}
+	source.add("/repos/search", "v1.0.0", &Component{Kind: schema.KindTool, Name: "search", Version: "1.0.0"})
+	source.add("/repos/search", "v2.0.0", &Component{Kind: schema.KindTool, Name: "search", Version: "2.0.0"})
+	source.add("/repos/helper", "v1.0.0", &Component{
+		Kind: schema.KindAgent, Name: "helper", Version: "1.0.0",
+		Dependencies: []schema.AgentDependency{dependency(schema.KindTool, "search", "/repos/search", "^2.0.0")},
+	})
+
+	_, err := NewResolver(source).Resolve(context.Background(), []Requirement{
+		{Kind: schema.KindTool, Name: "search", Repository: "/repos/search", Range: "^1.0.0", RequiredBy: WorkspaceFileName},
+		{Kind: schema.KindAgent, Name: "helper", Repository: "/repos/helper", RequiredBy: WorkspaceFileName},
+	})
+	require.Error(t, err)
+
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "/repos/search", conflict.Repository)
+	assert.Contains(t, err.Error(), "forge.yaml requires tool search ^1.0.0")
+	assert.Contains(t, err.Error(), "agent helper 1.0.0 requires tool search ^2.0.0")
+	assert.Contains(t, err.Error(), "available tags:")
+}
+
+func TestResolveKindMismatch(t *testing.T) {
+	source := &fakeSource{}
+	source.add("/repos/search", "v1.0.0", &Component{Kind: schema.KindTool, Name: "search", Version: "1.0.0"})
+
+	_, err := NewResolver(source).Resolve(context.Background(), []Requirement{
+		{Kind: schema.KindPrompt, Name: "search", Repository: "/repos/search", RequiredBy: WorkspaceFileName},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains tool search")
+}
+
+func TestLockfileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockfileName)
+	lockfile := &Lockfile{Components: []LockedComponent{
+		{Kind: schema.KindTool, Name: "search", Version: "1.0.0", Repository: "/repos/search", Ref: "v1.0.0", Commit: "abc", SpecHash: "def"},
+		{Kind: schema.KindAgent, Name: "assistant", Version: "1.0.0", Repository: "/repos/assistant", Commit: "123", SpecHash: "456",
+			Requirements: []string{"^1.0.0"}, Dependencies: []string{"tool/search"}},
+	}}
+	require.NoError(t, lockfile.Write(path))
+
+	read, err := ReadLockfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, lockfileVersion, read.LockfileVersion)
+	assert.Equal(t, lockfile.Components, read.Components)
+	assert.Equal(t, "agent/assistant", read.Components[0].Key(), "components are written in key order")
+}
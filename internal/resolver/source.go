@@ -0,0 +1,172 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// Component is a component manifest fetched from a repository at a ref.
+type Component struct {
+	Kind    schema.ComponentKind
+	Name    string
+	Version string
+	Commit  string
+	// SpecHash is the SHA-256 of the serialized manifest, as recorded on install
+	SpecHash string
+	// Spec is the serialized manifest
+	Spec         []byte
+	Dependencies []schema.AgentDependency
+}
+
+// Source provides the version tags and manifests of components in repositories.
+type Source interface {
+	Tags(ctx context.Context, repository string) ([]string, error)
+	Fetch(ctx context.Context, repository, ref string) (*Component, error)
+}
+
+// TokenFunc returns the access token for a repository URL, or "" for anonymous access.
+type TokenFunc func(ctx context.Context, repository string) string
+
+// gitSource reads tags and manifests from git repositories.
+type gitSource struct {
+	git   git.GitClient
+	token TokenFunc
+}
+
+// NewGitSource creates a source reading remote git repositories with gitClient.
+// The optional token function authenticates against private repositories.
+func NewGitSource(gitClient git.GitClient, token TokenFunc) Source {
+	return &gitSource{git: gitClient, token: token}
+}
+
+// Tags returns the tags of repository.
+func (s *gitSource) Tags(ctx context.Context, repository string) ([]string, error) {
+	return s.git.ListTags(ctx, repository, s.accessToken(ctx, repository))
+}
+
+// accessToken returns the access token for repository, if any.
+func (s *gitSource) accessToken(ctx context.Context, repository string) string {
+	if s.token == nil {
+		return ""
+	}
+	return s.token(ctx, repository)
+}
+
+// Fetch clones repository at ref, a tag (with or without "v" prefix) or branch, and
+// reads the single component manifest it contains. An empty ref fetches the default branch.
+func (s *gitSource) Fetch(ctx context.Context, repository, ref string) (*Component, error) {
+	tmpDir, err := os.MkdirTemp("", "forge-resolve-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	checkout := filepath.Join(tmpDir, "repo")
+	opts := git.CloneOptions{
+		URL:         repository,
+		Destination: checkout,
+		Depth:       1,
+		Token:       s.accessToken(ctx, repository),
+	}
+	if _, err := git.CloneVersion(ctx, s.git, opts, ref); err != nil {
+		return nil, err
+	}
+
+	commit, err := s.git.HeadCommit(ctx, checkout)
+	if err != nil {
+		return nil, err
+	}
+	component, err := readComponent(checkout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read component of %s at %s: %w", repository, refName(ref), err)
+	}
+	component.Commit = commit
+	return component, nil
+}
+
+// readComponent reads the single component manifest below root.
+func readComponent(root string) (*Component, error) {
+	var found []*Component
+	var invalid error
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || entry.Name() != schema.ManifestFileName {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		component, err := parseComponent(content)
+		if err != nil {
+			invalid = err
+			return nil
+		}
+		found = append(found, component)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(found) {
+	case 0:
+		if invalid != nil {
+			return nil, fmt.Errorf("no valid component manifest found: %w", invalid)
+		}
+		return nil, fmt.Errorf("no component manifest found")
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("repository contains %d component manifests, only single-component repositories are supported", len(found))
+	}
+}
+
+// parseComponent parses a manifest into its kind, metadata, spec hash and dependencies.
+func parseComponent(content []byte) (*Component, error) {
+	parser := schema.NewComponentParser()
+	parsed, err := parser.ParseComponent(content)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := parser.SerializeComponent(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize manifest: %w", err)
+	}
+	hash := sha256.Sum256(spec)
+	component := &Component{SpecHash: hex.EncodeToString(hash[:]), Spec: spec}
+
+	switch manifest := parsed.(type) {
+	case *schema.Tool:
+		component.Kind, component.Name, component.Version = schema.KindTool, manifest.Metadata.Name, manifest.Metadata.Version
+	case *schema.Prompt:
+		component.Kind, component.Name, component.Version = schema.KindPrompt, manifest.Metadata.Name, manifest.Metadata.Version
+	case *schema.Agent:
+		component.Kind, component.Name, component.Version = schema.KindAgent, manifest.Metadata.Name, manifest.Metadata.Version
+		component.Dependencies = manifest.Dependencies()
+	default:
+		return nil, fmt.Errorf("unsupported component %T", parsed)
+	}
+	return component, nil
+}
+
+// refName describes a ref for messages.
+func refName(ref string) string {
+	if ref == "" {
+		return "the default branch"
+	}
+	return ref
+}
@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// constraintsOf splits requirements into semver ranges and literal refs. Empty
+// requirements, * and latest accept any version.
+func constraintsOf(requirements []string) ([]*semver.Constraints, []string) {
+	var constraints []*semver.Constraints
+	var literals []string
+	for _, requirement := range requirements {
+		requirement = strings.TrimSpace(requirement)
+		if requirement == "" || requirement == "*" || requirement == "latest" {
+			continue
+		}
+		if constraint, err := semver.NewConstraint(requirement); err == nil {
+			constraints = append(constraints, constraint)
+			continue
+		}
+		literals = append(literals, requirement)
+	}
+	return constraints, literals
+}
+
+// satisfiesAll reports whether version meets every constraint.
+func satisfiesAll(version *semver.Version, constraints []*semver.Constraints) bool {
+	for _, constraint := range constraints {
+		if !constraint.Check(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveRef returns the ref to check out for requirements given the tags of a
+// repository. Requirements are semver ranges, matched against the semver tags
+// with the highest match winning, or literal tags and branches, which must all
+// be equal. An empty ref selects the default branch of a repository without
+// semver tags.
+func ResolveRef(tags []string, requirements ...string) (string, error) {
+	constraints, literals := constraintsOf(requirements)
+	if len(literals) > 0 {
+		for _, literal := range literals[1:] {
+			if literal != literals[0] {
+				return "", fmt.Errorf("refs %s and %s differ", literals[0], literal)
+			}
+		}
+		if len(constraints) > 0 {
+			return "", fmt.Errorf("ref %s is not a version and cannot be checked against version ranges", literals[0])
+		}
+		return literals[0], nil
+	}
+
+	var best *semver.Version
+	bestTag := ""
+	semverTags := 0
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		semverTags++
+		// Without ranges prereleases are only picked when asked for
+		if len(constraints) == 0 && version.Prerelease() != "" {
+			continue
+		}
+		if satisfiesAll(version, constraints) && (best == nil || version.GreaterThan(best)) {
+			best, bestTag = version, tag
+		}
+	}
+
+	switch {
+	case best != nil:
+		return bestTag, nil
+	case len(constraints) == 0 && semverTags == 0:
+		return "", nil
+	case len(constraints) == 0:
+		return "", fmt.Errorf("no released version tag")
+	default:
+		var ranges []string
+		for _, constraint := range constraints {
+			ranges = append(ranges, constraint.String())
+		}
+		return "", fmt.Errorf("no version tag satisfies %s", strings.Join(ranges, ", "))
+	}
+}
+
+// Satisfies reports whether an installed version meets a requirement. Literal
+// refs are met by the equal version, ignoring a v prefix.
+func Satisfies(version, requirement string) bool {
+	constraints, literals := constraintsOf([]string{requirement})
+	if len(literals) > 0 {
+		return strings.TrimPrefix(version, "v") == strings.TrimPrefix(literals[0], "v")
+	}
+	if len(constraints) == 0 {
+		return true
+	}
+	parsed, err := semver.NewVersion(version)
+	return err == nil && satisfiesAll(parsed, constraints)
+}
@@ -0,0 +1,68 @@
+package resolver
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/denkhaus/agentforge/internal/git"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// WorkspaceFileName is the workspace manifest listing the components a workspace requires.
+const WorkspaceFileName = "forge.yaml"
+
+// Workspace is the workspace manifest.
+type Workspace struct {
+	Dependencies []WorkspaceDependency `yaml:"dependencies"`
+}
+
+// WorkspaceDependency is a component required by the workspace. Source and
+// Version follow the sources of agent dependencies, e.g. user/repo@^1.0.0.
+type WorkspaceDependency struct {
+	Kind    schema.ComponentKind `yaml:"kind"`
+	Name    string               `yaml:"name"`
+	Source  string               `yaml:"source"`
+	Version string               `yaml:"version,omitempty"`
+}
+
+// ReadWorkspace reads and validates the workspace manifest at path.
+func ReadWorkspace(path string) (*Workspace, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace manifest: %w", err)
+	}
+	var workspace Workspace
+	if err := yaml.Unmarshal(content, &workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace manifest %s: %w", path, err)
+	}
+
+	for i, dependency := range workspace.Dependencies {
+		switch {
+		case dependency.Kind != schema.KindTool && dependency.Kind != schema.KindPrompt && dependency.Kind != schema.KindAgent:
+			return nil, fmt.Errorf("dependency %d of %s has invalid kind %q", i+1, path, dependency.Kind)
+		case dependency.Name == "":
+			return nil, fmt.Errorf("dependency %d of %s has no name", i+1, path)
+		case !schema.IsRepository(dependency.Source):
+			return nil, fmt.Errorf("dependency %s of %s has no repository source", dependency.Name, path)
+		}
+	}
+	return &workspace, nil
+}
+
+// Requirements returns the requirements of the workspace dependencies.
+func (w *Workspace) Requirements() []Requirement {
+	requirements := make([]Requirement, 0, len(w.Dependencies))
+	for _, dependency := range w.Dependencies {
+		repository, version := schema.ParseSource(dependency.Source, dependency.Version)
+		requirements = append(requirements, Requirement{
+			Kind:       dependency.Kind,
+			Name:       dependency.Name,
+			Repository: git.RepositoryURL(repository),
+			Range:      version,
+			RequiredBy: WorkspaceFileName,
+		})
+	}
+	return requirements
+}
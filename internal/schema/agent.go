@@ -1,6 +1,9 @@
 package schema
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // AgentType represents the type of agent implementation.
 type AgentType string
@@ -35,7 +38,7 @@ type AgentTool struct {
 // AgentPrompt represents a prompt that the agent uses.
 type AgentPrompt struct {
 	Name     string            `yaml:"name" json:"name" validate:"required"`
-	Type     string            `yaml:"type" json:"type" validate:"required,oneof=system instruction template"`
+	Type     string            `yaml:"type" json:"type" validate:"required,oneof=system instruction template conversation"`
 	Source   string            `yaml:"source" json:"source" validate:"required"`
 	Version  string            `yaml:"version,omitempty" json:"version,omitempty"`
 	Priority int               `yaml:"priority,omitempty" json:"priority,omitempty"`
@@ -150,6 +153,9 @@ type AgentSpec struct {
 	// Behavioral configuration
 	Behavior *AgentBehavior `yaml:"behavior,omitempty" json:"behavior,omitempty"`
 	
+	// OutputSchema is the JSON Schema the agent replies must match
+	OutputSchema map[string]interface{} `yaml:"outputSchema,omitempty" json:"outputSchema,omitempty"`
+	
 	// Workflow configuration (for task/workflow agents)
 	Workflow *AgentWorkflow `yaml:"workflow,omitempty" json:"workflow,omitempty"`
 	
@@ -263,4 +269,64 @@ func (a *Agent) HasTool(name string) bool {
 // HasPrompt checks if a prompt exists.
 func (a *Agent) HasPrompt(name string) bool {
 	return a.GetPromptByName(name) != nil
-}
\ No newline at end of file
+}
+
+// AgentDependency is a tool, prompt or sub-agent an agent depends on. Agent
+// tools of type agent are sub-agents.
+type AgentDependency struct {
+	Kind ComponentKind
+	Name string
+	// Source is the declared source, Repository and Version are parsed from it
+	Source     string
+	Repository string
+	// Version is a semver range, a tag or a branch, empty for the latest version
+	Version   string
+	Required  bool
+	Condition string
+}
+
+// Dependencies returns the tools, prompts and sub-agents the agent depends on.
+func (a *Agent) Dependencies() []AgentDependency {
+	var dependencies []AgentDependency
+	for _, tool := range a.Spec.Tools {
+		kind := KindTool
+		if tool.Type == "agent" {
+			kind = KindAgent
+		}
+		dependency := AgentDependency{Kind: kind, Name: tool.Name, Source: tool.Source,
+			Required: tool.Required, Condition: tool.Condition}
+		dependency.Repository, dependency.Version = ParseSource(tool.Source, tool.Version)
+		dependencies = append(dependencies, dependency)
+	}
+	for _, prompt := range a.Spec.Prompts {
+		dependency := AgentDependency{Kind: KindPrompt, Name: prompt.Name, Source: prompt.Source, Required: true}
+		dependency.Repository, dependency.Version = ParseSource(prompt.Source, prompt.Version)
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+// ParseSource splits a dependency source such as user/repo@v1.0.0 or
+// forge://user/repo:^1.0.0 into repository and version. A non-empty version
+// overrides the version of the source.
+func ParseSource(source, version string) (string, string) {
+	repository, ref := source, ""
+	if rest, ok := strings.CutPrefix(source, "forge://"); ok {
+		repository = rest
+		if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+			repository, ref = rest[:i], rest[i+1:]
+		}
+	} else if i := strings.LastIndex(source, "@"); i > strings.LastIndex(source, "/") {
+		repository, ref = source[:i], source[i+1:]
+	}
+	if version != "" {
+		ref = version
+	}
+	return repository, ref
+}
+
+// IsRepository reports whether a parsed source repository can be cloned, as
+// opposed to names of built-in components such as builtin.
+func IsRepository(repository string) bool {
+	return strings.Contains(repository, "/")
+}
@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -142,4 +144,51 @@ func TestComponentSerialization(t *testing.T) {
 	if parsedTool.Metadata.Name != "test-tool" {
 		t.Errorf("Expected name 'test-tool', got '%s'", parsedTool.Metadata.Name)
 	}
-}
\ No newline at end of file
+}
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		source, version      string
+		repository, expected string
+	}{
+		{"user/repo@v1.0.0", "", "user/repo", "v1.0.0"},
+		{"forge://user/repo:^1.0.0", "", "user/repo", "^1.0.0"},
+		{"https://example.com/user/repo.git", "", "https://example.com/user/repo.git", ""},
+		{"user/repo@v1.0.0", "~1.2.0", "user/repo", "~1.2.0"},
+		{"builtin", "", "builtin", ""},
+	}
+
+	for _, tt := range tests {
+		repository, version := ParseSource(tt.source, tt.version)
+		if repository != tt.repository || version != tt.expected {
+			t.Errorf("ParseSource(%q, %q) = %q, %q, expected %q, %q",
+				tt.source, tt.version, repository, version, tt.repository, tt.expected)
+		}
+	}
+
+	if IsRepository("builtin") {
+		t.Errorf("Expected builtin not to be a repository")
+	}
+}
+
+func TestParseComponentRejectsUnsafeNames(t *testing.T) {
+	content, err := os.ReadFile("../../examples/tool.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read tool example: %v", err)
+	}
+
+	for _, name := range []string{"../../x", "tools/evil", "Upper", "-leading", "with space"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("Expected name %q to be rejected", name)
+		}
+
+		renamed := strings.Replace(string(content), "name: weather-lookup", fmt.Sprintf("name: %q", name), 1)
+		if _, err := NewComponentParser().ParseComponent([]byte(renamed)); err == nil || !strings.Contains(err.Error(), "metadata.name") {
+			t.Errorf("Expected manifest named %q to fail name validation, got %v", name, err)
+		}
+	}
+
+	if err := ValidateName("web-search2"); err != nil {
+		t.Errorf("Expected web-search2 to be valid: %v", err)
+	}
+}
@@ -52,6 +52,28 @@ type PromptExample struct {
 	Variables   map[string]interface{} `yaml:"variables,omitempty" json:"variables,omitempty"`
 	Expected    string                 `yaml:"expected,omitempty" json:"expected,omitempty"`
 	Context     string                 `yaml:"context,omitempty" json:"context,omitempty"`
+	// Scorers used by forge prompt eval for this example, e.g. contains or judge
+	Scorers []string `yaml:"scorers,omitempty" json:"scorers,omitempty"`
+}
+
+// PromptDependency references another prompt by name and version range. It is
+// the manifest declaration, like AgentTool for agents. Prompts are composed from
+// the prompt directories, which have no database rows for the ent
+// PromptDependency edges to hang off.
+type PromptDependency struct {
+	Name string `yaml:"name" json:"name" validate:"required"`
+	// Version is a semver range such as ^1.2.0, empty for any version
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// Alias is the template name the prompt is included as, defaults to Name
+	Alias string `yaml:"alias,omitempty" json:"alias,omitempty"`
+}
+
+// TemplateName returns the name the dependency is included as.
+func (d PromptDependency) TemplateName() string {
+	if d.Alias != "" {
+		return d.Alias
+	}
+	return d.Name
 }
 
 // PromptValidation represents validation rules for the prompt.
@@ -93,6 +115,18 @@ type PromptSpec struct {
 	// Examples of prompt usage
 	Examples []PromptExample `yaml:"examples,omitempty" json:"examples,omitempty"`
 	
+	// Dependencies are the prompts included or extended by this prompt
+	Dependencies []PromptDependency `yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
+	
+	// Extends names the dependency whose template this prompt inherits
+	Extends string `yaml:"extends,omitempty" json:"extends,omitempty"`
+	
+	// Blocks override the named blocks of the extended template
+	Blocks map[string]string `yaml:"blocks,omitempty" json:"blocks,omitempty"`
+	
+	// OutputSchema is the JSON Schema structured replies must match
+	OutputSchema map[string]interface{} `yaml:"outputSchema,omitempty" json:"outputSchema,omitempty"`
+	
 	// Validation rules
 	Validation *PromptValidation `yaml:"validation,omitempty" json:"validation,omitempty"`
 	
@@ -146,10 +180,22 @@ func (p *Prompt) Validate() error {
 	
 	// Validate that at least one content field is provided
 	hasContent := p.Spec.Content != "" || p.Spec.Template != "" || len(p.Spec.Messages) > 0
-	if !hasContent {
+	if !hasContent && p.Spec.Extends == "" {
 		return fmt.Errorf("prompt must have content, template, or messages")
 	}
 	
+	// Validate dependencies are unique and the extended prompt is one of them
+	dependencyNames := make(map[string]bool)
+	for _, dependency := range p.Spec.Dependencies {
+		if dependencyNames[dependency.TemplateName()] {
+			return fmt.Errorf("duplicate dependency: %s", dependency.TemplateName())
+		}
+		dependencyNames[dependency.TemplateName()] = true
+	}
+	if p.Spec.Extends != "" && !dependencyNames[p.Spec.Extends] {
+		return fmt.Errorf("extended prompt %s must be listed in dependencies", p.Spec.Extends)
+	}
+	
 	// Validate variable names are unique
 	variableNames := make(map[string]bool)
 	for _, variable := range p.Spec.Variables {
@@ -38,6 +38,9 @@ type ToolParameter struct {
 	MaxLength   *int        `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
 	Minimum     *float64    `yaml:"minimum,omitempty" json:"minimum,omitempty"`
 	Maximum     *float64    `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	In          string      `yaml:"in,omitempty" json:"in,omitempty" validate:"omitempty,oneof=path query header body"`
+	// Schema is the full JSON Schema of the parameter, used for nested objects and arrays
+	Schema map[string]any `yaml:"schema,omitempty" json:"schema,omitempty"`
 }
 
 // ToolFunction represents a function exposed by the tool.
@@ -47,6 +50,17 @@ type ToolFunction struct {
 	Parameters  []ToolParameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
 	Returns     *ToolParameter  `yaml:"returns,omitempty" json:"returns,omitempty"`
 	Examples    []ToolExample   `yaml:"examples,omitempty" json:"examples,omitempty"`
+	Request     *ToolRequest    `yaml:"request,omitempty" json:"request,omitempty"`
+}
+
+// ToolRequest describes how an http or webhook tool function is called.
+// Path, header and query values may reference parameters as {name} and environment
+// variables or declared secrets as ${NAME}.
+type ToolRequest struct {
+	Method  string            `yaml:"method,omitempty" json:"method,omitempty"`
+	Path    string            `yaml:"path,omitempty" json:"path,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Query   map[string]string `yaml:"query,omitempty" json:"query,omitempty"`
 }
 
 // ToolExample represents an example usage of a tool function.
@@ -55,6 +69,8 @@ type ToolExample struct {
 	Description string                 `yaml:"description" json:"description"`
 	Input       map[string]interface{} `yaml:"input" json:"input"`
 	Output      interface{}            `yaml:"output" json:"output"`
+	// Match selects how forge tool test compares the output: exact, subset or regex
+	Match string `yaml:"match,omitempty" json:"match,omitempty" validate:"omitempty,oneof=exact subset regex"`
 }
 
 // ToolDependency represents a dependency required by the tool.
@@ -137,6 +153,7 @@ type ToolSecurity struct {
 	ReadOnlyRootFS    bool     `yaml:"readOnlyRootFS,omitempty" json:"readOnlyRootFS,omitempty"`
 	AllowedCapabilities []string `yaml:"allowedCapabilities,omitempty" json:"allowedCapabilities,omitempty"`
 	DroppedCapabilities []string `yaml:"droppedCapabilities,omitempty" json:"droppedCapabilities,omitempty"`
+	NetworkIsolation    bool     `yaml:"networkIsolation,omitempty" json:"networkIsolation,omitempty"`
 }
 
 // Tool represents a complete Tool component manifest.
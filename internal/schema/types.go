@@ -3,12 +3,28 @@ package schema
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 )
 
 // APIVersion represents the AgentForge API version.
 const APIVersion = "forge.dev/v1"
 
+// ManifestFileName is the file name of a component manifest within its directory.
+const ManifestFileName = "component.yaml"
+
+// namePattern matches valid component names (DNS-1123 labels).
+var namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidateName returns an error unless name is a DNS-1123 label. Component names
+// are used as install directory names and must not contain path separators.
+func ValidateName(name string) error {
+	if len(name) > 63 || !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid component name %q: must contain only lowercase letters, numbers, and hyphens", name)
+	}
+	return nil
+}
+
 // ComponentKind represents the type of AgentForge component.
 type ComponentKind string
 
@@ -140,6 +156,9 @@ func (bc *BaseComponent) Validate() error {
 	if bc.Metadata.Name == "" {
 		return fmt.Errorf("metadata.name is required")
 	}
+	if err := ValidateName(bc.Metadata.Name); err != nil {
+		return fmt.Errorf("metadata.name: %w", err)
+	}
 	
 	if bc.Metadata.Version == "" {
 		return fmt.Errorf("metadata.version is required")
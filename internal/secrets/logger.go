@@ -0,0 +1,7 @@
+package secrets
+
+import "github.com/denkhaus/agentforge/internal/logger"
+
+var (
+	log = logger.WithPackage("secrets")
+)
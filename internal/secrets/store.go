@@ -0,0 +1,380 @@
+// Package secrets provides an encrypted local file store for named secrets.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/scrypt"
+
+	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+const (
+	// storeVersion is the version of the encrypted store file format.
+	storeVersion = 1
+	// Key derivation methods recorded in the store file.
+	kdfScrypt  = "scrypt"
+	kdfKeyFile = "keyfile"
+	// keySize is the AES-256 key size in bytes.
+	keySize  = 32
+	saltSize = 16
+	// scrypt parameters recommended for interactive use.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// namePattern restricts secret names to environment-variable-like identifiers,
+// optionally namespaced with slashes, dots or dashes.
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_./-]*$`)
+
+// Options configures the file store.
+type Options struct {
+	// Path is the encrypted store file
+	Path string
+	// KeyFile holds a random key used when no passphrase is set, created on first write
+	KeyFile string
+	// Passphrase derives the key with scrypt instead of using the key file
+	Passphrase string
+}
+
+// storeFile is the on-disk format of the store. Nonce, salt and ciphertext are hex encoded.
+type storeFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       string `json:"salt,omitempty"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// fileStore is a private implementation of types.SecretStore interface that keeps
+// all secrets AES-GCM encrypted in a single file.
+type fileStore struct {
+	opts  Options
+	mutex sync.Mutex
+}
+
+// NewFileStore creates a secret store backed by the encrypted file in opts.Path.
+func NewFileStore(opts Options) (types.SecretStore, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("secrets file path is required")
+	}
+	if opts.Passphrase == "" && opts.KeyFile == "" {
+		return nil, fmt.Errorf("either a secrets passphrase or a key file is required")
+	}
+	return &fileStore{opts: opts}, nil
+}
+
+// Set stores or replaces a secret.
+func (s *fileStore) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	return s.save(values)
+}
+
+// Get returns the value of a secret.
+func (s *fileStore) Get(name string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	value, exists := values[name]
+	if !exists {
+		return "", fmt.Errorf("secret %s: %w", name, internalErrors.ErrSecretNotFound)
+	}
+	return value, nil
+}
+
+// Lookup returns the value of a secret and whether it exists. Store errors are logged
+// and reported as a missing secret.
+func (s *fileStore) Lookup(name string) (string, bool) {
+	value, err := s.Get(name)
+	if err != nil {
+		if !errors.Is(err, internalErrors.ErrSecretNotFound) {
+			log.Warn("Failed to read secret", zap.String("name", name), zap.Error(err))
+		}
+		return "", false
+	}
+	return value, true
+}
+
+// List returns the names of all secrets, sorted.
+func (s *fileStore) List() ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete removes a secret.
+func (s *fileStore) Delete(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, exists := values[name]; !exists {
+		return fmt.Errorf("secret %s: %w", name, internalErrors.ErrSecretNotFound)
+	}
+	delete(values, name)
+	return s.save(values)
+}
+
+// load decrypts the store file. A missing file is an empty store.
+func (s *fileStore) load() (map[string]string, error) {
+	content, err := os.ReadFile(s.opts.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]string), nil
+		}
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+	if file.Version != storeVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", file.Version)
+	}
+
+	salt, err := hex.DecodeString(file.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets file salt: %w", err)
+	}
+	nonce, err := hex.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets file nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(file.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets file ciphertext: %w", err)
+	}
+
+	key, err := s.readKey(file.KDF, salt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid secrets file nonce size %d", len(nonce))
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(file.KDF))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets file, wrong passphrase or key file: %w", internalErrors.ErrUnauthorized)
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted secrets: %w", err)
+	}
+	return values, nil
+}
+
+// save encrypts the secrets with a fresh nonce and atomically replaces the store file.
+// A configured passphrase takes precedence over the key file.
+func (s *fileStore) save(values map[string]string) error {
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to serialize secrets: %w", err)
+	}
+
+	file := storeFile{Version: storeVersion, KDF: kdfKeyFile}
+	var key []byte
+	if s.opts.Passphrase != "" {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+		file.KDF = kdfScrypt
+		file.Salt = hex.EncodeToString(salt)
+		key, err = deriveKey(s.opts.Passphrase, salt)
+	} else {
+		key, err = s.loadOrCreateKeyFile()
+	}
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	file.Nonce = hex.EncodeToString(nonce)
+	file.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, plaintext, []byte(file.KDF)))
+
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize secrets file: %w", err)
+	}
+	return writeFileAtomic(s.opts.Path, content)
+}
+
+// readKey returns the key for decrypting a store written with the given key derivation.
+func (s *fileStore) readKey(kdf string, salt []byte) ([]byte, error) {
+	switch kdf {
+	case kdfScrypt:
+		if s.opts.Passphrase == "" {
+			return nil, fmt.Errorf("secrets file is protected by a passphrase, set SECRETS_PASSPHRASE: %w", internalErrors.ErrUnauthorized)
+		}
+		return deriveKey(s.opts.Passphrase, salt)
+	case kdfKeyFile:
+		if s.opts.KeyFile == "" {
+			return nil, fmt.Errorf("secrets file is protected by a key file, set SECRETS_KEY_FILE: %w", internalErrors.ErrUnauthorized)
+		}
+		return readKeyFile(s.opts.KeyFile)
+	default:
+		return nil, fmt.Errorf("unsupported secrets key derivation %q", kdf)
+	}
+}
+
+// loadOrCreateKeyFile reads the key file, generating a random key on first use.
+func (s *fileStore) loadOrCreateKeyFile() ([]byte, error) {
+	key, err := readKeyFile(s.opts.KeyFile)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return key, err
+	}
+
+	key = make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate secrets key: %w", err)
+	}
+	if err := writeFileAtomic(s.opts.KeyFile, []byte(hex.EncodeToString(key)+"\n")); err != nil {
+		return nil, err
+	}
+
+	log.Info("Created secrets key file", zap.String("path", s.opts.KeyFile))
+	return key, nil
+}
+
+// readKeyFile reads a hex encoded key.
+func readKeyFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets key file: %w", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("secrets key file %s must contain a %d byte hex encoded key", path, keySize)
+	}
+	return key, nil
+}
+
+// deriveKey derives an AES key from a passphrase.
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive secrets key: %w", err)
+	}
+	return key, nil
+}
+
+// newAEAD creates an AES-GCM cipher.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// writeFileAtomic writes a private file through a temporary file and a rename.
+func writeFileAtomic(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary secrets file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("failed to protect secrets file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace secrets file: %w", err)
+	}
+	return nil
+}
+
+// ValidateName checks that a secret name is a valid identifier.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q, use letters, digits, '_', '.', '/' or '-': %w",
+			name, internalErrors.ErrInvalidInput)
+	}
+	return nil
+}
+
+// NewLookup returns a lookup resolving names from the store first and the process
+// environment second. A nil store only consults the environment.
+func NewLookup(store types.SecretStore) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		if store != nil {
+			if value, ok := store.Lookup(name); ok {
+				return value, true
+			}
+		}
+		return os.LookupEnv(name)
+	}
+}
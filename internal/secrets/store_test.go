@@ -0,0 +1,108 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalErrors "github.com/denkhaus/agentforge/internal/errors"
+)
+
+func TestFileStore_KeyFile(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Path: filepath.Join(dir, "secrets.json"), KeyFile: filepath.Join(dir, "secrets.key")}
+
+	store, err := NewFileStore(opts)
+	require.NoError(t, err)
+
+	names, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	require.NoError(t, store.Set("OPENAI_API_KEY", "sk-test"))
+	require.NoError(t, store.Set("repository/main/access_token", "ghp_test"))
+
+	content, err := os.ReadFile(opts.Path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "sk-test")
+
+	info, err := os.Stat(opts.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	info, err = os.Stat(opts.KeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reopened, err := NewFileStore(opts)
+	require.NoError(t, err)
+	value, err := reopened.Get("OPENAI_API_KEY")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-test", value)
+
+	names, err = reopened.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OPENAI_API_KEY", "repository/main/access_token"}, names)
+
+	require.NoError(t, reopened.Delete("OPENAI_API_KEY"))
+	_, err = reopened.Get("OPENAI_API_KEY")
+	assert.ErrorIs(t, err, internalErrors.ErrSecretNotFound)
+	assert.ErrorIs(t, reopened.Delete("OPENAI_API_KEY"), internalErrors.ErrSecretNotFound)
+
+	_, ok := reopened.Lookup("repository/main/access_token")
+	assert.True(t, ok)
+}
+
+func TestFileStore_Passphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+
+	store, err := NewFileStore(Options{Path: path, Passphrase: "correct horse"})
+	require.NoError(t, err)
+	require.NoError(t, store.Set("API_TOKEN", "token"))
+
+	wrong, err := NewFileStore(Options{Path: path, Passphrase: "battery staple"})
+	require.NoError(t, err)
+	_, err = wrong.Get("API_TOKEN")
+	assert.ErrorIs(t, err, internalErrors.ErrUnauthorized)
+
+	missing, err := NewFileStore(Options{Path: path, KeyFile: filepath.Join(t.TempDir(), "secrets.key")})
+	require.NoError(t, err)
+	_, err = missing.List()
+	assert.ErrorContains(t, err, "SECRETS_PASSPHRASE")
+}
+
+func TestFileStore_InvalidName(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(Options{Path: filepath.Join(dir, "secrets.json"), KeyFile: filepath.Join(dir, "key")})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, store.Set("bad name", "value"), internalErrors.ErrInvalidInput)
+	assert.ErrorIs(t, store.Set("1ST", "value"), internalErrors.ErrInvalidInput)
+}
+
+func TestNewLookup(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(Options{Path: filepath.Join(dir, "secrets.json"), KeyFile: filepath.Join(dir, "key")})
+	require.NoError(t, err)
+	require.NoError(t, store.Set("FROM_STORE", "stored"))
+	t.Setenv("FROM_STORE", "env")
+	t.Setenv("FROM_ENV", "env")
+
+	lookup := NewLookup(store)
+	value, ok := lookup("FROM_STORE")
+	assert.True(t, ok)
+	assert.Equal(t, "stored", value)
+
+	value, ok = lookup("FROM_ENV")
+	assert.True(t, ok)
+	assert.Equal(t, "env", value)
+
+	_, ok = lookup("MISSING_SECRET_FOR_TEST")
+	assert.False(t, ok)
+
+	value, ok = NewLookup(nil)("FROM_ENV")
+	assert.True(t, ok)
+	assert.Equal(t, "env", value)
+}
@@ -13,6 +13,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/denkhaus/agentforge/internal/errors"
+	"github.com/denkhaus/agentforge/internal/structured"
 	"github.com/denkhaus/agentforge/internal/types"
 )
 
@@ -64,13 +65,12 @@ func (cm *chatManager) processChat(ctx context.Context, initialMessage string) e
 	}
 
 	choice := resp.Choices[0]
-
-	// Add assistant response to history
-	assistantResponse := cm.buildAssistantResponse(choice)
-	cm.session.addMessageToHistory(assistantResponse)
+	cm.session.structured = nil
 
 	// Execute tool calls if any
 	if len(choice.ToolCalls) > 0 {
+		// Add assistant response to history
+		cm.session.addMessageToHistory(cm.buildAssistantResponse(choice))
 		if err := cm.executeToolCalls(ctx, choice.ToolCalls); err != nil {
 			return fmt.Errorf("tool execution failed: %w", err)
 		}
@@ -79,10 +79,41 @@ func (cm *chatManager) processChat(ctx context.Context, initialMessage string) e
 		return cm.generateFinalResponse(ctx, tools)
 	}
 
+	if outputSchema := types.OutputSchema(cm.session.agent); outputSchema != nil {
+		return cm.structuredResponse(ctx, outputSchema, choice.Content)
+	}
+
+	cm.session.addMessageToHistory(cm.buildAssistantResponse(choice))
 	log.Info("Direct response", zap.String("content", choice.Content))
 	return nil
 }
 
+// structuredResponse completes the turn of an agent with an output schema. A
+// reply matching the schema is kept, otherwise the reply is generated again in
+// the structured mode of the provider and repaired until it matches.
+func (cm *chatManager) structuredResponse(ctx context.Context, outputSchema map[string]any, content string) error {
+	value, err := structured.Validate(outputSchema, content)
+	if err == nil {
+		cm.session.structured = &structured.Result{Value: value, Raw: content, Attempts: 1}
+		cm.session.addMessageToHistory(llms.TextParts(llms.ChatMessageTypeAI, content))
+		return nil
+	}
+	log.Info("Reply does not match the output schema, generating structured reply", zap.Error(err))
+
+	result, err := structured.Generate(ctx, cm.session.llm, cm.session.messageHistory, structured.Options{
+		Schema: outputSchema,
+		Mode:   structured.ModeFor(cm.session.agent.GetLLMConfig().GetProvider()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate structured response: %w", err)
+	}
+
+	cm.session.structured = result
+	cm.session.addMessageToHistory(llms.TextParts(llms.ChatMessageTypeAI, result.Raw))
+	log.Info("Structured response", zap.String("content", result.Raw), zap.Int("attempts", result.Attempts))
+	return nil
+}
+
 // buildAssistantResponse creates an assistant message with tool calls.
 func (cm *chatManager) buildAssistantResponse(choice *llms.ContentChoice) llms.MessageContent {
 	assistantResponse := llms.TextParts(llms.ChatMessageTypeAI, choice.Content)
@@ -162,6 +193,9 @@ func (cm *chatManager) generateFinalResponse(ctx context.Context, tools []llms.T
 
 	if len(finalResp.Choices) > 0 {
 		finalChoice := finalResp.Choices[0]
+		if outputSchema := types.OutputSchema(cm.session.agent); outputSchema != nil {
+			return cm.structuredResponse(ctx, outputSchema, finalChoice.Content)
+		}
 		finalAssistantResponse := llms.TextParts(llms.ChatMessageTypeAI, finalChoice.Content)
 		cm.session.addMessageToHistory(finalAssistantResponse)
 
@@ -224,9 +258,11 @@ func (cm *chatManager) convertToLLMTools(agentTools []tools.Tool) []llms.Tool {
 			Function: &llms.FunctionDefinition{
 				Name:        agentTool.Name(),
 				Description: agentTool.Description(),
-				Parameters:  nil, // Schema not available from tools.Tool
 			},
 		}
+		if schema := types.ToolSchema(agentTool); schema != nil {
+			llmTool.Function.Parameters = schema
+		}
 		llmTools = append(llmTools, llmTool)
 	}
 	
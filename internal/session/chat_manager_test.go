@@ -0,0 +1,152 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// fakeModel answers with the queued replies and records the calls it received.
+type fakeModel struct {
+	replies  []string
+	messages [][]llms.MessageContent
+	options  []llms.CallOptions
+}
+
+func (m *fakeModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	m.options = append(m.options, opts)
+	m.messages = append(m.messages, messages)
+
+	index := len(m.messages) - 1
+	if index >= len(m.replies) {
+		return nil, errors.New("no reply queued")
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: m.replies[index]}}}, nil
+}
+
+func (m *fakeModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// noTools is a tool provider without tools.
+type noTools struct{}
+
+func (noTools) GetTools() []tools.Tool                                   { return nil }
+func (noTools) GetToolsForAgent(agent types.Agent) ([]tools.Tool, error) { return nil, nil }
+func (noTools) ExecuteTool(ctx context.Context, name, input string) (string, error) {
+	return "", errors.New("no tools")
+}
+func (noTools) RegisterTool(tool tools.Tool) error                { return nil }
+func (noTools) HasTool(name string) bool                          { return false }
+func (noTools) ValidateAgentRequirements(agent types.Agent) error { return nil }
+func (noTools) GetToolNames() []string                            { return nil }
+
+// testAgent is an agent replying with JSON matching outputSchema, or free text without it.
+type testAgent struct {
+	outputSchema map[string]any
+}
+
+func (a *testAgent) GetName() string                            { return "extractor" }
+func (a *testAgent) GetDescription() string                     { return "Extracts people" }
+func (a *testAgent) GetSystemPrompt() string                    { return "Extract the person." }
+func (a *testAgent) GetRequiredTools() []string                 { return nil }
+func (a *testAgent) HasRequiredTool(toolName string) bool       { return false }
+func (a *testAgent) Clone(overrides map[string]any) types.Agent { return a }
+func (a *testAgent) GetOutputSchema() map[string]any            { return a.outputSchema }
+func (a *testAgent) GetLLMConfig() types.LLMConfig {
+	return llm.NewLLMConfig(llm.ProviderOpenAI, "gpt-4o-mini", 0, 0, nil)
+}
+
+var personSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"name", "age"},
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string"},
+		"age":  map[string]any{"type": "integer"},
+	},
+}
+
+func newTestSession(t *testing.T, agent types.Agent, model llms.Model) types.AgentSession {
+	t.Helper()
+	agentSession, err := NewAgentSession(nil, agent, model, noTools{}, nil, nil)
+	require.NoError(t, err)
+	return agentSession
+}
+
+func TestChat_KeepsReplyMatchingOutputSchema(t *testing.T) {
+	model := &fakeModel{replies: []string{"```json\n{\"name\": \"Ada\", \"age\": 36}\n```"}}
+	agentSession := newTestSession(t, &testAgent{outputSchema: personSchema}, model)
+
+	require.NoError(t, agentSession.Chat(context.Background(), "Ada is 36."))
+	assert.Len(t, model.messages, 1, "valid replies are not generated again")
+
+	value, ok := agentSession.GetStructuredOutput()
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"name": "Ada", "age": float64(36)}, value)
+}
+
+func TestChat_RepairsReplyNotMatchingOutputSchema(t *testing.T) {
+	model := &fakeModel{replies: []string{
+		"Ada is 36 years old.",
+		`{"name": "Ada"}`,
+		`{"name": "Ada", "age": 36}`,
+	}}
+	agentSession := newTestSession(t, &testAgent{outputSchema: personSchema}, model)
+
+	require.NoError(t, agentSession.Chat(context.Background(), "Ada is 36."))
+	require.Len(t, model.messages, 3)
+	assert.True(t, model.options[1].JSONMode, "openai replies are requested in JSON mode")
+	assert.Contains(t, promptText(model.messages[2]), "age", "the validation errors are sent back")
+
+	value, ok := agentSession.GetStructuredOutput()
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"name": "Ada", "age": float64(36)}, value)
+
+	history := agentSession.GetMessageHistory()
+	last := history[len(history)-1]
+	assert.Equal(t, llms.ChatMessageTypeAI, last.Role)
+	assert.Equal(t, llms.TextPart(`{"name": "Ada", "age": 36}`), last.Parts[0])
+}
+
+func TestChat_FailsAfterRepairsAreExhausted(t *testing.T) {
+	model := &fakeModel{replies: []string{"no", "no", "no", "no"}}
+	agentSession := newTestSession(t, &testAgent{outputSchema: personSchema}, model)
+
+	err := agentSession.Chat(context.Background(), "Ada is 36.")
+	assert.ErrorContains(t, err, "does not match the output schema")
+	_, ok := agentSession.GetStructuredOutput()
+	assert.False(t, ok)
+}
+
+func TestChat_FreeTextWithoutOutputSchema(t *testing.T) {
+	model := &fakeModel{replies: []string{"Ada is 36 years old."}}
+	agentSession := newTestSession(t, &testAgent{}, model)
+
+	require.NoError(t, agentSession.Chat(context.Background(), "How old is Ada?"))
+	_, ok := agentSession.GetStructuredOutput()
+	assert.False(t, ok)
+	assert.Len(t, agentSession.GetMessageHistory(), 3)
+}
+
+// promptText returns the text of the last message of a model call.
+func promptText(messages []llms.MessageContent) string {
+	var text string
+	for _, part := range messages[len(messages)-1].Parts {
+		if content, ok := part.(llms.TextContent); ok {
+			text += content.Text
+		}
+	}
+	return text
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/tmc/langchaingo/tools"
 	"go.uber.org/zap"
 
+	"github.com/denkhaus/agentforge/internal/structured"
 	"github.com/denkhaus/agentforge/internal/types"
 )
 
@@ -21,10 +22,12 @@ type agentSession struct {
 	agent          types.Agent
 	llm            llms.Model
 	messageHistory []llms.MessageContent
+	prefixLength   int // Messages of the history that belong to the agent prefix
 	toolProvider   types.ToolProvider
 	agentProvider  types.AgentProvider
 	sessionConfig  types.AgentSessionConfig
-	llmService     types.LLMService // Added for dynamic LLM reinitialization
+	llmService     types.LLMService   // Added for dynamic LLM reinitialization
+	structured     *structured.Result // Validated last reply of agents with an output schema
 	mutex          sync.RWMutex
 
 	// Performance optimizations
@@ -55,9 +58,11 @@ func NewAgentSessionWithConfig(
 	llmService types.LLMService,
 	sessionConfig types.AgentSessionConfig,
 ) (types.AgentSession, error) {
-	// Pre-allocate message history with reasonable capacity
-	messageHistory := make([]llms.MessageContent, 1, 16) // Start with 1, capacity for 16
-	messageHistory[0] = llms.TextParts(llms.ChatMessageTypeSystem, agent.GetSystemPrompt())
+	// Pre-allocate message history with reasonable capacity, starting with the
+	// system prompt and the prompt messages of conversation agents
+	prefix := types.SessionPrefix(agent)
+	messageHistory := make([]llms.MessageContent, len(prefix), len(prefix)+16)
+	copy(messageHistory, prefix)
 
 	session := &agentSession{
 		config:         config,
@@ -68,6 +73,7 @@ func NewAgentSessionWithConfig(
 		sessionConfig:  sessionConfig,
 		llmService:     llmService,
 		messageHistory: messageHistory,
+		prefixLength:   len(prefix),
 		messagePool: sync.Pool{
 			New: func() interface{} {
 				// Pre-allocate slice with reasonable capacity
@@ -212,7 +218,7 @@ func (s *agentSession) GetMessageHistory() []llms.MessageContent {
 	// Return copy and put slice back in pool
 	result := make([]llms.MessageContent, len(history))
 	copy(result, history)
-	s.messagePool.Put(&history) // Adjusted line length
+	s.messagePool.Put(history[:0]) // The pool holds slices, as returned by New
 
 	return result
 }
@@ -222,10 +228,9 @@ func (s *agentSession) ClearMessageHistory() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// Keep only the current agent's system prompt
-	s.messageHistory = []llms.MessageContent{
-		llms.TextParts(llms.ChatMessageTypeSystem, s.agent.GetSystemPrompt()),
-	}
+	// Keep only the current agent's system prompt and prompt messages
+	s.messageHistory = types.SessionPrefix(s.agent)
+	s.prefixLength = len(s.messageHistory)
 
 	log.Info("Message history cleared")
 }
@@ -271,16 +276,13 @@ func (s *agentSession) SwitchAgent(ctx context.Context, agentName string) error
 	s.toolsCache = nil
 	s.toolsCacheTime = 0
 
-	// Update system prompt in message history efficiently
-	systemPrompt := llms.TextParts(llms.ChatMessageTypeSystem, newAgent.GetSystemPrompt())
-	if len(s.messageHistory) > 0 && s.messageHistory[0].Role == llms.ChatMessageTypeSystem {
-		s.messageHistory[0] = systemPrompt
-	} else {
-		// Efficient prepend: grow slice and shift elements
-		s.messageHistory = append(s.messageHistory, llms.MessageContent{})
-		copy(s.messageHistory[1:], s.messageHistory[0:])
-		s.messageHistory[0] = systemPrompt
-	}
+	// Replace the prefix of the previous agent with the new system prompt and prompt messages
+	prefix := types.SessionPrefix(newAgent)
+	conversation := s.messageHistory[min(s.prefixLength, len(s.messageHistory)):]
+	history := make([]llms.MessageContent, 0, len(prefix)+len(conversation)+8)
+	history = append(history, prefix...)
+	s.messageHistory = append(history, conversation...)
+	s.prefixLength = len(prefix)
 
 	log.Info("Agent switched",
 		zap.String("from", oldAgentName),
@@ -303,6 +305,17 @@ func (s *agentSession) GetSessionConfig() types.AgentSessionConfig {
 	return s.sessionConfig
 }
 
+// GetStructuredOutput returns the validated value of the last reply of an agent
+// with an output schema.
+func (s *agentSession) GetStructuredOutput() (any, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.structured == nil {
+		return nil, false
+	}
+	return s.structured.Value, true
+}
+
 // convertToLLMTools converts tools.Tool to llms.Tool for direct LLM calls.
 func (s *agentSession) convertToLLMTools(agentTools []tools.Tool) []llms.Tool {
 	llmTools := make([]llms.Tool, 0, len(agentTools))
@@ -313,11 +326,12 @@ func (s *agentSession) convertToLLMTools(agentTools []tools.Tool) []llms.Tool {
 			Function: &llms.FunctionDefinition{
 				Name:        tool.Name(),
 				Description: tool.Description(),
-				// Note: Parameters are not available from tools.Tool interface
-				// LLM will rely on the description for input format
-				Parameters: nil,
 			},
 		}
+		// Tools without a schema leave the input format to the description
+		if schema := types.ToolSchema(tool); schema != nil {
+			llmTool.Function.Parameters = schema
+		}
 		llmTools = append(llmTools, llmTool)
 	}
 
@@ -0,0 +1,251 @@
+// Package structured generates model replies that are JSON values valid
+// against a JSON Schema. Providers are asked for JSON through their JSON mode
+// or by forcing a tool call, and invalid replies are repaired by sending the
+// validation errors back to the model.
+package structured
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/tmc/langchaingo/llms"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/jsonschema"
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/logger"
+	"github.com/denkhaus/agentforge/internal/prompteval"
+)
+
+var log = logger.WithPackage("structured")
+
+// defaultMaxRepairs is the number of repair requests after the first reply.
+const defaultMaxRepairs = 2
+
+// ToolName is the tool models call with the structured reply in tool mode.
+const ToolName = "respond"
+
+// Mode is how a model is asked for JSON.
+type Mode string
+
+const (
+	// ModeJSON enables the JSON mode of the provider
+	ModeJSON Mode = "json"
+	// ModeTool forces a call of the respond tool whose parameters are the schema
+	ModeTool Mode = "tool"
+	// ModePrompt only instructs the model to answer with JSON
+	ModePrompt Mode = "prompt"
+)
+
+// ModeFor returns the mode a provider supports best.
+func ModeFor(provider string) Mode {
+	switch provider {
+	case llm.ProviderAnthropic:
+		return ModeTool
+	case llm.ProviderOpenAI, llm.ProviderGoogleAI, llm.ProviderOllama:
+		return ModeJSON
+	default:
+		return ModePrompt
+	}
+}
+
+// Options configure a structured generation.
+type Options struct {
+	// Schema is the JSON Schema the reply must match
+	Schema map[string]any
+	// Mode defaults to ModePrompt
+	Mode Mode
+	// MaxRepairs bounds the repair requests, defaults to 2, negative disables repairs
+	MaxRepairs int
+	// CallOptions are passed to every model call
+	CallOptions []llms.CallOption
+}
+
+// Result is a reply that is valid against the schema.
+type Result struct {
+	Value    any       `json:"value"`
+	Raw      string    `json:"raw"`
+	Attempts int       `json:"attempts"`
+	Usage    llm.Usage `json:"usage"`
+}
+
+// Decode converts the value of the result into target, e.g. a struct.
+func (r *Result) Decode(target any) error {
+	data, err := json.Marshal(r.Value)
+	if err != nil {
+		return fmt.Errorf("failed to encode structured value: %w", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to decode structured value: %w", err)
+	}
+	return nil
+}
+
+// Generate sends messages to model and returns its reply once it is valid
+// against the schema. Invalid replies are sent back with the validation
+// errors until the repairs are exhausted.
+func Generate(ctx context.Context, model llms.Model, messages []llms.MessageContent, opts Options) (*Result, error) {
+	if len(opts.Schema) == 0 {
+		return nil, fmt.Errorf("an output schema is required for structured output")
+	}
+	if opts.Mode == "" {
+		opts.Mode = ModePrompt
+	}
+	if opts.MaxRepairs == 0 {
+		opts.MaxRepairs = defaultMaxRepairs
+	}
+
+	schemaJSON, err := json.MarshalIndent(opts.Schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output schema: %w", err)
+	}
+
+	conversation := append([]llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, instruction(opts.Mode, string(schemaJSON))),
+	}, messages...)
+	callOpts := append(slices.Clone(opts.CallOptions), modeOptions(opts.Mode, opts.Schema)...)
+
+	result := &Result{}
+	for {
+		result.Attempts++
+		response, err := model.GenerateContent(ctx, conversation, callOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("model call failed: %w", err)
+		}
+		result.Usage = result.Usage.Add(llm.ResponseUsage(response))
+		result.Raw = replyText(response)
+
+		value, err := Validate(opts.Schema, result.Raw)
+		if err == nil {
+			result.Value = value
+			return result, nil
+		}
+		if result.Attempts > opts.MaxRepairs {
+			return nil, &Error{Attempts: result.Attempts, Raw: result.Raw, Err: err}
+		}
+
+		log.Debug("Repairing structured reply", zap.Int("attempt", result.Attempts), zap.Error(err))
+		conversation = append(conversation,
+			llms.TextParts(llms.ChatMessageTypeAI, result.Raw),
+			llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(repairPrompt, err, schemaJSON)),
+		)
+	}
+}
+
+// GenerateAs generates a structured reply and decodes it into T.
+func GenerateAs[T any](ctx context.Context, model llms.Model, messages []llms.MessageContent, opts Options) (T, *Result, error) {
+	var value T
+	result, err := Generate(ctx, model, messages, opts)
+	if err != nil {
+		return value, nil, err
+	}
+	if err := result.Decode(&value); err != nil {
+		return value, result, err
+	}
+	return value, result, nil
+}
+
+// Error is returned when no reply matched the schema.
+type Error struct {
+	Attempts int
+	// Raw is the last reply
+	Raw string
+	Err error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("reply does not match the output schema after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the last validation error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// instruction asks for a reply matching the schema.
+func instruction(mode Mode, schemaJSON string) string {
+	if mode == ModeTool {
+		return fmt.Sprintf("Reply by calling the %s tool. Its arguments must match this JSON Schema:\n%s", ToolName, schemaJSON)
+	}
+	return fmt.Sprintf("Reply only with a JSON value matching this JSON Schema, without any other text:\n%s", schemaJSON)
+}
+
+// repairPrompt sends validation errors back to the model.
+const repairPrompt = `Your reply is invalid: %v
+
+Reply again with only the corrected JSON value matching this JSON Schema:
+%s`
+
+// modeOptions returns the call options of a mode.
+func modeOptions(mode Mode, schema map[string]any) []llms.CallOption {
+	switch mode {
+	case ModeJSON:
+		return []llms.CallOption{llms.WithJSONMode()}
+	case ModeTool:
+		return []llms.CallOption{
+			llms.WithTools([]llms.Tool{{
+				Type: "function",
+				Function: &llms.FunctionDefinition{
+					Name:        ToolName,
+					Description: "Respond with the structured reply",
+					Parameters:  toolParameters(schema),
+				},
+			}}),
+			llms.WithToolChoice(llms.ToolChoice{
+				Type:     "function",
+				Function: &llms.FunctionReference{Name: ToolName},
+			}),
+		}
+	default:
+		return nil
+	}
+}
+
+// toolParameters returns the tool parameters for schema. Tool arguments are
+// objects, so other schemas are wrapped in a value property.
+func toolParameters(schema map[string]any) map[string]any {
+	if schema["type"] == "object" {
+		return schema
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"value": schema},
+		"required":   []any{"value"},
+	}
+}
+
+// replyText returns the tool call arguments of a reply, or its content.
+func replyText(response *llms.ContentResponse) string {
+	if len(response.Choices) == 0 {
+		return ""
+	}
+	choice := response.Choices[0]
+	for _, call := range choice.ToolCalls {
+		if call.FunctionCall != nil && call.FunctionCall.Name == ToolName {
+			return call.FunctionCall.Arguments
+		}
+	}
+	return choice.Content
+}
+
+// Validate parses a reply as JSON and returns its value if it is valid against
+// schema. JSON in code fences and tool arguments wrapping the value are accepted.
+func Validate(schema map[string]any, raw string) (any, error) {
+	var value any
+	if err := json.Unmarshal([]byte(prompteval.ExtractJSON(raw)), &value); err != nil {
+		return nil, fmt.Errorf("reply is not valid JSON: %w", err)
+	}
+	// Tool arguments wrap schemas that are not objects
+	if wrapped, ok := value.(map[string]any); ok && schema["type"] != "object" && len(wrapped) == 1 {
+		if inner, exists := wrapped["value"]; exists && jsonschema.Validate(schema, value) != nil {
+			value = inner
+		}
+	}
+	if err := jsonschema.Validate(schema, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,142 @@
+package structured
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+)
+
+// fakeModel answers with the queued choices and records the calls it received.
+type fakeModel struct {
+	choices  []*llms.ContentChoice
+	messages [][]llms.MessageContent
+	options  []llms.CallOptions
+}
+
+func (m *fakeModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	m.options = append(m.options, opts)
+	m.messages = append(m.messages, messages)
+
+	index := len(m.messages) - 1
+	if index >= len(m.choices) {
+		return nil, errors.New("no response queued")
+	}
+	choice := m.choices[index]
+	choice.GenerationInfo = map[string]any{"PromptTokens": 10, "CompletionTokens": 5}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}, nil
+}
+
+func (m *fakeModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func replies(contents ...string) []*llms.ContentChoice {
+	choices := make([]*llms.ContentChoice, len(contents))
+	for i, content := range contents {
+		choices[i] = &llms.ContentChoice{Content: content}
+	}
+	return choices
+}
+
+var personSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"name", "age"},
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string"},
+		"age":  map[string]any{"type": "integer"},
+	},
+}
+
+var question = []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Who is Ada?")}
+
+func TestGenerateValidReply(t *testing.T) {
+	model := &fakeModel{choices: replies("```json\n{\"name\": \"Ada\", \"age\": 36}\n```")}
+
+	result, err := Generate(context.Background(), model, question, Options{Schema: personSchema, Mode: ModeJSON})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"name": "Ada", "age": float64(36)}, result.Value)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Equal(t, 10, result.Usage.InputTokens)
+	assert.True(t, model.options[0].JSONMode)
+	require.Len(t, model.messages[0], 2)
+	assert.Equal(t, llms.ChatMessageTypeSystem, model.messages[0][0].Role)
+}
+
+func TestGenerateRepairsInvalidReply(t *testing.T) {
+	model := &fakeModel{choices: replies(`{"name": "Ada"}`, `{"name": "Ada", "age": 36}`)}
+
+	result, err := Generate(context.Background(), model, question, Options{Schema: personSchema})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Attempts)
+	assert.Equal(t, 20, result.Usage.InputTokens)
+	repair := model.messages[1]
+	require.Len(t, repair, 4)
+	assert.Equal(t, llms.ChatMessageTypeAI, repair[2].Role)
+	assert.Contains(t, repair[3].Parts[0].(llms.TextContent).Text, "age")
+	assert.False(t, model.options[0].JSONMode)
+}
+
+func TestGenerateGivesUpAfterMaxRepairs(t *testing.T) {
+	model := &fakeModel{choices: replies("not json", `{"name": 1}`)}
+
+	_, err := Generate(context.Background(), model, question, Options{Schema: personSchema, MaxRepairs: 1})
+	require.Error(t, err)
+
+	var structuredErr *Error
+	require.True(t, errors.As(err, &structuredErr))
+	assert.Equal(t, 2, structuredErr.Attempts)
+	assert.Equal(t, `{"name": 1}`, structuredErr.Raw)
+	assert.Contains(t, err.Error(), "after 2 attempts")
+	assert.Len(t, model.messages, 2)
+}
+
+func TestGenerateToolMode(t *testing.T) {
+	schema := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	model := &fakeModel{choices: []*llms.ContentChoice{{
+		ToolCalls: []llms.ToolCall{{
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: ToolName, Arguments: `{"value": ["a", "b"]}`},
+		}},
+	}}}
+
+	result, err := Generate(context.Background(), model, question, Options{Schema: schema, Mode: ModeTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, []any{"a", "b"}, result.Value)
+	require.Len(t, model.options[0].Tools, 1)
+	assert.Equal(t, ToolName, model.options[0].Tools[0].Function.Name)
+	assert.NotNil(t, model.options[0].ToolChoice)
+}
+
+func TestGenerateAs(t *testing.T) {
+	type person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	model := &fakeModel{choices: replies(`{"name": "Ada", "age": 36}`)}
+
+	value, result, err := GenerateAs[person](context.Background(), model, question, Options{Schema: personSchema})
+	require.NoError(t, err)
+
+	assert.Equal(t, person{Name: "Ada", Age: 36}, value)
+	assert.Equal(t, 1, result.Attempts)
+}
+
+func TestModeFor(t *testing.T) {
+	assert.Equal(t, ModeTool, ModeFor(llm.ProviderAnthropic))
+	assert.Equal(t, ModeJSON, ModeFor(llm.ProviderOpenAI))
+	assert.Equal(t, ModeJSON, ModeFor(llm.ProviderOllama))
+	assert.Equal(t, ModePrompt, ModeFor("unknown"))
+}
@@ -0,0 +1,153 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/logger"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/templates/agents"
+)
+
+var agentLog *zap.Logger
+
+func init() {
+	agentLog = logger.WithPackage("templates.agents")
+}
+
+// AgentTemplateGenerator provides agent scaffolding functionality.
+type AgentTemplateGenerator interface {
+	GenerateAgentFiles(data AgentTemplateData, outputDir string) error
+}
+
+// AgentTemplateData contains all data needed to scaffold an agent.
+type AgentTemplateData struct {
+	Name        string
+	Version     string
+	Description string
+	Author      string
+	License     string
+	Type        string
+	Provider    string
+	Model       string
+	// Instructions become the personality of the agent behavior
+	Instructions string
+
+	// Tools and Prompts are the dependencies pulled with the agent
+	Tools   []schema.AgentTool
+	Prompts []schema.AgentPrompt
+}
+
+// agentTemplateGenerator implements AgentTemplateGenerator interface.
+type agentTemplateGenerator struct{}
+
+// NewAgentTemplateGenerator creates a new agent template generator.
+func NewAgentTemplateGenerator() AgentTemplateGenerator {
+	return &agentTemplateGenerator{}
+}
+
+// GenerateAgentFiles writes the agent manifest and README into outputDir.
+func (atg *agentTemplateGenerator) GenerateAgentFiles(data AgentTemplateData, outputDir string) error {
+	agentLog.Info("Generating agent files",
+		zap.String("name", data.Name),
+		zap.String("output_dir", outputDir))
+
+	if data.Version == "" {
+		data.Version = "0.1.0"
+	}
+	if data.Description == "" {
+		data.Description = fmt.Sprintf("AI agent %s", data.Name)
+	}
+	if data.License == "" {
+		data.License = "MIT"
+	}
+	if data.Type == "" {
+		data.Type = string(schema.AgentTypeConversational)
+	}
+	if data.Provider == "" {
+		data.Provider = "openai"
+	}
+	if data.Model == "" {
+		data.Model = "gpt-4o"
+	}
+
+	manifest, err := agentManifest(data)
+	if err != nil {
+		return err
+	}
+	readme, err := renderAgentTemplate("README.md.tmpl", data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	files := map[string][]byte{
+		schema.ManifestFileName: manifest,
+		"README.md":             []byte(readme),
+	}
+	for name, content := range files {
+		outputPath := filepath.Join(outputDir, name)
+		if err := os.WriteFile(outputPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", outputPath, err)
+		}
+	}
+
+	agentLog.Info("Agent files generated successfully",
+		zap.String("name", data.Name),
+		zap.Int("files", len(files)))
+	return nil
+}
+
+// renderAgentTemplate executes an embedded agent template with data.
+func renderAgentTemplate(templateFile string, data AgentTemplateData) (string, error) {
+	templateContent, err := agents.Templates.ReadFile(templateFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", templateFile, err)
+	}
+	tmpl, err := template.New(templateFile).Parse(string(templateContent))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", templateFile, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", templateFile, err)
+	}
+	return buf.String(), nil
+}
+
+// agentManifest builds and serializes the component.yaml of the scaffolded agent.
+func agentManifest(data AgentTemplateData) ([]byte, error) {
+	manifest := schema.NewAgent(data.Name, data.Version)
+	manifest.Metadata.Description = data.Description
+	manifest.Metadata.Author = data.Author
+	manifest.Metadata.License = data.License
+	manifest.Metadata.ForgeVersion = "0.1.0"
+	created := time.Now().UTC().Truncate(time.Second)
+	manifest.Metadata.CreationTimestamp = &created
+
+	manifest.Spec.Type = schema.AgentType(data.Type)
+	manifest.Spec.Model = schema.AgentModel{Provider: data.Provider, Model: data.Model}
+	manifest.Spec.Interface = schema.AgentInterface{Type: "cli"}
+	manifest.Spec.Tools = data.Tools
+	manifest.Spec.Prompts = data.Prompts
+	if data.Instructions != "" {
+		manifest.Spec.Behavior = &schema.AgentBehavior{Personality: data.Instructions}
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid agent manifest: %w", err)
+	}
+	content, err := schema.NewComponentParser().SerializeComponent(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize agent manifest: %w", err)
+	}
+	return content, nil
+}
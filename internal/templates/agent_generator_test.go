@@ -0,0 +1,44 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestGenerateAgentFiles(t *testing.T) {
+	dir := t.TempDir()
+	err := NewAgentTemplateGenerator().GenerateAgentFiles(AgentTemplateData{
+		Name:     "support-bot",
+		Provider: "anthropic",
+		Model:    "claude-3-5-sonnet",
+		Tools: []schema.AgentTool{
+			{Name: "ticket-search", Type: "tool", Source: "acme/ticket-search@v1.0.0", Required: true},
+		},
+		Prompts: []schema.AgentPrompt{{Name: "support-system", Type: "system", Source: "acme/support-system"}},
+	}, dir)
+	require.NoError(t, err)
+
+	readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(readme), "| ticket-search | acme/ticket-search@v1.0.0 | Yes |")
+
+	content, err := os.ReadFile(filepath.Join(dir, schema.ManifestFileName))
+	require.NoError(t, err)
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	require.NoError(t, err)
+	agent, ok := component.(*schema.Agent)
+	require.True(t, ok)
+
+	assert.Equal(t, "0.1.0", agent.Metadata.Version)
+	assert.Equal(t, schema.AgentTypeConversational, agent.Spec.Type)
+	assert.Equal(t, "anthropic", agent.Spec.Model.Provider)
+	assert.Equal(t, "cli", agent.Spec.Interface.Type)
+	assert.True(t, agent.HasTool("ticket-search"))
+	assert.True(t, agent.HasPrompt("support-system"))
+}
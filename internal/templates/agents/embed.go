@@ -0,0 +1,6 @@
+package agents
+
+import "embed"
+
+//go:embed *.tmpl
+var Templates embed.FS
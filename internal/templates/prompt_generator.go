@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/denkhaus/agentforge/internal/logger"
+	"github.com/denkhaus/agentforge/internal/schema"
 	"github.com/denkhaus/agentforge/internal/templates/prompts"
 	"go.uber.org/zap"
 )
@@ -49,6 +51,8 @@ type PromptTemplateData struct {
 	Instructions      string
 	OutputFormat      string
 	PrimaryVariable   string
+	// Template is the prompt text, rendered from template.txt.tmpl when empty
+	Template          string
 	
 	// Variables
 	Variables []PromptVariable
@@ -87,7 +91,7 @@ func NewPromptTemplateGenerator() PromptTemplateGenerator {
 	return &promptTemplateGenerator{}
 }
 
-// GeneratePromptFiles generates all prompt files using virtual filesystem templates
+// GeneratePromptFiles generates the prompt manifest and README using virtual filesystem templates
 func (ptg *promptTemplateGenerator) GeneratePromptFiles(data PromptTemplateData, outputDir string) error {
 	promptLog.Info("Generating prompt files", 
 		zap.String("name", data.Name),
@@ -148,52 +152,109 @@ func (ptg *promptTemplateGenerator) GeneratePromptFiles(data PromptTemplateData,
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 	
-	// Template files to generate
-	templateFiles := map[string]string{
-		"component.yaml.tmpl": "component.yaml",
-		"template.txt.tmpl":   "template.txt",
-		"variables.json.tmpl": "variables.json",
-		"README.md.tmpl":      "README.md",
-	}
-	
-	// Generate each file
-	for templateFile, outputFile := range templateFiles {
-		promptLog.Debug("Generating file", 
-			zap.String("template", templateFile),
-			zap.String("output", outputFile))
-		
-		// Read template from embedded filesystem
-		templateContent, err := prompts.Templates.ReadFile(templateFile)
-		if err != nil {
-			return fmt.Errorf("failed to read template %s: %w", templateFile, err)
-		}
-		
-		// Parse template
-		tmpl, err := template.New(templateFile).Parse(string(templateContent))
+	if data.Template == "" {
+		template, err := renderPromptTemplate("template.txt.tmpl", data)
 		if err != nil {
-			return fmt.Errorf("failed to parse template %s: %w", templateFile, err)
+			return err
 		}
-		
-		// Execute template
-		var buf bytes.Buffer
-		if err := tmpl.Execute(&buf, data); err != nil {
-			return fmt.Errorf("failed to execute template %s: %w", templateFile, err)
-		}
-		
-		// Write to output file
-		outputPath := filepath.Join(outputDir, outputFile)
-		if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		data.Template = template
+	}
+
+	manifest, err := promptManifest(data)
+	if err != nil {
+		return err
+	}
+	readme, err := renderPromptTemplate("README.md.tmpl", data)
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		schema.ManifestFileName: manifest,
+		"README.md":             []byte(readme),
+	}
+	for name, content := range files {
+		outputPath := filepath.Join(outputDir, name)
+		if err := os.WriteFile(outputPath, content, 0644); err != nil {
 			return fmt.Errorf("failed to write file %s: %w", outputPath, err)
 		}
-		
-		promptLog.Debug("Generated file successfully", 
+		promptLog.Debug("Generated file successfully",
 			zap.String("path", outputPath),
-			zap.Int("size", buf.Len()))
+			zap.Int("size", len(content)))
 	}
-	
-	promptLog.Info("Prompt files generated successfully", 
+
+	promptLog.Info("Prompt files generated successfully",
 		zap.String("name", data.Name),
-		zap.Int("files", len(templateFiles)))
-	
+		zap.Int("files", len(files)))
+
 	return nil
-}
\ No newline at end of file
+}
+
+// renderPromptTemplate executes an embedded prompt template with data.
+func renderPromptTemplate(templateFile string, data PromptTemplateData) (string, error) {
+	templateContent, err := prompts.Templates.ReadFile(templateFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", templateFile, err)
+	}
+	tmpl, err := template.New(templateFile).Parse(string(templateContent))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", templateFile, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", templateFile, err)
+	}
+	return buf.String(), nil
+}
+
+// promptManifest builds and serializes the component.yaml of the generated prompt.
+func promptManifest(data PromptTemplateData) ([]byte, error) {
+	manifest := schema.NewPrompt(data.Name, data.Version)
+	manifest.Metadata.Namespace = data.Namespace
+	manifest.Metadata.Description = data.Description
+	manifest.Metadata.Author = data.Author
+	manifest.Metadata.License = data.License
+	manifest.Metadata.Homepage = data.Homepage
+	manifest.Metadata.Documentation = data.Documentation
+	manifest.Metadata.Tags = data.Tags
+	manifest.Metadata.Categories = data.Categories
+	manifest.Metadata.Keywords = data.Keywords
+	manifest.Metadata.Stability = schema.Stability(data.Stability)
+	manifest.Metadata.Maturity = schema.Maturity(data.Maturity)
+	manifest.Metadata.ForgeVersion = data.ForgeVersion
+	manifest.Metadata.Platforms = data.Platforms
+	if created, err := time.Parse(time.RFC3339, data.Created); err == nil {
+		manifest.Metadata.CreationTimestamp = &created
+	}
+	if data.Language != "" {
+		manifest.Metadata.Labels = map[string]string{"language": data.Language}
+	}
+
+	manifest.Spec.Type = schema.PromptType(strings.ToLower(data.PromptType))
+	manifest.Spec.Format = schema.FormatMarkdown
+	manifest.Spec.Template = data.Template
+	for _, variable := range data.Variables {
+		manifest.Spec.Variables = append(manifest.Spec.Variables, schema.PromptVariable{
+			Name:        variable.Name,
+			Type:        variable.Type,
+			Description: variable.Description,
+			Required:    variable.Required,
+			Default:     variable.Default,
+		})
+	}
+	manifest.Spec.Models = data.ModelPreferences
+	manifest.Spec.Temperature = &data.Temperature
+	manifest.Spec.MaxTokens = &data.MaxTokens
+	if len(data.StopSequences) > 0 {
+		manifest.Spec.StopSequences = data.StopSequences
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid prompt manifest: %w", err)
+	}
+	content, err := schema.NewComponentParser().SerializeComponent(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize prompt manifest: %w", err)
+	}
+	return content, nil
+}
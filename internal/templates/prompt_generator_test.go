@@ -0,0 +1,48 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestGeneratePromptFiles(t *testing.T) {
+	dir := t.TempDir()
+	err := NewPromptTemplateGenerator().GeneratePromptFiles(PromptTemplateData{
+		Name:         "code-review",
+		Description:  "Reviews code changes",
+		Author:       "Jane Doe",
+		License:      "MIT",
+		PromptType:   "TEMPLATE",
+		Language:     "en",
+		Instructions: "Review the change.",
+		Variables:    []PromptVariable{{Name: "diff", Type: "string", Description: "The diff", Required: true}},
+	}, dir)
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dir, "README.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "template.txt"))
+
+	content, err := os.ReadFile(filepath.Join(dir, schema.ManifestFileName))
+	require.NoError(t, err)
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	require.NoError(t, err)
+	prompt, ok := component.(*schema.Prompt)
+	require.True(t, ok)
+
+	assert.Equal(t, "1.0.0", prompt.Metadata.Version)
+	assert.Equal(t, "Jane Doe", prompt.Metadata.Author)
+	assert.Equal(t, "en", prompt.Metadata.Labels["language"])
+	assert.Equal(t, schema.PromptTypeTemplate, prompt.Spec.Type)
+	assert.Contains(t, prompt.Spec.Template, "Review the change.")
+	assert.Contains(t, prompt.Spec.Template, "{{diff}}")
+	require.Len(t, prompt.Spec.Variables, 1)
+	assert.True(t, prompt.Spec.Variables[0].Required)
+	require.NotNil(t, prompt.Spec.Temperature)
+	assert.Equal(t, 0.7, *prompt.Spec.Temperature)
+}
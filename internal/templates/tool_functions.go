@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// functionNamePattern matches function and parameter names usable in generated code.
+var functionNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// ParseFunctionSpec parses a function declaration of the form
+//
+//	name(param:type!, other:type):description
+//
+// Parameters and the description are optional, the type defaults to string and
+// a trailing ! marks a parameter as required.
+func ParseFunctionSpec(spec string) (schema.ToolFunction, error) {
+	spec = strings.TrimSpace(spec)
+	head, description := spec, ""
+	params := ""
+
+	if open := strings.Index(spec, "("); open >= 0 {
+		closing := strings.Index(spec, ")")
+		if closing < open {
+			return schema.ToolFunction{}, fmt.Errorf("invalid function %q: missing closing parenthesis", spec)
+		}
+		head, params = spec[:open], spec[open+1:closing]
+		rest := strings.TrimSpace(spec[closing+1:])
+		if rest != "" {
+			if !strings.HasPrefix(rest, ":") {
+				return schema.ToolFunction{}, fmt.Errorf("invalid function %q: expected ':' before the description", spec)
+			}
+			description = rest[1:]
+		}
+	} else if name, desc, found := strings.Cut(spec, ":"); found {
+		head, description = name, desc
+	}
+
+	function := schema.ToolFunction{
+		Name:        strings.TrimSpace(head),
+		Description: strings.TrimSpace(description),
+	}
+	if !functionNamePattern.MatchString(function.Name) {
+		return schema.ToolFunction{}, fmt.Errorf("invalid function name %q: must start with a letter and contain only letters, numbers and underscores", function.Name)
+	}
+	if function.Description == "" {
+		function.Description = fmt.Sprintf("TODO: describe %s", function.Name)
+	}
+
+	for _, param := range strings.Split(params, ",") {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+		parameter, err := parseParameterSpec(param)
+		if err != nil {
+			return schema.ToolFunction{}, fmt.Errorf("invalid function %q: %w", spec, err)
+		}
+		function.Parameters = append(function.Parameters, parameter)
+	}
+	return function, nil
+}
+
+// parseParameterSpec parses a parameter declaration of the form name[:type][!].
+func parseParameterSpec(spec string) (schema.ToolParameter, error) {
+	required := strings.HasSuffix(spec, "!")
+	spec = strings.TrimSuffix(spec, "!")
+
+	name, paramType, found := strings.Cut(spec, ":")
+	name, paramType = strings.TrimSpace(name), strings.TrimSpace(paramType)
+	if !found || paramType == "" {
+		paramType = "string"
+	}
+
+	if !functionNamePattern.MatchString(name) {
+		return schema.ToolParameter{}, fmt.Errorf("invalid parameter name %q", name)
+	}
+	switch paramType {
+	case "string", "number", "boolean", "object", "array":
+	default:
+		return schema.ToolParameter{}, fmt.Errorf("parameter %s has unsupported type %q, use string, number, boolean, object or array", name, paramType)
+	}
+
+	return schema.ToolParameter{
+		Name:        name,
+		Type:        paramType,
+		Description: fmt.Sprintf("The %s parameter", name),
+		Required:    required,
+	}, nil
+}
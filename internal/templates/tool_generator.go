@@ -0,0 +1,331 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/denkhaus/agentforge/internal/logger"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/templates/tools"
+	"go.uber.org/zap"
+)
+
+var toolLog *zap.Logger
+
+func init() {
+	toolLog = logger.WithPackage("templates.tools")
+}
+
+const (
+	defaultToolGoVersion    = "1.23"
+	defaultToolMCPGoVersion = "v0.34.0"
+)
+
+// ToolTemplateGenerator provides tool project generation functionality.
+type ToolTemplateGenerator interface {
+	GenerateToolProject(data ToolTemplateData, outputDir string) error
+}
+
+// ToolTemplateData contains all data needed to generate a Go MCP server tool project.
+type ToolTemplateData struct {
+	Name         string
+	Module       string
+	Version      string
+	Description  string
+	Author       string
+	License      string
+	EntryPoint   string
+	GoVersion    string
+	MCPGoVersion string
+
+	// Functions become handler stubs, manifest functions and example tests
+	Functions []schema.ToolFunction
+}
+
+// toolTemplateGenerator implements ToolTemplateGenerator interface.
+type toolTemplateGenerator struct{}
+
+// NewToolTemplateGenerator creates a new tool project generator.
+func NewToolTemplateGenerator() ToolTemplateGenerator {
+	return &toolTemplateGenerator{}
+}
+
+// GenerateToolProject writes a compiling Go MCP server skeleton, its component.yaml,
+// example tests and a Makefile into outputDir.
+func (ttg *toolTemplateGenerator) GenerateToolProject(data ToolTemplateData, outputDir string) error {
+	toolLog.Info("Generating tool project",
+		zap.String("name", data.Name),
+		zap.String("output_dir", outputDir))
+
+	data = withToolDefaults(data)
+	for _, function := range data.Functions {
+		if !functionNamePattern.MatchString(function.Name) {
+			return fmt.Errorf("invalid function name %q: must start with a letter and contain only letters, numbers and underscores", function.Name)
+		}
+	}
+
+	manifest, err := toolManifest(data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	templateFiles := map[string]string{
+		"go.mod.tmpl":        "go.mod",
+		"main.go.tmpl":       "main.go",
+		"tools.go.tmpl":      "tools.go",
+		"tools_test.go.tmpl": "tools_test.go",
+		"Makefile.tmpl":      "Makefile",
+		"README.md.tmpl":     "README.md",
+		"gitignore.tmpl":     ".gitignore",
+	}
+
+	for templateFile, outputFile := range templateFiles {
+		templateContent, err := tools.Templates.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template %s: %w", templateFile, err)
+		}
+
+		tmpl, err := template.New(templateFile).Funcs(toolTemplateFuncs).Parse(string(templateContent))
+		if err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", templateFile, err)
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to execute template %s: %w", templateFile, err)
+		}
+
+		content := buf.Bytes()
+		if filepath.Ext(outputFile) == ".go" {
+			if content, err = format.Source(content); err != nil {
+				return fmt.Errorf("failed to format %s: %w", outputFile, err)
+			}
+		}
+
+		outputPath := filepath.Join(outputDir, outputFile)
+		if err := os.WriteFile(outputPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", outputPath, err)
+		}
+		toolLog.Debug("Generated file", zap.String("path", outputPath), zap.Int("size", len(content)))
+	}
+
+	manifestPath := filepath.Join(outputDir, schema.ManifestFileName)
+	if err := os.WriteFile(manifestPath, manifest, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", manifestPath, err)
+	}
+
+	toolLog.Info("Tool project generated successfully",
+		zap.String("name", data.Name),
+		zap.Int("functions", len(data.Functions)))
+	return nil
+}
+
+// withToolDefaults fills in the values left empty by the caller.
+func withToolDefaults(data ToolTemplateData) ToolTemplateData {
+	if data.Module == "" {
+		data.Module = data.Name
+	}
+	if data.Version == "" {
+		data.Version = "0.1.0"
+	}
+	if data.Description == "" {
+		data.Description = fmt.Sprintf("The %s tool", data.Name)
+	}
+	if data.Author == "" {
+		data.Author = "Your Name <your.email@example.com>"
+	}
+	if data.License == "" {
+		data.License = "MIT"
+	}
+	if data.EntryPoint == "" {
+		data.EntryPoint = "./bin/" + data.Name
+	}
+	if data.GoVersion == "" {
+		data.GoVersion = defaultToolGoVersion
+	}
+	if data.MCPGoVersion == "" {
+		data.MCPGoVersion = defaultToolMCPGoVersion
+	}
+	if len(data.Functions) == 0 {
+		data.Functions = []schema.ToolFunction{{
+			Name:        "hello",
+			Description: "Greets the given name",
+			Parameters: []schema.ToolParameter{
+				{Name: "name", Type: "string", Description: "Name to greet", Required: true},
+			},
+		}}
+	}
+
+	// Copy the functions so that adding examples does not modify the caller's manifest
+	functions := make([]schema.ToolFunction, len(data.Functions))
+	for i, function := range data.Functions {
+		if len(function.Examples) == 0 {
+			function.Examples = []schema.ToolExample{sampleExample(function)}
+		}
+		functions[i] = function
+	}
+	data.Functions = functions
+	return data
+}
+
+// toolManifest builds and serializes the component.yaml of the generated project.
+func toolManifest(data ToolTemplateData) ([]byte, error) {
+	manifest := schema.NewTool(data.Name, data.Version)
+	manifest.Metadata.Description = data.Description
+	manifest.Metadata.Author = data.Author
+	manifest.Metadata.License = data.License
+	manifest.Metadata.ForgeVersion = "1.0.0"
+	manifest.Spec.Type = schema.ToolTypeMCPServer
+	manifest.Spec.Runtime = schema.RuntimeGo
+	manifest.Spec.EntryPoint = data.EntryPoint
+	manifest.Spec.Functions = data.Functions
+
+	content, err := schema.NewComponentParser().SerializeComponent(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize tool manifest: %w", err)
+	}
+	return content, nil
+}
+
+// sampleExample returns an example calling function with placeholder arguments.
+// The generated handlers echo their arguments, so the expected output is the input.
+func sampleExample(function schema.ToolFunction) schema.ToolExample {
+	input := make(map[string]interface{}, len(function.Parameters))
+	for _, parameter := range function.Parameters {
+		input[parameter.Name] = sampleValue(parameter)
+	}
+	return schema.ToolExample{
+		Name:        "echo",
+		Description: "Replace with a real example once the handler is implemented",
+		Input:       input,
+		Output:      input,
+	}
+}
+
+// sampleValue returns a placeholder value of the parameter's type.
+func sampleValue(parameter schema.ToolParameter) interface{} {
+	if parameter.Default != nil {
+		return parameter.Default
+	}
+	switch parameter.Type {
+	case "number":
+		return 1
+	case "boolean":
+		return true
+	case "object":
+		return map[string]interface{}{}
+	case "array":
+		return []interface{}{}
+	default:
+		if len(parameter.Enum) > 0 {
+			return parameter.Enum[0]
+		}
+		return "example"
+	}
+}
+
+// toolTemplateFuncs are the helpers available to the tool project templates.
+var toolTemplateFuncs = template.FuncMap{
+	"quote":          strconv.Quote,
+	"goName":         goName,
+	"comment":        comment,
+	"paramOption":    paramOption,
+	"jsonInput":      jsonText,
+	"expectedOutput": expectedOutput,
+	"matchMode":      matchMode,
+}
+
+// goName converts a function name like get_user or get-user to GetUser.
+func goName(name string) string {
+	var builder strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// comment collapses text onto a single line for use in a Go comment.
+func comment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// paramOption renders the mcp-go option declaring parameter.
+func paramOption(parameter schema.ToolParameter) string {
+	option := "WithString"
+	switch parameter.Type {
+	case "number":
+		option = "WithNumber"
+	case "boolean":
+		option = "WithBoolean"
+	case "object":
+		option = "WithObject"
+	case "array":
+		option = "WithArray"
+	}
+
+	args := []string{strconv.Quote(parameter.Name)}
+	if parameter.Required {
+		args = append(args, "mcp.Required()")
+	}
+	if parameter.Description != "" {
+		args = append(args, fmt.Sprintf("mcp.Description(%s)", strconv.Quote(parameter.Description)))
+	}
+	if parameter.Type == "string" && len(parameter.Enum) > 0 {
+		values := make([]string, len(parameter.Enum))
+		for i, value := range parameter.Enum {
+			values[i] = strconv.Quote(value)
+		}
+		args = append(args, fmt.Sprintf("mcp.Enum(%s)", strings.Join(values, ", ")))
+	}
+	return fmt.Sprintf("mcp.%s(%s)", option, strings.Join(args, ", "))
+}
+
+// jsonText marshals value to JSON, falling back to null.
+func jsonText(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
+
+// matchMode returns the match mode of an example, subset when unset as in forge tool test.
+func matchMode(match string) string {
+	if match == "" {
+		return "subset"
+	}
+	return match
+}
+
+// expectedOutput renders an example output as the text a handler returns.
+// Strings are returned verbatim, everything else as JSON.
+func expectedOutput(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return jsonText(value)
+}
@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestParseFunctionSpec(t *testing.T) {
+	function, err := ParseFunctionSpec("search(query:string!, limit:number, raw):Search the index")
+	require.NoError(t, err)
+
+	assert.Equal(t, "search", function.Name)
+	assert.Equal(t, "Search the index", function.Description)
+	require.Len(t, function.Parameters, 3)
+	assert.Equal(t, schema.ToolParameter{Name: "query", Type: "string", Description: "The query parameter", Required: true}, function.Parameters[0])
+	assert.Equal(t, "number", function.Parameters[1].Type)
+	assert.False(t, function.Parameters[1].Required)
+	assert.Equal(t, "string", function.Parameters[2].Type)
+
+	function, err = ParseFunctionSpec("ping")
+	require.NoError(t, err)
+	assert.Equal(t, "ping", function.Name)
+	assert.Empty(t, function.Parameters)
+	assert.NotEmpty(t, function.Description)
+
+	for _, spec := range []string{"", "1st", "search(query", "search(q:date)", "search(q) trailing", "search(bad-name)"} {
+		_, err := ParseFunctionSpec(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestGenerateToolProject(t *testing.T) {
+	dir := t.TempDir()
+	search, err := ParseFunctionSpec("search(query:string!, limit:number, tags:array)")
+	require.NoError(t, err)
+
+	err = NewToolTemplateGenerator().GenerateToolProject(ToolTemplateData{
+		Name:      "demo",
+		Module:    "example.com/demo",
+		Functions: []schema.ToolFunction{search, {Name: "get_item", Description: "Gets an item"}},
+	}, dir)
+	require.NoError(t, err)
+
+	for _, file := range []string{"go.mod", "main.go", "tools.go", "tools_test.go", "Makefile", "README.md", ".gitignore", schema.ManifestFileName} {
+		assert.FileExists(t, filepath.Join(dir, file))
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range []string{"main.go", "tools.go", "tools_test.go"} {
+		_, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, parser.AllErrors)
+		assert.NoError(t, err, file)
+	}
+
+	tools, err := os.ReadFile(filepath.Join(dir, "tools.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(tools), `mcp.WithString("query", mcp.Required(), mcp.Description("The query parameter"))`)
+	assert.Contains(t, string(tools), "func handleGetItem(")
+
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(goMod), "module example.com/demo")
+
+	content, err := os.ReadFile(filepath.Join(dir, schema.ManifestFileName))
+	require.NoError(t, err)
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	require.NoError(t, err)
+	manifest, ok := component.(*schema.Tool)
+	require.True(t, ok)
+
+	assert.Equal(t, schema.ToolTypeMCPServer, manifest.Spec.Type)
+	assert.Equal(t, "./bin/demo", manifest.Spec.EntryPoint)
+	require.Len(t, manifest.Spec.Functions, 2)
+	require.Len(t, manifest.Spec.Functions[0].Examples, 1)
+	assert.Equal(t, "example", manifest.Spec.Functions[0].Examples[0].Input["query"])
+	assert.Empty(t, search.Examples, "caller functions must not be modified")
+}
+
+func TestGenerateToolProject_RejectsInvalidFunctionName(t *testing.T) {
+	err := NewToolTemplateGenerator().GenerateToolProject(ToolTemplateData{
+		Name:      "demo",
+		Functions: []schema.ToolFunction{{Name: "get-item", Description: "Gets an item"}},
+	}, t.TempDir())
+	assert.Error(t, err)
+}
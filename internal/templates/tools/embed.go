@@ -0,0 +1,6 @@
+package tools
+
+import "embed"
+
+//go:embed *.tmpl
+var Templates embed.FS
@@ -0,0 +1,73 @@
+package toolruntime
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// parseArguments decodes the JSON tool input and checks it against the declared parameters.
+// Defaults are applied for missing parameters. A non-JSON input is accepted for functions
+// with a single string parameter, since models often pass the bare value.
+func parseArguments(function schema.ToolFunction, input string) (map[string]any, error) {
+	args := make(map[string]any)
+
+	trimmed := strings.TrimSpace(input)
+	if trimmed != "" {
+		if err := json.Unmarshal([]byte(trimmed), &args); err != nil {
+			if len(function.Parameters) != 1 || function.Parameters[0].Type != "string" {
+				return nil, fmt.Errorf("invalid input for %s, expected a JSON object: %w", function.Name, err)
+			}
+			args[function.Parameters[0].Name] = input
+		}
+	}
+
+	for _, param := range function.Parameters {
+		value, exists := args[param.Name]
+		if !exists || value == nil {
+			if param.Default != nil {
+				args[param.Name] = param.Default
+				continue
+			}
+			if param.Required {
+				return nil, fmt.Errorf("missing required parameter %s", param.Name)
+			}
+			continue
+		}
+
+		if err := checkParameter(param, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// checkParameter checks the type and enum constraint of a single argument.
+func checkParameter(param schema.ToolParameter, value any) error {
+	valid := true
+	switch param.Type {
+	case "string":
+		_, valid = value.(string)
+	case "number":
+		_, valid = value.(float64)
+	case "boolean":
+		_, valid = value.(bool)
+	case "object":
+		_, valid = value.(map[string]any)
+	case "array":
+		_, valid = value.([]any)
+	}
+	if !valid {
+		return fmt.Errorf("parameter %s must be of type %s", param.Name, param.Type)
+	}
+
+	if len(param.Enum) > 0 && !slices.Contains(param.Enum, fmt.Sprint(value)) {
+		return fmt.Errorf("parameter %s must be one of: %s", param.Name, strings.Join(param.Enum, ", "))
+	}
+
+	return nil
+}
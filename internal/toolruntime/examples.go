@@ -0,0 +1,253 @@
+package toolruntime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/tools"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// Example match modes, set per example or as the default for a run.
+const (
+	// MatchExact requires the output to equal the expected output. JSON outputs are
+	// compared structurally, everything else as trimmed text.
+	MatchExact = "exact"
+	// MatchSubset requires every field and array element of the expected JSON output
+	// to be present in the actual output.
+	MatchSubset = "subset"
+	// MatchRegex requires the output to match the expected output as a regular expression.
+	MatchRegex = "regex"
+)
+
+// errCallFailed marks example results whose function could not be called, as
+// opposed to calls whose output did not match.
+var errCallFailed = errors.New("call failed")
+
+// maxReportedOutput limits the length of outputs quoted in mismatch errors.
+const maxReportedOutput = 200
+
+// ExampleResult is the outcome of calling a function with one of its examples.
+type ExampleResult struct {
+	Function string
+	Example  string
+	Match    string
+	Output   string
+	Err      error
+	Duration time.Duration
+}
+
+// Passed reports whether the example call succeeded and its output matched.
+func (r ExampleResult) Passed() bool {
+	return r.Err == nil
+}
+
+// RunExamples calls the loaded function tools of manifest with the input of every
+// function example and matches the results against the expected outputs.
+// Examples without a match mode use defaultMatch.
+func RunExamples(ctx context.Context, manifest *schema.Tool, loaded []tools.Tool, defaultMatch string, timeout time.Duration) []ExampleResult {
+	byName := make(map[string]tools.Tool, len(loaded))
+	for _, tool := range loaded {
+		byName[tool.Name()] = tool
+	}
+
+	results := make([]ExampleResult, 0)
+	for _, function := range manifest.Spec.Functions {
+		tool := byName[functionToolName(manifest, function)]
+		for _, example := range function.Examples {
+			result := ExampleResult{
+				Function: function.Name,
+				Example:  example.Name,
+				Match:    example.Match,
+			}
+			if result.Match == "" {
+				result.Match = defaultMatch
+			}
+
+			if tool == nil {
+				result.Err = fmt.Errorf("%w: function %s is not served by the tool", errCallFailed, function.Name)
+				results = append(results, result)
+				continue
+			}
+
+			result.Output, result.Duration, result.Err = callExample(ctx, tool, example, timeout)
+			if result.Err == nil {
+				result.Err = MatchOutput(result.Match, example.Output, result.Output)
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// callExample calls tool with the example input and returns its output.
+func callExample(ctx context.Context, tool tools.Tool, example schema.ToolExample, timeout time.Duration) (string, time.Duration, error) {
+	input := example.Input
+	if input == nil {
+		input = map[string]interface{}{}
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to encode example input: %w", err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	output, err := tool.Call(callCtx, string(data))
+	duration := time.Since(start)
+	if err != nil {
+		return output, duration, fmt.Errorf("%w: %w", errCallFailed, err)
+	}
+	return output, duration, nil
+}
+
+// MatchOutput checks actual against the expected example output using mode and
+// returns an error describing the mismatch. Examples without an output only check
+// that the call succeeds.
+func MatchOutput(mode string, expected interface{}, actual string) error {
+	switch mode {
+	case MatchRegex:
+		if expected == nil {
+			return nil
+		}
+		pattern, ok := expected.(string)
+		if !ok {
+			return fmt.Errorf("regex match needs a string pattern as expected output, got %T", expected)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid output pattern: %w", err)
+		}
+		if !re.MatchString(actual) {
+			return fmt.Errorf("output %s does not match /%s/", truncateOutput(actual), pattern)
+		}
+		return nil
+
+	case MatchExact, MatchSubset:
+		if expected == nil {
+			return nil
+		}
+		if text, ok := expected.(string); ok {
+			if matchText(text, actual) {
+				return nil
+			}
+			return mismatch(text, actual)
+		}
+
+		want, err := normalizeJSON(expected)
+		if err != nil {
+			return fmt.Errorf("invalid expected output: %w", err)
+		}
+		var got interface{}
+		if err := json.Unmarshal([]byte(actual), &got); err != nil {
+			return fmt.Errorf("output is not JSON: %s", truncateOutput(actual))
+		}
+
+		matched := reflect.DeepEqual(want, got)
+		if mode == MatchSubset {
+			matched = containsJSON(want, got)
+		}
+		if !matched {
+			return mismatch(compactJSON(want), actual)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown match mode %q, use %s, %s or %s", mode, MatchExact, MatchSubset, MatchRegex)
+	}
+}
+
+// matchText compares an expected text with the output, which may also be a JSON string.
+func matchText(expected, actual string) bool {
+	if strings.TrimSpace(expected) == strings.TrimSpace(actual) {
+		return true
+	}
+	var text string
+	return json.Unmarshal([]byte(actual), &text) == nil && text == expected
+}
+
+// containsJSON reports whether every object field and array element of want is
+// present in got. Array elements may appear in any order.
+func containsJSON(want, got interface{}) bool {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		object, ok := got.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			field, exists := object[key]
+			if !exists || !containsJSON(value, field) {
+				return false
+			}
+		}
+		return true
+
+	case []interface{}:
+		list, ok := got.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, value := range want {
+			found := false
+			for _, element := range list {
+				if containsJSON(value, element) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return reflect.DeepEqual(want, got)
+	}
+}
+
+// normalizeJSON converts a YAML decoded value to the types produced by encoding/json.
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// compactJSON renders value as JSON for error messages.
+func compactJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// mismatch returns the error for an output that differs from the expected output.
+func mismatch(expected, actual string) error {
+	return fmt.Errorf("expected %s, got %s", truncateOutput(expected), truncateOutput(actual))
+}
+
+// truncateOutput quotes output, shortened to maxReportedOutput characters.
+func truncateOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxReportedOutput {
+		output = output[:maxReportedOutput] + "..."
+	}
+	return fmt.Sprintf("%q", output)
+}
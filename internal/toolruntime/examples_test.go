@@ -0,0 +1,139 @@
+package toolruntime
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestMatchOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		expected interface{}
+		actual   string
+		matches  bool
+	}{
+		{"exact text", MatchExact, "echo: hi", "echo: hi\n", true},
+		{"exact text mismatch", MatchExact, "echo: hi", "echo: ho", false},
+		{"exact JSON string", MatchExact, "hi", `"hi"`, true},
+		{"exact JSON", MatchExact, map[string]interface{}{"count": 2}, `{"count": 2.0}`, true},
+		{"exact JSON extra field", MatchExact, map[string]interface{}{"count": 2}, `{"count":2,"more":true}`, false},
+		{"exact not JSON", MatchExact, map[string]interface{}{"count": 2}, "count: 2", false},
+		{"subset object", MatchSubset, map[string]interface{}{"user": map[string]interface{}{"id": 1}}, `{"user":{"id":1,"name":"a"},"ok":true}`, true},
+		{"subset missing field", MatchSubset, map[string]interface{}{"id": 1, "name": "a"}, `{"id":1}`, false},
+		{"subset array any order", MatchSubset, []interface{}{map[string]interface{}{"id": 2}}, `[{"id":1},{"id":2,"x":0}]`, true},
+		{"subset array missing element", MatchSubset, []interface{}{3}, `[1,2]`, false},
+		{"subset type mismatch", MatchSubset, map[string]interface{}{"id": "1"}, `{"id":1}`, false},
+		{"regex", MatchRegex, `^echo: \w+$`, "echo: hi", true},
+		{"regex mismatch", MatchRegex, `^\d+$`, "echo: hi", false},
+		{"regex not a string", MatchRegex, 42, "42", false},
+		{"no expected output", MatchSubset, nil, "anything", true},
+		{"unknown mode", "fuzzy", "a", "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MatchOutput(tt.mode, tt.expected, tt.actual)
+			if tt.matches {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRunExamples_MCPServerTool(t *testing.T) {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	manifest := newTestTool(schema.ToolTypeMCPServer, schema.RuntimeRust, executable)
+	manifest.Spec.Configuration.Environment = map[string]string{testServerEnv: "1"}
+	input := map[string]interface{}{"text": "hi"}
+	manifest.Spec.Functions = []schema.ToolFunction{
+		{
+			Name:        "echo",
+			Description: "Echoes the text",
+			Parameters: []schema.ToolParameter{
+				{Name: "text", Type: "string", Description: "Text to echo", Required: true},
+			},
+			Examples: []schema.ToolExample{
+				{Name: "text", Input: input, Output: "echo: hi"},
+				{Name: "pattern", Input: input, Output: "^echo", Match: MatchRegex},
+				{Name: "wrong", Input: input, Output: "echo: ho", Match: MatchExact},
+			},
+		},
+		{
+			Name:        "missing",
+			Description: "Not served by the server",
+			Examples:    []schema.ToolExample{{Name: "call", Output: "x"}},
+		},
+	}
+
+	runtime := NewToolRuntime(zaptest.NewLogger(t), Options{Timeout: 10 * time.Second})
+	defer runtime.Close()
+
+	loaded, err := runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	require.NoError(t, err)
+
+	results := RunExamples(context.Background(), manifest, loaded, MatchSubset, 10*time.Second)
+	require.Len(t, results, 4)
+
+	assert.True(t, results[0].Passed(), results[0].Err)
+	assert.Equal(t, MatchSubset, results[0].Match)
+	assert.Equal(t, "echo: hi", results[0].Output)
+	assert.True(t, results[1].Passed(), results[1].Err)
+	assert.False(t, results[2].Passed())
+	assert.ErrorContains(t, results[2].Err, "expected")
+	assert.NotErrorIs(t, results[2].Err, errCallFailed)
+	assert.False(t, results[3].Passed())
+	assert.ErrorContains(t, results[3].Err, "not served")
+	assert.ErrorIs(t, results[3].Err, errCallFailed)
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	results := []ExampleResult{
+		{Function: "echo", Example: "text", Match: MatchExact, Output: "echo: hi", Duration: 1500 * time.Millisecond},
+		{Function: "echo", Example: "wrong", Match: MatchExact, Err: errors.New("expected a, got b")},
+		{Function: "echo", Example: "timeout", Match: MatchExact, Err: fmt.Errorf("%w: deadline exceeded", errCallFailed)},
+		{Example: "health", Output: "healthy", Err: errors.New("health ping failed")},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnitReport(&buf, "test-tool", results, time.Now()))
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	require.Len(t, report.Suites, 1)
+
+	suite := report.Suites[0]
+	assert.Equal(t, "test-tool", suite.Name)
+	assert.Equal(t, 4, suite.Tests)
+	assert.Equal(t, 3, suite.Failures)
+	require.Len(t, suite.Cases, 4)
+	assert.Equal(t, "echo/text", suite.Cases[0].Name)
+	assert.Equal(t, "test-tool.echo", suite.Cases[0].Classname)
+	assert.Equal(t, "1.500", suite.Cases[0].Time)
+	assert.Nil(t, suite.Cases[0].Failure)
+	require.NotNil(t, suite.Cases[1].Failure)
+	assert.Equal(t, "expected a, got b", suite.Cases[1].Failure.Message)
+	assert.Equal(t, MatchExact, suite.Cases[1].Failure.Type)
+	require.NotNil(t, suite.Cases[2].Failure)
+	assert.Equal(t, "call failed: deadline exceeded", suite.Cases[2].Failure.Message)
+	assert.Equal(t, "call", suite.Cases[2].Failure.Type, "call errors are not reported as mismatches")
+	assert.Equal(t, "health", suite.Cases[3].Name)
+	assert.Equal(t, "test-tool", suite.Cases[3].Classname)
+	assert.Equal(t, "check", suite.Cases[3].Failure.Type)
+}
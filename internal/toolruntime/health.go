@@ -0,0 +1,315 @@
+package toolruntime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+const (
+	// healthMonitorTick is how often the monitor looks for due health checks.
+	healthMonitorTick = time.Second
+	// Defaults for unset ToolHealthCheck fields.
+	defaultHealthInterval         = 30 * time.Second
+	defaultHealthTimeout          = 5 * time.Second
+	defaultHealthFailureThreshold = 3
+)
+
+// healthMonitor is a private implementation of types.ToolHealthMonitor interface.
+type healthMonitor struct {
+	log       *zap.Logger
+	runtime   *toolRuntime
+	health    map[string]*types.ToolHealth
+	functions map[string]string // function tool name -> tool name
+	callbacks []func(health types.ToolHealth)
+	cancel    context.CancelFunc
+	done      chan struct{}
+	mutex     sync.RWMutex
+}
+
+// NewHealthMonitor creates a monitor running the declared health checks of the tools
+// loaded by the runtime. Tools without an enabled health check are always healthy.
+func NewHealthMonitor(log *zap.Logger, runtime types.ToolRuntime) (types.ToolHealthMonitor, error) {
+	toolRuntime, ok := runtime.(*toolRuntime)
+	if !ok {
+		return nil, fmt.Errorf("unsupported tool runtime %T", runtime)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor := &healthMonitor{
+		log:       log,
+		runtime:   toolRuntime,
+		health:    make(map[string]*types.ToolHealth),
+		functions: make(map[string]string),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go monitor.run(ctx)
+
+	return monitor, nil
+}
+
+// IsToolHealthy reports whether a function tool or tool is not marked unhealthy.
+func (m *healthMonitor) IsToolHealthy(name string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if tool, exists := m.functions[name]; exists {
+		name = tool
+	}
+	health, exists := m.health[name]
+	return !exists || health.State != types.ToolHealthUnhealthy
+}
+
+// GetHealth returns the health of all loaded tools.
+func (m *healthMonitor) GetHealth() []types.ToolHealth {
+	m.sync()
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	result := make([]types.ToolHealth, 0, len(m.health))
+	for _, health := range m.health {
+		result = append(result, *health)
+	}
+	slices.SortFunc(result, func(a, b types.ToolHealth) int {
+		return strings.Compare(a.Tool, b.Tool)
+	})
+	return result
+}
+
+// CheckTool runs the health check of a loaded tool immediately. Tools without a declared
+// health check are probed with the default settings.
+func (m *healthMonitor) CheckTool(ctx context.Context, tool string) (types.ToolHealth, error) {
+	m.sync()
+
+	loaded := m.runtime.getLoaded(tool)
+	if loaded == nil {
+		return types.ToolHealth{}, fmt.Errorf("tool %s is not loaded", tool)
+	}
+
+	m.check(ctx, loaded)
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return *m.health[tool], nil
+}
+
+// OnHealthChange registers a callback for health state changes.
+func (m *healthMonitor) OnHealthChange(callback func(health types.ToolHealth)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// Close stops the monitor loop.
+func (m *healthMonitor) Close() error {
+	m.cancel()
+	<-m.done
+	return nil
+}
+
+// Shutdown implements do.Shutdownable so the container stops the monitor.
+func (m *healthMonitor) Shutdown() error {
+	return m.Close()
+}
+
+// run checks the tools whose health check is due until the context is cancelled.
+func (m *healthMonitor) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(healthMonitorTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sync()
+			for _, name := range m.runtime.GetLoadedTools() {
+				loaded := m.runtime.getLoaded(name)
+				if loaded != nil && m.isDue(loaded) {
+					m.check(ctx, loaded)
+				}
+			}
+		}
+	}
+}
+
+// sync adds entries for newly loaded tools and drops those of unloaded tools.
+func (m *healthMonitor) sync() {
+	loadedNames := m.runtime.GetLoadedTools()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for name := range m.health {
+		if !slices.Contains(loadedNames, name) {
+			delete(m.health, name)
+		}
+	}
+
+	m.functions = make(map[string]string)
+	for _, name := range loadedNames {
+		loaded := m.runtime.getLoaded(name)
+		if loaded == nil {
+			continue
+		}
+		functions := loaded.functionNames()
+		for _, function := range functions {
+			m.functions[function] = name
+		}
+		if _, exists := m.health[name]; !exists {
+			m.health[name] = &types.ToolHealth{
+				Tool:             name,
+				State:            types.ToolHealthUnknown,
+				Checked:          healthCheckEnabled(loaded.manifest),
+				Functions:        functions,
+				FailureThreshold: healthSettings(loaded.manifest).failureThreshold,
+			}
+		}
+	}
+}
+
+// isDue reports whether the declared health check of a tool should run now.
+func (m *healthMonitor) isDue(loaded *loadedTool) bool {
+	if !healthCheckEnabled(loaded.manifest) {
+		return false
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	health, exists := m.health[loaded.manifest.Metadata.Name]
+	return !exists || time.Since(health.LastCheck) >= healthSettings(loaded.manifest).interval
+}
+
+// check probes a tool and records the result, notifying callbacks on state changes.
+func (m *healthMonitor) check(ctx context.Context, loaded *loadedTool) {
+	name := loaded.manifest.Metadata.Name
+	settings := healthSettings(loaded.manifest)
+
+	probeCtx, cancel := context.WithTimeout(ctx, settings.timeout)
+	err := m.runtime.probe(probeCtx, loaded)
+	cancel()
+
+	m.mutex.Lock()
+	health, exists := m.health[name]
+	if !exists {
+		m.mutex.Unlock()
+		return
+	}
+
+	previous := health.State
+	health.LastCheck = time.Now()
+	if err == nil {
+		health.State = types.ToolHealthHealthy
+		health.LastError = ""
+		health.ConsecutiveFailures = 0
+	} else {
+		health.LastError = err.Error()
+		health.ConsecutiveFailures++
+		if health.ConsecutiveFailures >= settings.failureThreshold {
+			health.State = types.ToolHealthUnhealthy
+		}
+	}
+	snapshot := *health
+	callbacks := append([]func(types.ToolHealth){}, m.callbacks...)
+	m.mutex.Unlock()
+
+	if snapshot.State == previous {
+		return
+	}
+
+	if snapshot.State == types.ToolHealthUnhealthy {
+		m.log.Warn("Tool became unhealthy",
+			zap.String("tool", name),
+			zap.Int("failures", snapshot.ConsecutiveFailures),
+			zap.String("error", snapshot.LastError))
+	} else {
+		m.log.Info("Tool is healthy", zap.String("tool", name))
+	}
+	for _, callback := range callbacks {
+		callback(snapshot)
+	}
+}
+
+// probe runs a single health probe: a ping for mcp-server tools and a GET of the
+// health check path for http and webhook tools.
+func (r *toolRuntime) probe(ctx context.Context, loaded *loadedTool) error {
+	if loaded.client != nil {
+		if err := loaded.client.Ping(ctx); err != nil {
+			if violation := loaded.sandbox.violation("health check"); violation != nil {
+				return violation
+			}
+			return fmt.Errorf("ping failed: %w", err)
+		}
+		return nil
+	}
+
+	target := strings.TrimRight(loaded.manifest.Spec.EntryPoint, "/")
+	if check := loaded.manifest.Spec.HealthCheck; check != nil && check.Path != "" {
+		target += "/" + strings.TrimLeft(check.Path, "/")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("invalid health check url: %w", err)
+	}
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("health check request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("health check returned status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// healthCheckSettings holds the effective health check settings of a tool.
+type healthCheckSettings struct {
+	interval         time.Duration
+	timeout          time.Duration
+	failureThreshold int
+}
+
+// healthCheckEnabled reports whether a tool declares an enabled health check.
+func healthCheckEnabled(manifest *schema.Tool) bool {
+	return manifest.Spec.HealthCheck != nil && manifest.Spec.HealthCheck.Enabled
+}
+
+// healthSettings returns the health check settings of a tool with defaults applied.
+func healthSettings(manifest *schema.Tool) healthCheckSettings {
+	settings := healthCheckSettings{
+		interval:         defaultHealthInterval,
+		timeout:          defaultHealthTimeout,
+		failureThreshold: defaultHealthFailureThreshold,
+	}
+
+	check := manifest.Spec.HealthCheck
+	if check == nil {
+		return settings
+	}
+	if check.IntervalSeconds > 0 {
+		settings.interval = time.Duration(check.IntervalSeconds) * time.Second
+	}
+	if check.TimeoutSeconds > 0 {
+		settings.timeout = time.Duration(check.TimeoutSeconds) * time.Second
+	}
+	if check.FailureThreshold > 0 {
+		settings.failureThreshold = check.FailureThreshold
+	}
+	return settings
+}
@@ -0,0 +1,94 @@
+package toolruntime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+func TestHealthMonitor_HTTPTool(t *testing.T) {
+	var healthy atomic.Bool
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/healthz", r.URL.Path)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer httpServer.Close()
+
+	manifest := newTestTool(schema.ToolTypeHTTP, schema.RuntimeGo, httpServer.URL)
+	manifest.Spec.Functions = []schema.ToolFunction{{Name: "lookup", Description: "Looks something up"}}
+	manifest.Spec.HealthCheck = &schema.ToolHealthCheck{Enabled: true, Path: "/healthz", FailureThreshold: 2}
+
+	log := zaptest.NewLogger(t)
+	runtime := NewToolRuntime(log, Options{Timeout: 5 * time.Second})
+	defer runtime.Close()
+
+	monitor, err := NewHealthMonitor(log, runtime)
+	require.NoError(t, err)
+	defer monitor.Close()
+
+	changes := make([]types.ToolHealth, 0)
+	monitor.OnHealthChange(func(health types.ToolHealth) {
+		changes = append(changes, health)
+	})
+
+	_, err = runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	health, err := monitor.CheckTool(ctx, "test-tool")
+	require.NoError(t, err)
+	assert.Equal(t, types.ToolHealthUnknown, health.State)
+	assert.Equal(t, 1, health.ConsecutiveFailures)
+	assert.True(t, monitor.IsToolHealthy("test-tool_lookup"))
+
+	health, err = monitor.CheckTool(ctx, "test-tool")
+	require.NoError(t, err)
+	assert.Equal(t, types.ToolHealthUnhealthy, health.State)
+	assert.Contains(t, health.LastError, "status 503")
+	assert.False(t, monitor.IsToolHealthy("test-tool_lookup"))
+	assert.False(t, monitor.IsToolHealthy("test-tool"))
+
+	healthy.Store(true)
+	health, err = monitor.CheckTool(ctx, "test-tool")
+	require.NoError(t, err)
+	assert.Equal(t, types.ToolHealthHealthy, health.State)
+	assert.Zero(t, health.ConsecutiveFailures)
+	assert.True(t, monitor.IsToolHealthy("test-tool_lookup"))
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, types.ToolHealthUnhealthy, changes[0].State)
+	assert.Equal(t, types.ToolHealthHealthy, changes[1].State)
+
+	require.NoError(t, runtime.UnloadTool("test-tool"))
+	assert.Empty(t, monitor.GetHealth())
+	_, err = monitor.CheckTool(ctx, "test-tool")
+	assert.ErrorContains(t, err, "not loaded")
+}
+
+func TestHealthSettings_Defaults(t *testing.T) {
+	manifest := newTestTool(schema.ToolTypeHTTP, schema.RuntimeGo, "http://localhost:1")
+	assert.False(t, healthCheckEnabled(manifest))
+	assert.Equal(t, healthCheckSettings{
+		interval:         defaultHealthInterval,
+		timeout:          defaultHealthTimeout,
+		failureThreshold: defaultHealthFailureThreshold,
+	}, healthSettings(manifest))
+
+	manifest.Spec.HealthCheck = &schema.ToolHealthCheck{Enabled: true, IntervalSeconds: 10, TimeoutSeconds: 2}
+	settings := healthSettings(manifest)
+	assert.Equal(t, 10*time.Second, settings.interval)
+	assert.Equal(t, 2*time.Second, settings.timeout)
+	assert.Equal(t, defaultHealthFailureThreshold, settings.failureThreshold)
+}
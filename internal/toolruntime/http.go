@@ -0,0 +1,208 @@
+package toolruntime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// maxResponseSize limits how much of an http tool response is read.
+const maxResponseSize = 10 * 1024 * 1024
+
+// newHTTPTools wraps every function of an http or webhook manifest.
+func (r *toolRuntime) newHTTPTools(manifest *schema.Tool) []tools.Tool {
+	result := make([]tools.Tool, 0, len(manifest.Spec.Functions))
+	for _, function := range manifest.Spec.Functions {
+		result = append(result, &httpFunctionTool{
+			name:     functionToolName(manifest, function),
+			spec:     manifest.Spec,
+			function: function,
+			client:   r.httpClient,
+			secrets:  r.opts.Secrets,
+		})
+	}
+	return result
+}
+
+// httpFunctionTool exposes a function of an http or webhook tool as a langchain-go tool.
+type httpFunctionTool struct {
+	name     string
+	spec     schema.ToolSpec
+	function schema.ToolFunction
+	client   *http.Client
+	secrets  SecretLookup
+}
+
+// Name returns the registered tool name.
+func (t *httpFunctionTool) Name() string {
+	return t.name
+}
+
+// Description returns the function description.
+func (t *httpFunctionTool) Description() string {
+	return t.function.Description
+}
+
+// Schema returns the JSON Schema of the function input.
+func (t *httpFunctionTool) Schema() map[string]any {
+	return functionSchema(t.function)
+}
+
+// Call sends the request built from the arguments and returns the response body.
+func (t *httpFunctionTool) Call(ctx context.Context, input string) (string, error) {
+	args, err := parseArguments(t.function, input)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := t.buildRequest(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to call %s: %w", t.name, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response of %s: %w", t.name, err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return "", fmt.Errorf("%s returned status %d: %s", t.name, response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return string(body), nil
+}
+
+// buildRequest renders the request template of the function with the given arguments.
+// Parameters go to the path, query, headers or body as declared by their "in" field;
+// the default is the query for GET and DELETE requests and the body otherwise.
+func (t *httpFunctionTool) buildRequest(ctx context.Context, args map[string]any) (*http.Request, error) {
+	template := t.requestTemplate()
+	method := strings.ToUpper(template.Method)
+
+	path := template.Path
+	query := url.Values{}
+	headers := http.Header{}
+	body := make(map[string]any)
+
+	for _, param := range t.function.Parameters {
+		value, exists := args[param.Name]
+		if !exists {
+			continue
+		}
+
+		switch parameterLocation(param, method) {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(fmt.Sprint(value)))
+		case "query":
+			query.Set(param.Name, fmt.Sprint(value))
+		case "header":
+			headers.Set(param.Name, fmt.Sprint(value))
+		default:
+			body[param.Name] = value
+		}
+	}
+
+	target, err := url.Parse(strings.TrimRight(t.spec.EntryPoint, "/") + path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint for %s: %w", t.name, err)
+	}
+	for key, value := range template.Query {
+		query.Set(key, t.expand(value, args))
+	}
+	if len(query) > 0 {
+		values := target.Query()
+		for key := range query {
+			values.Set(key, query.Get(key))
+		}
+		target.RawQuery = values.Encode()
+	}
+
+	var payload io.Reader
+	if t.spec.Type == schema.ToolTypeWebhook {
+		body = map[string]any{"function": t.function.Name, "arguments": body}
+	}
+	if len(body) > 0 {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		payload = bytes.NewReader(data)
+		headers.Set("Content-Type", "application/json")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", t.name, err)
+	}
+
+	for key, value := range template.Headers {
+		request.Header.Set(key, t.expand(value, args))
+	}
+	for key := range headers {
+		request.Header.Set(key, headers.Get(key))
+	}
+	return request, nil
+}
+
+// requestTemplate returns the request template of the function with defaults applied.
+// http functions default to POST /<function>, webhooks to POST on the entry point itself.
+func (t *httpFunctionTool) requestTemplate() schema.ToolRequest {
+	template := schema.ToolRequest{}
+	if t.function.Request != nil {
+		template = *t.function.Request
+	}
+	if template.Method == "" {
+		template.Method = http.MethodPost
+	}
+	if template.Path == "" && t.spec.Type == schema.ToolTypeHTTP {
+		template.Path = "/" + t.function.Name
+	}
+	return template
+}
+
+// expand replaces {param} references with arguments and ${NAME} references with the
+// tool's declared environment or secrets, falling back to the process environment.
+func (t *httpFunctionTool) expand(value string, args map[string]any) string {
+	value = os.Expand(value, func(name string) string {
+		if declared, exists := t.spec.Configuration.Environment[name]; exists {
+			return declared
+		}
+		if t.secrets != nil && slices.Contains(t.spec.Configuration.Secrets, name) {
+			if secret, ok := t.secrets(name); ok {
+				return secret
+			}
+		}
+		return os.Getenv(name)
+	})
+	for name, arg := range args {
+		value = strings.ReplaceAll(value, "{"+name+"}", fmt.Sprint(arg))
+	}
+	return value
+}
+
+// parameterLocation returns where a parameter is placed in the request.
+func parameterLocation(param schema.ToolParameter, method string) string {
+	if param.In != "" {
+		return param.In
+	}
+	if method == http.MethodGet || method == http.MethodDelete {
+		return "query"
+	}
+	return "body"
+}
@@ -0,0 +1,105 @@
+package toolruntime
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the test cases of one tool.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a single check, failed when Failure is set.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure describes why a test case failed.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes the results of testing the named tool as JUnit XML.
+// Results are reported as test cases named <function>/<example> in class <tool>.<function>.
+func WriteJUnitReport(w io.Writer, tool string, results []ExampleResult, started time.Time) error {
+	suite := junitTestSuite{
+		Name:      tool,
+		Tests:     len(results),
+		Timestamp: started.UTC().Format(time.RFC3339),
+		Time:      junitSeconds(time.Since(started)),
+		Cases:     make([]junitTestCase, 0, len(results)),
+	}
+
+	for _, result := range results {
+		name, classname := result.Example, tool
+		if result.Function != "" {
+			name = result.Function + "/" + result.Example
+			classname = tool + "." + result.Function
+		}
+		testCase := junitTestCase{
+			Name:      name,
+			Classname: classname,
+			Time:      junitSeconds(result.Duration),
+			SystemOut: result.Output,
+		}
+		if !result.Passed() {
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: result.Err.Error(),
+				Type:    failureType(result),
+				Text:    result.Err.Error(),
+			}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// failureType names the kind of check that failed: call for functions that could
+// not be called, the match mode for mismatching outputs and check otherwise.
+func failureType(result ExampleResult) string {
+	switch {
+	case errors.Is(result.Err, errCallFailed):
+		return "call"
+	case result.Match == "":
+		return "check"
+	default:
+		return result.Match
+	}
+}
+
+// junitSeconds formats a duration as JUnit seconds.
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
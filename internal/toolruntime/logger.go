@@ -0,0 +1,7 @@
+package toolruntime
+
+import "github.com/denkhaus/agentforge/internal/logger"
+
+var (
+	log = logger.WithPackage("toolruntime")
+)
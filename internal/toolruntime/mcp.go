@@ -0,0 +1,165 @@
+package toolruntime
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tmc/langchaingo/tools"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// startMCPServer spawns an mcp-server tool over stdio inside its sandbox and wraps its
+// declared functions.
+func (r *toolRuntime) startMCPServer(ctx context.Context, loaded *loadedTool) error {
+	manifest := loaded.manifest
+	name := manifest.Metadata.Name
+
+	toolSandbox, err := newSandbox(r.log, manifest, loaded.dir, r.opts.CgroupRoot, r.opts.Secrets)
+	if err != nil {
+		return err
+	}
+
+	commandFunc := func(ctx context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+		return toolSandbox.command(ctx, command, args)
+	}
+
+	command, args := resolveCommand(manifest.Spec, loaded.dir)
+	client, err := mcpclient.NewStdioMCPClientWithOptions(command, nil, args, transport.WithCommandFunc(commandFunc))
+	if err != nil {
+		toolSandbox.cleanup()
+		return fmt.Errorf("failed to start tool %s: %w", name, err)
+	}
+	toolSandbox.started()
+	r.drainStderr(name, client)
+
+	startCtx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+	defer cancel()
+
+	serverTools, err := initializeMCPClient(startCtx, client)
+	if err != nil {
+		violation := toolSandbox.violation("initialize")
+		_ = client.Close()
+		toolSandbox.cleanup()
+		if violation != nil {
+			return violation
+		}
+		return fmt.Errorf("failed to initialize tool %s: %w", name, err)
+	}
+
+	functionTools := make([]tools.Tool, 0, len(manifest.Spec.Functions))
+	for _, function := range manifest.Spec.Functions {
+		serverTool, exists := serverTools[function.Name]
+		if !exists {
+			r.log.Warn("Declared function is not served by tool",
+				zap.String("tool", name),
+				zap.String("function", function.Name))
+			continue
+		}
+		functionTools = append(functionTools, &mcpFunctionTool{
+			name:     functionToolName(manifest, function),
+			function: function,
+			client:   client,
+			sandbox:  toolSandbox,
+			timeout:  r.opts.Timeout,
+			schema:   serverToolSchema(serverTool),
+		})
+	}
+
+	loaded.client = client
+	loaded.sandbox = toolSandbox
+	loaded.tools = functionTools
+	return nil
+}
+
+// initializeMCPClient performs the MCP handshake and returns the served tools by name.
+func initializeMCPClient(ctx context.Context, client mcpclient.MCPClient) (map[string]mcp.Tool, error) {
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "agentforge", Version: "1.0.0"}
+
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		return nil, err
+	}
+
+	result, err := client.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+
+	served := make(map[string]mcp.Tool, len(result.Tools))
+	for _, tool := range result.Tools {
+		served[tool.Name] = tool
+	}
+	return served, nil
+}
+
+// drainStderr forwards the stderr of a tool process to the debug log.
+func (r *toolRuntime) drainStderr(name string, client mcpclient.MCPClient) {
+	stdioClient, ok := client.(*mcpclient.Client)
+	if !ok {
+		return
+	}
+	stderr, ok := mcpclient.GetStderr(stdioClient)
+	if !ok {
+		return
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			r.log.Debug("Tool stderr", zap.String("tool", name), zap.String("line", scanner.Text()))
+		}
+	}()
+}
+
+// resolveCommand derives the command line of a tool process from its runtime and entry point.
+// Tools run in a separate working directory, so paths are resolved against the install dir.
+func resolveCommand(spec schema.ToolSpec, dir string) (string, []string) {
+	entryPoint := spec.EntryPoint
+	switch spec.Runtime {
+	case schema.RuntimeGo:
+		if strings.HasSuffix(entryPoint, ".go") || isDir(filepath.Join(dir, entryPoint)) {
+			return "go", append([]string{"-C", dir, "run", entryPoint}, spec.Args...)
+		}
+	case schema.RuntimePython:
+		return "python3", append([]string{ensureAbsolute(entryPoint, dir)}, spec.Args...)
+	case schema.RuntimeNode:
+		return "node", append([]string{ensureAbsolute(entryPoint, dir)}, spec.Args...)
+	case schema.RuntimeJava:
+		return "java", append([]string{"-jar", ensureAbsolute(entryPoint, dir)}, spec.Args...)
+	case schema.RuntimeDocker:
+		return "docker", append([]string{"run", "--rm", "-i", entryPoint}, spec.Args...)
+	}
+
+	if strings.ContainsRune(entryPoint, filepath.Separator) {
+		entryPoint = ensureAbsolute(entryPoint, dir)
+	}
+	return entryPoint, spec.Args
+}
+
+// resolveWorkingDir returns the working directory of a tool process, relative to its install dir.
+func resolveWorkingDir(spec schema.ToolSpec, dir string) string {
+	if spec.WorkingDir == "" {
+		return dir
+	}
+	if filepath.IsAbs(spec.WorkingDir) {
+		return spec.WorkingDir
+	}
+	return filepath.Join(dir, spec.WorkingDir)
+}
+
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
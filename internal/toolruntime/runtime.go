@@ -0,0 +1,208 @@
+// Package toolruntime launches installed Tool manifests and exposes their functions as tools.
+package toolruntime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/tmc/langchaingo/tools"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// SecretLookup resolves the value of a secret declared by a tool.
+type SecretLookup func(name string) (string, bool)
+
+// Options configures the tool runtime.
+type Options struct {
+	// Timeout bounds server startup and single calls
+	Timeout time.Duration
+	// CgroupRoot is a delegated cgroup v2 directory for tool cgroups, detected when empty
+	CgroupRoot string
+	// Secrets resolves declared secrets, defaults to the process environment
+	Secrets SecretLookup
+}
+
+// loadedTool holds the live state of a loaded tool manifest.
+type loadedTool struct {
+	manifest *schema.Tool
+	dir      string
+	client   mcpclient.MCPClient
+	sandbox  *sandbox
+	tools    []tools.Tool
+}
+
+// toolRuntime is a private implementation of types.ToolRuntime interface.
+type toolRuntime struct {
+	log        *zap.Logger
+	opts       Options
+	httpClient *http.Client
+	loaded     map[string]*loadedTool
+	mutex      sync.RWMutex
+}
+
+// NewToolRuntime creates a tool runtime.
+func NewToolRuntime(log *zap.Logger, opts Options) types.ToolRuntime {
+	if opts.Secrets == nil {
+		opts.Secrets = os.LookupEnv
+	}
+	return &toolRuntime{
+		log:        log,
+		opts:       opts,
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		loaded:     make(map[string]*loadedTool),
+	}
+}
+
+// LoadTool parses the manifest installed in dir and starts the tool.
+func (r *toolRuntime) LoadTool(ctx context.Context, dir string) ([]tools.Tool, error) {
+	// Entry points resolve against dir, which must not depend on the process working directory
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tool directory: %w", err)
+	}
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return r.load(ctx, manifest, dir)
+}
+
+// load starts a tool manifest according to its type.
+func (r *toolRuntime) load(ctx context.Context, manifest *schema.Tool, dir string) ([]tools.Tool, error) {
+	name := manifest.Metadata.Name
+
+	r.mutex.RLock()
+	_, exists := r.loaded[name]
+	r.mutex.RUnlock()
+	if exists {
+		return nil, fmt.Errorf("tool %s is already loaded", name)
+	}
+
+	loaded := &loadedTool{manifest: manifest, dir: dir}
+
+	switch manifest.Spec.Type {
+	case schema.ToolTypeMCPServer:
+		if err := r.startMCPServer(ctx, loaded); err != nil {
+			return nil, err
+		}
+	case schema.ToolTypeHTTP, schema.ToolTypeWebhook:
+		loaded.tools = r.newHTTPTools(manifest)
+	default:
+		return nil, fmt.Errorf("tool type %s of %s is not supported by the runtime", manifest.Spec.Type, name)
+	}
+
+	r.mutex.Lock()
+	r.loaded[name] = loaded
+	r.mutex.Unlock()
+
+	r.log.Info("Tool loaded",
+		zap.String("tool", name),
+		zap.String("type", string(manifest.Spec.Type)),
+		zap.Int("functions", len(loaded.tools)))
+
+	result := make([]tools.Tool, len(loaded.tools))
+	copy(result, loaded.tools)
+	return result, nil
+}
+
+// UnloadTool stops a loaded tool.
+func (r *toolRuntime) UnloadTool(name string) error {
+	r.mutex.Lock()
+	loaded, exists := r.loaded[name]
+	delete(r.loaded, name)
+	r.mutex.Unlock()
+
+	if !exists {
+		return fmt.Errorf("tool %s is not loaded", name)
+	}
+
+	if loaded.client != nil {
+		err := loaded.client.Close()
+		loaded.sandbox.cleanup()
+		if err != nil {
+			return fmt.Errorf("failed to stop tool %s: %w", name, err)
+		}
+	}
+
+	r.log.Info("Tool unloaded", zap.String("tool", name))
+	return nil
+}
+
+// GetLoadedTools returns the names of all loaded tool manifests.
+func (r *toolRuntime) GetLoadedTools() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.loaded))
+	for name := range r.loaded {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close unloads all tools.
+func (r *toolRuntime) Close() error {
+	var firstErr error
+	for _, name := range r.GetLoadedTools() {
+		if err := r.UnloadTool(name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Shutdown implements do.Shutdownable so the container stops running tools.
+func (r *toolRuntime) Shutdown() error {
+	return r.Close()
+}
+
+// ReadManifest reads and validates the Tool manifest in dir.
+func ReadManifest(dir string) (*schema.Tool, error) {
+	content, err := os.ReadFile(filepath.Join(dir, schema.ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool manifest: %w", err)
+	}
+
+	component, err := schema.NewComponentParser().ParseComponent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tool manifest: %w", err)
+	}
+
+	manifest, ok := component.(*schema.Tool)
+	if !ok {
+		return nil, fmt.Errorf("manifest in %s is not a Tool", dir)
+	}
+	return manifest, nil
+}
+
+// functionToolName returns the name under which a tool function is registered.
+func functionToolName(manifest *schema.Tool, function schema.ToolFunction) string {
+	return manifest.Metadata.Name + "_" + function.Name
+}
+
+// getLoaded returns the live state of a loaded tool, or nil if it is not loaded.
+func (r *toolRuntime) getLoaded(name string) *loadedTool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.loaded[name]
+}
+
+// functionNames returns the registered names of the tool's functions.
+func (l *loadedTool) functionNames() []string {
+	names := make([]string, 0, len(l.tools))
+	for _, tool := range l.tools {
+		names = append(names, tool.Name())
+	}
+	return names
+}
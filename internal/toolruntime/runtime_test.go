@@ -0,0 +1,273 @@
+package toolruntime
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// testServerEnv makes the test binary act as an MCP server tool.
+const testServerEnv = "FORGE_TEST_MCP_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testServerEnv) == "1" {
+		runTestMCPServer()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runTestMCPServer serves an echo tool over stdio.
+func runTestMCPServer() {
+	s := server.NewMCPServer("echo", "1.0.0", server.WithToolCapabilities(false))
+	s.AddTool(mcp.NewTool("echo", mcp.WithString("text", mcp.Required())),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			text, err := request.RequireString("text")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return mcp.NewToolResultText("echo: " + text), nil
+		})
+	_ = server.ServeStdio(s)
+}
+
+// writeManifest writes a tool manifest into a new directory and returns it.
+func writeManifest(t *testing.T, tool *schema.Tool) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	data, err := schema.NewComponentParser().SerializeComponent(tool)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, schema.ManifestFileName), data, 0644))
+	return dir
+}
+
+// newTestTool creates a valid tool manifest with the given type and entry point.
+func newTestTool(toolType schema.ToolType, runtime schema.ToolRuntime, entryPoint string) *schema.Tool {
+	tool := schema.NewTool("test-tool", "1.0.0")
+	tool.Metadata.Description = "A tool for runtime tests"
+	tool.Metadata.Author = "Test Author"
+	tool.Metadata.License = "MIT"
+	tool.Spec.Type = toolType
+	tool.Spec.Runtime = runtime
+	tool.Spec.EntryPoint = entryPoint
+	return tool
+}
+
+func TestToolRuntime_HTTPTool(t *testing.T) {
+	var received map[string]any
+	var receivedPath, receivedAuth string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.RequestURI()
+		receivedAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer httpServer.Close()
+
+	manifest := newTestTool(schema.ToolTypeHTTP, schema.RuntimeGo, httpServer.URL)
+	manifest.Spec.Configuration.Environment = map[string]string{"API_TOKEN": "secret"}
+	manifest.Spec.Functions = []schema.ToolFunction{{
+		Name:        "update_item",
+		Description: "Updates an item",
+		Parameters: []schema.ToolParameter{
+			{Name: "id", Type: "string", Description: "Item id", Required: true, In: "path"},
+			{Name: "verbose", Type: "boolean", Description: "Verbose output", In: "query"},
+			{Name: "title", Type: "string", Description: "New title", Required: true},
+			{Name: "priority", Type: "number", Description: "Priority", Default: float64(3)},
+		},
+		Request: &schema.ToolRequest{
+			Method:  http.MethodPut,
+			Path:    "/items/{id}",
+			Headers: map[string]string{"Authorization": "Bearer ${API_TOKEN}"},
+		},
+	}}
+
+	runtime := NewToolRuntime(zaptest.NewLogger(t), Options{Timeout: 5 * time.Second})
+	defer runtime.Close()
+
+	loaded, err := runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "test-tool_update_item", loaded[0].Name())
+	assert.Equal(t, []string{"test-tool"}, runtime.GetLoadedTools())
+
+	result, err := loaded[0].Call(context.Background(), `{"id":"a b","verbose":true,"title":"New"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, result)
+	assert.Equal(t, "/items/a%20b?verbose=true", receivedPath)
+	assert.Equal(t, "Bearer secret", receivedAuth)
+	assert.Equal(t, map[string]any{"title": "New", "priority": float64(3)}, received)
+
+	_, err = loaded[0].Call(context.Background(), `{"id":"1"}`)
+	assert.ErrorContains(t, err, "missing required parameter title")
+
+	_, err = runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	assert.ErrorContains(t, err, "already loaded")
+
+	require.NoError(t, runtime.UnloadTool("test-tool"))
+	assert.Empty(t, runtime.GetLoadedTools())
+}
+
+func TestToolRuntime_HTTPToolSecretsAndSchema(t *testing.T) {
+	var receivedQuery string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer httpServer.Close()
+
+	ownerSchema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"name": map[string]any{"type": "string"}},
+	}
+	manifest := newTestTool(schema.ToolTypeHTTP, schema.RuntimeGo, httpServer.URL)
+	manifest.Spec.Configuration.Secrets = []string{"PETS_API_KEY"}
+	manifest.Spec.Functions = []schema.ToolFunction{{
+		Name:        "find_pets",
+		Description: "Finds pets",
+		Parameters: []schema.ToolParameter{
+			{Name: "limit", Type: "number", Description: "Max results", In: "query"},
+			{Name: "owner", Type: "object", Description: "Owner filter", In: "body", Schema: ownerSchema},
+		},
+		Request: &schema.ToolRequest{
+			Method: http.MethodGet,
+			Path:   "/pets",
+			Query:  map[string]string{"api_key": "${PETS_API_KEY}"},
+		},
+	}}
+
+	secrets := func(name string) (string, bool) {
+		return map[string]string{"PETS_API_KEY": "k3y"}[name], name == "PETS_API_KEY"
+	}
+	runtime := NewToolRuntime(zaptest.NewLogger(t), Options{Timeout: 5 * time.Second, Secrets: secrets})
+	defer runtime.Close()
+
+	loaded, err := runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+
+	inputSchema := types.ToolSchema(loaded[0])
+	require.NotNil(t, inputSchema)
+	properties := inputSchema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "number", "description": "Max results"}, properties["limit"])
+	assert.Equal(t, "Owner filter", properties["owner"].(map[string]any)["description"])
+	assert.Equal(t, ownerSchema["properties"], properties["owner"].(map[string]any)["properties"])
+
+	_, err = loaded[0].Call(context.Background(), `{"limit":5}`)
+	require.NoError(t, err)
+	assert.Equal(t, "api_key=k3y&limit=5", receivedQuery)
+}
+
+func TestToolRuntime_WebhookTool(t *testing.T) {
+	var received map[string]any
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("upstream down"))
+	}))
+	defer httpServer.Close()
+
+	manifest := newTestTool(schema.ToolTypeWebhook, schema.RuntimeGo, httpServer.URL+"/hook")
+	manifest.Spec.Functions = []schema.ToolFunction{{
+		Name:        "notify",
+		Description: "Sends a notification",
+		Parameters: []schema.ToolParameter{
+			{Name: "message", Type: "string", Description: "Message", Required: true},
+		},
+	}}
+
+	runtime := NewToolRuntime(zaptest.NewLogger(t), Options{Timeout: 5 * time.Second})
+	defer runtime.Close()
+
+	loaded, err := runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	require.NoError(t, err)
+
+	_, err = loaded[0].Call(context.Background(), "hello")
+	assert.ErrorContains(t, err, "returned status 502: upstream down")
+	assert.Equal(t, map[string]any{
+		"function":  "notify",
+		"arguments": map[string]any{"message": "hello"},
+	}, received)
+}
+
+func TestToolRuntime_MCPServerTool(t *testing.T) {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	manifest := newTestTool(schema.ToolTypeMCPServer, schema.RuntimeRust, executable)
+	manifest.Spec.Configuration.Environment = map[string]string{testServerEnv: "1"}
+	manifest.Spec.Functions = []schema.ToolFunction{
+		{
+			Name:        "echo",
+			Description: "Echoes the text",
+			Parameters: []schema.ToolParameter{
+				{Name: "text", Type: "string", Description: "Text to echo", Required: true},
+			},
+		},
+		{Name: "missing", Description: "Not served by the server"},
+	}
+
+	runtime := NewToolRuntime(zaptest.NewLogger(t), Options{Timeout: 10 * time.Second})
+	defer runtime.Close()
+
+	loaded, err := runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+
+	result, err := loaded[0].Call(context.Background(), `{"text":"hi"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hi", result)
+
+	assert.NoError(t, runtime.Close())
+	assert.Empty(t, runtime.GetLoadedTools())
+}
+
+func TestToolRuntime_UnsupportedType(t *testing.T) {
+	manifest := newTestTool(schema.ToolTypeGRPC, schema.RuntimeGo, "localhost:9000")
+	manifest.Spec.Functions = []schema.ToolFunction{{Name: "call", Description: "Calls the service"}}
+
+	runtime := NewToolRuntime(zaptest.NewLogger(t), Options{Timeout: time.Second})
+	_, err := runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestParseArguments(t *testing.T) {
+	function := schema.ToolFunction{
+		Name: "search",
+		Parameters: []schema.ToolParameter{
+			{Name: "mode", Type: "string", Enum: []string{"fast", "exact"}},
+			{Name: "limit", Type: "number"},
+		},
+	}
+
+	args, err := parseArguments(function, `{"mode":"fast","limit":5}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"mode": "fast", "limit": float64(5)}, args)
+
+	_, err = parseArguments(function, `{"mode":"slow"}`)
+	assert.ErrorContains(t, err, "must be one of")
+
+	_, err = parseArguments(function, `{"limit":"5"}`)
+	assert.ErrorContains(t, err, "must be of type number")
+
+	_, err = parseArguments(function, "not json")
+	assert.ErrorContains(t, err, "expected a JSON object")
+}
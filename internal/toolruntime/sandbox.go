@@ -0,0 +1,356 @@
+package toolruntime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+// sandboxBaseEnv lists the process environment variables a sandboxed tool inherits
+// in addition to its declared environment and secrets.
+var sandboxBaseEnv = []string{"PATH", "LANG", "LC_ALL", "TZ"}
+
+// goBaseEnv lists the Go toolchain variables `go run` tools need to reuse the caches.
+var goBaseEnv = []string{"GOPATH", "GOCACHE", "GOMODCACHE", "GOPROXY", "GOFLAGS", "GOTOOLCHAIN"}
+
+// dockerBaseEnv lists the extra variables the docker CLI needs to reach its daemon.
+var dockerBaseEnv = []string{"HOME", "DOCKER_HOST", "DOCKER_CONFIG", "DOCKER_CONTEXT", "DOCKER_CERT_PATH", "DOCKER_TLS_VERIFY"}
+
+// resourceLimits holds the parsed resource limits of a tool.
+type resourceLimits struct {
+	cpuMillis   int64
+	memoryBytes int64
+	diskBytes   int64
+}
+
+// sandbox confines a locally spawned tool process. Limits are enforced with cgroup v2
+// where a usable hierarchy is available and with rlimits otherwise; see sandbox_linux.go.
+type sandbox struct {
+	log         *zap.Logger
+	tool        string
+	runtime     schema.ToolRuntime
+	security    schema.ToolSecurity
+	limits      resourceLimits
+	workDir     string
+	ownsWorkDir bool
+	env         []string
+	cgroupRoot  string
+	cgroupDir   string
+	cgroupFD    int
+	cmd         *exec.Cmd
+}
+
+// newSandbox prepares the sandbox of a tool: it parses the limits, resolves the secrets
+// and creates a separate working directory unless the manifest declares one.
+func newSandbox(
+	log *zap.Logger,
+	manifest *schema.Tool,
+	dir string,
+	cgroupRoot string,
+	secrets SecretLookup,
+) (*sandbox, error) {
+	spec := manifest.Spec
+	s := &sandbox{
+		log:        log,
+		tool:       manifest.Metadata.Name,
+		runtime:    spec.Runtime,
+		cgroupRoot: cgroupRoot,
+	}
+	if spec.Security != nil {
+		s.security = *spec.Security
+	}
+
+	limits, err := parseResourceLimits(spec.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource limits of tool %s: %w", s.tool, err)
+	}
+	s.limits = limits
+
+	env, err := sandboxEnv(spec, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare environment of tool %s: %w", s.tool, err)
+	}
+
+	if spec.WorkingDir != "" {
+		s.workDir = resolveWorkingDir(spec, dir)
+	} else {
+		workDir, err := os.MkdirTemp("", "forge-tool-"+s.tool+"-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create working directory of tool %s: %w", s.tool, err)
+		}
+		s.workDir = workDir
+		s.ownsWorkDir = true
+	}
+
+	if s.runtime != schema.RuntimeDocker {
+		env = append(env, "HOME="+s.workDir)
+	}
+	s.env = env
+
+	return s, nil
+}
+
+// command creates the confined command of the tool process.
+func (s *sandbox) command(ctx context.Context, command string, args []string) (*exec.Cmd, error) {
+	if s.runtime == schema.RuntimeDocker {
+		args = s.dockerArgs(args)
+	}
+
+	// #nosec G204 -- command comes from the installed tool manifest
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = s.env
+	cmd.Dir = s.workDir
+
+	if s.runtime != schema.RuntimeDocker {
+		if err := s.confine(cmd); err != nil {
+			return nil, err
+		}
+	}
+
+	s.cmd = cmd
+	return cmd, nil
+}
+
+// started releases the resources only needed to start the tool process.
+func (s *sandbox) started() {
+	if s.runtime == schema.RuntimeDocker || s.cmd == nil || s.cmd.Process == nil {
+		return
+	}
+	s.afterStart(s.cmd.Process.Pid)
+}
+
+// cleanup removes the cgroup and the working directory created for the tool.
+func (s *sandbox) cleanup() {
+	s.removeCgroup()
+	if s.ownsWorkDir {
+		if err := os.RemoveAll(s.workDir); err != nil {
+			s.log.Warn("Failed to remove tool working directory",
+				zap.String("tool", s.tool),
+				zap.Error(err))
+		}
+	}
+}
+
+// dockerArgs inserts the container flags matching the resources and security context
+// in front of the image of a `docker run` command line.
+func (s *sandbox) dockerArgs(args []string) []string {
+	flags := make([]string, 0)
+	if s.limits.memoryBytes > 0 {
+		flags = append(flags, "--memory", strconv.FormatInt(s.limits.memoryBytes, 10))
+	}
+	if s.limits.cpuMillis > 0 {
+		flags = append(flags, "--cpus", strconv.FormatFloat(float64(s.limits.cpuMillis)/1000, 'f', -1, 64))
+	}
+	if s.limits.diskBytes > 0 {
+		flags = append(flags, "--ulimit", fmt.Sprintf("fsize=%d", s.limits.diskBytes))
+	}
+	if s.security.NetworkIsolation {
+		flags = append(flags, "--network", "none")
+	}
+	if s.security.ReadOnlyRootFS {
+		flags = append(flags, "--read-only")
+	}
+	if s.security.RunAsUser != nil {
+		user := strconv.FormatInt(*s.security.RunAsUser, 10)
+		if s.security.RunAsGroup != nil {
+			user += ":" + strconv.FormatInt(*s.security.RunAsGroup, 10)
+		}
+		flags = append(flags, "--user", user)
+	}
+	for _, capability := range s.security.DroppedCapabilities {
+		flags = append(flags, "--cap-drop", capability)
+	}
+	for _, capability := range s.security.AllowedCapabilities {
+		flags = append(flags, "--cap-add", capability)
+	}
+	for _, entry := range s.env {
+		name, _, _ := strings.Cut(entry, "=")
+		if !slices.Contains(dockerBaseEnv, name) && !slices.Contains(sandboxBaseEnv, name) {
+			flags = append(flags, "-e", name)
+		}
+	}
+
+	// args starts with "run --rm -i", see resolveCommand
+	prefix := min(3, len(args))
+	result := append([]string{}, args[:prefix]...)
+	result = append(result, flags...)
+	return append(result, args[prefix:]...)
+}
+
+// unenforced rejects a security setting the sandbox cannot enforce, so the tool
+// never runs without it.
+func (s *sandbox) unenforced(detail string) error {
+	return &types.ToolViolationError{
+		Tool:     s.tool,
+		Function: "start",
+		Limit:    "security",
+		Detail:   detail,
+	}
+}
+
+// violation reports whether the tool process was stopped for exceeding a limit.
+func (s *sandbox) violation(function string) *types.ToolViolationError {
+	if s.cmd == nil || s.cmd.Process == nil || s.runtime == schema.RuntimeDocker {
+		return nil
+	}
+
+	limit, detail := s.exitViolation(s.cmd.Process.Pid)
+	if limit == "" {
+		return nil
+	}
+	return &types.ToolViolationError{
+		Tool:     s.tool,
+		Function: function,
+		Limit:    limit,
+		Detail:   detail,
+	}
+}
+
+// sandboxEnv builds the scrubbed environment of a tool: a few base variables,
+// the declared environment and the declared secrets.
+func sandboxEnv(spec schema.ToolSpec, secrets SecretLookup) ([]string, error) {
+	values := make(map[string]string)
+
+	baseEnv := append([]string{}, sandboxBaseEnv...)
+	switch spec.Runtime {
+	case schema.RuntimeDocker:
+		baseEnv = append(baseEnv, dockerBaseEnv...)
+	case schema.RuntimeGo:
+		baseEnv = append(baseEnv, goBaseEnv...)
+		if goEnv, err := goToolchainEnv(); err == nil {
+			for key, value := range goEnv {
+				values[key] = value
+			}
+		}
+	}
+	for _, name := range baseEnv {
+		if value, exists := os.LookupEnv(name); exists {
+			values[name] = value
+		}
+	}
+
+	for key, value := range spec.Configuration.Environment {
+		values[key] = value
+	}
+
+	for _, name := range spec.Configuration.Secrets {
+		value, exists := secrets(name)
+		if !exists {
+			return nil, fmt.Errorf("secret %s is not set", name)
+		}
+		values[name] = value
+	}
+
+	env := make([]string, 0, len(values))
+	for key, value := range values {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env, nil
+}
+
+// goToolchainEnv returns the default Go cache locations of the current user, which would
+// otherwise move into the tool's separate home directory.
+func goToolchainEnv() (map[string]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"GOPATH":  filepath.Join(homeDir, "go"),
+		"GOCACHE": filepath.Join(cacheDir, "go-build"),
+	}, nil
+}
+
+// parseResourceLimits parses the resource limits of a tool manifest.
+func parseResourceLimits(resources *schema.ToolResources) (resourceLimits, error) {
+	limits := resourceLimits{}
+	if resources == nil || resources.Limits == nil {
+		return limits, nil
+	}
+
+	var err error
+	if limits.cpuMillis, err = parseCPU(resources.Limits.CPU); err != nil {
+		return limits, err
+	}
+	if limits.memoryBytes, err = parseBytes(resources.Limits.Memory); err != nil {
+		return limits, err
+	}
+	if limits.diskBytes, err = parseBytes(resources.Limits.Disk); err != nil {
+		return limits, err
+	}
+	return limits, nil
+}
+
+// parseCPU parses a Kubernetes style CPU quantity ("500m", "1.5") into millicores.
+func parseCPU(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if millis, found := strings.CutSuffix(value, "m"); found {
+		parsed, err := strconv.ParseInt(millis, 10, 64)
+		if err != nil || parsed <= 0 {
+			return 0, fmt.Errorf("invalid cpu limit %q", value)
+		}
+		return parsed, nil
+	}
+
+	cores, err := strconv.ParseFloat(value, 64)
+	if err != nil || cores <= 0 {
+		return 0, fmt.Errorf("invalid cpu limit %q", value)
+	}
+	return int64(cores * 1000), nil
+}
+
+// byteUnits maps Kubernetes style quantity suffixes to their multipliers.
+var byteUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+	{"K", 1000}, {"M", 1000 * 1000}, {"G", 1000 * 1000 * 1000}, {"T", 1000 * 1000 * 1000 * 1000},
+}
+
+// parseBytes parses a Kubernetes style byte quantity ("512Mi", "1G", "1024") into bytes.
+func parseBytes(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	number, multiplier := value, int64(1)
+	for _, unit := range byteUnits {
+		if trimmed, found := strings.CutSuffix(value, unit.suffix); found {
+			number, multiplier = trimmed, unit.multiplier
+			break
+		}
+	}
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(parsed * float64(multiplier)), nil
+}
+
+// ensureAbsolute resolves a relative path against dir.
+func ensureAbsolute(path, dir string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
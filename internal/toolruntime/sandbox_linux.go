@@ -0,0 +1,348 @@
+//go:build linux
+
+package toolruntime
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// cgroupMountPoint is where the cgroup v2 unified hierarchy is expected.
+	cgroupMountPoint = "/sys/fs/cgroup"
+	// cgroupCPUPeriod is the cpu.max period in microseconds.
+	cgroupCPUPeriod = 100000
+	// exitPollAttempts and exitPollInterval bound the wait for a failed tool process to exit.
+	exitPollAttempts = 10
+	exitPollInterval = 10 * time.Millisecond
+	// sandboxExecEnv passes the rlimits to the re-executed forge binary, see execSandboxed.
+	sandboxExecEnv = "FORGE_SANDBOX_RLIMITS"
+)
+
+// rlimitNames maps the rlimit names used in sandboxExecEnv to their resources.
+var rlimitNames = map[string]int{
+	"fsize": unix.RLIMIT_FSIZE,
+	"data":  unix.RLIMIT_DATA,
+}
+
+func init() {
+	if spec, ok := os.LookupEnv(sandboxExecEnv); ok && len(os.Args) > 1 {
+		execSandboxed(spec)
+	}
+}
+
+// execSandboxed runs in the re-executed forge binary: it applies the rlimits and
+// replaces itself with the tool command, so the tool never runs without them.
+func execSandboxed(spec string) {
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "forge sandbox: %v\n", err)
+		os.Exit(127)
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, value, _ := strings.Cut(entry, "=")
+		resource, exists := rlimitNames[name]
+		if !exists {
+			fail(fmt.Errorf("unknown rlimit %q", name))
+		}
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			fail(fmt.Errorf("invalid rlimit %q: %w", entry, err))
+		}
+		if err := unix.Setrlimit(resource, &unix.Rlimit{Cur: parsed, Max: parsed}); err != nil {
+			fail(fmt.Errorf("failed to set rlimit %s: %w", name, err))
+		}
+	}
+
+	if err := os.Unsetenv(sandboxExecEnv); err != nil {
+		fail(err)
+	}
+	// #nosec G204 -- the path was resolved by the parent from the tool manifest
+	fail(syscall.Exec(os.Args[1], os.Args[1:], os.Environ()))
+}
+
+// capabilities maps capability names to their numbers.
+var capabilities = map[string]uintptr{
+	"CHOWN":            unix.CAP_CHOWN,
+	"DAC_OVERRIDE":     unix.CAP_DAC_OVERRIDE,
+	"FOWNER":           unix.CAP_FOWNER,
+	"KILL":             unix.CAP_KILL,
+	"SETGID":           unix.CAP_SETGID,
+	"SETUID":           unix.CAP_SETUID,
+	"NET_BIND_SERVICE": unix.CAP_NET_BIND_SERVICE,
+	"NET_ADMIN":        unix.CAP_NET_ADMIN,
+	"NET_RAW":          unix.CAP_NET_RAW,
+	"IPC_LOCK":         unix.CAP_IPC_LOCK,
+	"SYS_PTRACE":       unix.CAP_SYS_PTRACE,
+	"SYS_ADMIN":        unix.CAP_SYS_ADMIN,
+	"SYS_NICE":         unix.CAP_SYS_NICE,
+	"SYS_RESOURCE":     unix.CAP_SYS_RESOURCE,
+	"SYS_TIME":         unix.CAP_SYS_TIME,
+}
+
+// confine sets up the process attributes: user, capabilities, network namespace and cgroup.
+func (s *sandbox) confine(cmd *exec.Cmd) error {
+	attr := &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+
+	if s.security.NetworkIsolation {
+		// An unprivileged user namespace is required to create the network namespace.
+		containerID := os.Getuid()
+		if s.security.RunAsUser != nil {
+			containerID = int(*s.security.RunAsUser)
+		}
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: containerID, HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+		attr.GidMappingsEnableSetgroups = false
+	} else if s.security.RunAsUser != nil {
+		attr.Credential = &syscall.Credential{Uid: uint32(*s.security.RunAsUser), Gid: uint32(os.Getgid())}
+		if s.security.RunAsGroup != nil {
+			attr.Credential.Gid = uint32(*s.security.RunAsGroup)
+		}
+	}
+
+	for _, name := range s.security.AllowedCapabilities {
+		capability, exists := capabilities[strings.TrimPrefix(strings.ToUpper(name), "CAP_")]
+		if !exists {
+			return fmt.Errorf("unknown capability %s for tool %s", name, s.tool)
+		}
+		attr.AmbientCaps = append(attr.AmbientCaps, capability)
+	}
+
+	if s.security.ReadOnlyRootFS {
+		return s.unenforced("readOnlyRootFS is only enforced for the docker runtime")
+	}
+	if len(s.security.DroppedCapabilities) > 0 {
+		return s.unenforced("droppedCapabilities are only enforced for the docker runtime")
+	}
+
+	if fd, ok := s.createCgroup(); ok {
+		attr.UseCgroupFD = true
+		attr.CgroupFD = fd
+		s.cgroupFD = fd
+	}
+
+	cmd.SysProcAttr = attr
+	return s.applyRlimits(cmd)
+}
+
+// applyRlimits re-executes the forge binary in front of the tool command when rlimits are
+// needed, so they are set before the tool starts. The disk limit is always an rlimit,
+// memory falls back to RLIMIT_DATA when no cgroup enforces it.
+func (s *sandbox) applyRlimits(cmd *exec.Cmd) error {
+	var rlimits []string
+	if s.limits.diskBytes > 0 {
+		rlimits = append(rlimits, fmt.Sprintf("fsize=%d", s.limits.diskBytes))
+	}
+	if s.limits.memoryBytes > 0 && s.cgroupDir == "" {
+		rlimits = append(rlimits, fmt.Sprintf("data=%d", s.limits.memoryBytes))
+	}
+	// A command that was not found keeps its lookup error for Start.
+	if len(rlimits) == 0 || cmd.Err != nil {
+		return nil
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the sandbox helper of tool %s: %w", s.tool, err)
+	}
+	cmd.Args = append([]string{self, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = self
+	cmd.Env = append(slices.Clone(cmd.Env), sandboxExecEnv+"="+strings.Join(rlimits, ","))
+	return nil
+}
+
+// createCgroup creates a cgroup with the tool's cpu and memory limits and returns an open
+// descriptor of it. It returns false when no limits are set or cgroup v2 is not usable.
+func (s *sandbox) createCgroup() (int, bool) {
+	if s.limits.cpuMillis == 0 && s.limits.memoryBytes == 0 {
+		return 0, false
+	}
+
+	root := s.cgroupRoot
+	if root == "" {
+		root = ownCgroup()
+	}
+	if root == "" || !hasControllers(root, s.limits) {
+		s.log.Info("cgroup v2 not usable, falling back to rlimits", zap.String("tool", s.tool))
+		return 0, false
+	}
+
+	dir, err := os.MkdirTemp(root, "forge-tool-"+s.tool+"-")
+	if err != nil {
+		s.log.Warn("Failed to create tool cgroup", zap.String("tool", s.tool), zap.Error(err))
+		return 0, false
+	}
+	s.cgroupDir = dir
+
+	settings := map[string]string{}
+	if s.limits.memoryBytes > 0 {
+		settings["memory.max"] = strconv.FormatInt(s.limits.memoryBytes, 10)
+		settings["memory.swap.max"] = "0"
+	}
+	if s.limits.cpuMillis > 0 {
+		settings["cpu.max"] = fmt.Sprintf("%d %d", s.limits.cpuMillis*cgroupCPUPeriod/1000, cgroupCPUPeriod)
+	}
+	for file, value := range settings {
+		path := filepath.Join(dir, file)
+		if _, err := os.Stat(path); os.IsNotExist(err) && file == "memory.swap.max" {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+			s.log.Warn("Failed to set tool cgroup limit",
+				zap.String("tool", s.tool),
+				zap.String("file", file),
+				zap.Error(err))
+			s.removeCgroup()
+			return 0, false
+		}
+	}
+
+	fd, err := unix.Open(dir, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		s.removeCgroup()
+		return 0, false
+	}
+	return fd, true
+}
+
+// removeCgroup removes the tool cgroup, which the kernel allows once it is empty.
+func (s *sandbox) removeCgroup() {
+	s.closeCgroupFD()
+	if s.cgroupDir == "" {
+		return
+	}
+	if err := os.Remove(s.cgroupDir); err != nil && !os.IsNotExist(err) {
+		s.log.Warn("Failed to remove tool cgroup", zap.String("tool", s.tool), zap.Error(err))
+	}
+	s.cgroupDir = ""
+}
+
+// closeCgroupFD closes the cgroup descriptor, which is only needed to start the process.
+func (s *sandbox) closeCgroupFD() {
+	if s.cgroupFD > 0 {
+		_ = unix.Close(s.cgroupFD)
+		s.cgroupFD = 0
+	}
+}
+
+// afterStart releases the cgroup descriptor, which is only needed to start the process.
+func (s *sandbox) afterStart(pid int) {
+	s.closeCgroupFD()
+	if s.limits.cpuMillis > 0 && s.cgroupDir == "" {
+		s.log.Warn("CPU limit requires cgroup v2 and is not enforced", zap.String("tool", s.tool))
+	}
+}
+
+// exitViolation inspects a terminated, not yet reaped tool process and maps its
+// termination signal to the exceeded limit.
+func (s *sandbox) exitViolation(pid int) (string, string) {
+	// A failed call may be noticed just before the exiting process turns into a zombie.
+	status, exited := exitStatus(pid)
+	for attempt := 0; !exited && attempt < exitPollAttempts; attempt++ {
+		time.Sleep(exitPollInterval)
+		status, exited = exitStatus(pid)
+	}
+	if !exited || !status.Signaled() {
+		return "", ""
+	}
+
+	switch status.Signal() {
+	case syscall.SIGXFSZ:
+		return "disk", fmt.Sprintf("file size limit of %d bytes exceeded", s.limits.diskBytes)
+	case syscall.SIGXCPU:
+		return "cpu", "cpu time limit exceeded"
+	case syscall.SIGKILL:
+		if s.cgroupDir != "" && oomKilled(s.cgroupDir) {
+			return "memory", fmt.Sprintf("memory limit of %d bytes exceeded", s.limits.memoryBytes)
+		}
+	case syscall.SIGSEGV, syscall.SIGABRT:
+		if s.limits.memoryBytes > 0 && s.cgroupDir == "" {
+			return "memory", fmt.Sprintf("process crashed with %s, likely exceeding the memory limit of %d bytes",
+				status.Signal(), s.limits.memoryBytes)
+		}
+	}
+	return "", ""
+}
+
+// exitStatus reads the wait status of a zombie process from /proc without reaping it.
+func exitStatus(pid int) (syscall.WaitStatus, bool) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, false
+	}
+
+	// Fields after the command name, which is enclosed in parentheses.
+	end := strings.LastIndexByte(string(data), ')')
+	if end < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(string(data)[end+1:])
+	// fields[0] is the state (field 3), the exit code is field 52.
+	if len(fields) < 50 || fields[0] != "Z" {
+		return 0, false
+	}
+
+	code, err := strconv.Atoi(fields[49])
+	if err != nil {
+		return 0, false
+	}
+	return syscall.WaitStatus(code), true
+}
+
+// oomKilled reports whether the OOM killer has killed a process of the cgroup.
+func oomKilled(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "memory.events"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if count, found := strings.CutPrefix(line, "oom_kill "); found {
+			return count != "0"
+		}
+	}
+	return false
+}
+
+// ownCgroup returns the cgroup v2 directory of the current process.
+func ownCgroup() string {
+	if _, err := os.Stat(filepath.Join(cgroupMountPoint, "cgroup.controllers")); err != nil {
+		return ""
+	}
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, found := strings.CutPrefix(line, "0::"); found {
+			return filepath.Join(cgroupMountPoint, path)
+		}
+	}
+	return ""
+}
+
+// hasControllers reports whether child cgroups of root get the controllers the limits need.
+func hasControllers(root string, limits resourceLimits) bool {
+	data, err := os.ReadFile(filepath.Join(root, "cgroup.subtree_control"))
+	if err != nil {
+		return false
+	}
+	enabled := strings.Fields(string(data))
+	if limits.memoryBytes > 0 && !slices.Contains(enabled, "memory") {
+		return false
+	}
+	if limits.cpuMillis > 0 && !slices.Contains(enabled, "cpu") {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+//go:build linux
+
+package toolruntime
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
+)
+
+func TestSandbox_ReportsViolation(t *testing.T) {
+	manifest := newTestTool(schema.ToolTypeMCPServer, schema.RuntimeRust, "/bin/sh")
+	manifest.Spec.Args = []string{"-c", "kill -XFSZ $$"}
+	manifest.Spec.Resources = &schema.ToolResources{Limits: &schema.ToolResourceSpec{Disk: "1Mi"}}
+	manifest.Spec.Functions = []schema.ToolFunction{{Name: "write", Description: "Writes a file"}}
+
+	runtime := NewToolRuntime(zaptest.NewLogger(t), Options{Timeout: 5 * time.Second})
+	_, err := runtime.LoadTool(context.Background(), writeManifest(t, manifest))
+
+	var violation *types.ToolViolationError
+	require.True(t, errors.As(err, &violation), "expected violation, got %v", err)
+	assert.Equal(t, "test-tool", violation.Tool)
+	assert.Equal(t, "disk", violation.Limit)
+}
+
+func TestSandbox_SeparateWorkingDirectory(t *testing.T) {
+	s, err := newSandbox(zaptest.NewLogger(t), newTestTool(schema.ToolTypeMCPServer, schema.RuntimeRust, "tool"),
+		t.TempDir(), "", os.LookupEnv)
+	require.NoError(t, err)
+
+	cmd, err := s.command(context.Background(), "/bin/sh", []string{"-c", "pwd; echo $HOME"})
+	require.NoError(t, err)
+	output, err := cmd.Output()
+	require.NoError(t, err)
+
+	lines := strings.Fields(string(output))
+	require.Len(t, lines, 2)
+	assert.Equal(t, s.workDir, lines[0])
+	assert.Equal(t, s.workDir, lines[1])
+
+	s.cleanup()
+	_, err = os.Stat(s.workDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSandbox_RlimitsSetBeforeExec(t *testing.T) {
+	manifest := newTestTool(schema.ToolTypeMCPServer, schema.RuntimeRust, "tool")
+	manifest.Spec.Resources = &schema.ToolResources{Limits: &schema.ToolResourceSpec{Disk: "1Mi"}}
+	s, err := newSandbox(zaptest.NewLogger(t), manifest, t.TempDir(), "", os.LookupEnv)
+	require.NoError(t, err)
+	t.Cleanup(s.cleanup)
+
+	cmd, err := s.command(context.Background(), "/bin/sh", []string{"-c", "grep 'Max file size' /proc/$$/limits; echo ${" + sandboxExecEnv + ":-unset}"})
+	require.NoError(t, err)
+	output, err := cmd.Output()
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"Max", "file", "size", "1048576", "1048576", "bytes"}, strings.Fields(lines[0]))
+	assert.Equal(t, "unset", lines[1])
+}
+
+func TestSandbox_RejectsUnenforceableSecurity(t *testing.T) {
+	tests := []struct {
+		name     string
+		security schema.ToolSecurity
+	}{
+		{name: "read-only root filesystem", security: schema.ToolSecurity{ReadOnlyRootFS: true}},
+		{name: "dropped capabilities", security: schema.ToolSecurity{DroppedCapabilities: []string{"NET_RAW"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := newTestTool(schema.ToolTypeMCPServer, schema.RuntimeRust, "tool")
+			manifest.Spec.Security = &tt.security
+			s, err := newSandbox(zaptest.NewLogger(t), manifest, t.TempDir(), "", os.LookupEnv)
+			require.NoError(t, err)
+			t.Cleanup(s.cleanup)
+
+			_, err = s.command(context.Background(), "/bin/true", nil)
+			var violation *types.ToolViolationError
+			require.True(t, errors.As(err, &violation), "expected violation, got %v", err)
+			assert.Equal(t, "security", violation.Limit)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !linux
+
+package toolruntime
+
+import (
+	"os/exec"
+
+	"go.uber.org/zap"
+)
+
+// confine rejects security settings on platforms without Linux sandboxing primitives
+// and only logs that resource limits are not enforced.
+func (s *sandbox) confine(cmd *exec.Cmd) error {
+	security := s.security
+	if security.NetworkIsolation || security.RunAsUser != nil || security.RunAsGroup != nil ||
+		security.ReadOnlyRootFS || len(security.AllowedCapabilities) > 0 || len(security.DroppedCapabilities) > 0 {
+		return s.unenforced("security settings are only enforced on Linux and for the docker runtime")
+	}
+	if s.limits != (resourceLimits{}) {
+		s.log.Warn("Tool sandboxing is only supported on Linux, limits are not enforced",
+			zap.String("tool", s.tool))
+	}
+	return nil
+}
+
+// removeCgroup is a no-op without cgroups.
+func (s *sandbox) removeCgroup() {}
+
+// afterStart is a no-op on platforms without prlimit.
+func (s *sandbox) afterStart(pid int) {}
+
+// exitViolation cannot inspect exit states without /proc.
+func (s *sandbox) exitViolation(pid int) (string, string) {
+	return "", ""
+}
@@ -0,0 +1,80 @@
+package toolruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+func TestParseResourceLimits(t *testing.T) {
+	limits, err := parseResourceLimits(&schema.ToolResources{
+		Limits: &schema.ToolResourceSpec{CPU: "500m", Memory: "256Mi", Disk: "1G"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, resourceLimits{cpuMillis: 500, memoryBytes: 256 << 20, diskBytes: 1000 * 1000 * 1000}, limits)
+
+	limits, err = parseResourceLimits(&schema.ToolResources{Limits: &schema.ToolResourceSpec{CPU: "1.5"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), limits.cpuMillis)
+
+	_, err = parseResourceLimits(&schema.ToolResources{Limits: &schema.ToolResourceSpec{Memory: "lots"}})
+	assert.ErrorContains(t, err, "invalid size")
+
+	_, err = parseResourceLimits(&schema.ToolResources{Limits: &schema.ToolResourceSpec{CPU: "-1"}})
+	assert.ErrorContains(t, err, "invalid cpu limit")
+}
+
+func TestSandboxEnv(t *testing.T) {
+	t.Setenv("FORGE_TEST_LEAK", "1")
+
+	spec := schema.ToolSpec{
+		Runtime: schema.RuntimePython,
+		Configuration: schema.ToolConfiguration{
+			Environment: map[string]string{"MODE": "test"},
+			Secrets:     []string{"API_KEY"},
+		},
+	}
+	secrets := func(name string) (string, bool) {
+		value, exists := map[string]string{"API_KEY": "s3cret"}[name]
+		return value, exists
+	}
+
+	env, err := sandboxEnv(spec, secrets)
+	require.NoError(t, err)
+	assert.Contains(t, env, "MODE=test")
+	assert.Contains(t, env, "API_KEY=s3cret")
+	assert.NotContains(t, env, "FORGE_TEST_LEAK=1")
+
+	spec.Configuration.Secrets = []string{"MISSING"}
+	_, err = sandboxEnv(spec, secrets)
+	assert.ErrorContains(t, err, "secret MISSING is not set")
+}
+
+func TestSandbox_DockerArgs(t *testing.T) {
+	user := int64(1000)
+	manifest := newTestTool(schema.ToolTypeMCPServer, schema.RuntimeDocker, "example/tool:1.0")
+	manifest.Spec.Configuration.Environment = map[string]string{"MODE": "test"}
+	manifest.Spec.Resources = &schema.ToolResources{Limits: &schema.ToolResourceSpec{CPU: "250m", Memory: "64Mi"}}
+	manifest.Spec.Security = &schema.ToolSecurity{
+		RunAsUser:           &user,
+		ReadOnlyRootFS:      true,
+		NetworkIsolation:    true,
+		DroppedCapabilities: []string{"ALL"},
+	}
+
+	s, err := newSandbox(zaptest.NewLogger(t), manifest, t.TempDir(), "", func(string) (string, bool) { return "", false })
+	require.NoError(t, err)
+	defer s.cleanup()
+
+	_, args := resolveCommand(manifest.Spec, t.TempDir())
+	assert.Equal(t, []string{
+		"run", "--rm", "-i",
+		"--memory", "67108864", "--cpus", "0.25", "--network", "none", "--read-only",
+		"--user", "1000", "--cap-drop", "ALL", "-e", "MODE",
+		"example/tool:1.0",
+	}, s.dockerArgs(args))
+}
@@ -0,0 +1,88 @@
+package toolruntime
+
+import (
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// functionSchema builds the JSON Schema of a function's input object from its parameters.
+func functionSchema(function schema.ToolFunction) map[string]any {
+	properties := make(map[string]any, len(function.Parameters))
+	required := make([]string, 0)
+	for _, param := range function.Parameters {
+		properties[param.Name] = parameterSchema(param)
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+
+	result := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	return result
+}
+
+// parameterSchema returns the JSON Schema of a parameter. A declared schema is used
+// as is, otherwise it is derived from the parameter constraints.
+func parameterSchema(param schema.ToolParameter) map[string]any {
+	result := make(map[string]any, len(param.Schema)+2)
+	for key, value := range param.Schema {
+		result[key] = value
+	}
+	if _, exists := result["type"]; !exists {
+		result["type"] = param.Type
+	}
+	if _, exists := result["description"]; !exists && param.Description != "" {
+		result["description"] = param.Description
+	}
+	if len(param.Schema) > 0 {
+		return result
+	}
+
+	if len(param.Enum) > 0 {
+		result["enum"] = param.Enum
+	}
+	if param.Default != nil {
+		result["default"] = param.Default
+	}
+	if param.Pattern != "" {
+		result["pattern"] = param.Pattern
+	}
+	if param.MinLength != nil {
+		result["minLength"] = *param.MinLength
+	}
+	if param.MaxLength != nil {
+		result["maxLength"] = *param.MaxLength
+	}
+	if param.Minimum != nil {
+		result["minimum"] = *param.Minimum
+	}
+	if param.Maximum != nil {
+		result["maximum"] = *param.Maximum
+	}
+	return result
+}
+
+// serverToolSchema converts the input schema reported by an MCP server, or returns nil.
+func serverToolSchema(tool mcp.Tool) map[string]any {
+	raw := tool.RawInputSchema
+	if raw == nil {
+		var err error
+		if raw, err = json.Marshal(tool.InputSchema); err != nil {
+			return nil
+		}
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(raw, &result); err != nil || len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package toolruntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// mcpFunctionTool exposes a function of an mcp-server tool as a langchain-go tool.
+type mcpFunctionTool struct {
+	name     string
+	function schema.ToolFunction
+	client   mcpclient.MCPClient
+	sandbox  *sandbox
+	timeout  time.Duration
+	schema   map[string]any
+}
+
+// Name returns the registered tool name.
+func (t *mcpFunctionTool) Name() string {
+	return t.name
+}
+
+// Description returns the function description.
+func (t *mcpFunctionTool) Description() string {
+	return t.function.Description
+}
+
+// Schema returns the input schema served by the MCP server, or the one declared in the manifest.
+func (t *mcpFunctionTool) Schema() map[string]any {
+	if t.schema != nil {
+		return t.schema
+	}
+	return functionSchema(t.function)
+}
+
+// Call invokes the function on the tool's MCP server.
+func (t *mcpFunctionTool) Call(ctx context.Context, input string) (string, error) {
+	args, err := parseArguments(t.function, input)
+	if err != nil {
+		return "", err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = t.function.Name
+	request.Params.Arguments = args
+
+	result, err := t.client.CallTool(callCtx, request)
+	if err != nil {
+		if violation := t.sandbox.violation(t.function.Name); violation != nil {
+			return "", violation
+		}
+		return "", fmt.Errorf("failed to call %s: %w", t.name, err)
+	}
+
+	text := joinTextContent(result.Content)
+	if result.IsError {
+		return "", fmt.Errorf("%s returned an error: %s", t.name, text)
+	}
+	return text, nil
+}
+
+// joinTextContent concatenates the text parts of an MCP call result.
+func joinTextContent(content []mcp.Content) string {
+	parts := make([]string, 0, len(content))
+	for _, item := range content {
+		if text, ok := mcp.AsTextContent(item); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/prompteval"
+)
+
+// CompareFunc runs the input of a prompt across the given models, identified as provider:model.
+type CompareFunc func(ctx context.Context, prompt, input string, models []string) (*prompteval.Comparison, error)
+
+// comparisonDoneMsg delivers the result of a comparison run.
+type comparisonDoneMsg struct {
+	comparison *prompteval.Comparison
+	err        error
+}
+
+// compareModel is a model the user can include in a comparison.
+type compareModel struct {
+	ref     string
+	enabled bool
+}
+
+// comparePanel compares the editor prompt across several models side by side.
+type comparePanel struct {
+	run     CompareFunc
+	models  []compareModel
+	cursor  int
+	running bool
+	result  *prompteval.Comparison
+	status  string
+	keys    compareKeyMap
+}
+
+type compareKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+	Run    key.Binding
+	Export key.Binding
+}
+
+func newCompareKeyMap() compareKeyMap {
+	return compareKeyMap{
+		Up:     key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:   key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		Toggle: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle model")),
+		Run:    key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "compare")),
+		Export: key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "export")),
+	}
+}
+
+// newComparePanel creates an empty compare panel, usable after SetComparisonRunner.
+func newComparePanel() *comparePanel {
+	return &comparePanel{keys: newCompareKeyMap()}
+}
+
+// SetComparisonRunner enables the Compare tab with the offered models. Models
+// listed in enabled are preselected.
+func (m *WorkbenchV3) SetComparisonRunner(models []string, enabled []string, run CompareFunc) {
+	m.compare.run = run
+	m.compare.models = make([]compareModel, 0, len(models))
+	for _, ref := range models {
+		selected := false
+		for _, candidate := range enabled {
+			selected = selected || candidate == ref
+		}
+		m.compare.models = append(m.compare.models, compareModel{ref: ref, enabled: selected})
+	}
+}
+
+// updateCompare handles keys and results of the Compare tab.
+func (m *WorkbenchV3) updateCompare(msg tea.Msg) tea.Cmd {
+	panel := m.compare
+
+	switch msg := msg.(type) {
+	case comparisonDoneMsg:
+		panel.running = false
+		if msg.err != nil {
+			panel.status = "Comparison failed: " + msg.err.Error()
+			return nil
+		}
+		panel.result = msg.comparison
+		panel.status = fmt.Sprintf("Compared %d models", len(msg.comparison.Results))
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, panel.keys.Up):
+			if panel.cursor > 0 {
+				panel.cursor--
+			}
+		case key.Matches(msg, panel.keys.Down):
+			if panel.cursor < len(panel.models)-1 {
+				panel.cursor++
+			}
+		case key.Matches(msg, panel.keys.Toggle):
+			if panel.cursor < len(panel.models) {
+				panel.models[panel.cursor].enabled = !panel.models[panel.cursor].enabled
+			}
+		case key.Matches(msg, panel.keys.Run):
+			return m.startComparison()
+		case key.Matches(msg, panel.keys.Export):
+			panel.status = m.exportComparison()
+		}
+	}
+	return nil
+}
+
+// startComparison renders the editor prompt with the variable values and runs
+// it across the enabled models.
+func (m *WorkbenchV3) startComparison() tea.Cmd {
+	panel := m.compare
+	if panel.running {
+		return nil
+	}
+	if panel.run == nil {
+		panel.status = "Model comparison is not available"
+		return nil
+	}
+
+	models := make([]string, 0, len(panel.models))
+	for _, model := range panel.models {
+		if model.enabled {
+			models = append(models, model.ref)
+		}
+	}
+	if len(models) == 0 {
+		panel.status = "Select at least one model with space"
+		return nil
+	}
+
+	input, missing, err := m.renderEditor(m.variableValues())
+	if err != nil {
+		panel.status = "Cannot render prompt: " + err.Error()
+		return nil
+	}
+
+	panel.running = true
+	panel.status = fmt.Sprintf("Running prompt across %d models...", len(models))
+	if len(missing) > 0 {
+		panel.status += " (rendered without values for: " + strings.Join(missing, ", ") + ")"
+	}
+	log.Info("Starting model comparison", zap.String("prompt", m.promptName), zap.Strings("models", models))
+
+	prompt, run := m.promptName, panel.run
+	return func() tea.Msg {
+		comparison, err := run(context.Background(), prompt, input, models)
+		return comparisonDoneMsg{comparison: comparison, err: err}
+	}
+}
+
+// exportComparison writes the last comparison as JSON and Markdown next to the
+// working directory and returns the status line.
+func (m *WorkbenchV3) exportComparison() string {
+	if m.compare.result == nil {
+		return "Nothing to export, run a comparison first"
+	}
+
+	base := fmt.Sprintf("%s-comparison-%s", m.promptName, m.compare.result.Started.Format("20060102-150405"))
+	formats := map[string]string{".json": prompteval.FormatJSON, ".md": prompteval.FormatMarkdown}
+	for extension, format := range formats {
+		file, err := os.Create(base + extension)
+		if err != nil {
+			return "Export failed: " + err.Error()
+		}
+		err = prompteval.WriteComparison(file, m.compare.result, format)
+		file.Close()
+		if err != nil {
+			return "Export failed: " + err.Error()
+		}
+	}
+	return fmt.Sprintf("Exported to %s.json and %s.md", base, base)
+}
+
+// renderCompareContent renders the model selection and the answers side by side.
+func (m *WorkbenchV3) renderCompareContent() string {
+	panel := m.compare
+	var builder strings.Builder
+	builder.WriteString("Multi-Model Comparison\n\n")
+
+	if len(panel.models) == 0 {
+		builder.WriteString("No models available. Configure an API key to compare models.\n")
+	}
+	for i, model := range panel.models {
+		cursor := "  "
+		if i == panel.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if model.enabled {
+			check = "[x]"
+		}
+		fmt.Fprintf(&builder, "%s%s %s\n", cursor, check, model.ref)
+	}
+
+	builder.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).
+		Render("space toggle • ctrl+r compare • ctrl+e export") + "\n")
+	if panel.status != "" {
+		builder.WriteString("\n" + panel.status + "\n")
+	}
+	if panel.running {
+		return builder.String()
+	}
+	if panel.result != nil && len(panel.result.Results) > 0 {
+		builder.WriteString("\n" + m.renderComparisonColumns(panel.result))
+	}
+	return builder.String()
+}
+
+// renderComparisonColumns renders one column per model with its metrics and answer.
+func (m *WorkbenchV3) renderComparisonColumns(comparison *prompteval.Comparison) string {
+	count := len(comparison.Results)
+	columnWidth := max((m.width-8)/count-2, 20)
+	outputLines := max(m.height-22-len(m.compare.models), 3)
+
+	columns := make([]string, 0, count)
+	for _, result := range comparison.Results {
+		header := lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(result.Model)
+		metrics := fmt.Sprintf("%s • %d/%d tok • %s",
+			result.Latency.Round(time.Millisecond), result.Usage.InputTokens,
+			result.Usage.OutputTokens, prompteval.FormatCost(result.Cost))
+
+		body := result.Output
+		if result.Error != "" {
+			body = lipgloss.NewStyle().Foreground(accentColor).Render(result.Error)
+		}
+		body = truncateLines(lipgloss.NewStyle().Width(columnWidth).Render(strings.TrimSpace(body)), outputLines)
+
+		columns = append(columns, blurredBorderStyle.Width(columnWidth).
+			Render(header+"\n"+metrics+"\n\n"+body))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+// truncateLines keeps the first limit lines of text.
+func truncateLines(text string, limit int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= limit {
+		return text
+	}
+	return strings.Join(lines[:limit], "\n") + "\n…"
+}
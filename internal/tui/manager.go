@@ -2,22 +2,40 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/denkhaus/agentforge/internal/types"
+	"github.com/tmc/langchaingo/llms"
 	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/llm"
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/prompts"
+	"github.com/denkhaus/agentforge/internal/schema"
+	"github.com/denkhaus/agentforge/internal/types"
 )
 
 // manager implements the TUIManager interface
 type manager struct {
-	logger *zap.Logger
+	logger     *zap.Logger
+	llmService types.LLMService
+	config     types.Config
+	history    *prompteval.RunHistory
+	prompts    prompts.PromptService
 }
 
-// NewManager creates a new TUI manager
-func NewManager(logger *zap.Logger) types.TUIManager {
+// NewManager creates a new TUI manager. The LLM service and config enable
+// model tests and comparisons in the workbench, test runs are recorded in history
+// and the workbench loads and saves prompts with the prompt service.
+func NewManager(logger *zap.Logger, llmService types.LLMService, config types.Config, history *prompteval.RunHistory, promptService prompts.PromptService) types.TUIManager {
 	return &manager{
-		logger: logger,
+		logger:     logger,
+		llmService: llmService,
+		config:     config,
+		history:    history,
+		prompts:    promptService,
 	}
 }
 
@@ -71,7 +89,26 @@ func (m *manager) RunPromptWorkbench(name string) error {
 	m.logger.Info("Starting prompt workbench TUI", zap.String("name", name))
 	
 	// Create and run the enhanced prompt workbench
-	workbench := NewPromptWorkbench(name, m.logger)
+	workbench := NewWorkbenchV3(name, m.logger)
+	if m.prompts != nil {
+		if err := workbench.SetPromptStore(m.loadPrompt, m.savePrompt); err != nil {
+			return err
+		}
+		workbench.SetPromptComposer(func(prompt *schema.Prompt) (*schema.Prompt, error) {
+			return m.prompts.ComposePrompt(filepath.Join("prompts", name, schema.ManifestFileName), prompt)
+		})
+	}
+	if m.llmService != nil && m.config != nil {
+		models, enabled := m.comparisonModels()
+		workbench.SetComparisonRunner(models, enabled, m.compareModels)
+
+		selected := models[0]
+		if len(enabled) > 0 {
+			selected = enabled[0]
+		}
+		workbench.SetTestRunner(models, selected, m.streamModel, m.history)
+		workbench.SetOptimizer(m.optimizePrompt)
+	}
 	
 	program := tea.NewProgram(workbench, tea.WithAltScreen())
 	_, err := program.Run()
@@ -83,4 +120,86 @@ func (m *manager) RunPromptWorkbench(name string) error {
 	
 	m.logger.Info("Prompt workbench completed successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// loadPrompt loads the manifest of a local prompt.
+func (m *manager) loadPrompt(name string) (*schema.Prompt, error) {
+	data, err := m.prompts.LoadPromptData(name)
+	if err != nil {
+		return nil, err
+	}
+	return data.Manifest, nil
+}
+
+// savePrompt saves the manifest of a local prompt.
+func (m *manager) savePrompt(name string, prompt *schema.Prompt) error {
+	return m.prompts.SavePromptData(name, prompts.NewPromptData(prompt))
+}
+
+// comparisonModels returns the default model of every provider. Models of
+// providers with a configured API key are enabled.
+func (m *manager) comparisonModels() (models []string, enabled []string) {
+	for _, provider := range llm.SupportedProviders() {
+		model := llm.DefaultModel(provider)
+		models = append(models, model)
+		if m.config.GetAPIKey(provider) != "" {
+			enabled = append(enabled, model)
+		}
+	}
+	return models, enabled
+}
+
+// streamModel sends input as user message to a model, streaming its answer.
+func (m *manager) streamModel(ctx context.Context, ref, input string, onChunk func(chunk string)) (prompteval.ComparisonResult, error) {
+	target, err := m.target(ctx, ref)
+	if err != nil {
+		return prompteval.ComparisonResult{}, err
+	}
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, input)}
+	return prompteval.RunTarget(ctx, messages, target, prompteval.CompareOptions{}, onChunk), nil
+}
+
+// optimizePrompt optimizes prompt with a model that also proposes the revisions.
+func (m *manager) optimizePrompt(ctx context.Context, prompt *schema.Prompt, ref, criteria string, iterations, candidates int, progress func(prompteval.Candidate)) (*prompteval.Trail, error) {
+	target, err := m.target(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return prompteval.Optimize(ctx, prompt, prompteval.OptimizeOptions{
+		Criteria:   criteria,
+		Iterations: iterations,
+		Candidates: candidates,
+		Eval:       prompteval.Options{Model: target.Model, ModelName: target.Name()},
+		Progress:   progress,
+	})
+}
+
+// target initializes the model of a provider:model reference.
+func (m *manager) target(ctx context.Context, ref string) (prompteval.Target, error) {
+	llmConfig, err := llm.ParseModel(ref, 0.7, 0)
+	if err != nil {
+		return prompteval.Target{}, err
+	}
+	model, err := m.llmService.InitializeLLM(ctx, m.config, llmConfig)
+	if err != nil {
+		return prompteval.Target{}, fmt.Errorf("failed to initialize model %s: %w", ref, err)
+	}
+	return prompteval.Target{Config: llmConfig, Model: model}, nil
+}
+
+// compareModels sends input as user message to the models concurrently.
+// Models that fail to initialize report the error in their column.
+func (m *manager) compareModels(ctx context.Context, prompt, input string, models []string) (*prompteval.Comparison, error) {
+	targets := make([]prompteval.Target, 0, len(models))
+	for _, ref := range models {
+		target, err := m.target(ctx, ref)
+		if err != nil {
+			m.logger.Warn("Failed to initialize model for comparison", zap.String("model", ref), zap.Error(err))
+			target = prompteval.FailedTarget(ref, nil, err)
+		}
+		targets = append(targets, target)
+	}
+
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, input)}
+	return prompteval.Compare(ctx, prompt, messages, targets, prompteval.CompareOptions{}), nil
+}
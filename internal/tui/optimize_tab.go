@@ -0,0 +1,345 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/promptrender"
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// Optimization settings of the workbench.
+const (
+	workbenchIterations = 3
+	workbenchCandidates = 2
+	shownCandidates     = 5
+)
+
+// OptimizeFunc optimizes prompt with a model, identified as provider:model,
+// towards criteria. Every evaluated candidate is passed to progress.
+type OptimizeFunc func(ctx context.Context, prompt *schema.Prompt, model, criteria string, iterations, candidates int, progress func(prompteval.Candidate)) (*prompteval.Trail, error)
+
+// optimizeProgressMsg reports an evaluated candidate.
+type optimizeProgressMsg struct{ candidate prompteval.Candidate }
+
+// optimizeDoneMsg ends an optimization run.
+type optimizeDoneMsg struct {
+	trail *prompteval.Trail
+	err   error
+}
+
+// optimizePanel revises the editor prompt towards user criteria and lets the
+// user accept the best revisions.
+type optimizePanel struct {
+	run       OptimizeFunc
+	criteria  textarea.Model
+	diff      viewport.Model
+	stream    chan tea.Msg
+	cancel    context.CancelFunc
+	evaluated int
+	events    []string
+	trail     *prompteval.Trail
+	best      []prompteval.Candidate
+	selected  int
+	status    string
+	keys      optimizeKeyMap
+}
+
+type optimizeKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Accept key.Binding
+	Back   key.Binding
+}
+
+func newOptimizeKeyMap() optimizeKeyMap {
+	return optimizeKeyMap{
+		Up:     key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:   key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		Accept: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "accept")),
+		Back:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel/back")),
+	}
+}
+
+// newOptimizePanel creates an optimize panel, usable after SetOptimizer.
+func newOptimizePanel() *optimizePanel {
+	criteria := textarea.New()
+	criteria.Placeholder = "Describe the desired outcome, e.g. answers cite the input and stay under 100 words"
+	criteria.SetHeight(3)
+	criteria.ShowLineNumbers = false
+
+	return &optimizePanel{criteria: criteria, diff: viewport.New(80, 10), keys: newOptimizeKeyMap()}
+}
+
+// SetOptimizer enables the Optimize tab.
+func (m *WorkbenchV3) SetOptimizer(run OptimizeFunc) {
+	m.optimizer.run = run
+}
+
+// setSize fits criteria and diff into the tab.
+func (p *optimizePanel) setSize(width, height int) {
+	p.criteria.SetWidth(max(width, 20))
+	p.diff.Width = max(width/2, 20)
+	p.diff.Height = max(height-8, 3)
+}
+
+// focusCriteria gives the criteria input the keyboard while no results are browsed.
+func (p *optimizePanel) focusCriteria(active bool) {
+	if active && p.trail == nil {
+		p.criteria.Focus()
+		return
+	}
+	p.criteria.Blur()
+}
+
+// startOptimization optimizes the editor prompt with the model selected in the Test tab.
+func (m *WorkbenchV3) startOptimization() tea.Cmd {
+	panel := m.optimizer
+	if panel.run == nil || len(m.tester.models) == 0 {
+		panel.status = "Prompt optimization is not available"
+		return nil
+	}
+	criteria := strings.TrimSpace(panel.criteria.Value())
+	if criteria == "" {
+		panel.status = "Describe the desired outcome first"
+		return nil
+	}
+
+	model := m.tester.models[m.tester.model]
+	m.optimizing = true
+	panel.trail, panel.best, panel.events, panel.evaluated = nil, nil, nil, 0
+	panel.criteria.Blur()
+	panel.status = "Optimizing with " + model + "..."
+	log.Info("Starting prompt optimization", zap.String("prompt", m.promptName), zap.String("model", model))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := make(chan tea.Msg, 32)
+	panel.cancel, panel.stream = cancel, stream
+
+	prompt, run := m.workbenchPrompt(), panel.run
+	go func() {
+		defer close(stream)
+		trail, err := run(ctx, prompt, model, criteria, workbenchIterations, workbenchCandidates, func(candidate prompteval.Candidate) {
+			stream <- optimizeProgressMsg{candidate: candidate}
+		})
+		stream <- optimizeDoneMsg{trail: trail, err: err}
+	}()
+	return waitForTestMsg(stream)
+}
+
+// updateOptimize handles progress, results and keys of the Optimize tab.
+func (m *WorkbenchV3) updateOptimize(msg tea.Msg) tea.Cmd {
+	panel := m.optimizer
+
+	switch msg := msg.(type) {
+	case optimizeProgressMsg:
+		panel.evaluated++
+		panel.events = append(panel.events, formatCandidateEvent(msg.candidate))
+		return waitForTestMsg(panel.stream)
+
+	case optimizeDoneMsg:
+		m.optimizing = false
+		panel.cancel()
+		if msg.err != nil {
+			panel.status = "Optimization failed: " + msg.err.Error()
+			panel.focusCriteria(true)
+			return nil
+		}
+		m.finishOptimization(msg.trail)
+		return nil
+
+	case tea.KeyMsg:
+		return m.handleOptimizeKey(msg)
+	}
+	return nil
+}
+
+// finishOptimization saves the trail next to the prompt and lists the best candidates.
+func (m *WorkbenchV3) finishOptimization(trail *prompteval.Trail) {
+	panel := m.optimizer
+	panel.trail = trail
+	panel.best = trail.Best(shownCandidates)
+	panel.selected = 0
+
+	path, err := prompteval.SaveTrail(filepath.Join("prompts", m.promptName), trail)
+	switch {
+	case err != nil:
+		panel.status = "Failed to save trail: " + err.Error()
+	case len(panel.best) == 0:
+		panel.status = fmt.Sprintf("No revision beat the current score %.2f • trail saved to %s", trail.Baseline.Score, path)
+	default:
+		panel.status = "Trail saved to " + path
+	}
+
+	if len(panel.best) == 0 {
+		panel.trail = nil
+		panel.focusCriteria(true)
+		return
+	}
+	m.showCandidateDiff()
+}
+
+// handleOptimizeKey edits criteria, cancels runs and browses candidates.
+func (m *WorkbenchV3) handleOptimizeKey(msg tea.KeyMsg) tea.Cmd {
+	panel := m.optimizer
+
+	if m.optimizing {
+		if key.Matches(msg, panel.keys.Back) {
+			panel.cancel()
+			panel.status = "Cancelling..."
+		}
+		return nil
+	}
+
+	if panel.trail == nil {
+		var cmd tea.Cmd
+		panel.criteria, cmd = panel.criteria.Update(msg)
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg, panel.keys.Up):
+		panel.selected = max(panel.selected-1, 0)
+		m.showCandidateDiff()
+	case key.Matches(msg, panel.keys.Down):
+		panel.selected = min(panel.selected+1, len(panel.best)-1)
+		m.showCandidateDiff()
+	case key.Matches(msg, panel.keys.Accept):
+		candidate := panel.best[panel.selected]
+		m.editor.SetValue(fromTemplateSyntax(candidate.Template))
+		panel.status = fmt.Sprintf("Accepted revision scoring %.2f into the editor", candidate.Score)
+		panel.trail = nil
+		panel.focusCriteria(true)
+	case key.Matches(msg, panel.keys.Back):
+		panel.trail = nil
+		panel.focusCriteria(true)
+	default:
+		var cmd tea.Cmd
+		panel.diff, cmd = panel.diff.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// showCandidateDiff shows the diff of the selected candidate against the editor.
+func (m *WorkbenchV3) showCandidateDiff() {
+	panel := m.optimizer
+	candidate := panel.best[panel.selected]
+	current := toTemplateSyntax(m.editor.Value())
+	panel.diff.SetContent(colorDiff(prompteval.TemplateDiff(current, candidate.Template)))
+	panel.diff.GotoTop()
+}
+
+// renderOptimizeContent renders criteria, progress or the candidates with their diff.
+func (m *WorkbenchV3) renderOptimizeContent() string {
+	panel := m.optimizer
+	var builder strings.Builder
+	builder.WriteString("AI-Powered Optimization")
+	if len(m.tester.models) > 0 {
+		fmt.Fprintf(&builder, "   Model: %s (select in Test tab)", m.tester.models[m.tester.model])
+	}
+	builder.WriteString("\n\nDesired outcome:\n")
+	builder.WriteString(panel.criteria.View() + "\n\n")
+
+	total := 1 + workbenchIterations*workbenchCandidates
+	if m.optimizing {
+		builder.WriteString(m.progress.ViewAs(min(float64(panel.evaluated)/float64(total), 1)) + "\n")
+		for _, event := range lastLines(panel.events, 8) {
+			builder.WriteString(event + "\n")
+		}
+	} else if panel.trail != nil {
+		builder.WriteString(m.renderCandidates())
+	}
+
+	if panel.status != "" {
+		builder.WriteString("\n" + panel.status + "\n")
+	}
+	hints := "ctrl+o optimize"
+	if m.optimizing {
+		hints = "esc cancel"
+	} else if panel.trail != nil {
+		hints = "↑/↓ select • enter accept into editor • pgup/pgdown scroll diff • esc back"
+	}
+	builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(hints))
+	return builder.String()
+}
+
+// renderCandidates lists the best candidates next to the diff of the selected one.
+func (m *WorkbenchV3) renderCandidates() string {
+	panel := m.optimizer
+	var list strings.Builder
+	fmt.Fprintf(&list, "Current template: %.2f\n\n", panel.trail.Baseline.Score)
+	for i, candidate := range panel.best {
+		cursor := "  "
+		if i == panel.selected {
+			cursor = "> "
+		}
+		fmt.Fprintf(&list, "%sround %d • score %.2f\n", cursor, candidate.Iteration, candidate.Score)
+		if candidate.Rationale != "" {
+			list.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).
+				Width(max(m.width/2-12, 20)).Render("    "+candidate.Rationale) + "\n")
+		}
+	}
+
+	left := lipgloss.NewStyle().Width(max(m.width/2-10, 20)).Render(list.String())
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, blurredBorderStyle.Render(panel.diff.View())) + "\n"
+}
+
+// workbenchPrompt builds a template prompt from the editor and the Variables tab.
+func (m *WorkbenchV3) workbenchPrompt() *schema.Prompt {
+	prompt := schema.NewPrompt(m.promptName, "0.0.0")
+	prompt.Spec.Type = schema.PromptTypeTemplate
+	prompt.Spec.Template = toTemplateSyntax(m.editor.Value())
+	for _, row := range m.variables.Rows() {
+		if len(row) < 5 || row[0] == "" {
+			continue
+		}
+		prompt.Spec.Variables = append(prompt.Spec.Variables, schema.PromptVariable{
+			Name:        row[0],
+			Type:        "string",
+			Description: row[4],
+			Default:     row[2],
+		})
+	}
+	return prompt
+}
+
+// toTemplateSyntax rewrites {{name}} placeholders of the editor as {{.name}}.
+func toTemplateSyntax(text string) string {
+	return promptrender.Normalize(text)
+}
+
+// fromTemplateSyntax rewrites {{.name}} placeholders as {{name}} for the editor.
+func fromTemplateSyntax(text string) string {
+	return placeholderPattern.ReplaceAllString(text, "{{$1}}")
+}
+
+// formatCandidateEvent describes an evaluated candidate in the progress log.
+func formatCandidateEvent(candidate prompteval.Candidate) string {
+	switch {
+	case candidate.Iteration == 0:
+		return fmt.Sprintf("Current template scores %.2f", candidate.Score)
+	case candidate.Error != "":
+		return fmt.Sprintf("Round %d: %s", candidate.Iteration, candidate.Error)
+	default:
+		return fmt.Sprintf("Round %d: revision scores %.2f", candidate.Iteration, candidate.Score)
+	}
+}
+
+// lastLines returns the last n lines.
+func lastLines(lines []string, n int) []string {
+	if len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,451 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/prompteval"
+	"github.com/denkhaus/agentforge/internal/promptrender"
+)
+
+// StreamFunc sends input to a model, identified as provider:model, and passes
+// the output to onChunk while it streams. Model errors are reported in the
+// result, the error is for runs that could not start.
+type StreamFunc func(ctx context.Context, model, input string, onChunk func(chunk string)) (prompteval.ComparisonResult, error)
+
+// placeholderPattern matches {{name}} and {{.name}} variable placeholders.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*\.?([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// testChunkMsg carries a streamed piece of output.
+type testChunkMsg struct{ chunk string }
+
+// testDoneMsg ends a test run.
+type testDoneMsg struct {
+	run prompteval.Run
+	err error
+}
+
+// testView is the content shown in the Test tab.
+type testView int
+
+const (
+	testOutputView testView = iota
+	testHistoryView
+	testDiffView
+)
+
+// testPanel runs the editor prompt against a model and keeps the run history.
+type testPanel struct {
+	run      StreamFunc
+	history  *prompteval.RunHistory
+	models   []string
+	model    int
+	view     testView
+	viewport viewport.Model
+	output   strings.Builder
+	stream   chan tea.Msg
+	cancel   context.CancelFunc
+	runs     []prompteval.Run
+	selected int
+	base     int
+	status   string
+	warning  string
+	keys     testKeyMap
+}
+
+type testKeyMap struct {
+	PrevModel key.Binding
+	NextModel key.Binding
+	View      key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Mark      key.Binding
+	Diff      key.Binding
+	Show      key.Binding
+	Cancel    key.Binding
+}
+
+func newTestKeyMap() testKeyMap {
+	return testKeyMap{
+		PrevModel: key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "prev model")),
+		NextModel: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "next model")),
+		View:      key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "output/history")),
+		Up:        key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:      key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		Mark:      key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark diff base")),
+		Diff:      key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "diff")),
+		Show:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "show run")),
+		Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel run")),
+	}
+}
+
+// newTestPanel creates an empty test panel, usable after SetTestRunner.
+func newTestPanel() *testPanel {
+	return &testPanel{viewport: viewport.New(80, 10), base: -1, keys: newTestKeyMap()}
+}
+
+// SetTestRunner enables the Test tab with the offered models, starting with
+// selected. Runs are recorded in history when it is not nil.
+func (m *WorkbenchV3) SetTestRunner(models []string, selected string, run StreamFunc, history *prompteval.RunHistory) {
+	panel := m.tester
+	panel.run, panel.history, panel.models = run, history, models
+	for i, model := range models {
+		if model == selected {
+			panel.model = i
+		}
+	}
+
+	if history == nil {
+		return
+	}
+	runs, err := history.Runs(m.promptName)
+	if err != nil {
+		panel.status = "Failed to load run history: " + err.Error()
+		return
+	}
+	panel.runs = runs
+	panel.selected = len(runs) - 1
+}
+
+// setSize fits the output viewport into the tab.
+func (p *testPanel) setSize(width, height int) {
+	p.viewport.Width = max(width, 20)
+	p.viewport.Height = max(height, 3)
+}
+
+// startTesting renders the editor prompt with the variable values and streams
+// the answer of the selected model.
+func (m *WorkbenchV3) startTesting() tea.Cmd {
+	panel := m.tester
+	if panel.run == nil || len(panel.models) == 0 {
+		panel.status = "Model testing is not available"
+		return nil
+	}
+
+	values := m.variableValues()
+	input, missing, err := m.renderEditor(values)
+	if err != nil {
+		panel.status = "Cannot render prompt: " + err.Error()
+		return nil
+	}
+
+	model := panel.models[panel.model]
+	m.testing = true
+	panel.view = testOutputView
+	panel.output.Reset()
+	panel.viewport.SetContent("")
+	panel.warning = ""
+	if len(missing) > 0 {
+		panel.warning = "Rendered without values for: " + strings.Join(missing, ", ")
+	}
+	panel.status = "Streaming from " + model + "..."
+	log.Info("Starting prompt test", zap.String("prompt", m.promptName), zap.String("model", model))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := make(chan tea.Msg, 128)
+	panel.cancel, panel.stream = cancel, stream
+
+	run := panel.run
+	go func() {
+		defer close(stream)
+		started := time.Now()
+		result, err := run(ctx, model, input, func(chunk string) {
+			stream <- testChunkMsg{chunk: chunk}
+		})
+		stream <- testDoneMsg{
+			run: prompteval.Run{Started: started, Input: input, Variables: values, ComparisonResult: result},
+			err: err,
+		}
+	}()
+	return waitForTestMsg(stream)
+}
+
+// waitForTestMsg delivers the next message of a running test.
+func waitForTestMsg(stream chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-stream
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// updateTest handles streamed output, finished runs and keys of the Test tab.
+func (m *WorkbenchV3) updateTest(msg tea.Msg) tea.Cmd {
+	panel := m.tester
+
+	switch msg := msg.(type) {
+	case testChunkMsg:
+		panel.output.WriteString(msg.chunk)
+		panel.setContent(panel.output.String())
+		panel.viewport.GotoBottom()
+		return waitForTestMsg(panel.stream)
+
+	case testDoneMsg:
+		m.testing = false
+		panel.cancel()
+		if msg.err != nil {
+			panel.status = "Test failed: " + msg.err.Error()
+			return nil
+		}
+		m.finishTest(msg.run)
+		return nil
+
+	case tea.KeyMsg:
+		return m.handleTestKey(msg)
+	}
+	return nil
+}
+
+// finishTest shows the stats of a finished run and records it.
+func (m *WorkbenchV3) finishTest(run prompteval.Run) {
+	panel := m.tester
+	if panel.output.Len() == 0 {
+		panel.setContent(run.Output)
+	}
+	panel.status = formatRunStats(run.ComparisonResult)
+
+	if panel.history == nil {
+		return
+	}
+	recorded, err := panel.history.Append(m.promptName, run)
+	if err != nil {
+		panel.status += " • " + err.Error()
+		return
+	}
+	panel.runs = append(panel.runs, recorded)
+	panel.selected = len(panel.runs) - 1
+}
+
+// handleTestKey handles keys of the Test tab.
+func (m *WorkbenchV3) handleTestKey(msg tea.KeyMsg) tea.Cmd {
+	panel := m.tester
+
+	switch {
+	case key.Matches(msg, panel.keys.Cancel):
+		if m.testing {
+			panel.cancel()
+			panel.status = "Cancelling..."
+		} else if panel.view != testOutputView {
+			panel.view = testHistoryView
+		}
+		return nil
+	case m.testing:
+	case key.Matches(msg, panel.keys.PrevModel):
+		panel.model = (panel.model + len(panel.models) - 1) % max(len(panel.models), 1)
+		return nil
+	case key.Matches(msg, panel.keys.NextModel):
+		panel.model = (panel.model + 1) % max(len(panel.models), 1)
+		return nil
+	case key.Matches(msg, panel.keys.View):
+		if panel.view == testOutputView {
+			panel.view = testHistoryView
+		} else {
+			panel.view = testOutputView
+		}
+		return nil
+	}
+
+	if panel.view == testHistoryView {
+		panel.handleHistoryKey(msg)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	panel.viewport, cmd = panel.viewport.Update(msg)
+	return cmd
+}
+
+// handleHistoryKey selects runs, marks the diff base and opens runs or diffs.
+func (p *testPanel) handleHistoryKey(msg tea.KeyMsg) {
+	if len(p.runs) == 0 {
+		return
+	}
+
+	switch {
+	case key.Matches(msg, p.keys.Up):
+		p.selected = min(p.selected+1, len(p.runs)-1)
+	case key.Matches(msg, p.keys.Down):
+		p.selected = max(p.selected-1, 0)
+	case key.Matches(msg, p.keys.Mark):
+		if p.base == p.selected {
+			p.base = -1
+		} else {
+			p.base = p.selected
+		}
+	case key.Matches(msg, p.keys.Show):
+		run := p.runs[p.selected]
+		p.setContent(run.Output)
+		p.status = fmt.Sprintf("Run #%d • %s • %s", run.ID, run.Model, formatRunStats(run.ComparisonResult))
+		p.view = testOutputView
+	case key.Matches(msg, p.keys.Diff):
+		base := p.base
+		if base < 0 || base == p.selected {
+			base = p.selected - 1
+		}
+		if base < 0 {
+			p.status = "Mark a run with space to diff against"
+			return
+		}
+		p.setContent(colorDiff(prompteval.DiffRuns(p.runs[base], p.runs[p.selected])))
+		p.status = fmt.Sprintf("Diff of run #%d and #%d", p.runs[base].ID, p.runs[p.selected].ID)
+		p.view = testDiffView
+	}
+}
+
+// setContent shows text wrapped to the viewport width.
+func (p *testPanel) setContent(text string) {
+	p.viewport.SetContent(lipgloss.NewStyle().Width(p.viewport.Width).Render(text))
+	p.viewport.GotoTop()
+}
+
+// renderTestContent renders the model selector, output or history, and stats.
+func (m *WorkbenchV3) renderTestContent() string {
+	panel := m.tester
+	var builder strings.Builder
+	builder.WriteString("Prompt Testing")
+	if len(panel.models) > 0 {
+		fmt.Fprintf(&builder, "   Model: ◀ %s ▶", panel.models[panel.model])
+	}
+	builder.WriteString("\n\n")
+
+	if panel.view == testHistoryView {
+		builder.WriteString(m.renderTestHistory())
+	} else {
+		builder.WriteString(focusedBorderStyle.Render(panel.viewport.View()))
+	}
+	builder.WriteString("\n")
+
+	if panel.status != "" {
+		builder.WriteString(panel.status + "\n")
+	}
+	if panel.warning != "" {
+		builder.WriteString(lipgloss.NewStyle().Foreground(warningColor).Render(panel.warning) + "\n")
+	}
+	hints := "ctrl+t run • ←/→ model • v history • pgup/pgdown scroll"
+	if m.testing {
+		hints = "esc cancel"
+	} else if panel.view == testHistoryView {
+		hints = "↑/↓ select • enter show • space mark base • d diff • v output"
+	}
+	builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(hints))
+	return builder.String()
+}
+
+// renderTestHistory lists the recorded runs, newest first.
+func (m *WorkbenchV3) renderTestHistory() string {
+	panel := m.tester
+	if len(panel.runs) == 0 {
+		return "No runs recorded yet, press ctrl+t to run the prompt.\n"
+	}
+
+	var builder strings.Builder
+	visible := max(panel.viewport.Height, 3)
+	for i := len(panel.runs) - 1; i >= 0 && len(panel.runs)-i <= visible; i-- {
+		run := panel.runs[i]
+		cursor := "  "
+		if i == panel.selected {
+			cursor = "> "
+		}
+		marker := " "
+		if i == panel.base {
+			marker = "*"
+		}
+		status := "✓"
+		if run.Error != "" {
+			status = "✗"
+		}
+		preview := strings.Join(strings.Fields(run.Output+run.Error), " ")
+		if len(preview) > 50 {
+			preview = preview[:50] + "…"
+		}
+		fmt.Fprintf(&builder, "%s%s#%-3d %s %s %-28s %6dms %5d tok %s\n", cursor, marker, run.ID,
+			run.Started.Format("01-02 15:04:05"), status, run.Model, run.LatencyMS, run.Usage.Total(), preview)
+	}
+	return builder.String()
+}
+
+// variableValues returns the default values of the Variables tab by name.
+func (m *WorkbenchV3) variableValues() map[string]string {
+	values := make(map[string]string)
+	for _, row := range m.variables.Rows() {
+		if len(row) > 2 && row[0] != "" {
+			values[row[0]] = row[2]
+		}
+	}
+	return values
+}
+
+// requiredVariables returns the names of required variables of the Variables tab.
+func (m *WorkbenchV3) requiredVariables() map[string]bool {
+	required := make(map[string]bool)
+	for _, row := range m.variables.Rows() {
+		if len(row) > 3 && row[3] != "" {
+			required[row[0]] = true
+		}
+	}
+	return required
+}
+
+// renderPlaceholders renders the editor text with the prompt render engine.
+// Variables without a value that are unknown or required render empty and are
+// returned as missing.
+func renderPlaceholders(text string, values map[string]string, required map[string]bool) (string, []string, error) {
+	references, err := promptrender.References(text)
+	if err != nil {
+		return "", nil, err
+	}
+
+	variables := promptrender.StringValues(values)
+	var missing []string
+	for _, name := range references {
+		value, known := values[name]
+		if !known {
+			variables[name] = ""
+		}
+		if !known || (value == "" && required[name]) {
+			missing = append(missing, name)
+		}
+	}
+
+	rendered, err := promptrender.New(nil, nil).Render("workbench", text, variables)
+	return rendered, missing, err
+}
+
+// formatRunStats summarizes latency, tokens and cost of a run.
+func formatRunStats(result prompteval.ComparisonResult) string {
+	if result.Error != "" {
+		return "Error: " + result.Error
+	}
+	latency := time.Duration(result.LatencyMS) * time.Millisecond
+	return fmt.Sprintf("%s • %d in / %d out tokens • %s",
+		latency, result.Usage.InputTokens, result.Usage.OutputTokens, prompteval.FormatCost(result.Cost))
+}
+
+// colorDiff colors added and removed lines of a unified diff.
+func colorDiff(diff string) string {
+	added := lipgloss.NewStyle().Foreground(successColor)
+	removed := lipgloss.NewStyle().Foreground(accentColor)
+
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			lines[i] = added.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = removed.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
+)
+
+// LoadPromptFunc loads the manifest of a prompt.
+type LoadPromptFunc func(name string) (*schema.Prompt, error)
+
+// SavePromptFunc saves the manifest of a prompt.
+type SavePromptFunc func(name string, prompt *schema.Prompt) error
+
+// ComposePromptFunc composes a prompt with its includes and base prompt.
+type ComposePromptFunc func(prompt *schema.Prompt) (*schema.Prompt, error)
+
+// SetPromptComposer composes the edited prompt with its includes and base
+// prompt before it is rendered for tests and comparisons.
+func (m *WorkbenchV3) SetPromptComposer(compose ComposePromptFunc) {
+	m.compose = compose
+}
+
+// renderEditor renders the edited prompt with the variable values. Variables
+// without a value render empty and are returned as missing.
+func (m *WorkbenchV3) renderEditor(values map[string]string) (string, []string, error) {
+	text := m.editor.Value()
+	if m.compose != nil {
+		composed, err := m.compose(m.editedPrompt())
+		if err != nil {
+			return "", nil, err
+		}
+		if text = composed.Spec.Template; text == "" {
+			text = composed.Spec.Content
+		}
+	}
+	return renderPlaceholders(text, values, m.requiredVariables())
+}
+
+// SetPromptStore loads the prompt into the editor and the Variables tab and
+// enables saving the edits with ctrl+s.
+func (m *WorkbenchV3) SetPromptStore(load LoadPromptFunc, save SavePromptFunc) error {
+	m.save = save
+
+	prompt, err := load(m.promptName)
+	if err != nil {
+		return fmt.Errorf("failed to load prompt %s: %w", m.promptName, err)
+	}
+	m.manifest = prompt
+
+	text := prompt.Spec.Template
+	if text == "" {
+		text = prompt.Spec.Content
+	}
+	m.editor.SetValue(fromTemplateSyntax(text))
+
+	rows := make([]table.Row, 0, len(prompt.Spec.Variables))
+	for _, variable := range prompt.Spec.Variables {
+		rows = append(rows, variableRow(variable))
+	}
+	m.variables.SetRows(rows)
+	return nil
+}
+
+// savePrompt saves the edited prompt and reports the outcome in the status line.
+func (m *WorkbenchV3) savePrompt() {
+	if m.save == nil {
+		m.status = "Saving is not available"
+		return
+	}
+
+	prompt := m.editedPrompt()
+	if err := m.save(m.promptName, prompt); err != nil {
+		log.Warn("Failed to save prompt", zap.String("prompt", m.promptName), zap.Error(err))
+		m.status = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+	m.manifest = prompt
+	m.status = fmt.Sprintf("Saved %s", m.promptName)
+}
+
+// editedPrompt applies the editor and the Variables tab to the loaded manifest.
+// Declarations of variables keep the fields the Variables tab does not show.
+func (m *WorkbenchV3) editedPrompt() *schema.Prompt {
+	base := m.manifest
+	if base == nil {
+		base = schema.NewPrompt(m.promptName, "1.0.0")
+		base.Metadata.Description = fmt.Sprintf("AI prompt for %s", m.promptName)
+		base.Spec.Type = schema.PromptTypeTemplate
+		base.Spec.Format = schema.FormatText
+	}
+	prompt := *base
+
+	text := toTemplateSyntax(m.editor.Value())
+	if prompt.Spec.Template == "" && prompt.Spec.Content != "" {
+		prompt.Spec.Content = text
+	} else {
+		prompt.Spec.Template = text
+	}
+
+	prompt.Spec.Variables = nil
+	for _, row := range m.variables.Rows() {
+		if len(row) < 5 || row[0] == "" {
+			continue
+		}
+		variable := schema.PromptVariable{Name: row[0], Type: "string"}
+		if declared := base.GetVariableByName(row[0]); declared != nil {
+			variable = *declared
+		}
+		if row[1] != "" {
+			variable.Type = row[1]
+		}
+		if row[2] != defaultText(variable.Default) {
+			variable.Default = row[2]
+		}
+		variable.Required = row[3] != ""
+		variable.Description = row[4]
+		prompt.Spec.Variables = append(prompt.Spec.Variables, variable)
+	}
+	return &prompt
+}
+
+// variableRow returns the Variables tab row of a variable declaration.
+func variableRow(variable schema.PromptVariable) table.Row {
+	required := ""
+	if variable.Required {
+		required = "✓"
+	}
+	return table.Row{variable.Name, variable.Type, defaultText(variable.Default), required, variable.Description}
+}
+
+// defaultText formats a variable default for the Variables tab.
+func defaultText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
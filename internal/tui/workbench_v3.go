@@ -14,6 +14,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"go.uber.org/zap"
+
+	"github.com/denkhaus/agentforge/internal/schema"
 )
 
 // Enhanced color scheme with adaptive support
@@ -64,8 +66,12 @@ const (
 	VariablesTab
 	TestTab
 	OptimizeTab
+	CompareTab
 )
 
+// tabCount is the number of workbench tabs.
+const tabCount = int(CompareTab) + 1
+
 func (t TabType) String() string {
 	switch t {
 	case EditorTab:
@@ -76,6 +82,8 @@ func (t TabType) String() string {
 		return "Test"
 	case OptimizeTab:
 		return "Optimize"
+	case CompareTab:
+		return "Compare"
 	default:
 		return "Unknown"
 	}
@@ -160,10 +168,17 @@ type WorkbenchV3 struct {
 	height int
 
 	// Components (from reference patterns)
-	editor     textarea.Model
-	variables  table.Model
-	testRunner *TestRunner
-	optimizer  *OptimizerV3
+	editor    textarea.Model
+	variables table.Model
+	tester    *testPanel
+	optimizer *optimizePanel
+	compare   *comparePanel
+
+	// Prompt manifest loaded by SetPromptStore and how edits are saved
+	manifest *schema.Prompt
+	save     SavePromptFunc
+	compose  ComposePromptFunc
+	status   string
 
 	// Progress tracking (from reference/progress/)
 	progress progress.Model
@@ -179,48 +194,6 @@ type WorkbenchV3 struct {
 	optimizing bool
 }
 
-// Test runner with progress tracking
-type TestRunner struct {
-	providers []ModelProvider
-	results   []TestResultV3
-	progress  float64
-	active    bool
-}
-
-type ModelProvider struct {
-	Name    string
-	Model   string
-	Enabled bool
-	Status  string
-}
-
-type TestResultV3 struct {
-	Provider string
-	Model    string
-	Response string
-	Latency  string
-	Success  bool
-	Tokens   int
-	Cost     float64
-}
-
-// Enhanced optimizer with iteration tracking
-type OptimizerV3 struct {
-	iterations    int
-	maxIterations int
-	progress      float64
-	active        bool
-	suggestions   []OptimizationStep
-	bestScore     float64
-}
-
-type OptimizationStep struct {
-	Iteration   int
-	Suggestion  string
-	Score       float64
-	Improvement float64
-}
-
 // Custom border function (from reference/tabs/seamless-tabs.go)
 func tabBorderWithBottom(left, middle, right string) lipgloss.Border {
 	border := lipgloss.RoundedBorder()
@@ -295,25 +268,6 @@ Format your response in {{format}} format.`, promptName))
 		Bold(false)
 	variables.SetStyles(tableStyles)
 
-	// Initialize test runner
-	testRunner := &TestRunner{
-		providers: []ModelProvider{
-			{"OpenAI", "gpt-4", true, "Ready"},
-			{"OpenAI", "gpt-3.5-turbo", true, "Ready"},
-			{"Anthropic", "claude-3-sonnet", false, "Disabled"},
-			{"Google", "gemini-pro", false, "Disabled"},
-		},
-		results: []TestResultV3{},
-		active:  false,
-	}
-
-	// Initialize optimizer
-	optimizer := &OptimizerV3{
-		maxIterations: 5,
-		suggestions:   []OptimizationStep{},
-		active:        false,
-	}
-
 	// Progress bar with gradient
 	progressBar := progress.New(progress.WithDefaultGradient())
 
@@ -323,8 +277,9 @@ Format your response in {{format}} format.`, promptName))
 		logger:     logger,
 		editor:     editor,
 		variables:  variables,
-		testRunner: testRunner,
-		optimizer:  optimizer,
+		tester:     newTestPanel(),
+		optimizer:  newOptimizePanel(),
+		compare:    newComparePanel(),
 		progress:   progressBar,
 		help:       help.New(),
 		keys:       newWorkbenchKeyMap(),
@@ -351,7 +306,7 @@ func (m *WorkbenchV3) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, m.keys.Quit):
+		case key.Matches(msg, m.keys.Quit) && !m.typing(msg):
 			m.quitting = true
 			return m, tea.Quit
 
@@ -363,7 +318,7 @@ func (m *WorkbenchV3) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.Save):
 			m.logger.Info("Save requested", zap.String("prompt", m.promptName))
-			// TODO: Implement save functionality
+			m.savePrompt()
 
 		case key.Matches(msg, m.keys.Test):
 			if m.activeTab == TestTab && !m.testing {
@@ -375,10 +330,19 @@ func (m *WorkbenchV3) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.startOptimization()
 			}
 
-		case key.Matches(msg, m.keys.Help):
+		case key.Matches(msg, m.keys.Help) && !m.typing(msg):
 			m.help.ShowAll = !m.help.ShowAll
 		}
 
+	case comparisonDoneMsg:
+		return m, m.updateCompare(msg)
+
+	case testChunkMsg, testDoneMsg:
+		return m, m.updateTest(msg)
+
+	case optimizeProgressMsg, optimizeDoneMsg:
+		return m, m.updateOptimize(msg)
+
 	// Progress animation (from reference/progress/animated-progress.go)
 	case progress.FrameMsg:
 		progressModel, cmd := m.progress.Update(msg)
@@ -395,9 +359,15 @@ func (m *WorkbenchV3) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.variables, cmd = m.variables.Update(msg)
 		cmds = append(cmds, cmd)
 	case TestTab:
-		// Update test runner
+		if _, isKey := msg.(tea.KeyMsg); isKey {
+			cmds = append(cmds, m.updateTest(msg))
+		}
 	case OptimizeTab:
-		// Update optimizer
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey && !key.Matches(keyMsg, m.keys.Optimize) {
+			cmds = append(cmds, m.updateOptimize(msg))
+		}
+	case CompareTab:
+		cmds = append(cmds, m.updateCompare(msg))
 	}
 
 	return m, tea.Batch(cmds...)
@@ -427,6 +397,10 @@ func (m *WorkbenchV3) View() string {
 	// Render help
 	helpView := m.help.View(m.keys)
 
+	if m.status != "" {
+		helpView = lipgloss.NewStyle().Foreground(accentColor).Render(m.status) + "\n" + helpView
+	}
+
 	return tabs + "\n" + content + "\n" + helpView
 }
 
@@ -441,18 +415,20 @@ func (m *WorkbenchV3) updateComponentSizes() {
 	m.editor.SetHeight(contentHeight)
 
 	m.progress.Width = contentWidth - 4
+	m.tester.setSize(contentWidth-8, contentHeight-10)
+	m.optimizer.setSize(contentWidth-8, contentHeight-10)
 }
 
 // nextTab switches to the next tab
 func (m *WorkbenchV3) nextTab() {
-	m.activeTab = (m.activeTab + 1) % 4
+	m.activeTab = (m.activeTab + 1) % TabType(tabCount)
 	m.updateFocus()
 	m.logger.Debug("Switched to next tab", zap.String("tab", m.activeTab.String()))
 }
 
 // prevTab switches to the previous tab
 func (m *WorkbenchV3) prevTab() {
-	m.activeTab = (m.activeTab + 3) % 4 // +3 is equivalent to -1 in modulo 4
+	m.activeTab = (m.activeTab + TabType(tabCount) - 1) % TabType(tabCount)
 	m.updateFocus()
 	m.logger.Debug("Switched to previous tab", zap.String("tab", m.activeTab.String()))
 }
@@ -470,39 +446,22 @@ func (m *WorkbenchV3) updateFocus() {
 		m.editor.Blur()
 		m.variables.Blur()
 	}
+	m.optimizer.focusCriteria(m.activeTab == OptimizeTab)
 }
 
-// startTesting initiates the testing process
-func (m *WorkbenchV3) startTesting() tea.Cmd {
-	m.testing = true
-	m.testRunner.active = true
-	m.logger.Info("Starting prompt testing")
-
-	// TODO: Implement actual testing logic
-	return func() tea.Msg {
-		return tea.Msg("test_started")
-	}
-}
-
-// startOptimization initiates the optimization process
-func (m *WorkbenchV3) startOptimization() tea.Cmd {
-	m.optimizing = true
-	m.optimizer.active = true
-	m.logger.Info("Starting prompt optimization")
-
-	// TODO: Implement actual optimization logic
-	return func() tea.Msg {
-		return tea.Msg("optimization_started")
-	}
+// typing reports whether msg is text typed into a focused input, which must
+// not trigger single-letter shortcuts.
+func (m *WorkbenchV3) typing(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyRunes && (m.editor.Focused() || m.optimizer.criteria.Focused())
 }
 
 // renderSeamlessTabs renders tabs with seamless borders
 func (m *WorkbenchV3) renderSeamlessTabs() string {
 	var renderedTabs []string
 
-	for i := EditorTab; i <= OptimizeTab; i++ {
+	for i := EditorTab; i <= CompareTab; i++ {
 		var style lipgloss.Style
-		isFirst, isLast, isActive := i == 0, i == OptimizeTab, i == m.activeTab
+		isFirst, isLast, isActive := i == 0, i == CompareTab, i == m.activeTab
 
 		if isActive {
 			style = activeTabStyle
@@ -542,6 +501,8 @@ func (m *WorkbenchV3) renderTabContent() string {
 		content = m.renderTestContent()
 	case OptimizeTab:
 		content = m.renderOptimizeContent()
+	case CompareTab:
+		content = m.renderCompareContent()
 	}
 
 	// Calculate content width based on tabs
@@ -560,36 +521,6 @@ func (m *WorkbenchV3) renderVariablesContent() string {
 	return m.variables.View()
 }
 
-func (m *WorkbenchV3) renderTestContent() string {
-	content := "Multi-Model Testing\n\n"
-
-	for _, provider := range m.testRunner.providers {
-		status := "❌"
-		if provider.Enabled {
-			status = "✅"
-		}
-		content += fmt.Sprintf("%s %s - %s (%s)\n", status, provider.Name, provider.Model, provider.Status)
-	}
-
-	if m.testing {
-		content += "\n" + m.progress.View()
-	}
-
-	return content
-}
-
-func (m *WorkbenchV3) renderOptimizeContent() string {
-	content := "AI-Powered Optimization\n\n"
-	content += fmt.Sprintf("Iterations: %d/%d\n", m.optimizer.iterations, m.optimizer.maxIterations)
-	content += fmt.Sprintf("Best Score: %.2f\n", m.optimizer.bestScore)
-
-	if m.optimizing {
-		content += "\n" + m.progress.View()
-	}
-
-	return content
-}
-
 // Helper function
 func max(a, b int) int {
 	if a > b {
@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
@@ -51,6 +52,42 @@ type Agent interface {
 	// This keeps agents focused on their core responsibility: representing agent data
 }
 
+// ConversationAgent is an agent whose sessions start with prompt messages after
+// the system prompt, e.g. the few-shot turns of a conversation prompt.
+type ConversationAgent interface {
+	Agent
+
+	// GetPromptMessages returns the messages following the system prompt
+	GetPromptMessages() []llms.MessageContent
+}
+
+// SessionPrefix returns the messages every session of agent starts with: the
+// system prompt, followed by the prompt messages of conversation agents.
+func SessionPrefix(agent Agent) []llms.MessageContent {
+	prefix := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeSystem, agent.GetSystemPrompt())}
+	if conversation, ok := agent.(ConversationAgent); ok {
+		prefix = append(prefix, conversation.GetPromptMessages()...)
+	}
+	return prefix
+}
+
+// StructuredAgent is an agent whose replies are JSON values matching a schema.
+type StructuredAgent interface {
+	Agent
+
+	// GetOutputSchema returns the JSON Schema of the agent replies, nil for free text
+	GetOutputSchema() map[string]any
+}
+
+// OutputSchema returns the JSON Schema the replies of agent must match, or nil
+// if the agent replies with free text.
+func OutputSchema(agent Agent) map[string]any {
+	if structured, ok := agent.(StructuredAgent); ok {
+		return structured.GetOutputSchema()
+	}
+	return nil
+}
+
 // LLMConfig represents the configuration for the underlying LLM.
 type LLMConfig interface {
 	// GetProvider returns the LLM provider name (e.g., "googleai", "openai", "anthropic")
@@ -262,4 +299,96 @@ type AgentSession interface {
 
 	// GetSessionConfig returns the session configuration
 	GetSessionConfig() AgentSessionConfig
+
+	// GetStructuredOutput returns the validated value of the last reply of an
+	// agent with an output schema
+	GetStructuredOutput() (any, bool)
+}
+
+// SchemaTool is a tool that describes its input as JSON Schema.
+type SchemaTool interface {
+	tools.Tool
+
+	// Schema returns the JSON Schema of the tool input object
+	Schema() map[string]any
+}
+
+// ToolSchema returns the input JSON Schema of a tool, or nil if it does not declare one.
+func ToolSchema(tool tools.Tool) map[string]any {
+	if schemaTool, ok := tool.(SchemaTool); ok {
+		return schemaTool.Schema()
+	}
+	return nil
+}
+
+// ToolRuntime turns installed Tool manifests into live langchain-go tools.
+type ToolRuntime interface {
+	// LoadTool starts the tool whose manifest is installed in dir and returns one tool per function
+	LoadTool(ctx context.Context, dir string) ([]tools.Tool, error)
+
+	// UnloadTool stops a loaded tool and releases its process or connections
+	UnloadTool(name string) error
+
+	// GetLoadedTools returns the names of all loaded tool manifests, sorted alphabetically
+	GetLoadedTools() []string
+
+	// Close unloads all tools
+	Close() error
+}
+
+// ToolViolationError reports that a sandboxed tool process was stopped for exceeding a limit.
+type ToolViolationError struct {
+	Tool     string `json:"tool"`
+	Function string `json:"function"`
+	Limit    string `json:"limit"`
+	Detail   string `json:"detail"`
+}
+
+// Error implements the error interface.
+func (e *ToolViolationError) Error() string {
+	return fmt.Sprintf("tool %s violated its %s limit in %s: %s", e.Tool, e.Limit, e.Function, e.Detail)
+}
+
+// ToolHealthState represents the health of a loaded tool.
+type ToolHealthState string
+
+const (
+	ToolHealthUnknown   ToolHealthState = "unknown"
+	ToolHealthHealthy   ToolHealthState = "healthy"
+	ToolHealthUnhealthy ToolHealthState = "unhealthy"
+)
+
+// ToolHealth describes the health check state of a loaded tool manifest.
+type ToolHealth struct {
+	Tool                string          `json:"tool"`
+	State               ToolHealthState `json:"state"`
+	Checked             bool            `json:"checked"`
+	Functions           []string        `json:"functions"`
+	LastCheck           time.Time       `json:"lastCheck,omitempty"`
+	LastError           string          `json:"lastError,omitempty"`
+	ConsecutiveFailures int             `json:"consecutiveFailures"`
+	FailureThreshold    int             `json:"failureThreshold"`
+}
+
+// ToolHealthChecker is implemented by components that track the health of tools.
+type ToolHealthChecker interface {
+	// IsToolHealthy reports whether the tool with the given name may be used
+	IsToolHealthy(name string) bool
+}
+
+// ToolHealthMonitor periodically runs the health checks declared by loaded tools.
+type ToolHealthMonitor interface {
+	ToolHealthChecker
+
+	// GetHealth returns the health of all loaded tools, sorted by tool name
+	GetHealth() []ToolHealth
+
+	// CheckTool runs the health check of a loaded tool immediately
+	CheckTool(ctx context.Context, tool string) (ToolHealth, error)
+
+	// OnHealthChange registers a callback invoked when a tool turns healthy or unhealthy
+	OnHealthChange(callback func(health ToolHealth))
+
+	// Close stops the monitor
+	Close() error
 }
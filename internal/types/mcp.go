@@ -0,0 +1,114 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+)
+
+// MCPServerRegistry provides access to the clients of running MCP servers.
+type MCPServerRegistry interface {
+	// GetClient returns the initialized client for a running server
+	GetClient(serverName string) (mcpclient.MCPClient, bool)
+
+	// GetServerNames returns the names of all running servers, sorted alphabetically
+	GetServerNames() []string
+
+	// Close shuts down all server connections
+	Close() error
+}
+
+// MCPResource describes a resource published by an MCP server.
+type MCPResource struct {
+	Server      string `json:"server"`
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mimeType,omitempty"`
+}
+
+// MCPResourceContent holds the content of a resource read from an MCP server.
+type MCPResourceContent struct {
+	URI      string `json:"uri"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"` // base64 encoded binary data
+}
+
+// ResourceProvider defines the interface for listing and reading MCP resources.
+type ResourceProvider interface {
+	// ListResources returns the resources of all running servers
+	ListResources(ctx context.Context) ([]MCPResource, error)
+
+	// ReadResource reads a resource by URI from the server that published it
+	ReadResource(ctx context.Context, uri string) ([]MCPResourceContent, error)
+}
+
+// MCPServer exposes forge tools, prompts and agents over the Model Context Protocol.
+type MCPServer interface {
+	// ServeStdio serves MCP over stdin/stdout until the context is cancelled
+	ServeStdio(ctx context.Context) error
+
+	// ServeHTTP serves MCP over streamable HTTP on the given address until the context is cancelled
+	ServeHTTP(ctx context.Context, addr string) error
+}
+
+// MCPServerState describes the lifecycle state of a supervised MCP server.
+type MCPServerState string
+
+const (
+	MCPServerStateStopped  MCPServerState = "stopped"
+	MCPServerStateStarting MCPServerState = "starting"
+	MCPServerStateRunning  MCPServerState = "running"
+	MCPServerStateError    MCPServerState = "error"
+	MCPServerStateDisabled MCPServerState = "disabled"
+)
+
+// MCPServerInfo is a snapshot of a supervised MCP server.
+type MCPServerInfo struct {
+	Name      string         `json:"name"`
+	Transport string         `json:"transport"`
+	State     MCPServerState `json:"state"`
+	PID       int            `json:"pid,omitempty"`
+	StartedAt time.Time      `json:"startedAt,omitempty"`
+	LastPing  time.Time      `json:"lastPing,omitempty"`
+	LastError string         `json:"lastError,omitempty"`
+	ToolCount int            `json:"toolCount"`
+	Restarts  int            `json:"restarts"`
+	LogFile   string         `json:"logFile,omitempty"`
+}
+
+// MCPSupervisor tracks, health-checks and restarts the configured MCP servers.
+type MCPSupervisor interface {
+	// ListServers returns a snapshot of all configured servers, sorted by name
+	ListServers() []MCPServerInfo
+
+	// GetServer returns a snapshot of a single server
+	GetServer(name string) (MCPServerInfo, error)
+
+	// CheckServer pings a server immediately and returns the updated snapshot
+	CheckServer(ctx context.Context, name string) (MCPServerInfo, error)
+
+	// RestartServer stops and starts a server and waits until it is running
+	RestartServer(ctx context.Context, name string) error
+
+	// OnRestart registers a callback invoked after a server was restarted
+	OnRestart(callback func(serverName string))
+
+	// Close stops supervision
+	Close() error
+}
+
+// MCPController manages the MCP servers supervised by a forge process, either
+// this one or a long running process reached over its control socket.
+type MCPController interface {
+	// ListServers returns a snapshot of all configured servers, sorted by name
+	ListServers(ctx context.Context) ([]MCPServerInfo, error)
+
+	// CheckServer pings a server immediately and returns the updated snapshot
+	CheckServer(ctx context.Context, name string) (MCPServerInfo, error)
+
+	// RestartServer restarts a server and returns its snapshot once it is running
+	RestartServer(ctx context.Context, name string) (MCPServerInfo, error)
+}
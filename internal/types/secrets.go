@@ -0,0 +1,19 @@
+package types
+
+// SecretStore keeps named secrets such as tool credentials and provider API keys.
+type SecretStore interface {
+	// Set stores or replaces a secret
+	Set(name, value string) error
+
+	// Get returns the value of a secret, errors.ErrSecretNotFound if it does not exist
+	Get(name string) (string, error)
+
+	// Lookup returns the value of a secret and whether it exists
+	Lookup(name string) (string, bool)
+
+	// List returns the names of all secrets, sorted
+	List() ([]string, error)
+
+	// Delete removes a secret, errors.ErrSecretNotFound if it does not exist
+	Delete(name string) error
+}